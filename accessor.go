@@ -0,0 +1,144 @@
+package ical
+
+import (
+	"strings"
+	"time"
+
+	"github.com/knsh14/ical/property"
+)
+
+// Start returns DTSTART, false if it is not set.
+// DATE is midnight in UTC.
+func (e *Event) Start() (time.Time, bool) {
+	return startOf(e.DateTimeStart)
+}
+
+// End returns DTEND, or DTSTART plus DURATION.
+// without both of them, it is the next day of DATE or DTSTART itself.
+// https://tools.ietf.org/html/rfc5545#section-3.6.1
+func (e *Event) End() (time.Time, bool) {
+	if e.DateTimeEnd != nil {
+		return timeOf(e.DateTimeEnd.Value), true
+	}
+	start, ok := e.Start()
+	if !ok {
+		return time.Time{}, false
+	}
+	return e.recurrence().endTime(start), true
+}
+
+// IsAllDay returns true if DTSTART is DATE
+func (e *Event) IsAllDay() bool {
+	return e.DateTimeStart != nil && isDate(e.DateTimeStart.Value)
+}
+
+// AttendeeEmails returns email addresses of ATTENDEEs, attendees of other URI than mailto are skipped
+func (e *Event) AttendeeEmails() []string {
+	return emailsOf(e.Attendees)
+}
+
+// CategoriesList returns values of all CATEGORIES
+func (e *Event) CategoriesList() []string {
+	return categoriesOf(e.Categories)
+}
+
+// GeoPosition returns latitude and longitude of GEO, false if it is not set
+func (e *Event) GeoPosition() (float64, float64, bool) {
+	return geoOf(e.Geo)
+}
+
+// Start returns DTSTART, false if it is not set.
+// DATE is midnight in UTC.
+func (todo *ToDo) Start() (time.Time, bool) {
+	return startOf(todo.DateTimeStart)
+}
+
+// Due returns DUE, or DTSTART plus DURATION, false if neither is set
+func (todo *ToDo) Due() (time.Time, bool) {
+	if todo.DateTimeDue != nil {
+		return timeOf(todo.DateTimeDue.Value), true
+	}
+	start, ok := todo.Start()
+	if !ok || todo.Duration == nil {
+		return time.Time{}, false
+	}
+	return todo.Duration.Value.Add(start), true
+}
+
+// IsAllDay returns true if DTSTART or DUE is DATE
+func (todo *ToDo) IsAllDay() bool {
+	if todo.DateTimeStart != nil {
+		return isDate(todo.DateTimeStart.Value)
+	}
+	return todo.DateTimeDue != nil && isDate(todo.DateTimeDue.Value)
+}
+
+// AttendeeEmails returns email addresses of ATTENDEEs, attendees of other URI than mailto are skipped
+func (todo *ToDo) AttendeeEmails() []string {
+	return emailsOf(todo.Attendees)
+}
+
+// CategoriesList returns values of all CATEGORIES
+func (todo *ToDo) CategoriesList() []string {
+	return categoriesOf(todo.Categories)
+}
+
+// GeoPosition returns latitude and longitude of GEO, false if it is not set
+func (todo *ToDo) GeoPosition() (float64, float64, bool) {
+	return geoOf(todo.Geo)
+}
+
+// Start returns DTSTART, false if it is not set.
+// DATE is midnight in UTC.
+func (j *Journal) Start() (time.Time, bool) {
+	return startOf(j.DateTimeStart)
+}
+
+// IsAllDay returns true if DTSTART is DATE
+func (j *Journal) IsAllDay() bool {
+	return j.DateTimeStart != nil && isDate(j.DateTimeStart.Value)
+}
+
+// AttendeeEmails returns email addresses of ATTENDEEs, attendees of other URI than mailto are skipped
+func (j *Journal) AttendeeEmails() []string {
+	return emailsOf(j.Attendees)
+}
+
+// CategoriesList returns values of all CATEGORIES
+func (j *Journal) CategoriesList() []string {
+	return categoriesOf(j.Categories)
+}
+
+func startOf(p *property.DateTimeStart) (time.Time, bool) {
+	if p == nil {
+		return time.Time{}, false
+	}
+	return timeOf(p.Value), true
+}
+
+func emailsOf(attendees []*property.Attendee) []string {
+	var res []string
+	for _, a := range attendees {
+		u := a.Value.URI
+		if u == nil || !strings.EqualFold(u.Scheme, "mailto") || u.Opaque == "" {
+			continue
+		}
+		res = append(res, u.Opaque)
+	}
+	return res
+}
+
+func categoriesOf(categories []*property.Categories) []string {
+	var res []string
+	for _, c := range categories {
+		res = append(res, stringsOfTexts(c.Values)...)
+	}
+	return res
+}
+
+func geoOf(g *property.Geo) (float64, float64, bool) {
+	if g == nil {
+		return 0, 0, false
+	}
+	return float64(g.Latitude), float64(g.Longitude), true
+}
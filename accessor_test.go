@@ -0,0 +1,148 @@
+package ical
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/knsh14/ical/parameter"
+	"github.com/knsh14/ical/types"
+)
+
+func TestEvent_Accessors(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2020, 1, 10, 10, 0, 0, 0, time.UTC)
+
+	type result struct {
+		Start, End         time.Time
+		HasStart, HasEnd   bool
+		AllDay             bool
+		Emails, Categories []string
+		Lat, Lon           float64
+		HasGeo             bool
+	}
+	testcases := map[string]struct {
+		input    func(*testing.T) *Event
+		expected result
+	}{
+		"empty": {
+			input: func(t *testing.T) *Event {
+				return NewEvent()
+			},
+		},
+		"end": {
+			input: func(t *testing.T) *Event {
+				e := NewEvent()
+				mustNoError(t, e.SetDateTimeStart(parameter.Container{}, types.DateTime(start)))
+				mustNoError(t, e.SetDateTimeEnd(parameter.Container{}, types.DateTime(start.Add(time.Hour))))
+				for _, a := range []string{"mailto:bob@example.com", "https://example.com/carol", "MAILTO:dave@example.com"} {
+					cua, err := types.NewCalenderUserAddress(a)
+					mustNoError(t, err)
+					mustNoError(t, e.AddAttendee(parameter.Container{}, cua))
+				}
+				mustNoError(t, e.AddCategories(parameter.Container{}, []types.Text{"MEETING", "PROJECT"}))
+				mustNoError(t, e.AddCategories(parameter.Container{}, []types.Text{"WORK"}))
+				mustNoError(t, e.SetGeo(parameter.Container{}, 37.386013, -122.082932))
+				return e
+			},
+			expected: result{
+				Start: start, HasStart: true,
+				End: start.Add(time.Hour), HasEnd: true,
+				Emails:     []string{"bob@example.com", "dave@example.com"},
+				Categories: []string{"MEETING", "PROJECT", "WORK"},
+				Lat:        37.386013, Lon: -122.082932, HasGeo: true,
+			},
+		},
+		"duration": {
+			input: func(t *testing.T) *Event {
+				e := NewEvent()
+				mustNoError(t, e.SetDateTimeStart(parameter.Container{}, types.DateTime(start)))
+				mustNoError(t, e.SetDuration(parameter.Container{}, types.Duration{HourDuration: 90 * time.Minute}))
+				return e
+			},
+			expected: result{
+				Start: start, HasStart: true,
+				End: start.Add(90 * time.Minute), HasEnd: true,
+			},
+		},
+		"all-day": {
+			input: func(t *testing.T) *Event {
+				e := NewEvent()
+				mustNoError(t, e.SetDateTimeStart(parameter.Container{}, types.Date(time.Date(2020, 1, 10, 0, 0, 0, 0, time.UTC))))
+				return e
+			},
+			expected: result{
+				Start: time.Date(2020, 1, 10, 0, 0, 0, 0, time.UTC), HasStart: true,
+				End: time.Date(2020, 1, 11, 0, 0, 0, 0, time.UTC), HasEnd: true,
+				AllDay: true,
+			},
+		},
+	}
+
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			e := tc.input(t)
+			var got result
+			got.Start, got.HasStart = e.Start()
+			got.End, got.HasEnd = e.End()
+			got.AllDay = e.IsAllDay()
+			got.Emails = e.AttendeeEmails()
+			got.Categories = e.CategoriesList()
+			got.Lat, got.Lon, got.HasGeo = e.GeoPosition()
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("diff: (-expected +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestToDo_Due(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2020, 1, 10, 10, 0, 0, 0, time.UTC)
+
+	testcases := map[string]struct {
+		input    func(*testing.T) *ToDo
+		expected time.Time
+		ok       bool
+	}{
+		"due": {
+			input: func(t *testing.T) *ToDo {
+				todo := NewToDo()
+				mustNoError(t, todo.SetDateTimeDue(parameter.Container{}, types.DateTime(start)))
+				return todo
+			},
+			expected: start,
+			ok:       true,
+		},
+		"duration": {
+			input: func(t *testing.T) *ToDo {
+				todo := NewToDo()
+				mustNoError(t, todo.SetDateTimeStart(parameter.Container{}, types.DateTime(start)))
+				mustNoError(t, todo.SetDuration(parameter.Container{}, types.Duration{Day: 2}))
+				return todo
+			},
+			expected: start.AddDate(0, 0, 2),
+			ok:       true,
+		},
+		"start only": {
+			input: func(t *testing.T) *ToDo {
+				todo := NewToDo()
+				mustNoError(t, todo.SetDateTimeStart(parameter.Container{}, types.DateTime(start)))
+				return todo
+			},
+		},
+	}
+
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, ok := tc.input(t).Due()
+			if ok != tc.ok || !got.Equal(tc.expected) {
+				t.Errorf("expected %v %v, but %v %v", tc.expected, tc.ok, got, ok)
+			}
+		})
+	}
+}
@@ -1,7 +1,6 @@
 package ical
 
 import (
-	"fmt"
 	"io"
 
 	"github.com/knsh14/ical/component"
@@ -36,29 +35,44 @@ type AlarmAudio struct {
 
 	XProperties    []*property.NonStandard
 	IANAProperties []*property.IANA
+
+	// PropertyOrder is order of property names in parsed stream.
+	// Decode writes properties in this order if it is set.
+	PropertyOrder []property.Name
 }
 
 func (aa *AlarmAudio) implementAlarm() {}
 
 func (aa *AlarmAudio) Decode(w io.Writer) error {
-	fmt.Fprintf(w, "%s:%s", property.NameBegin, component.TypeAlarm)
-	if err := aa.Action.Decode(w); err != nil {
+	if err := encodeBegin(w, component.TypeAlarm); err != nil {
 		return err
 	}
-	if err := aa.Trigger.Decode(w); err != nil {
-		return err
+	pw := newPropertyWriter(w, aa.PropertyOrder)
+	if aa.Action != nil {
+		pw.add(property.NameAction, aa.Action)
 	}
-	if err := aa.Duration.Decode(w); err != nil {
-		return err
+	if aa.Trigger != nil {
+		pw.add(property.NameTrigger, aa.Trigger)
 	}
-	if err := aa.RepeatCount.Decode(w); err != nil {
-		return err
+	if aa.Duration != nil {
+		pw.add(property.NameDuration, aa.Duration)
+	}
+	if aa.RepeatCount != nil {
+		pw.add(property.NameRepeatCount, aa.RepeatCount)
+	}
+	if aa.Attachment != nil {
+		pw.add(property.NameAttachment, aa.Attachment)
+	}
+	for _, v := range aa.XProperties {
+		pw.add(property.Name(v.Name), v)
 	}
-	if err := aa.Attachment.Decode(w); err != nil {
+	for _, v := range aa.IANAProperties {
+		pw.add(property.Name(v.Name), v)
+	}
+	if err := pw.flush(); err != nil {
 		return err
 	}
-	fmt.Fprintf(w, "%s:%s", property.NameEnd, component.TypeAlarm)
-	return nil
+	return encodeEnd(w, component.TypeAlarm)
 }
 
 func (aa *AlarmAudio) Validate() error {
@@ -144,29 +158,44 @@ type AlarmDisplay struct {
 
 	XProperties    []*property.NonStandard
 	IANAProperties []*property.IANA
+
+	// PropertyOrder is order of property names in parsed stream.
+	// Decode writes properties in this order if it is set.
+	PropertyOrder []property.Name
 }
 
 func (ad *AlarmDisplay) implementAlarm() {}
 
 func (ad *AlarmDisplay) Decode(w io.Writer) error {
-	fmt.Fprintf(w, "%s:%s", property.NameBegin, component.TypeAlarm)
-	if err := ad.Action.Decode(w); err != nil {
+	if err := encodeBegin(w, component.TypeAlarm); err != nil {
 		return err
 	}
-	if err := ad.Description.Decode(w); err != nil {
-		return err
+	pw := newPropertyWriter(w, ad.PropertyOrder)
+	if ad.Action != nil {
+		pw.add(property.NameAction, ad.Action)
 	}
-	if err := ad.Trigger.Decode(w); err != nil {
-		return err
+	if ad.Description != nil {
+		pw.add(property.NameDescription, ad.Description)
 	}
-	if err := ad.Duration.Decode(w); err != nil {
-		return err
+	if ad.Trigger != nil {
+		pw.add(property.NameTrigger, ad.Trigger)
+	}
+	if ad.Duration != nil {
+		pw.add(property.NameDuration, ad.Duration)
 	}
-	if err := ad.RepeatCount.Decode(w); err != nil {
+	if ad.RepeatCount != nil {
+		pw.add(property.NameRepeatCount, ad.RepeatCount)
+	}
+	for _, v := range ad.XProperties {
+		pw.add(property.Name(v.Name), v)
+	}
+	for _, v := range ad.IANAProperties {
+		pw.add(property.Name(v.Name), v)
+	}
+	if err := pw.flush(); err != nil {
 		return err
 	}
-	fmt.Fprintf(w, "%s:%s", property.NameEnd, component.TypeAlarm)
-	return nil
+	return encodeEnd(w, component.TypeAlarm)
 }
 
 func (ad *AlarmDisplay) Validate() error {
@@ -258,41 +287,52 @@ type AlarmEmail struct {
 	Attachments    []*property.Attachment
 	XProperties    []*property.NonStandard
 	IANAProperties []*property.IANA
+
+	// PropertyOrder is order of property names in parsed stream.
+	// Decode writes properties in this order if it is set.
+	PropertyOrder []property.Name
 }
 
 func (ae *AlarmEmail) implementAlarm() {}
 func (ae *AlarmEmail) Decode(w io.Writer) error {
-	fmt.Fprintf(w, "%s:%s", property.NameBegin, component.TypeAlarm)
-	if err := ae.Action.Decode(w); err != nil {
+	if err := encodeBegin(w, component.TypeAlarm); err != nil {
 		return err
 	}
-	if err := ae.Description.Decode(w); err != nil {
-		return err
+	pw := newPropertyWriter(w, ae.PropertyOrder)
+	if ae.Action != nil {
+		pw.add(property.NameAction, ae.Action)
 	}
-	if err := ae.Trigger.Decode(w); err != nil {
-		return err
+	if ae.Description != nil {
+		pw.add(property.NameDescription, ae.Description)
 	}
-	if err := ae.Summary.Decode(w); err != nil {
-		return err
+	if ae.Trigger != nil {
+		pw.add(property.NameTrigger, ae.Trigger)
 	}
-	for _, a := range ae.Attendees {
-		if err := a.Decode(w); err != nil {
-			return err
-		}
+	if ae.Summary != nil {
+		pw.add(property.NameSummary, ae.Summary)
 	}
-	if err := ae.Duration.Decode(w); err != nil {
-		return err
+	for _, v := range ae.Attendees {
+		pw.add(property.NameAttendee, v)
 	}
-	if err := ae.RepeatCount.Decode(w); err != nil {
-		return err
+	if ae.Duration != nil {
+		pw.add(property.NameDuration, ae.Duration)
 	}
-	for _, a := range ae.Attachments {
-		if err := a.Decode(w); err != nil {
-			return err
-		}
+	if ae.RepeatCount != nil {
+		pw.add(property.NameRepeatCount, ae.RepeatCount)
 	}
-	fmt.Fprintf(w, "%s:%s", property.NameEnd, component.TypeAlarm)
-	return nil
+	for _, v := range ae.Attachments {
+		pw.add(property.NameAttachment, v)
+	}
+	for _, v := range ae.XProperties {
+		pw.add(property.Name(v.Name), v)
+	}
+	for _, v := range ae.IANAProperties {
+		pw.add(property.Name(v.Name), v)
+	}
+	if err := pw.flush(); err != nil {
+		return err
+	}
+	return encodeEnd(w, component.TypeAlarm)
 }
 
 func (ae *AlarmEmail) Validate() error {
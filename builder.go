@@ -0,0 +1,202 @@
+package ical
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/knsh14/ical/component"
+	"github.com/knsh14/ical/parameter"
+	"github.com/knsh14/ical/property"
+	"github.com/knsh14/ical/types"
+)
+
+// Param adds a parameter to property which builders create.
+// kind is type of the component which has the property.
+type Param func(params parameter.Container, kind component.Type) error
+
+// RSVP returns Param of RSVP
+// https://tools.ietf.org/html/rfc5545#section-3.2.17
+func RSVP(b bool) Param {
+	return func(params parameter.Container, kind component.Type) error {
+		params[parameter.TypeNameRSVP] = []parameter.Base{&parameter.RSVP{Value: types.Boolean(b)}}
+		return nil
+	}
+}
+
+// CommonName returns Param of CN
+// https://tools.ietf.org/html/rfc5545#section-3.2.2
+func CommonName(name string) Param {
+	return func(params parameter.Container, kind component.Type) error {
+		params[parameter.TypeNameCommonName] = []parameter.Base{parameter.NewCommonName(name)}
+		return nil
+	}
+}
+
+// Role returns Param of ROLE
+// https://tools.ietf.org/html/rfc5545#section-3.2.16
+func Role(role parameter.ParticipationRoleType) Param {
+	return func(params parameter.Container, kind component.Type) error {
+		r, err := parameter.NewParticipationRole(string(role))
+		if err != nil {
+			return err
+		}
+		params[parameter.TypeNameParticipationRole] = []parameter.Base{r}
+		return nil
+	}
+}
+
+// ParticipationStatus returns Param of PARTSTAT
+// https://tools.ietf.org/html/rfc5545#section-3.2.12
+func ParticipationStatus(status parameter.ParticipationStatusType) Param {
+	return func(params parameter.Container, kind component.Type) error {
+		ps, err := parameter.NewParticipationStatus(string(status), kind)
+		if err != nil {
+			return err
+		}
+		params[parameter.TypeNameParticipationStatus] = []parameter.Base{ps}
+		return nil
+	}
+}
+
+// Language returns Param of LANGUAGE
+// https://tools.ietf.org/html/rfc5545#section-3.2.10
+func Language(tag string) Param {
+	return func(params parameter.Container, kind component.Type) error {
+		l, err := parameter.NewLanguage(tag)
+		if err != nil {
+			return err
+		}
+		params[parameter.TypeNameLanguage] = []parameter.Base{l}
+		return nil
+	}
+}
+
+// newParams returns parameter.Container which has parameters of ps
+func newParams(kind component.Type, ps []Param) (parameter.Container, error) {
+	params := parameter.Container{}
+	for _, p := range ps {
+		if err := p(params, kind); err != nil {
+			return nil, err
+		}
+	}
+	return params, nil
+}
+
+// dateTimeOf returns DATE-TIME of t and TZID of its location.
+// time in time.Local or location without name is converted to UTC.
+func dateTimeOf(t time.Time) (parameter.Container, types.DateTime, error) {
+	params := parameter.Container{}
+	switch loc := t.Location(); {
+	case loc == time.UTC:
+	case loc == time.Local || loc.String() == "":
+		t = t.UTC()
+	default:
+		tzid, err := parameter.NewReferenceTimezone(loc.String())
+		if err != nil {
+			return nil, types.DateTime{}, err
+		}
+		params[parameter.TypeNameReferenceTimezone] = []parameter.Base{tzid}
+	}
+	return params, types.DateTime(t.Truncate(time.Second)), nil
+}
+
+// dateOf returns DATE of t in its location and VALUE=DATE
+func dateOf(t time.Time) (parameter.Container, types.Date) {
+	params := parameter.Container{
+		parameter.TypeNameValueType: []parameter.Base{parameter.NewValueType(string(types.ValueTypeDate))},
+	}
+	return params, types.Date(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC))
+}
+
+// durationOf returns DURATION of d, whole days of d are written in days
+func durationOf(d time.Duration) types.Duration {
+	var dir string
+	if d < 0 {
+		dir, d = "-", -d
+	}
+	day := 24 * time.Hour
+	return types.Duration{Direction: dir, Day: int64(d / day), HourDuration: d % day}
+}
+
+// addressOf returns CAL-ADDRESS of uri and its parameters
+func addressOf(kind component.Type, uri string, ps []Param) (parameter.Container, types.CalenderUserAddress, error) {
+	params, err := newParams(kind, ps)
+	if err != nil {
+		return nil, types.CalenderUserAddress{}, err
+	}
+	cua, err := types.NewCalenderUserAddress(uri)
+	if err != nil {
+		return nil, types.CalenderUserAddress{}, err
+	}
+	return params, cua, nil
+}
+
+// textsOf returns TEXT values of vs
+func textsOf(vs []string) []types.Text {
+	res := make([]types.Text, len(vs))
+	for i, v := range vs {
+		res[i] = types.NewText(v)
+	}
+	return res
+}
+
+// buildError returns error of setting property pname
+func buildError(pname property.Name, err error) error {
+	return fmt.Errorf("set %s: %w", pname, err)
+}
+
+// CalendarBuilder builds VCALENDAR.
+// the first error in building is returned by Build.
+type CalendarBuilder struct {
+	calendar *Calendar
+	// from and to are range of VTIMEZONEs which Build adds
+	from, to time.Time
+	err      error
+}
+
+// NewCalendarBuilder returns CalendarBuilder of calendar which has PRODID prodID
+func NewCalendarBuilder(prodID string) *CalendarBuilder {
+	b := &CalendarBuilder{calendar: NewCalendar()}
+	return b.set(property.NameProdID, b.calendar.SetProdID(parameter.Container{}, types.NewText(prodID)))
+}
+
+func (b *CalendarBuilder) set(pname property.Name, err error) *CalendarBuilder {
+	if err != nil && b.err == nil {
+		b.err = buildError(pname, err)
+	}
+	return b
+}
+
+// Method sets METHOD of iTIP
+func (b *CalendarBuilder) Method(m property.MethodType) *CalendarBuilder {
+	return b.set(property.NameMethod, b.calendar.SetMethod(parameter.Container{}, types.NewText(string(m))))
+}
+
+// Component adds components to the calendar
+func (b *CalendarBuilder) Component(cs ...CalenderComponent) *CalendarBuilder {
+	b.calendar.Components = append(b.calendar.Components, cs...)
+	return b
+}
+
+// Timezones makes Build add VTIMEZONE for each TZID which components refer, they follow time zones from from to to.
+func (b *CalendarBuilder) Timezones(from, to time.Time) *CalendarBuilder {
+	b.from, b.to = from, to
+	return b
+}
+
+// Build returns the calendar.
+// it returns error of building, or Findings of errors which Check finds.
+func (b *CalendarBuilder) Build() (*Calendar, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	if !b.from.IsZero() || !b.to.IsZero() {
+		if err := b.calendar.AddMissingTimezones(b.from, b.to); err != nil {
+			return nil, err
+		}
+	}
+	if errs := b.calendar.Check().Errors(); len(errs) > 0 {
+		return nil, errs
+	}
+	return b.calendar, nil
+}
@@ -0,0 +1,233 @@
+package ical
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/knsh14/ical/parameter"
+	"github.com/knsh14/ical/property"
+)
+
+func TestEventBuilder(t *testing.T) {
+	t.Parallel()
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	mustNoError(t, err)
+	stamp := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	testcases := map[string]struct {
+		build    func() (*Event, error)
+		expected []string
+	}{
+		"event": {
+			build: func() (*Event, error) {
+				return NewEventBuilder().
+					UID("meeting@example.com").
+					Stamp(stamp).
+					Start(time.Date(2020, 1, 10, 10, 0, 0, 0, tokyo)).
+					End(time.Date(2020, 1, 10, 11, 0, 0, 0, tokyo)).
+					Summary("Planning", Language("en")).
+					Organizer("mailto:alice@example.com", CommonName("Alice")).
+					Attendee("mailto:bob@example.com", RSVP(true), Role(parameter.ParticipationRoleTypeRequestedParticipant)).
+					Attendee("mailto:carol@example.com", ParticipationStatus(parameter.ParticipationStatusTypeAccepted)).
+					Categories("MEETING").
+					Build()
+			},
+			expected: []string{
+				"BEGIN:VEVENT",
+				"UID:meeting@example.com",
+				"DTSTAMP:20200102T030405Z",
+				"DTSTART;TZID=Asia/Tokyo:20200110T100000",
+				"ORGANIZER;CN=Alice:mailto:alice@example.com",
+				"SUMMARY;LANGUAGE=en:Planning",
+				"DTEND;TZID=Asia/Tokyo:20200110T110000",
+				"ATTENDEE;ROLE=REQ-PARTICIPANT;RSVP=TRUE:mailto:bob@example.com",
+				"ATTENDEE;PARTSTAT=ACCEPTED:mailto:carol@example.com",
+				"CATEGORIES:MEETING",
+				"END:VEVENT",
+			},
+		},
+		"all-day event with duration": {
+			build: func() (*Event, error) {
+				return NewEventBuilder().
+					UID("holiday@example.com").
+					Stamp(stamp).
+					StartDate(time.Date(2020, 1, 1, 0, 0, 0, 0, tokyo)).
+					Duration(48 * time.Hour).
+					RecurrenceRule("FREQ=YEARLY").
+					Build()
+			},
+			expected: []string{
+				"BEGIN:VEVENT",
+				"UID:holiday@example.com",
+				"DTSTAMP:20200102T030405Z",
+				"DTSTART;VALUE=DATE:20200101",
+				"RRULE:FREQ=YEARLY",
+				"DURATION:P2D",
+				"END:VEVENT",
+			},
+		},
+		"all-day event with end date": {
+			build: func() (*Event, error) {
+				return NewEventBuilder().
+					UID("trip@example.com").
+					Stamp(stamp).
+					StartDate(time.Date(2020, 1, 1, 8, 0, 0, 0, tokyo)).
+					EndDate(time.Date(2020, 1, 3, 8, 0, 0, 0, tokyo)).
+					Build()
+			},
+			expected: []string{
+				"BEGIN:VEVENT",
+				"UID:trip@example.com",
+				"DTSTAMP:20200102T030405Z",
+				"DTSTART;VALUE=DATE:20200101",
+				"DTEND;VALUE=DATE:20200103",
+				"END:VEVENT",
+			},
+		},
+	}
+
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			e, err := tc.build()
+			if err != nil {
+				t.Fatal(err)
+			}
+			b := &bytes.Buffer{}
+			mustNoError(t, e.Decode(b))
+			got := strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n")
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("diff: (-expected +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestEventBuilder_Error(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2020, 1, 10, 10, 0, 0, 0, time.UTC)
+
+	testcases := map[string]struct {
+		build func() (*Event, error)
+		rule  Rule
+	}{
+		"invalid address": {
+			build: func() (*Event, error) {
+				return NewEventBuilder().UID("1").Start(start).Attendee("%%").Build()
+			},
+		},
+		"invalid participation status": {
+			build: func() (*Event, error) {
+				return NewEventBuilder().UID("1").Start(start).Attendee("mailto:bob@example.com", ParticipationStatus(parameter.ParticipationStatusTypeCompleted)).Build()
+			},
+		},
+		"invalid rule": {
+			build: func() (*Event, error) {
+				return NewEventBuilder().UID("1").Start(start).RecurrenceRule("FREQ=SOMETIMES").Build()
+			},
+		},
+		"no UID": {
+			build: func() (*Event, error) {
+				return NewEventBuilder().Start(start).Build()
+			},
+			rule: RuleRequired,
+		},
+		"end before start": {
+			build: func() (*Event, error) {
+				return NewEventBuilder().UID("1").Start(start).End(start.Add(-time.Hour)).Build()
+			},
+			rule: RuleEndAfterStart,
+		},
+	}
+
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			e, err := tc.build()
+			if err == nil {
+				t.Fatalf("expected error, but %v", e)
+			}
+			var findings Findings
+			if errors.As(err, &findings) != (tc.rule != "") {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if tc.rule != "" && findings[0].Rule != tc.rule {
+				t.Errorf("expected %s, but %v", tc.rule, findings)
+			}
+		})
+	}
+}
+
+func TestToDoBuilder(t *testing.T) {
+	t.Parallel()
+	todo, err := NewToDoBuilder().
+		UID("todo@example.com").
+		Stamp(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)).
+		Due(time.Date(2020, 1, 10, 10, 0, 0, 0, time.Local)).
+		Summary("Write minutes").
+		Status(property.StatusTypeNeedsAction).
+		PercentComplete(10).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := &bytes.Buffer{}
+	mustNoError(t, todo.Decode(b))
+	expected := []string{
+		"BEGIN:VTODO",
+		"UID:todo@example.com",
+		"DTSTAMP:20200102T030405Z",
+		"PERCENT-COMPLETE:10",
+		"STATUS:NEEDS-ACTION",
+		"SUMMARY:Write minutes",
+		"DUE:" + time.Date(2020, 1, 10, 10, 0, 0, 0, time.Local).UTC().Format("20060102T150405Z"),
+		"END:VTODO",
+	}
+	got := strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n")
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("diff: (-expected +got)\n%s", diff)
+	}
+}
+
+func TestCalendarBuilder(t *testing.T) {
+	t.Parallel()
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	mustNoError(t, err)
+	e, err := NewEventBuilder().
+		UID("meeting@example.com").
+		Start(time.Date(2020, 1, 10, 10, 0, 0, 0, tokyo)).
+		Duration(time.Hour).
+		Build()
+	mustNoError(t, err)
+
+	_, err = NewCalendarBuilder("-//knsh14//ical//EN").Component(e).Build()
+	var findings Findings
+	if !errors.As(err, &findings) || findings[0].Rule != RuleTimezoneDefined {
+		t.Fatalf("expected %s, but %v", RuleTimezoneDefined, err)
+	}
+
+	from := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	c, err := NewCalendarBuilder("-//knsh14//ical//EN").
+		Method(property.MethodTypePublish).
+		Component(e).
+		Timezones(from, from.AddDate(1, 0, 0)).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Components) != 2 {
+		t.Fatalf("expected VTIMEZONE and VEVENT, but %v", c.Components)
+	}
+	if tz, ok := c.Components[0].(*Timezone); !ok || tz.TimezoneIdentifier.Value != "Asia/Tokyo" {
+		t.Errorf("expected VTIMEZONE of Asia/Tokyo, but %v", c.Components[0])
+	}
+	if c.Method == nil || c.Method.Value != "PUBLISH" {
+		t.Errorf("expected METHOD:PUBLISH, but %v", c.Method)
+	}
+}
@@ -0,0 +1,239 @@
+package ical
+
+import (
+	"crypto/rand"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/knsh14/ical/parameter"
+	"github.com/knsh14/ical/property"
+	"github.com/knsh14/ical/types"
+)
+
+// busyTypes is FBTYPEs of busy time, later one has higher priority if periods overlap
+var busyTypes = []parameter.FreeBusyTimeTypeKind{
+	parameter.FreeBusyTimeTypeKindBusyTentative,
+	parameter.FreeBusyTimeTypeKindBusy,
+	parameter.FreeBusyTimeTypeKindBusyUnavailable,
+}
+
+// NewFreeBusyFromCalendars returns VFREEBUSY which has busy time in calendars between start and end.
+// instances of recurring VEVENTs are expanded, and FREEBUSY in VFREEBUSYs of calendars is merged.
+// VEVENT which is TRANSPARENT or CANCELLED is not busy, and TENTATIVE one is BUSY-TENTATIVE.
+// DATE of all-day VEVENT is a day in location of start.
+// UID is a random UUID and DTSTAMP is the current time, SetUID and SetDateTimeStamp replace them.
+// https://tools.ietf.org/html/rfc5545#section-3.6.4
+func NewFreeBusyFromCalendars(start, end time.Time, calendars ...*Calendar) (*FreeBusy, error) {
+	var periods []busyPeriod
+	for _, c := range calendars {
+		ps, err := busyPeriods(c, start, end)
+		if err != nil {
+			return nil, err
+		}
+		periods = append(periods, ps...)
+	}
+
+	fb := NewFreeBusy()
+	params := parameter.Container{}
+	uid, err := newUID()
+	if err != nil {
+		return nil, err
+	}
+	if err := fb.SetUID(params, types.NewText(uid)); err != nil {
+		return nil, err
+	}
+	if err := fb.SetDateTimeStamp(params, types.DateTime(time.Now().UTC().Truncate(time.Second))); err != nil {
+		return nil, err
+	}
+	if err := fb.SetDateTimeStart(params, types.DateTime(start.UTC())); err != nil {
+		return nil, err
+	}
+	if err := fb.SetDateTimeEnd(params, types.DateTime(end.UTC())); err != nil {
+		return nil, err
+	}
+	merged := mergeBusyPeriods(periods)
+	for _, kind := range []parameter.FreeBusyTimeTypeKind{
+		parameter.FreeBusyTimeTypeKindBusy,
+		parameter.FreeBusyTimeTypeKindBusyUnavailable,
+		parameter.FreeBusyTimeTypeKindBusyTentative,
+	} {
+		var values []types.Period
+		for _, p := range merged {
+			if p.kind != kind {
+				continue
+			}
+			values = append(values, types.Period{
+				Start: types.DateTime(p.start.UTC()),
+				Type:  types.PeriodTypeExplicit,
+				End:   types.DateTime(p.end.UTC()),
+			})
+		}
+		if len(values) == 0 {
+			continue
+		}
+		fbtype, err := parameter.NewFreeBusyTimeType(string(kind))
+		if err != nil {
+			return nil, err
+		}
+		if err := fb.AddFreeBusyTime(parameter.Container{
+			parameter.TypeNameFreeBusyTimeType: []parameter.Base{fbtype},
+		}, values); err != nil {
+			return nil, err
+		}
+	}
+	return fb, nil
+}
+
+// newUID returns random UUID as recommended for UID
+// https://tools.ietf.org/html/rfc7986#section-5.3
+func newUID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate UID: %w", err)
+	}
+	b[6] = b[6]&0x0f | 0x40 // version 4
+	b[8] = b[8]&0x3f | 0x80 // variant of RFC 4122
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
+
+// busyPeriod is busy time of FBTYPE kind
+type busyPeriod struct {
+	start, end time.Time
+	kind       parameter.FreeBusyTimeTypeKind
+}
+
+// busyPeriods returns busy time in c which is clipped by start and end
+func busyPeriods(c *Calendar, start, end time.Time) ([]busyPeriod, error) {
+	var res []busyPeriod
+	add := func(s, e time.Time, kind parameter.FreeBusyTimeTypeKind) {
+		if s.Before(start) {
+			s = start
+		}
+		if e.After(end) {
+			e = end
+		}
+		if s.Before(e) {
+			res = append(res, busyPeriod{start: s, end: e, kind: kind})
+		}
+	}
+
+	// only VEVENTs block time, other components are not expanded
+	events := &Calendar{}
+	for _, cc := range c.Components {
+		if e, ok := cc.(*Event); ok {
+			events.Components = append(events.Components, e)
+		}
+	}
+	// all-day events may be in the range in location of start
+	occs, err := events.Occurrences(start.AddDate(0, 0, -1), end.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+	for _, o := range occs {
+		e, ok := o.Component.(*Event)
+		if !ok {
+			continue
+		}
+		kind, busy := e.busyType()
+		if !busy {
+			continue
+		}
+		s, en := o.Start, o.End
+		if e.DateTimeStart != nil && isDate(e.DateTimeStart.Value) {
+			s = inLocation(s, start.Location())
+			en = inLocation(en, start.Location())
+		}
+		add(s, en, kind)
+	}
+
+	for _, cc := range c.Components {
+		fb, ok := cc.(*FreeBusy)
+		if !ok {
+			continue
+		}
+		for _, fbt := range fb.FreeBusyTimes {
+			kind := fbt.FreeBusyType().Type
+			if kind == parameter.FreeBusyTimeTypeKindFree || kind == parameter.FreeBusyTimeTypeKindXName {
+				continue
+			}
+			for _, p := range fbt.Values {
+				s := time.Time(p.Start)
+				e := time.Time(p.End)
+				if p.Type == types.PeriodTypeStart {
+					e = p.Range.Add(s)
+				}
+				add(s, e, kind)
+			}
+		}
+	}
+	return res, nil
+}
+
+// busyType returns FBTYPE of e, and false if e does not block time
+// https://tools.ietf.org/html/rfc5545#section-3.8.2.7
+func (e *Event) busyType() (parameter.FreeBusyTimeTypeKind, bool) {
+	if e.TimeTransparency != nil && e.TimeTransparency.Value == property.TransparencyValueTypeTransparent {
+		return "", false
+	}
+	if e.Status != nil {
+		switch e.Status.Value {
+		case property.StatusTypeCancelled:
+			return "", false
+		case property.StatusTypeTentative:
+			return parameter.FreeBusyTimeTypeKindBusyTentative, true
+		}
+	}
+	return parameter.FreeBusyTimeTypeKindBusy, true
+}
+
+// mergeBusyPeriods merges overlapping periods.
+// FBTYPE of overlapped time is one which has the highest priority.
+func mergeBusyPeriods(periods []busyPeriod) []busyPeriod {
+	type edge struct {
+		at    time.Time
+		kind  int
+		delta int
+	}
+	var edges []edge
+	for _, p := range periods {
+		for i, kind := range busyTypes {
+			if p.kind == kind {
+				edges = append(edges, edge{at: p.start, kind: i, delta: 1}, edge{at: p.end, kind: i, delta: -1})
+			}
+		}
+	}
+	sort.Slice(edges, func(i, j int) bool { return edges[i].at.Before(edges[j].at) })
+
+	var res []busyPeriod
+	active := make([]int, len(busyTypes))
+	for i := 0; i < len(edges); {
+		at := edges[i].at
+		for ; i < len(edges) && edges[i].at.Equal(at); i++ {
+			active[edges[i].kind] += edges[i].delta
+		}
+		kind := -1
+		for k := len(active) - 1; k >= 0; k-- {
+			if active[k] > 0 {
+				kind = k
+				break
+			}
+		}
+		n := len(res)
+		if n > 0 && res[n-1].end.IsZero() {
+			if kind >= 0 && res[n-1].kind == busyTypes[kind] {
+				continue
+			}
+			res[n-1].end = at
+		}
+		if kind >= 0 {
+			res = append(res, busyPeriod{start: at, kind: busyTypes[kind]})
+		}
+	}
+	return res
+}
+
+// inLocation returns the same wall clock as t in loc
+func inLocation(t time.Time, loc *time.Location) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)
+}
@@ -0,0 +1,147 @@
+package ical
+
+import (
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/knsh14/ical/parameter"
+	"github.com/knsh14/ical/property"
+	"github.com/knsh14/ical/types"
+)
+
+func TestNewFreeBusyFromCalendars(t *testing.T) {
+	t.Parallel()
+	utc := func(d, h, min int) time.Time {
+		return time.Date(2020, 1, d, h, min, 0, 0, time.UTC)
+	}
+	tokyo := time.FixedZone("JST", 9*60*60)
+	event := func(t *testing.T, uid string, start, end types.TimeValue) *Event {
+		t.Helper()
+		e := NewEvent()
+		mustNoError(t, e.SetUID(parameter.Container{}, types.NewText(uid)))
+		mustNoError(t, e.SetDateTimeStamp(parameter.Container{}, types.DateTime(utc(1, 0, 0))))
+		mustNoError(t, e.SetDateTimeStart(parameter.Container{}, start))
+		mustNoError(t, e.SetDateTimeEnd(parameter.Container{}, end))
+		return e
+	}
+	type busy struct {
+		Kind       parameter.FreeBusyTimeTypeKind
+		Start, End time.Time
+	}
+
+	testcases := map[string]struct {
+		start, end time.Time
+		calendars  func(t *testing.T) []*Calendar
+		expected   []busy
+	}{
+		"transparency and status": {
+			start: utc(6, 0, 0),
+			end:   utc(7, 0, 0),
+			calendars: func(t *testing.T) []*Calendar {
+				opaque := event(t, "opaque", types.DateTime(utc(6, 9, 0)), types.DateTime(utc(6, 10, 0)))
+				transparent := event(t, "transparent", types.DateTime(utc(6, 11, 0)), types.DateTime(utc(6, 12, 0)))
+				mustNoError(t, transparent.SetTimeTransparency(parameter.Container{}, property.TransparencyValueTypeTransparent))
+				cancelled := event(t, "cancelled", types.DateTime(utc(6, 13, 0)), types.DateTime(utc(6, 14, 0)))
+				mustNoError(t, cancelled.SetStatus(parameter.Container{}, types.NewText("CANCELLED")))
+				tentative := event(t, "tentative", types.DateTime(utc(6, 9, 30)), types.DateTime(utc(6, 11, 0)))
+				mustNoError(t, tentative.SetStatus(parameter.Container{}, types.NewText("TENTATIVE")))
+				c := newTestCalendar(t)
+				c.Components = append(c.Components, opaque, transparent, cancelled, tentative)
+				return []*Calendar{c}
+			},
+			expected: []busy{
+				{Kind: parameter.FreeBusyTimeTypeKindBusy, Start: utc(6, 9, 0), End: utc(6, 10, 0)},
+				{Kind: parameter.FreeBusyTimeTypeKindBusyTentative, Start: utc(6, 10, 0), End: utc(6, 11, 0)},
+			},
+		},
+		"recurrence over calendars": {
+			start: utc(6, 0, 0),
+			end:   utc(16, 0, 0),
+			calendars: func(t *testing.T) []*Calendar {
+				weekly := event(t, "weekly", types.DateTime(utc(1, 9, 0)), types.DateTime(utc(1, 10, 0)))
+				rule, err := types.NewRecurrenceRule("FREQ=WEEKLY")
+				mustNoError(t, err)
+				mustNoError(t, weekly.SetRecurrenceRule(parameter.Container{}, rule))
+				a := newTestCalendar(t)
+				a.Components = append(a.Components, weekly)
+
+				adjacent := event(t, "adjacent", types.DateTime(utc(8, 10, 0)), types.DateTime(utc(8, 11, 0)))
+				fb := NewFreeBusy()
+				mustNoError(t, fb.AddFreeBusyTime(parameter.Container{
+					parameter.TypeNameFreeBusyTimeType: []parameter.Base{&parameter.FreeBusyTimeType{Type: parameter.FreeBusyTimeTypeKindBusyUnavailable}},
+				}, []types.Period{{Start: types.DateTime(utc(15, 8, 0)), Type: types.PeriodTypeStart, Range: types.Duration{HourDuration: 2 * time.Hour}}}))
+				b := newTestCalendar(t)
+				b.Components = append(b.Components, adjacent, fb)
+				return []*Calendar{a, b}
+			},
+			expected: []busy{
+				{Kind: parameter.FreeBusyTimeTypeKindBusy, Start: utc(8, 9, 0), End: utc(8, 11, 0)},
+				{Kind: parameter.FreeBusyTimeTypeKindBusyUnavailable, Start: utc(15, 8, 0), End: utc(15, 10, 0)},
+			},
+		},
+		"all-day event in location of start": {
+			start: time.Date(2020, 1, 6, 0, 0, 0, 0, tokyo),
+			end:   time.Date(2020, 1, 8, 0, 0, 0, 0, tokyo),
+			calendars: func(t *testing.T) []*Calendar {
+				allDay := event(t, "all-day", types.Date(utc(6, 0, 0)), types.Date(utc(7, 0, 0)))
+				c := newTestCalendar(t)
+				c.Components = append(c.Components, allDay)
+				return []*Calendar{c}
+			},
+			expected: []busy{
+				{Kind: parameter.FreeBusyTimeTypeKindBusy, Start: utc(5, 15, 0), End: utc(6, 15, 0)},
+			},
+		},
+		"to-dos do not block time": {
+			start: utc(6, 0, 0),
+			end:   utc(7, 0, 0),
+			calendars: func(t *testing.T) []*Calendar {
+				opaque := event(t, "opaque", types.DateTime(utc(6, 9, 0)), types.DateTime(utc(6, 10, 0)))
+				undated := NewToDo()
+				mustNoError(t, undated.SetUID(parameter.Container{}, types.NewText("undated")))
+				mustNoError(t, undated.SetDateTimeStamp(parameter.Container{}, types.DateTime(utc(1, 0, 0))))
+				dated := NewToDo()
+				mustNoError(t, dated.SetUID(parameter.Container{}, types.NewText("dated")))
+				mustNoError(t, dated.SetDateTimeStamp(parameter.Container{}, types.DateTime(utc(1, 0, 0))))
+				mustNoError(t, dated.SetDateTimeStart(parameter.Container{}, types.DateTime(utc(6, 11, 0))))
+				c := newTestCalendar(t)
+				c.Components = append(c.Components, undated, opaque, dated)
+				return []*Calendar{c}
+			},
+			expected: []busy{
+				{Kind: parameter.FreeBusyTimeTypeKindBusy, Start: utc(6, 9, 0), End: utc(6, 10, 0)},
+			},
+		},
+	}
+
+	for title, tt := range testcases {
+		tt := tt
+		t.Run(title, func(t *testing.T) {
+			t.Parallel()
+			fb, err := NewFreeBusyFromCalendars(tt.start, tt.end, tt.calendars(t)...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []busy
+			for _, fbt := range fb.FreeBusyTimes {
+				for _, p := range fbt.Values {
+					got = append(got, busy{Kind: fbt.FreeBusyType().Type, Start: time.Time(p.Start), End: time.Time(p.End)})
+				}
+			}
+			if diff := cmp.Diff(tt.expected, got); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+
+			c := newTestCalendar(t)
+			c.Components = append(c.Components, fb)
+			if err := c.Validate(); err != nil {
+				t.Fatalf("VFREEBUSY is invalid: %v", err)
+			}
+			if err := c.Decode(ioutil.Discard); err != nil {
+				t.Fatalf("VFREEBUSY can not be encoded: %v", err)
+			}
+		})
+	}
+}
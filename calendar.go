@@ -30,28 +30,56 @@ type Calendar struct {
 	XProperties    []*property.NonStandard // https://tools.ietf.org/html/rfc5545#section-3.8.8.2
 	IANAProperties []*property.IANA
 
+	// PropertyOrder is order of property names in parsed stream.
+	// Decode writes properties in this order if it is set.
+	PropertyOrder []property.Name
+
 	Components []CalenderComponent
 }
 
 func (c *Calendar) Decode(w io.Writer) error {
-	fmt.Fprintf(w, "%s:%s", property.NameBegin, component.TypeCalendar)
-	if err := c.ProdID.Decode(w); err != nil {
+	if err := encodeBegin(w, component.TypeCalendar); err != nil {
 		return err
 	}
-	if err := c.Version.Decode(w); err != nil {
-		return err
+	pw := newPropertyWriter(w, c.PropertyOrder)
+	if c.ProdID != nil {
+		pw.add(property.NameProdID, c.ProdID)
 	}
-	if err := c.CalScale.Decode(w); err != nil {
-		return err
+	if c.Version != nil {
+		pw.add(property.NameVersion, c.Version)
+	}
+	if c.CalScale != nil {
+		pw.add(property.NameCalScale, c.CalScale)
+	}
+	if c.Method != nil {
+		pw.add(property.NameMethod, c.Method)
+	}
+	for _, v := range c.XProperties {
+		pw.add(property.Name(v.Name), v)
 	}
-	if err := c.Method.Decode(w); err != nil {
+	for _, v := range c.IANAProperties {
+		pw.add(property.Name(v.Name), v)
+	}
+	if err := pw.flush(); err != nil {
 		return err
 	}
-	fmt.Fprintf(w, "%s:%s", property.NameEnd, component.TypeCalendar)
-	return nil
+	for _, v := range c.Components {
+		if err := v.Decode(w); err != nil {
+			return err
+		}
+	}
+	return encodeEnd(w, component.TypeCalendar)
 }
 
-func (c *Calendar) Validate() error {
+// Validate returns the first problem of c.
+// if profiles are given, it returns Findings of all errors which Check finds with them instead.
+func (c *Calendar) Validate(profiles ...Profile) error {
+	if len(profiles) > 0 {
+		if errs := c.Check(profiles...).Errors(); len(errs) > 0 {
+			return errs
+		}
+		return nil
+	}
 	if c.ProdID == nil {
 		return NewValidationError(component.TypeCalendar, property.NameProdID, "must not to be nil")
 	}
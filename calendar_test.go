@@ -5,35 +5,93 @@ import (
 	"io/ioutil"
 	"os"
 	"testing"
+	"time"
+
+	"github.com/knsh14/ical/parameter"
+	"github.com/knsh14/ical/property"
+	"github.com/knsh14/ical/types"
 )
 
 func TestCalendar_Decode(t *testing.T) {
 	t.Parallel()
 	testcases := []struct {
 		title  string
-		input  *Calendar
+		input  func(*testing.T) *Calendar
 		expect string
 	}{
 		{
-			title: "",
-			input: nil,
+			title:  "empty calendar",
+			input:  newTestCalendar,
+			expect: "testdata/empty.ics",
+		},
+		{
+			title: "event with escaped and folded text",
+			input: func(t *testing.T) *Calendar {
+				c := newTestCalendar(t)
+				e := newTestEvent(t)
+				mustNoError(t, e.SetSummary(parameter.Container{}, types.NewText("Lunch; with Alice, Bob")))
+				mustNoError(t, e.SetDescription(parameter.Container{}, types.NewText("first line\nsecond line has a backslash \\ and it is long enough to be folded into multiple lines")))
+				mustNoError(t, e.SetLocation(parameter.Container{}, types.NewText("日本語の場所、東京都千代田区千代田一丁目一番一号、とても長い住所です")))
+				cua, err := types.NewCalenderUserAddress("mailto:alice@example.com")
+				mustNoError(t, err)
+				mustNoError(t, e.AddAttendee(parameter.Container{
+					parameter.TypeNameCommonName: []parameter.Base{parameter.NewCommonName("Doe, Alice")},
+				}, cua))
+				mustNoError(t, e.AddCategories(parameter.Container{}, []types.Text{"MEETING", "A,B"}))
+				ns, err := property.NewNonStandard("X-EXAMPLE", parameter.Container{}, []string{"raw", "values"})
+				mustNoError(t, err)
+				e.XProperties = append(e.XProperties, ns)
+				a := NewAlarmAudio()
+				mustNoError(t, a.SetTrigger(parameter.Container{}, types.Duration{Direction: "-", HourDuration: 15 * time.Minute}))
+				e.AddAlarm(a)
+				c.Components = append(c.Components, e)
+				return c
+			},
+			expect: "testdata/event.ics",
 		},
 	}
 
 	for _, tt := range testcases {
+		tt := tt
 		t.Run(tt.title, func(t *testing.T) {
 			t.Parallel()
-			file := hoge(t, tt.expect)
+			file := readFile(t, tt.expect)
 			b := &bytes.Buffer{}
-			tt.input.Decode(b)
+			if err := NewEncoder(b).Encode(tt.input(t)); err != nil {
+				t.Fatal(err)
+			}
 			if !bytes.Equal(b.Bytes(), file) {
-				t.Fatal("result is not expected")
+				t.Fatalf("result is not expected\nexpect:\n%q\ngot:\n%q", file, b.Bytes())
 			}
 		})
 	}
 }
 
-func hoge(t *testing.T, path string) []byte {
+func newTestCalendar(t *testing.T) *Calendar {
+	t.Helper()
+	c := NewCalendar()
+	mustNoError(t, c.SetProdID(parameter.Container{}, types.NewText("-//knsh14//ical//EN")))
+	return c
+}
+
+func newTestEvent(t *testing.T) *Event {
+	t.Helper()
+	e := NewEvent()
+	mustNoError(t, e.SetUID(parameter.Container{}, types.NewText("19970610T172345Z-AF23B2@example.com")))
+	mustNoError(t, e.SetDateTimeStamp(parameter.Container{}, types.DateTime(time.Date(1997, 6, 10, 17, 23, 45, 0, time.UTC))))
+	mustNoError(t, e.SetDateTimeStart(parameter.Container{}, types.DateTime(time.Date(1997, 7, 14, 17, 0, 0, 0, time.UTC))))
+	mustNoError(t, e.SetDateTimeEnd(parameter.Container{}, types.DateTime(time.Date(1997, 7, 15, 4, 0, 0, 0, time.UTC))))
+	return e
+}
+
+func mustNoError(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readFile(t *testing.T, path string) []byte {
 	t.Helper()
 	file, err := os.Open(path)
 	if err != nil {
@@ -18,12 +18,27 @@ type Parameter struct {
 	Values []string
 }
 
+// SyntaxError is error of content line syntax with its position
+type SyntaxError struct {
+	// Offset is offset of the rune which causes the error in unfolded content line, it starts with 0
+	Offset int
+	Err    error
+}
+
+func (e *SyntaxError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *SyntaxError) Unwrap() error {
+	return e.Err
+}
+
 func ConvertContentLine(l *lexer.Lexer) (*ContentLine, error) {
 	var cl ContentLine
 	// get name
 	n, t, err := getName(l)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get name: %w", err)
+		return nil, &SyntaxError{Offset: t.Pos, Err: fmt.Errorf("failed to get name: %w", err)}
 	}
 	cl.Name = n
 
@@ -31,7 +46,7 @@ func ConvertContentLine(l *lexer.Lexer) (*ContentLine, error) {
 	for t.Type == token.SEMICOLON {
 		p, token, err := getParameter(l)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get parameter: %w", err)
+			return nil, &SyntaxError{Offset: token.Pos, Err: fmt.Errorf("failed to get parameter: %w", err)}
 		}
 		t = token
 		cl.Parameters = append(cl.Parameters, p)
@@ -39,18 +54,18 @@ func ConvertContentLine(l *lexer.Lexer) (*ContentLine, error) {
 
 	// get values until illegal or eof
 	if t.Type != token.COLON {
-		return nil, fmt.Errorf("expected \":\" but got %s[%s]", t.Type, t.Value)
+		return nil, &SyntaxError{Offset: t.Pos, Err: fmt.Errorf("expected \":\" but got %s[%s]", t.Type, t.Value)}
 	}
 	for t.Type != token.EOF && t.Type != token.ILLEGAL {
 		v, token, err := getValue(l)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get value: %w", err)
+			return nil, &SyntaxError{Offset: token.Pos, Err: fmt.Errorf("failed to get value: %w", err)}
 		}
 		t = token
 		cl.Values = append(cl.Values, v)
 	}
 	if t.Type == token.ILLEGAL {
-		return nil, fmt.Errorf("received ILLEGAL %v", t.Value)
+		return nil, &SyntaxError{Offset: t.Pos, Err: fmt.Errorf("received ILLEGAL %v", t.Value)}
 	}
 	return &cl, nil
 }
@@ -1,6 +1,7 @@
 package contentline
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 
@@ -187,3 +188,29 @@ this is test`},
 		})
 	}
 }
+
+func TestContentLine_SyntaxError(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		input  string
+		offset int
+	}{
+		"name":      {input: "EX@MPLE:DDDD", offset: 2},
+		"parameter": {input: "DTSTART;VA`UE=DATE:20200301", offset: 10},
+		"no colon":  {input: "DTSTART", offset: 7},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			_, err := ConvertContentLine(lexer.New(tt.input))
+			var se *SyntaxError
+			if !errors.As(err, &se) {
+				t.Fatalf("expected SyntaxError, but %v", err)
+			}
+			if se.Offset != tt.offset {
+				t.Errorf("expected offset %d, but %d", tt.offset, se.Offset)
+			}
+		})
+	}
+}
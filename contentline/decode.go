@@ -0,0 +1,35 @@
+package contentline
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// MaxLineLength is maximum length of a physical line which Unfold reads.
+// lines of inline binary such as ATTACH;ENCODING=BASE64 are often longer than default of bufio.Scanner.
+const MaxLineLength = 64 * 1024 * 1024
+
+// Unfold reads r and returns logical lines which folded lines are joined into.
+// empty lines are skipped.
+// https://tools.ietf.org/html/rfc5545#section-3.1
+func Unfold(r io.Reader) ([]string, error) {
+	var lines []string
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), MaxLineLength)
+	for s.Scan() {
+		l := strings.TrimSuffix(s.Text(), "\r")
+		if l == "" {
+			continue
+		}
+		if n := len(lines); n > 0 && (l[0] == ' ' || l[0] == '\t') {
+			lines[n-1] += l[1:]
+			continue
+		}
+		lines = append(lines, l)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
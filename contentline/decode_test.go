@@ -0,0 +1,51 @@
+package contentline
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestUnfold(t *testing.T) {
+	t.Parallel()
+	testcases := map[string]struct {
+		input    string
+		expected []string
+	}{
+		"not folded": {
+			input:    "BEGIN:VEVENT\r\nEND:VEVENT\r\n",
+			expected: []string{"BEGIN:VEVENT", "END:VEVENT"},
+		},
+		"folded by space and tab": {
+			input:    "DESCRIPTION:a\r\n b\r\n\tc\r\nEND:VEVENT\r\n",
+			expected: []string{"DESCRIPTION:abc", "END:VEVENT"},
+		},
+		"round trip of Fold": {
+			input:    Fold(strings.Repeat("あ", 100)),
+			expected: []string{strings.Repeat("あ", 100)},
+		},
+		"line over 1 MiB": {
+			input:    "ATTACH;ENCODING=BASE64;VALUE=BINARY:" + strings.Repeat("A", 2*1024*1024) + "\r\n",
+			expected: []string{"ATTACH;ENCODING=BASE64;VALUE=BINARY:" + strings.Repeat("A", 2*1024*1024)},
+		},
+		"empty lines and LF": {
+			input:    "BEGIN:VEVENT\n\nEND:VEVENT",
+			expected: []string{"BEGIN:VEVENT", "END:VEVENT"},
+		},
+	}
+
+	for title, tt := range testcases {
+		tt := tt
+		t.Run(title, func(t *testing.T) {
+			t.Parallel()
+			got, err := Unfold(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.expected, got); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
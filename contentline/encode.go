@@ -0,0 +1,43 @@
+package contentline
+
+import (
+	"io"
+	"unicode/utf8"
+)
+
+// maxLineOctets is limit of a line length excluding line break
+// https://tools.ietf.org/html/rfc5545#section-3.1
+const maxLineOctets = 75
+
+// Encode writes s as a content line.
+// Lines longer than 75 octets are folded with CRLF and a single space,
+// and the line is terminated by CRLF.
+// https://tools.ietf.org/html/rfc5545#section-3.1
+func Encode(w io.Writer, s string) error {
+	if _, err := io.WriteString(w, Fold(s)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Fold splits s into lines at most 75 octets and returns it with CRLF terminator.
+// multi-octet characters are never split.
+func Fold(s string) string {
+	buf := make([]byte, 0, len(s)+len(s)/maxLineOctets*3+2)
+	limit := maxLineOctets
+	n := 0
+	for i := 0; i < len(s); {
+		_, size := utf8.DecodeRuneInString(s[i:])
+		if n+size > limit {
+			buf = append(buf, '\r', '\n', ' ')
+			// continuation line starts with a space, so it can contain 74 octets
+			limit = maxLineOctets - 1
+			n = 0
+		}
+		buf = append(buf, s[i:i+size]...)
+		n += size
+		i += size
+	}
+	buf = append(buf, '\r', '\n')
+	return string(buf)
+}
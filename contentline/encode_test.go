@@ -0,0 +1,45 @@
+package contentline
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFold(t *testing.T) {
+	t.Parallel()
+	testcases := map[string]struct {
+		input    string
+		expected string
+	}{
+		"short": {
+			input:    "BEGIN:VEVENT",
+			expected: "BEGIN:VEVENT\r\n",
+		},
+		"exactly 75 octets": {
+			input:    strings.Repeat("a", 75),
+			expected: strings.Repeat("a", 75) + "\r\n",
+		},
+		"76 octets": {
+			input:    strings.Repeat("a", 76),
+			expected: strings.Repeat("a", 75) + "\r\n a\r\n",
+		},
+		"continuation line has 74 octets": {
+			input:    strings.Repeat("a", 75+74+1),
+			expected: strings.Repeat("a", 75) + "\r\n " + strings.Repeat("a", 74) + "\r\n a\r\n",
+		},
+		"multi octet character is not split": {
+			input:    strings.Repeat("a", 74) + "あ",
+			expected: strings.Repeat("a", 74) + "\r\n あ\r\n",
+		},
+	}
+
+	for title, tt := range testcases {
+		tt := tt
+		t.Run(title, func(t *testing.T) {
+			t.Parallel()
+			if got := Fold(tt.input); got != tt.expected {
+				t.Fatalf("unexpected result\nexpect:\t%q\ngot:\t%q", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/knsh14/ical/component"
+	"github.com/knsh14/ical/contentline"
+	"github.com/knsh14/ical/property"
+)
+
+// Encoder writes Calendar as iCalendar stream
+// https://tools.ietf.org/html/rfc5545#section-3
+type Encoder struct {
+	w io.Writer
+}
+
+// NewEncoder returns Encoder which writes to w
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+// Encode validates c and writes it.
+// every content line is terminated by CRLF and folded at 75 octets.
+func (e *Encoder) Encode(c *Calendar) error {
+	if c == nil {
+		return fmt.Errorf("calendar is nil")
+	}
+	if err := c.Validate(); err != nil {
+		return fmt.Errorf("validate calendar: %w", err)
+	}
+	bw := bufio.NewWriter(e.w)
+	if err := c.Decode(bw); err != nil {
+		return fmt.Errorf("encode calendar: %w", err)
+	}
+	return bw.Flush()
+}
+
+func encodeBegin(w io.Writer, c component.Type) error {
+	return contentline.Encode(w, fmt.Sprintf("%s:%s", property.NameBegin, c))
+}
+
+func encodeEnd(w io.Writer, c component.Type) error {
+	return contentline.Encode(w, fmt.Sprintf("%s:%s", property.NameEnd, c))
+}
+
+// propertyEncoder is a property which writes its content lines
+type propertyEncoder interface {
+	Decode(w io.Writer) error
+}
+
+type namedProperty struct {
+	name     property.Name
+	property propertyEncoder
+}
+
+// propertyWriter collects properties of a component and writes them in order.
+type propertyWriter struct {
+	w          io.Writer
+	order      []property.Name
+	properties []namedProperty
+}
+
+func newPropertyWriter(w io.Writer, order []property.Name) *propertyWriter {
+	return &propertyWriter{
+		w:     w,
+		order: order,
+	}
+}
+
+// add adds property p of name
+func (pw *propertyWriter) add(name property.Name, p propertyEncoder) {
+	pw.properties = append(pw.properties, namedProperty{name: name, property: p})
+}
+
+// flush writes added properties.
+// n-th property with a name is placed at n-th position of the name in order.
+// properties which are not in order are written after them in added order.
+func (pw *propertyWriter) flush() error {
+	byName := map[property.Name][]int{}
+	for i, p := range pw.properties {
+		name := property.Name(strings.ToUpper(string(p.name)))
+		byName[name] = append(byName[name], i)
+	}
+	placed := make([]bool, len(pw.properties))
+	var sorted []propertyEncoder
+	for _, name := range pw.order {
+		is := byName[name]
+		if len(is) == 0 {
+			continue
+		}
+		sorted = append(sorted, pw.properties[is[0]].property)
+		placed[is[0]] = true
+		byName[name] = is[1:]
+	}
+	for i, p := range pw.properties {
+		if !placed[i] {
+			sorted = append(sorted, p.property)
+		}
+	}
+	pw.properties = nil
+	for _, p := range sorted {
+		if err := p.Decode(pw.w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package ical
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/knsh14/ical/property"
+)
+
+// testProperty writes its name and value by separate Write calls
+type testProperty struct {
+	name  string
+	value string
+}
+
+func (p testProperty) Decode(w io.Writer) error {
+	if _, err := io.WriteString(w, p.name); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, ":"+p.value+"\r\n")
+	return err
+}
+
+func TestPropertyWriter(t *testing.T) {
+	t.Parallel()
+
+	testcases := map[string]struct {
+		order    []property.Name
+		expected string
+	}{
+		"added order": {
+			expected: "COMMENT:first\r\nSUMMARY:summary\r\nCOMMENT:second\r\nX-A:x\r\n",
+		},
+		"parsed order": {
+			order:    []property.Name{property.NameComment, "X-A", property.NameSummary, property.NameComment},
+			expected: "COMMENT:first\r\nX-A:x\r\nSUMMARY:summary\r\nCOMMENT:second\r\n",
+		},
+	}
+
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			b := &bytes.Buffer{}
+			pw := newPropertyWriter(b, tc.order)
+			pw.add(property.NameComment, testProperty{name: "COMMENT", value: "first"})
+			pw.add(property.NameSummary, testProperty{name: "SUMMARY", value: "summary"})
+			pw.add(property.NameComment, testProperty{name: "COMMENT", value: "second"})
+			pw.add("X-A", testProperty{name: "X-A", value: "x"})
+			mustNoError(t, pw.flush())
+			if diff := cmp.Diff(tc.expected, b.String()); diff != "" {
+				t.Errorf("diff: (-expected +got)\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package errcode
+
+import (
+	"fmt"
+
+	"github.com/morikuni/failure"
+)
+
+// codes of errors returned by parser, property, parameter and types.
+// check them with failure.Is(err, code)
+const (
+	// Invalid is invalid structure of iCalendar stream such as unmatched BEGIN and END
+	Invalid failure.StringCode = "Invalid"
+	// Syntax is content line which does not follow the syntax
+	// https://tools.ietf.org/html/rfc5545#section-3.1
+	Syntax failure.StringCode = "Syntax"
+	// NoEnd is component which finished without END
+	NoEnd failure.StringCode = "NoEnd"
+	// UnsupportedComponent is unknown component or component which is not allowed in its parent
+	UnsupportedComponent failure.StringCode = "UnsupportedComponent"
+	// UnknownProperty is unknown property or property which is not allowed in its component
+	UnknownProperty failure.StringCode = "UnknownProperty"
+	// UnknownParameter is unknown parameter or parameter which is not allowed in its property
+	UnknownParameter failure.StringCode = "UnknownParameter"
+	// MissingProperty is required property or parameter which is not found
+	MissingProperty failure.StringCode = "MissingProperty"
+	// Cardinality is property, parameter or value which occurs more or less than allowed
+	Cardinality failure.StringCode = "Cardinality"
+	// ValueFormat is value which does not match its value type or allowed values
+	ValueFormat failure.StringCode = "ValueFormat"
+	// TimezoneNotFound is TZID which is neither defined by VTIMEZONE nor in IANA time zone database
+	TimezoneNotFound failure.StringCode = "TimezoneNotFound"
+)
+
+// Errorf formats error as fmt.Errorf and annotates it with code.
+// unlike failure.Translate, message of the error is kept as it is.
+func Errorf(code failure.Code, format string, args ...interface{}) error {
+	return Wrap(code, fmt.Errorf(format, args...))
+}
+
+// Wrap annotates err with code, it returns nil if err is nil
+func Wrap(code failure.Code, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &withCode{code: code, err: err}
+}
+
+type withCode struct {
+	code failure.Code
+	err  error
+}
+
+func (w *withCode) Error() string {
+	return w.err.Error()
+}
+
+func (w *withCode) Unwrap() error {
+	return w.err
+}
+
+// As extracts code for failure.CodeOf
+func (w *withCode) As(x interface{}) bool {
+	if c, ok := x.(*failure.Code); ok {
+		*c = w.code
+		return true
+	}
+	return false
+}
@@ -0,0 +1,54 @@
+package errcode
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/morikuni/failure"
+)
+
+func TestErrorf(t *testing.T) {
+	t.Parallel()
+	base := errors.New("base")
+	testcases := map[string]struct {
+		err             error
+		expectedCode    failure.Code
+		expectedMessage string
+	}{
+		"code": {
+			err:             Errorf(ValueFormat, "invalid value %s", "x"),
+			expectedCode:    ValueFormat,
+			expectedMessage: "invalid value x",
+		},
+		"wrapped by fmt": {
+			err:             fmt.Errorf("parse: %w", Errorf(TimezoneNotFound, "get timezone: %w", base)),
+			expectedCode:    TimezoneNotFound,
+			expectedMessage: "parse: get timezone: base",
+		},
+		"outer code": {
+			err:             Wrap(Syntax, Errorf(ValueFormat, "invalid value")),
+			expectedCode:    Syntax,
+			expectedMessage: "invalid value",
+		},
+	}
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			if !failure.Is(tc.err, tc.expectedCode) {
+				code, _ := failure.CodeOf(tc.err)
+				t.Errorf("expected code %v, but %v", tc.expectedCode, code)
+			}
+			if got := tc.err.Error(); got != tc.expectedMessage {
+				t.Errorf("expected message %q, but %q", tc.expectedMessage, got)
+			}
+		})
+	}
+	if !errors.Is(Wrap(Syntax, base), base) {
+		t.Error("wrapped error is lost")
+	}
+	if Wrap(Syntax, nil) != nil {
+		t.Error("expected nil for nil error")
+	}
+}
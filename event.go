@@ -61,124 +61,124 @@ type Event struct {
 
 	XProperties    []*property.NonStandard
 	IANAProperties []*property.IANA
+
+	// PropertyOrder is order of property names in parsed stream.
+	// Decode writes properties in this order if it is set.
+	PropertyOrder []property.Name
 }
 
 func (e *Event) implementCalender() {}
 
 func (e *Event) Decode(w io.Writer) error {
-	fmt.Fprintf(w, "%s:%s", property.NameBegin, component.TypeEvent)
-	if err := e.UID.Decode(w); err != nil {
+	if err := encodeBegin(w, component.TypeEvent); err != nil {
 		return err
 	}
-	if err := e.DateTimeStamp.Decode(w); err != nil {
-		return err
+	pw := newPropertyWriter(w, e.PropertyOrder)
+	if e.UID != nil {
+		pw.add(property.NameUID, e.UID)
 	}
-	if err := e.DateTimeStart.Decode(w); err != nil {
-		return err
+	if e.DateTimeStamp != nil {
+		pw.add(property.NameDateTimeStamp, e.DateTimeStamp)
 	}
-	if err := e.Class.Decode(w); err != nil {
-		return err
+	if e.DateTimeStart != nil {
+		pw.add(property.NameDateTimeStart, e.DateTimeStart)
 	}
-	if err := e.DateTimeCreated.Decode(w); err != nil {
-		return err
+	if e.Class != nil {
+		pw.add(property.NameClass, e.Class)
 	}
-	if err := e.Description.Decode(w); err != nil {
-		return err
+	if e.DateTimeCreated != nil {
+		pw.add(property.NameDateTimeCreated, e.DateTimeCreated)
 	}
-	if err := e.Geo.Decode(w); err != nil {
-		return err
+	if e.Description != nil {
+		pw.add(property.NameDescription, e.Description)
 	}
-	if err := e.LastModified.Decode(w); err != nil {
-		return err
+	if e.Geo != nil {
+		pw.add(property.NameGeo, e.Geo)
 	}
-	if err := e.Location.Decode(w); err != nil {
-		return err
+	if e.LastModified != nil {
+		pw.add(property.NameLastModified, e.LastModified)
 	}
-	if err := e.Organizer.Decode(w); err != nil {
-		return err
+	if e.Location != nil {
+		pw.add(property.NameLocation, e.Location)
 	}
-	if err := e.Priority.Decode(w); err != nil {
-		return err
+	if e.Organizer != nil {
+		pw.add(property.NameOrganizer, e.Organizer)
 	}
-	if err := e.SequenceNumber.Decode(w); err != nil {
-		return err
+	if e.Priority != nil {
+		pw.add(property.NamePriority, e.Priority)
 	}
-	if err := e.Status.Decode(w); err != nil {
-		return err
+	if e.SequenceNumber != nil {
+		pw.add(property.NameSequenceNumber, e.SequenceNumber)
 	}
-	if err := e.Summary.Decode(w); err != nil {
-		return err
+	if e.Status != nil {
+		pw.add(property.NameStatus, e.Status)
 	}
-	if err := e.TimeTransparency.Decode(w); err != nil {
-		return err
+	if e.Summary != nil {
+		pw.add(property.NameSummary, e.Summary)
 	}
-	if err := e.URL.Decode(w); err != nil {
-		return err
+	if e.TimeTransparency != nil {
+		pw.add(property.NameTimeTransparency, e.TimeTransparency)
 	}
-	if err := e.RecurrenceID.Decode(w); err != nil {
-		return err
+	if e.URL != nil {
+		pw.add(property.NameURL, e.URL)
 	}
-	if err := e.RecurrenceRule.Decode(w); err != nil {
-		return err
+	if e.RecurrenceID != nil {
+		pw.add(property.NameRecurrenceID, e.RecurrenceID)
 	}
-	if err := e.DateTimeEnd.Decode(w); err != nil {
-		return err
+	if e.RecurrenceRule != nil {
+		pw.add(property.NameRecurrenceRule, e.RecurrenceRule)
 	}
-	if err := e.Duration.Decode(w); err != nil {
-		return err
+	if e.DateTimeEnd != nil {
+		pw.add(property.NameDateTimeEnd, e.DateTimeEnd)
+	}
+	if e.Duration != nil {
+		pw.add(property.NameDuration, e.Duration)
 	}
 	for _, v := range e.Attachments {
-		if err := v.Decode(w); err != nil {
-			return err
-		}
+		pw.add(property.NameAttachment, v)
 	}
 	for _, v := range e.Attendees {
-		if err := v.Decode(w); err != nil {
-			return err
-		}
+		pw.add(property.NameAttendee, v)
 	}
 	for _, v := range e.Categories {
-		if err := v.Decode(w); err != nil {
-			return err
-		}
+		pw.add(property.NameCategories, v)
 	}
 	for _, v := range e.Comments {
-		if err := v.Decode(w); err != nil {
-			return err
-		}
+		pw.add(property.NameComment, v)
 	}
 	for _, v := range e.Contacts {
-		if err := v.Decode(w); err != nil {
-			return err
-		}
+		pw.add(property.NameContact, v)
 	}
 	for _, v := range e.ExceptionDateTimes {
-		if err := v.Decode(w); err != nil {
-			return err
-		}
+		pw.add(property.NameExceptionDateTimes, v)
 	}
 	for _, v := range e.RequestStatus {
-		if err := v.Decode(w); err != nil {
-			return err
-		}
+		pw.add(property.NameRequestStatus, v)
 	}
 	for _, v := range e.RelatedTos {
-		if err := v.Decode(w); err != nil {
-			return err
-		}
+		pw.add(property.NameRelatedTo, v)
 	}
 	for _, v := range e.Resources {
-		if err := v.Decode(w); err != nil {
-			return err
-		}
+		pw.add(property.NameResources, v)
 	}
 	for _, v := range e.RecurrenceDateTimes {
+		pw.add(property.NameRecurrenceDateTimes, v)
+	}
+	for _, v := range e.XProperties {
+		pw.add(property.Name(v.Name), v)
+	}
+	for _, v := range e.IANAProperties {
+		pw.add(property.Name(v.Name), v)
+	}
+	if err := pw.flush(); err != nil {
+		return err
+	}
+	for _, v := range e.Alarms {
 		if err := v.Decode(w); err != nil {
 			return err
 		}
 	}
-	fmt.Fprintf(w, "%s:%s", property.NameEnd, component.TypeEvent)
-	return nil
+	return encodeEnd(w, component.TypeEvent)
 }
 
 func (e *Event) Validate() error {
@@ -0,0 +1,180 @@
+package ical
+
+import (
+	"time"
+
+	"github.com/knsh14/ical/component"
+	"github.com/knsh14/ical/parameter"
+	"github.com/knsh14/ical/property"
+	"github.com/knsh14/ical/types"
+)
+
+// EventBuilder builds VEVENT from time.Time, time.Duration and strings.
+// the first error in building is returned by Build.
+type EventBuilder struct {
+	event *Event
+	err   error
+}
+
+// NewEventBuilder returns EventBuilder of empty VEVENT
+func NewEventBuilder() *EventBuilder {
+	return &EventBuilder{event: NewEvent()}
+}
+
+func (b *EventBuilder) set(pname property.Name, err error) *EventBuilder {
+	if err != nil && b.err == nil {
+		b.err = buildError(pname, err)
+	}
+	return b
+}
+
+// UID sets UID
+func (b *EventBuilder) UID(uid string) *EventBuilder {
+	return b.set(property.NameUID, b.event.SetUID(parameter.Container{}, types.NewText(uid)))
+}
+
+// Stamp sets DTSTAMP, Build sets current time if it is not set
+func (b *EventBuilder) Stamp(t time.Time) *EventBuilder {
+	return b.set(property.NameDateTimeStamp, b.event.SetDateTimeStamp(parameter.Container{}, types.DateTime(t.UTC().Truncate(time.Second))))
+}
+
+// Start sets DTSTART of DATE-TIME, TZID is name of location of t
+func (b *EventBuilder) Start(t time.Time) *EventBuilder {
+	params, dt, err := dateTimeOf(t)
+	if err != nil {
+		return b.set(property.NameDateTimeStart, err)
+	}
+	return b.set(property.NameDateTimeStart, b.event.SetDateTimeStart(params, dt))
+}
+
+// StartDate sets DTSTART of DATE for all-day event
+func (b *EventBuilder) StartDate(t time.Time) *EventBuilder {
+	params, d := dateOf(t)
+	return b.set(property.NameDateTimeStart, b.event.SetDateTimeStart(params, d))
+}
+
+// End sets DTEND of DATE-TIME, TZID is name of location of t
+func (b *EventBuilder) End(t time.Time) *EventBuilder {
+	params, dt, err := dateTimeOf(t)
+	if err != nil {
+		return b.set(property.NameDateTimeEnd, err)
+	}
+	return b.set(property.NameDateTimeEnd, b.event.SetDateTimeEnd(params, dt))
+}
+
+// EndDate sets DTEND of DATE, it is the day after the last day of all-day event
+func (b *EventBuilder) EndDate(t time.Time) *EventBuilder {
+	params, d := dateOf(t)
+	return b.set(property.NameDateTimeEnd, b.event.SetDateTimeEnd(params, d))
+}
+
+// Duration sets DURATION
+func (b *EventBuilder) Duration(d time.Duration) *EventBuilder {
+	return b.set(property.NameDuration, b.event.SetDuration(parameter.Container{}, durationOf(d)))
+}
+
+// Summary sets SUMMARY
+func (b *EventBuilder) Summary(s string, ps ...Param) *EventBuilder {
+	params, err := newParams(component.TypeEvent, ps)
+	if err != nil {
+		return b.set(property.NameSummary, err)
+	}
+	return b.set(property.NameSummary, b.event.SetSummary(params, types.NewText(s)))
+}
+
+// Description sets DESCRIPTION
+func (b *EventBuilder) Description(s string, ps ...Param) *EventBuilder {
+	params, err := newParams(component.TypeEvent, ps)
+	if err != nil {
+		return b.set(property.NameDescription, err)
+	}
+	return b.set(property.NameDescription, b.event.SetDescription(params, types.NewText(s)))
+}
+
+// Location sets LOCATION
+func (b *EventBuilder) Location(s string, ps ...Param) *EventBuilder {
+	params, err := newParams(component.TypeEvent, ps)
+	if err != nil {
+		return b.set(property.NameLocation, err)
+	}
+	return b.set(property.NameLocation, b.event.SetLocation(params, types.NewText(s)))
+}
+
+// Organizer sets ORGANIZER of uri such as mailto:alice@example.com
+func (b *EventBuilder) Organizer(uri string, ps ...Param) *EventBuilder {
+	params, cua, err := addressOf(component.TypeEvent, uri, ps)
+	if err != nil {
+		return b.set(property.NameOrganizer, err)
+	}
+	return b.set(property.NameOrganizer, b.event.SetOrganizer(params, cua))
+}
+
+// Attendee adds ATTENDEE of uri such as mailto:bob@example.com
+func (b *EventBuilder) Attendee(uri string, ps ...Param) *EventBuilder {
+	params, cua, err := addressOf(component.TypeEvent, uri, ps)
+	if err != nil {
+		return b.set(property.NameAttendee, err)
+	}
+	return b.set(property.NameAttendee, b.event.AddAttendee(params, cua))
+}
+
+// Categories adds CATEGORIES
+func (b *EventBuilder) Categories(categories ...string) *EventBuilder {
+	return b.set(property.NameCategories, b.event.AddCategories(parameter.Container{}, textsOf(categories)))
+}
+
+// Status sets STATUS such as CONFIRMED
+func (b *EventBuilder) Status(s property.StatusType) *EventBuilder {
+	return b.set(property.NameStatus, b.event.SetStatus(parameter.Container{}, types.NewText(string(s))))
+}
+
+// Sequence sets SEQUENCE
+func (b *EventBuilder) Sequence(n int) *EventBuilder {
+	return b.set(property.NameSequenceNumber, b.event.SetSequenceNumber(parameter.Container{}, types.Integer(n)))
+}
+
+// Priority sets PRIORITY
+func (b *EventBuilder) Priority(n int) *EventBuilder {
+	return b.set(property.NamePriority, b.event.SetPriority(parameter.Container{}, types.Integer(n)))
+}
+
+// URL sets URL
+func (b *EventBuilder) URL(s string) *EventBuilder {
+	u, err := types.NewURI(s)
+	if err != nil {
+		return b.set(property.NameURL, err)
+	}
+	return b.set(property.NameURL, b.event.SetURL(parameter.Container{}, u))
+}
+
+// RecurrenceRule sets RRULE such as FREQ=WEEKLY;COUNT=10
+func (b *EventBuilder) RecurrenceRule(rule string) *EventBuilder {
+	rr, err := types.NewRecurrenceRule(rule)
+	if err != nil {
+		return b.set(property.NameRecurrenceRule, err)
+	}
+	return b.set(property.NameRecurrenceRule, b.event.SetRecurrenceRule(parameter.Container{}, rr))
+}
+
+// Alarm adds VALARM
+func (b *EventBuilder) Alarm(a Alarm) *EventBuilder {
+	b.event.AddAlarm(a)
+	return b
+}
+
+// Build returns the event.
+// it returns error of building, or Findings of errors which Check finds in the event.
+func (b *EventBuilder) Build() (*Event, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	if b.event.DateTimeStamp == nil {
+		b.Stamp(time.Now())
+	}
+	ch := &checker{}
+	ch.checkEvent(nil, b.event, false)
+	if errs := ch.findings.Errors(); len(errs) > 0 {
+		return nil, errs
+	}
+	return b.event, nil
+}
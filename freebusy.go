@@ -0,0 +1,238 @@
+package ical
+
+import (
+	"io"
+	"time"
+
+	"github.com/knsh14/ical/component"
+	"github.com/knsh14/ical/errcode"
+	"github.com/knsh14/ical/parameter"
+	"github.com/knsh14/ical/property"
+	"github.com/knsh14/ical/types"
+)
+
+func NewFreeBusy() *FreeBusy {
+	return &FreeBusy{}
+}
+
+// FreeBusy is VFREEBUSY
+// https://tools.ietf.org/html/rfc5545#section-3.6.4
+type FreeBusy struct {
+	// required fields
+	UID           *property.UID
+	DateTimeStamp *property.DateTimeStamp
+
+	Contact       *property.Contact
+	DateTimeStart *property.DateTimeStart
+	DateTimeEnd   *property.DateTimeEnd
+	Organizer     *property.Organizer
+	URL           *property.URL
+
+	Attendees     []*property.Attendee
+	Comments      []*property.Comment
+	FreeBusyTimes []*property.FreeBusyTime
+	RequestStatus []*property.RequestStatus
+
+	XProperties    []*property.NonStandard
+	IANAProperties []*property.IANA
+
+	// PropertyOrder is order of property names in parsed stream.
+	// Decode writes properties in this order if it is set.
+	PropertyOrder []property.Name
+}
+
+func (fb *FreeBusy) implementCalender() {}
+
+func (fb *FreeBusy) Decode(w io.Writer) error {
+	if err := encodeBegin(w, component.TypeFreeBusy); err != nil {
+		return err
+	}
+	pw := newPropertyWriter(w, fb.PropertyOrder)
+	if fb.UID != nil {
+		pw.add(property.NameUID, fb.UID)
+	}
+	if fb.DateTimeStamp != nil {
+		pw.add(property.NameDateTimeStamp, fb.DateTimeStamp)
+	}
+	if fb.Contact != nil {
+		pw.add(property.NameContact, fb.Contact)
+	}
+	if fb.DateTimeStart != nil {
+		pw.add(property.NameDateTimeStart, fb.DateTimeStart)
+	}
+	if fb.DateTimeEnd != nil {
+		pw.add(property.NameDateTimeEnd, fb.DateTimeEnd)
+	}
+	if fb.Organizer != nil {
+		pw.add(property.NameOrganizer, fb.Organizer)
+	}
+	if fb.URL != nil {
+		pw.add(property.NameURL, fb.URL)
+	}
+	for _, v := range fb.Attendees {
+		pw.add(property.NameAttendee, v)
+	}
+	for _, v := range fb.Comments {
+		pw.add(property.NameComment, v)
+	}
+	for _, v := range fb.FreeBusyTimes {
+		pw.add(property.NameFreeBusyTime, v)
+	}
+	for _, v := range fb.RequestStatus {
+		pw.add(property.NameRequestStatus, v)
+	}
+	for _, v := range fb.XProperties {
+		pw.add(property.Name(v.Name), v)
+	}
+	for _, v := range fb.IANAProperties {
+		pw.add(property.Name(v.Name), v)
+	}
+	if err := pw.flush(); err != nil {
+		return err
+	}
+	return encodeEnd(w, component.TypeFreeBusy)
+}
+
+func (fb *FreeBusy) Validate() error {
+	if fb.UID == nil {
+		return NewValidationError(component.TypeFreeBusy, property.NameUID, "must not to be nil")
+	}
+	if fb.UID.Value == "" {
+		return NewValidationError(component.TypeFreeBusy, property.NameUID, "must not to be empty")
+	}
+	if fb.DateTimeStamp == nil {
+		return NewValidationError(component.TypeFreeBusy, property.NameDateTimeStamp, "must not to be nil")
+	}
+	if fb.DateTimeStart != nil && fb.DateTimeEnd != nil && !timeOf(fb.DateTimeStart.Value).Before(timeOf(fb.DateTimeEnd.Value)) {
+		return NewValidationError(component.TypeFreeBusy, property.NameDateTimeEnd, "must be later than DTSTART")
+	}
+	return nil
+}
+
+func (fb *FreeBusy) SetUID(params parameter.Container, value types.Text) error {
+	if fb.UID != nil {
+		return fb.UID.SetUID(params, value)
+	}
+	uid := &property.UID{}
+	if err := uid.SetUID(params, value); err != nil {
+		return err
+	}
+	fb.UID = uid
+	return nil
+}
+
+func (fb *FreeBusy) SetDateTimeStamp(params parameter.Container, value types.DateTime) error {
+	if fb.DateTimeStamp != nil {
+		return fb.DateTimeStamp.SetDateTimeStamp(params, value)
+	}
+	dts := &property.DateTimeStamp{}
+	if err := dts.SetDateTimeStamp(params, value); err != nil {
+		return err
+	}
+	fb.DateTimeStamp = dts
+	return nil
+}
+
+func (fb *FreeBusy) SetContact(params parameter.Container, value types.Text) error {
+	if fb.Contact != nil {
+		return fb.Contact.SetContact(params, value)
+	}
+	c := &property.Contact{}
+	if err := c.SetContact(params, value); err != nil {
+		return err
+	}
+	fb.Contact = c
+	return nil
+}
+
+// SetDateTimeStart sets DTSTART. value must be UTC time.
+func (fb *FreeBusy) SetDateTimeStart(params parameter.Container, value types.DateTime) error {
+	if time.Time(value).Location() != time.UTC {
+		return errcode.Errorf(errcode.ValueFormat, "%s must be UTC time, but %v", property.NameDateTimeStart, value)
+	}
+	if fb.DateTimeStart != nil {
+		return fb.DateTimeStart.SetStart(params, value)
+	}
+	dts := &property.DateTimeStart{}
+	if err := dts.SetStart(params, value); err != nil {
+		return err
+	}
+	fb.DateTimeStart = dts
+	return nil
+}
+
+// SetDateTimeEnd sets DTEND. value must be UTC time.
+func (fb *FreeBusy) SetDateTimeEnd(params parameter.Container, value types.DateTime) error {
+	if time.Time(value).Location() != time.UTC {
+		return errcode.Errorf(errcode.ValueFormat, "%s must be UTC time, but %v", property.NameDateTimeEnd, value)
+	}
+	if fb.DateTimeEnd != nil {
+		return fb.DateTimeEnd.SetEnd(params, value)
+	}
+	dte := &property.DateTimeEnd{}
+	if err := dte.SetEnd(params, value); err != nil {
+		return err
+	}
+	fb.DateTimeEnd = dte
+	return nil
+}
+
+func (fb *FreeBusy) SetOrganizer(params parameter.Container, value types.CalenderUserAddress) error {
+	if fb.Organizer != nil {
+		return fb.Organizer.SetOrganizer(params, value)
+	}
+	o := &property.Organizer{}
+	if err := o.SetOrganizer(params, value); err != nil {
+		return err
+	}
+	fb.Organizer = o
+	return nil
+}
+
+func (fb *FreeBusy) SetURL(params parameter.Container, value types.URI) error {
+	if fb.URL != nil {
+		return fb.URL.SetURL(params, value)
+	}
+	url := &property.URL{}
+	if err := url.SetURL(params, value); err != nil {
+		return err
+	}
+	fb.URL = url
+	return nil
+}
+
+func (fb *FreeBusy) AddAttendee(params parameter.Container, value types.CalenderUserAddress) error {
+	a := &property.Attendee{}
+	if err := a.SetAttendee(params, value); err != nil {
+		return err
+	}
+	fb.Attendees = append(fb.Attendees, a)
+	return nil
+}
+
+func (fb *FreeBusy) AddComment(params parameter.Container, value types.Text) error {
+	c := &property.Comment{}
+	if err := c.SetComment(params, value); err != nil {
+		return err
+	}
+	fb.Comments = append(fb.Comments, c)
+	return nil
+}
+
+func (fb *FreeBusy) AddFreeBusyTime(params parameter.Container, values []types.Period) error {
+	fbt := &property.FreeBusyTime{}
+	if err := fbt.SetFreeBusyTime(params, values); err != nil {
+		return err
+	}
+	fb.FreeBusyTimes = append(fb.FreeBusyTimes, fbt)
+	return nil
+}
+
+func (fb *FreeBusy) AddRequestStatus(params parameter.Container, value types.Text) error {
+	rs := &property.RequestStatus{}
+	if err := rs.SetRequestStatus(params, value); err != nil {
+		return err
+	}
+	fb.RequestStatus = append(fb.RequestStatus, rs)
+	return nil
+}
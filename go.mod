@@ -6,7 +6,6 @@ require (
 	github.com/Masterminds/semver/v3 v3.1.0
 	github.com/google/go-cmp v0.5.1
 	github.com/morikuni/failure v0.13.0
-	golang.org/x/sync v0.0.0-20200625203802-6e8e738ad208
 	golang.org/x/text v0.3.3
 	golang.org/x/xerrors v0.0.0-20200804184101-5ec99f83aff1 // indirect
 )
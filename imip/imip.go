@@ -0,0 +1,185 @@
+package imip
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"github.com/knsh14/ical"
+	"github.com/knsh14/ical/parser"
+)
+
+// ErrNoCalendar is returned when message does not have text/calendar part
+var ErrNoCalendar = fmt.Errorf("text/calendar part is not found")
+
+// now is replaced in tests
+var now = time.Now
+
+// Header is header and text body of iMIP message
+type Header struct {
+	From    *mail.Address
+	To      []*mail.Address
+	Subject string
+	// Text is body of text/plain part which is shown by clients which do not support iCalendar
+	Text string
+}
+
+// Encode writes RFC 5322 message which has iTIP calendar c as multipart/alternative.
+// Content-Type of the calendar part is text/calendar with method of c and charset UTF-8.
+// https://tools.ietf.org/html/rfc6047#section-2.4
+func Encode(w io.Writer, h Header, c *ical.Calendar) error {
+	if c.Method == nil {
+		return fmt.Errorf("calendar must have METHOD")
+	}
+	b := &bytes.Buffer{}
+	if err := ical.NewEncoder(b).Encode(c); err != nil {
+		return fmt.Errorf("encode calendar: %w", err)
+	}
+
+	bw := bufio.NewWriter(w)
+	mw := multipart.NewWriter(bw)
+	var header [][2]string
+	if h.From != nil {
+		header = append(header, [2]string{"From", h.From.String()})
+	}
+	if len(h.To) > 0 {
+		to := make([]string, 0, len(h.To))
+		for _, a := range h.To {
+			to = append(to, a.String())
+		}
+		header = append(header, [2]string{"To", strings.Join(to, ", ")})
+	}
+	header = append(header,
+		[2]string{"Subject", mime.QEncoding.Encode("utf-8", h.Subject)},
+		[2]string{"Date", now().Format(time.RFC1123Z)},
+		[2]string{"MIME-Version", "1.0"},
+		[2]string{"Content-Type", mime.FormatMediaType("multipart/alternative", map[string]string{"boundary": mw.Boundary()})},
+	)
+	for _, f := range header {
+		if _, err := fmt.Fprintf(bw, "%s: %s\r\n", f[0], f[1]); err != nil {
+			return err
+		}
+	}
+	if _, err := io.WriteString(bw, "\r\n"); err != nil {
+		return err
+	}
+
+	if err := writePart(mw, "text/plain", map[string]string{"charset": "UTF-8"}, []byte(h.Text)); err != nil {
+		return err
+	}
+	params := map[string]string{
+		"method":  string(c.Method.Value),
+		"charset": "UTF-8",
+	}
+	if err := writePart(mw, "text/calendar", params, b.Bytes()); err != nil {
+		return err
+	}
+	if err := mw.Close(); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// writePart writes body as quoted-printable part of mediaType
+func writePart(mw *multipart.Writer, mediaType string, params map[string]string, body []byte) error {
+	pw, err := mw.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {mime.FormatMediaType(mediaType, params)},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return fmt.Errorf("create %s part: %w", mediaType, err)
+	}
+	qw := quotedprintable.NewWriter(pw)
+	if _, err := qw.Write(body); err != nil {
+		return fmt.Errorf("write %s part: %w", mediaType, err)
+	}
+	return qw.Close()
+}
+
+// Decode reads RFC 5322 message from r and parses its first text/calendar part by parser.Parse.
+// METHOD of the calendar must be the same as method parameter of Content-Type.
+// https://tools.ietf.org/html/rfc6047#section-2.4
+func Decode(r io.Reader, opts ...parser.Option) (*ical.Calendar, error) {
+	msg, err := mail.ReadMessage(r)
+	if err != nil {
+		return nil, fmt.Errorf("read message: %w", err)
+	}
+	body, params, err := findCalendar(textproto.MIMEHeader(msg.Header), msg.Body)
+	if err != nil {
+		return nil, err
+	}
+	c, err := parser.Parse(body, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("parse text/calendar: %w", err)
+	}
+	if method, ok := params["method"]; ok {
+		if c.Method == nil || !strings.EqualFold(string(c.Method.Value), method) {
+			return nil, fmt.Errorf("METHOD of calendar is not %s in Content-Type", method)
+		}
+	}
+	return c, nil
+}
+
+// findCalendar returns decoded body of the first text/calendar part and its Content-Type parameters
+func findCalendar(header textproto.MIMEHeader, body io.Reader) (io.Reader, map[string]string, error) {
+	contentType := header.Get("Content-Type")
+	if contentType == "" {
+		contentType = "text/plain"
+	}
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parse Content-Type %q: %w", contentType, err)
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		mr := multipart.NewReader(body, params["boundary"])
+		for {
+			p, err := mr.NextPart()
+			if err == io.EOF {
+				return nil, nil, ErrNoCalendar
+			}
+			if err != nil {
+				return nil, nil, fmt.Errorf("read %s: %w", mediaType, err)
+			}
+			b, ps, err := findCalendar(p.Header, p)
+			if err == ErrNoCalendar {
+				continue
+			}
+			return b, ps, err
+		}
+	}
+	if mediaType != "text/calendar" {
+		return nil, nil, ErrNoCalendar
+	}
+	if cs, ok := params["charset"]; ok && !strings.EqualFold(cs, "UTF-8") && !strings.EqualFold(cs, "US-ASCII") {
+		return nil, nil, fmt.Errorf("charset %s is not supported", cs)
+	}
+
+	var decoded io.Reader
+	switch cte := strings.ToLower(header.Get("Content-Transfer-Encoding")); cte {
+	case "", "7bit", "8bit", "binary":
+		decoded = body
+	case "quoted-printable":
+		decoded = quotedprintable.NewReader(body)
+	case "base64":
+		decoded = base64.NewDecoder(base64.StdEncoding, body)
+	default:
+		return nil, nil, fmt.Errorf("Content-Transfer-Encoding %s is not supported", cte)
+	}
+	// read whole part before next part of multipart is read
+	b, err := ioutil.ReadAll(decoded)
+	if err != nil {
+		return nil, nil, fmt.Errorf("decode text/calendar: %w", err)
+	}
+	return bytes.NewReader(b), params, nil
+}
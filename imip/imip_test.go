@@ -0,0 +1,194 @@
+package imip
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"mime"
+	"net/mail"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/knsh14/ical"
+	"github.com/knsh14/ical/parameter"
+	"github.com/knsh14/ical/types"
+)
+
+func TestMain(m *testing.M) {
+	now = func() time.Time { return time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC) }
+	os.Exit(m.Run())
+}
+
+func newTestCalendar(t *testing.T) *ical.Calendar {
+	t.Helper()
+	c := ical.NewCalendar()
+	mustNoError(t, c.SetProdID(parameter.Container{}, types.NewText("-//knsh14//ical//EN")))
+	mustNoError(t, c.SetMethod(parameter.Container{}, types.NewText("REQUEST")))
+	e := ical.NewEvent()
+	mustNoError(t, e.SetUID(parameter.Container{}, types.NewText("meeting@example.com")))
+	mustNoError(t, e.SetDateTimeStamp(parameter.Container{}, types.DateTime(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC))))
+	mustNoError(t, e.SetDateTimeStart(parameter.Container{}, types.DateTime(time.Date(2020, 1, 10, 10, 0, 0, 0, time.UTC))))
+	mustNoError(t, e.SetSummary(parameter.Container{}, types.NewText("会議室で週次ミーティング、議題は来期の計画と予算について")))
+	c.Components = append(c.Components, e)
+	return c
+}
+
+func TestEncode(t *testing.T) {
+	t.Parallel()
+	c := newTestCalendar(t)
+	b := &bytes.Buffer{}
+	h := Header{
+		From:    &mail.Address{Name: "Alice", Address: "alice@example.com"},
+		To:      []*mail.Address{{Address: "bob@example.com"}},
+		Subject: "Invitation: 週次ミーティング",
+		Text:    "You are invited.",
+	}
+	if err := Encode(b, h, c); err != nil {
+		t.Fatal(err)
+	}
+
+	msg, err := mail.ReadMessage(bytes.NewReader(b.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	mustNoError(t, err)
+	if subject != h.Subject {
+		t.Errorf("expected subject %q, but got %q", h.Subject, subject)
+	}
+	if !strings.HasPrefix(msg.Header.Get("Content-Type"), "multipart/alternative;") {
+		t.Errorf("Content-Type is %s", msg.Header.Get("Content-Type"))
+	}
+	if !bytes.Contains(b.Bytes(), []byte("Content-Type: text/calendar; charset=UTF-8; method=REQUEST\r\n")) {
+		t.Errorf("text/calendar part is not found in\n%s", b.String())
+	}
+
+	got, err := Decode(bytes.NewReader(b.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(encode(t, c), encode(t, got)); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	t.Parallel()
+	cal := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"PRODID:-//knsh14//ical//EN",
+		"VERSION:2.0",
+		"METHOD:REPLY",
+		"BEGIN:VEVENT",
+		"UID:meeting@example.com",
+		"DTSTAMP:20200102T030405Z",
+		"ORGANIZER:mailto:alice@example.com",
+		"ATTENDEE;PARTSTAT=ACCEPTED:mailto:bob@example.com",
+		"END:VEVENT",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n")
+
+	testcases := map[string]struct {
+		input  string
+		expect error
+	}{
+		"single part": {
+			input: strings.Join([]string{
+				"From: bob@example.com",
+				"Content-Type: text/calendar; method=REPLY; charset=UTF-8",
+				"",
+				cal,
+			}, "\r\n"),
+		},
+		"base64 in nested multipart": {
+			input: strings.Join([]string{
+				"From: bob@example.com",
+				"MIME-Version: 1.0",
+				`Content-Type: multipart/mixed; boundary="outer"`,
+				"",
+				"--outer",
+				`Content-Type: multipart/alternative; boundary="inner"`,
+				"",
+				"--inner",
+				"Content-Type: text/plain",
+				"",
+				"Bob accepted.",
+				"--inner",
+				`Content-Type: text/calendar; method="REPLY"; charset="utf-8"`,
+				"Content-Transfer-Encoding: base64",
+				"",
+				wrap(base64.StdEncoding.EncodeToString([]byte(cal)), 76),
+				"--inner--",
+				"--outer--",
+				"",
+			}, "\r\n"),
+		},
+		"no calendar": {
+			input: strings.Join([]string{
+				"From: bob@example.com",
+				"Content-Type: text/plain",
+				"",
+				"hello",
+			}, "\r\n"),
+			expect: ErrNoCalendar,
+		},
+	}
+
+	for title, tt := range testcases {
+		tt := tt
+		t.Run(title, func(t *testing.T) {
+			t.Parallel()
+			c, err := Decode(strings.NewReader(tt.input))
+			if tt.expect != nil {
+				if !errors.Is(err, tt.expect) {
+					t.Fatalf("expected %v, but got %v", tt.expect, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(cal, encode(t, c)); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDecode_MethodMismatch(t *testing.T) {
+	t.Parallel()
+	b := &bytes.Buffer{}
+	mustNoError(t, Encode(b, Header{}, newTestCalendar(t)))
+	input := strings.Replace(b.String(), "method=REQUEST", "method=CANCEL", 1)
+	if _, err := Decode(strings.NewReader(input)); err == nil {
+		t.Fatal("expected error but got nil")
+	}
+}
+
+// wrap splits s into lines of n characters
+func wrap(s string, n int) string {
+	var lines []string
+	for len(s) > n {
+		lines = append(lines, s[:n])
+		s = s[n:]
+	}
+	return strings.Join(append(lines, s), "\r\n")
+}
+
+func encode(t *testing.T, c *ical.Calendar) string {
+	t.Helper()
+	b := &bytes.Buffer{}
+	mustNoError(t, ical.NewEncoder(b).Encode(c))
+	return b.String()
+}
+
+func mustNoError(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,197 @@
+package itip
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/knsh14/ical"
+	"github.com/knsh14/ical/component"
+	"github.com/knsh14/ical/parameter"
+	"github.com/knsh14/ical/property"
+	"github.com/knsh14/ical/types"
+)
+
+// ProdID is PRODID of calendars which this package creates
+var ProdID = types.NewText("-//knsh14//ical//EN")
+
+// now is replaced in tests
+var now = time.Now
+
+// Publish returns PUBLISH calendar of VEVENT or VTODO c.
+// SEQUENCE is incremented.
+// https://tools.ietf.org/html/rfc5546#section-3.2.1
+func Publish(c ical.CalenderComponent) (*ical.Calendar, error) {
+	return build(property.MethodTypePublish, c, func(m *message) error {
+		m.increment()
+		return nil
+	})
+}
+
+// Request returns REQUEST calendar of VEVENT or VTODO c which is sent by organizer.
+// SEQUENCE is incremented.
+// https://tools.ietf.org/html/rfc5546#section-3.2.2
+func Request(c ical.CalenderComponent, organizer types.CalenderUserAddress) (*ical.Calendar, error) {
+	return build(property.MethodTypeRequest, c, func(m *message) error {
+		if m.organizer == nil || !SameAddress(m.organizer.Value, organizer) {
+			m.organizer = &property.Organizer{Parameter: parameter.Container{}, Value: organizer}
+		}
+		if len(m.attendees) == 0 {
+			return fmt.Errorf("%s must have ATTENDEE", property.MethodTypeRequest)
+		}
+		m.increment()
+		return nil
+	})
+}
+
+// Reply returns REPLY calendar of VEVENT or VTODO c which is sent by attendee.
+// ATTENDEE is only attendee whose PARTSTAT is partstat.
+// https://tools.ietf.org/html/rfc5546#section-3.2.3
+func Reply(c ical.CalenderComponent, attendee types.CalenderUserAddress, partstat parameter.ParticipationStatusType) (*ical.Calendar, error) {
+	return build(property.MethodTypeReply, c, func(m *message) error {
+		ps, err := parameter.NewParticipationStatus(string(partstat), m.kind)
+		if err != nil {
+			return fmt.Errorf("participation status: %w", err)
+		}
+		a := m.attendee(attendee)
+		params := parameter.Container{}
+		for k, v := range a.Parameter {
+			params[k] = v
+		}
+		delete(params, parameter.TypeNameRSVP)
+		params[parameter.TypeNameParticipationStatus] = []parameter.Base{ps}
+		m.attendees = []*property.Attendee{{Parameter: params, Value: a.Value}}
+		return nil
+	})
+}
+
+// Cancel returns CANCEL calendar of VEVENT or VTODO c.
+// STATUS is CANCELLED and SEQUENCE is incremented.
+// https://tools.ietf.org/html/rfc5546#section-3.2.5
+func Cancel(c ical.CalenderComponent) (*ical.Calendar, error) {
+	return build(property.MethodTypeCancel, c, func(m *message) error {
+		m.status = &property.Status{Parameter: parameter.Container{}, Value: property.StatusTypeCancelled}
+		m.increment()
+		return nil
+	})
+}
+
+// Refresh returns REFRESH calendar of VEVENT or VTODO c which is sent by attendee.
+// https://tools.ietf.org/html/rfc5546#section-3.2.6
+func Refresh(c ical.CalenderComponent, attendee types.CalenderUserAddress) (*ical.Calendar, error) {
+	return build(property.MethodTypeRefresh, c, func(m *message) error {
+		m.attendees = []*property.Attendee{m.attendee(attendee)}
+		return nil
+	})
+}
+
+// Counter returns COUNTER calendar of VEVENT or VTODO c which has changes proposed by attendee.
+// https://tools.ietf.org/html/rfc5546#section-3.2.7
+func Counter(c ical.CalenderComponent, attendee types.CalenderUserAddress) (*ical.Calendar, error) {
+	return build(property.MethodTypeCounter, c, func(m *message) error {
+		for _, a := range m.attendees {
+			if SameAddress(a.Value, attendee) {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s is not attendee", attendee)
+	})
+}
+
+// DeclineCounter returns DECLINECOUNTER calendar of VEVENT or VTODO c which is sent to attendee.
+// https://tools.ietf.org/html/rfc5546#section-3.2.8
+func DeclineCounter(c ical.CalenderComponent, attendee types.CalenderUserAddress) (*ical.Calendar, error) {
+	return build(property.MethodTypeDeclinecounter, c, func(m *message) error {
+		m.attendees = []*property.Attendee{m.attendee(attendee)}
+		return nil
+	})
+}
+
+// SameAddress returns true if a and b is the same calendar user.
+// scheme and address are compared case-insensitively, such as mailto:A@example.com and MAILTO:a@example.com.
+func SameAddress(a, b types.CalenderUserAddress) bool {
+	return strings.EqualFold(a.String(), b.String())
+}
+
+// message is properties of VEVENT or VTODO which builders change
+type message struct {
+	kind      component.Type
+	uid       *property.UID
+	stamp     *property.DateTimeStamp
+	sequence  *property.SequenceNumber
+	organizer *property.Organizer
+	attendees []*property.Attendee
+	status    *property.Status
+}
+
+// increment increments SEQUENCE, which is 0 if it is not set
+func (m *message) increment() {
+	seq := types.Integer(1)
+	if m.sequence != nil {
+		seq = m.sequence.Value + 1
+	}
+	m.sequence = &property.SequenceNumber{Parameter: parameter.Container{}, Value: seq}
+}
+
+// attendee returns ATTENDEE of address, new one is returned if it is not found
+func (m *message) attendee(address types.CalenderUserAddress) *property.Attendee {
+	for _, a := range m.attendees {
+		if SameAddress(a.Value, address) {
+			return a
+		}
+	}
+	return &property.Attendee{Parameter: parameter.Container{}, Value: address}
+}
+
+// build returns calendar of method which has copy of c.
+// c is not modified, properties which builders change are replaced with new ones.
+func build(method property.MethodType, c ical.CalenderComponent, edit func(*message) error) (*ical.Calendar, error) {
+	p := ical.Profile(method)
+	m := &message{}
+	var (
+		res   ical.CalenderComponent
+		apply func()
+	)
+	switch v := c.(type) {
+	case *ical.Event:
+		e := filterEvent(v, p)
+		m.kind, m.uid = component.TypeEvent, e.UID
+		m.sequence, m.organizer, m.attendees, m.status = e.SequenceNumber, e.Organizer, e.Attendees, e.Status
+		res = e
+		apply = func() {
+			e.DateTimeStamp, e.SequenceNumber, e.Organizer, e.Attendees, e.Status = m.stamp, m.sequence, m.organizer, m.attendees, m.status
+		}
+	case *ical.ToDo:
+		todo := filterToDo(v, p)
+		m.kind, m.uid = component.TypeTODO, todo.UID
+		m.sequence, m.organizer, m.attendees, m.status = todo.SequenceNumber, todo.Organizer, todo.Attendees, todo.Status
+		res = todo
+		apply = func() {
+			todo.DateTimeStamp, todo.SequenceNumber, todo.Organizer, todo.Attendees, todo.Status = m.stamp, m.sequence, m.organizer, m.attendees, m.status
+		}
+	default:
+		return nil, fmt.Errorf("%T is not VEVENT or VTODO", c)
+	}
+	if m.uid == nil {
+		return nil, fmt.Errorf("%s must have UID", method)
+	}
+
+	if err := edit(m); err != nil {
+		return nil, err
+	}
+	if m.organizer == nil {
+		return nil, fmt.Errorf("%s must have ORGANIZER", method)
+	}
+	m.stamp = &property.DateTimeStamp{Parameter: parameter.Container{}, Value: types.DateTime(now().UTC().Truncate(time.Second))}
+	apply()
+
+	cal := ical.NewCalendar()
+	if err := cal.SetProdID(parameter.Container{}, ProdID); err != nil {
+		return nil, err
+	}
+	if err := cal.SetMethod(parameter.Container{}, types.Text(method)); err != nil {
+		return nil, err
+	}
+	cal.Components = append(cal.Components, res)
+	return cal, nil
+}
@@ -0,0 +1,275 @@
+package itip
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/knsh14/ical"
+	"github.com/knsh14/ical/parameter"
+	"github.com/knsh14/ical/types"
+)
+
+func TestMain(m *testing.M) {
+	now = func() time.Time { return time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC) }
+	os.Exit(m.Run())
+}
+
+func TestBuilders(t *testing.T) {
+	t.Parallel()
+	organizer := mustAddress(t, "mailto:alice@example.com")
+	bob := mustAddress(t, "mailto:bob@example.com")
+	carol := mustAddress(t, "mailto:carol@example.com")
+
+	newEvent := func(t *testing.T) *ical.Event {
+		t.Helper()
+		e := ical.NewEvent()
+		mustNoError(t, e.SetUID(parameter.Container{}, types.NewText("meeting@example.com")))
+		mustNoError(t, e.SetDateTimeStamp(parameter.Container{}, types.DateTime(time.Date(2019, 12, 1, 0, 0, 0, 0, time.UTC))))
+		mustNoError(t, e.SetDateTimeStart(parameter.Container{}, types.DateTime(time.Date(2020, 1, 10, 10, 0, 0, 0, time.UTC))))
+		mustNoError(t, e.SetDateTimeEnd(parameter.Container{}, types.DateTime(time.Date(2020, 1, 10, 11, 0, 0, 0, time.UTC))))
+		mustNoError(t, e.SetSummary(parameter.Container{}, types.NewText("Planning")))
+		mustNoError(t, e.SetOrganizer(parameter.Container{}, organizer))
+		mustNoError(t, e.AddContact(parameter.Container{}, types.NewText("Alice")))
+		for _, a := range []types.CalenderUserAddress{bob, carol} {
+			rsvp, err := parameter.NewRSVP("TRUE")
+			mustNoError(t, err)
+			mustNoError(t, e.AddAttendee(parameter.Container{
+				parameter.TypeNameRSVP: []parameter.Base{rsvp},
+			}, a))
+		}
+		a := ical.NewAlarmAudio()
+		mustNoError(t, a.SetTrigger(parameter.Container{}, types.Duration{Direction: "-", HourDuration: 15 * time.Minute}))
+		e.AddAlarm(a)
+		return e
+	}
+
+	testcases := map[string]struct {
+		build  func(*testing.T) (*ical.Calendar, error)
+		expect []string
+	}{
+		"request": {
+			build: func(t *testing.T) (*ical.Calendar, error) {
+				e := newEvent(t)
+				mustNoError(t, e.SetSequenceNumber(parameter.Container{}, 2))
+				return Request(e, organizer)
+			},
+			expect: []string{
+				"BEGIN:VCALENDAR",
+				"PRODID:-//knsh14//ical//EN",
+				"VERSION:2.0",
+				"METHOD:REQUEST",
+				"BEGIN:VEVENT",
+				"UID:meeting@example.com",
+				"DTSTAMP:20200102T030405Z",
+				"DTSTART:20200110T100000Z",
+				"ORGANIZER:mailto:alice@example.com",
+				"SEQUENCE:3",
+				"SUMMARY:Planning",
+				"DTEND:20200110T110000Z",
+				"ATTENDEE;RSVP=TRUE:mailto:bob@example.com",
+				"ATTENDEE;RSVP=TRUE:mailto:carol@example.com",
+				"CONTACT:Alice",
+				"BEGIN:VALARM",
+				"ACTION:AUDIO",
+				"TRIGGER:-PT15M",
+				"END:VALARM",
+				"END:VEVENT",
+				"END:VCALENDAR",
+			},
+		},
+		"reply": {
+			build: func(t *testing.T) (*ical.Calendar, error) {
+				return Reply(newEvent(t), mustAddress(t, "mailto:BOB@example.com"), parameter.ParticipationStatusTypeAccepted)
+			},
+			expect: []string{
+				"BEGIN:VCALENDAR",
+				"PRODID:-//knsh14//ical//EN",
+				"VERSION:2.0",
+				"METHOD:REPLY",
+				"BEGIN:VEVENT",
+				"UID:meeting@example.com",
+				"DTSTAMP:20200102T030405Z",
+				"DTSTART:20200110T100000Z",
+				"ORGANIZER:mailto:alice@example.com",
+				"SUMMARY:Planning",
+				"DTEND:20200110T110000Z",
+				"ATTENDEE;PARTSTAT=ACCEPTED:mailto:bob@example.com",
+				"END:VEVENT",
+				"END:VCALENDAR",
+			},
+		},
+		"cancel": {
+			build: func(t *testing.T) (*ical.Calendar, error) {
+				return Cancel(newEvent(t))
+			},
+			expect: []string{
+				"BEGIN:VCALENDAR",
+				"PRODID:-//knsh14//ical//EN",
+				"VERSION:2.0",
+				"METHOD:CANCEL",
+				"BEGIN:VEVENT",
+				"UID:meeting@example.com",
+				"DTSTAMP:20200102T030405Z",
+				"DTSTART:20200110T100000Z",
+				"ORGANIZER:mailto:alice@example.com",
+				"SEQUENCE:1",
+				"STATUS:CANCELLED",
+				"SUMMARY:Planning",
+				"DTEND:20200110T110000Z",
+				"ATTENDEE;RSVP=TRUE:mailto:bob@example.com",
+				"ATTENDEE;RSVP=TRUE:mailto:carol@example.com",
+				"CONTACT:Alice",
+				"END:VEVENT",
+				"END:VCALENDAR",
+			},
+		},
+		"refresh": {
+			build: func(t *testing.T) (*ical.Calendar, error) {
+				return Refresh(newEvent(t), carol)
+			},
+			expect: []string{
+				"BEGIN:VCALENDAR",
+				"PRODID:-//knsh14//ical//EN",
+				"VERSION:2.0",
+				"METHOD:REFRESH",
+				"BEGIN:VEVENT",
+				"UID:meeting@example.com",
+				"DTSTAMP:20200102T030405Z",
+				"ORGANIZER:mailto:alice@example.com",
+				"ATTENDEE;RSVP=TRUE:mailto:carol@example.com",
+				"END:VEVENT",
+				"END:VCALENDAR",
+			},
+		},
+		"publish todo": {
+			build: func(t *testing.T) (*ical.Calendar, error) {
+				todo := ical.NewToDo()
+				mustNoError(t, todo.SetUID(parameter.Container{}, types.NewText("todo@example.com")))
+				mustNoError(t, todo.SetOrganizer(parameter.Container{}, organizer))
+				mustNoError(t, todo.SetSummary(parameter.Container{}, types.NewText("Write minutes")))
+				mustNoError(t, todo.AddAttendee(parameter.Container{}, bob))
+				return Publish(todo)
+			},
+			expect: []string{
+				"BEGIN:VCALENDAR",
+				"PRODID:-//knsh14//ical//EN",
+				"VERSION:2.0",
+				"METHOD:PUBLISH",
+				"BEGIN:VTODO",
+				"UID:todo@example.com",
+				"DTSTAMP:20200102T030405Z",
+				"ORGANIZER:mailto:alice@example.com",
+				"SEQUENCE:1",
+				"SUMMARY:Write minutes",
+				"END:VTODO",
+				"END:VCALENDAR",
+			},
+		},
+	}
+
+	for title, tt := range testcases {
+		tt := tt
+		t.Run(title, func(t *testing.T) {
+			t.Parallel()
+			c, err := tt.build(t)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b := &bytes.Buffer{}
+			if err := ical.NewEncoder(b).Encode(c); err != nil {
+				t.Fatal(err)
+			}
+			got := strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n")
+			if diff := cmp.Diff(tt.expect, got); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestBuilders_Error(t *testing.T) {
+	t.Parallel()
+	organizer := mustAddress(t, "mailto:alice@example.com")
+	bob := mustAddress(t, "mailto:bob@example.com")
+
+	newEvent := func(t *testing.T) *ical.Event {
+		t.Helper()
+		e := ical.NewEvent()
+		mustNoError(t, e.SetUID(parameter.Container{}, types.NewText("meeting@example.com")))
+		return e
+	}
+
+	testcases := map[string]func(*testing.T) (*ical.Calendar, error){
+		"request without attendee": func(t *testing.T) (*ical.Calendar, error) {
+			return Request(newEvent(t), organizer)
+		},
+		"cancel without organizer": func(t *testing.T) (*ical.Calendar, error) {
+			return Cancel(newEvent(t))
+		},
+		"counter by not attendee": func(t *testing.T) (*ical.Calendar, error) {
+			e := newEvent(t)
+			mustNoError(t, e.SetOrganizer(parameter.Container{}, organizer))
+			return Counter(e, bob)
+		},
+		"reply with status for todo": func(t *testing.T) (*ical.Calendar, error) {
+			e := newEvent(t)
+			mustNoError(t, e.SetOrganizer(parameter.Container{}, organizer))
+			return Reply(e, bob, parameter.ParticipationStatusTypeCompleted)
+		},
+		"without uid": func(t *testing.T) (*ical.Calendar, error) {
+			e := ical.NewEvent()
+			mustNoError(t, e.SetOrganizer(parameter.Container{}, organizer))
+			return Publish(e)
+		},
+		"journal": func(t *testing.T) (*ical.Calendar, error) {
+			return Publish(ical.NewJournal())
+		},
+	}
+
+	for title, build := range testcases {
+		build := build
+		t.Run(title, func(t *testing.T) {
+			t.Parallel()
+			if _, err := build(t); err == nil {
+				t.Fatal("expected error but got nil")
+			}
+		})
+	}
+}
+
+func TestBuilders_NotModifyInput(t *testing.T) {
+	t.Parallel()
+	e := ical.NewEvent()
+	mustNoError(t, e.SetUID(parameter.Container{}, types.NewText("meeting@example.com")))
+	mustNoError(t, e.SetOrganizer(parameter.Container{}, mustAddress(t, "mailto:alice@example.com")))
+	mustNoError(t, e.SetSequenceNumber(parameter.Container{}, 1))
+	mustNoError(t, e.AddAttendee(parameter.Container{}, mustAddress(t, "mailto:bob@example.com")))
+
+	if _, err := Cancel(e); err != nil {
+		t.Fatal(err)
+	}
+	if e.SequenceNumber.Value != 1 {
+		t.Errorf("SEQUENCE of input is changed to %d", e.SequenceNumber.Value)
+	}
+	if e.Status != nil || e.DateTimeStamp != nil {
+		t.Errorf("input is changed, STATUS %v, DTSTAMP %v", e.Status, e.DateTimeStamp)
+	}
+}
+
+func mustAddress(t *testing.T, v string) types.CalenderUserAddress {
+	t.Helper()
+	a, err := types.NewCalenderUserAddress(v)
+	mustNoError(t, err)
+	return a
+}
+
+func mustNoError(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,433 @@
+package itip
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/knsh14/ical"
+	"github.com/knsh14/ical/component"
+	"github.com/knsh14/ical/parameter"
+	"github.com/knsh14/ical/property"
+	"github.com/knsh14/ical/types"
+)
+
+var (
+	// ErrStale is returned when message is older than the stored component
+	ErrStale = fmt.Errorf("stale message")
+	// ErrNotFound is returned when the stored calendar does not have component of the message
+	ErrNotFound = fmt.Errorf("component not found")
+)
+
+// ReplyStamps is DTSTAMP of the last REPLY from each attendee, which Process uses to find stale REPLY.
+// it is not a part of iCalendar data, so the caller keeps it with the stored calendar.
+type ReplyStamps map[ReplyKey]time.Time
+
+// ReplyKey is an attendee of VEVENT or VTODO, or of its instance
+type ReplyKey struct {
+	UID string
+	// RecurrenceID is RECURRENCE-ID in UTC such as 20200113T100000Z, it is empty for all instances
+	RecurrenceID string
+	// Attendee is address of the attendee in lower case
+	Attendee string
+}
+
+// replyKey returns ReplyKey of attendee a in m
+func replyKey(m *entry, a types.CalenderUserAddress) ReplyKey {
+	k := ReplyKey{UID: string(m.uid.Value), Attendee: strings.ToLower(a.String())}
+	if m.recurrenceID != nil {
+		k.RecurrenceID = types.DateTime(timeOf(m.recurrenceID.Value).UTC()).String()
+	}
+	return k
+}
+
+// Process applies REPLY, REFRESH, CANCEL or DECLINECOUNTER message msg to VEVENT or VTODO in stored.
+// component is matched by UID and RECURRENCE-ID.
+// REPLY for an instance which is not overridden adds a component which overrides the instance to stored.
+// message is stale if its SEQUENCE is smaller than the stored one,
+// or its DTSTAMP is before the stored one with the same SEQUENCE.
+// DTSTAMP of REPLY is compared with the last REPLY from the same attendee in stamps, and it is recorded in stamps.
+// stamps may be nil if the caller does not keep them.
+// stored and stamps are not modified if message is stale or its component is not found.
+// https://tools.ietf.org/html/rfc5546#section-2.1.5
+func Process(stored, msg *ical.Calendar, stamps ReplyStamps) (*Report, error) {
+	if msg.Method == nil {
+		return nil, fmt.Errorf("message does not have METHOD")
+	}
+	method := property.MethodType(msg.Method.Value)
+	switch method {
+	case property.MethodTypeReply, property.MethodTypeRefresh, property.MethodTypeCancel, property.MethodTypeDeclinecounter:
+	default:
+		return nil, fmt.Errorf("%s is not supported", method)
+	}
+
+	type target struct {
+		stored, msg *entry
+		// instance is true if msg is for an instance which stored does not override
+		instance bool
+	}
+	var targets []target
+	for _, c := range msg.Components {
+		m, ok := entryOf(c)
+		if !ok {
+			continue
+		}
+		s, exact, err := find(stored, m)
+		if err != nil {
+			return nil, err
+		}
+		switch method {
+		case property.MethodTypeReply:
+			if err := checkReplyStale(s, m, stamps); err != nil {
+				return nil, err
+			}
+		case property.MethodTypeRefresh:
+		default:
+			if err := checkStale(s, m); err != nil {
+				return nil, err
+			}
+		}
+		targets = append(targets, target{stored: s, msg: m, instance: !exact})
+	}
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("message does not have VEVENT or VTODO")
+	}
+	for _, t := range targets {
+		if t.instance && method == property.MethodTypeCancel && t.msg.recurrenceID.Parameter[parameter.TypeNameRecurrenceIDRange] != nil {
+			return nil, fmt.Errorf("cancel instances with RANGE=THISANDFUTURE which are not overridden is not supported")
+		}
+	}
+
+	r := &Report{Method: method}
+	for _, t := range targets {
+		tgt := t.msg.target()
+		switch method {
+		case property.MethodTypeReply:
+			if t.instance {
+				o := override(t.stored.component, t.msg.recurrenceID)
+				stored.Components = append(stored.Components, o)
+				t.stored, _ = entryOf(o)
+			}
+			r.Changes = append(r.Changes, reply(t.stored, t.msg, stamps)...)
+		case property.MethodTypeRefresh:
+			for _, a := range *t.msg.attendees {
+				r.Changes = append(r.Changes, &RefreshRequested{Target: tgt, Attendee: a.Value})
+			}
+		case property.MethodTypeCancel:
+			if t.instance {
+				rid := t.msg.recurrenceID
+				if err := t.stored.addExceptionDateTimes(rid.Parameter, []types.TimeValue{rid.Value}); err != nil {
+					return nil, fmt.Errorf("add EXDATE %s: %w", rid.Value, err)
+				}
+			} else {
+				*t.stored.status = &property.Status{Parameter: parameter.Container{}, Value: property.StatusTypeCancelled}
+				*t.stored.sequence = *t.msg.sequence
+				*t.stored.stamp = *t.msg.stamp
+			}
+			r.Changes = append(r.Changes, &Cancelled{Target: tgt})
+		case property.MethodTypeDeclinecounter:
+			for _, a := range *t.msg.attendees {
+				r.Changes = append(r.Changes, &CounterDeclined{Target: tgt, Attendee: a.Value})
+			}
+		}
+		for _, rs := range *t.msg.requestStatus {
+			*t.stored.requestStatus = append(*t.stored.requestStatus, rs)
+			r.Changes = append(r.Changes, &RequestStatusRecorded{Target: tgt, Status: rs})
+		}
+	}
+	return r, nil
+}
+
+// reply updates PARTSTAT of attendees in s by REPLY m.
+// DTSTAMP of m is recorded in stamps.
+func reply(s, m *entry, stamps ReplyStamps) []Change {
+	var res []Change
+	tgt := m.target()
+	for _, a := range *m.attendees {
+		if stamps != nil {
+			stamps[replyKey(m, a.Value)] = m.stampTime()
+		}
+		to := participationStatus(a.Parameter)
+		found := false
+		for i, sa := range *s.attendees {
+			if !SameAddress(sa.Value, a.Value) {
+				continue
+			}
+			found = true
+			params := parameter.Container{}
+			for k, v := range sa.Parameter {
+				params[k] = v
+			}
+			delete(params, parameter.TypeNameParticipationStatus)
+			if ps, ok := a.Parameter[parameter.TypeNameParticipationStatus]; ok {
+				params[parameter.TypeNameParticipationStatus] = ps
+			}
+			(*s.attendees)[i] = &property.Attendee{Parameter: params, Value: sa.Value}
+			if from := participationStatus(sa.Parameter); from != to {
+				res = append(res, &ParticipationStatusChanged{Target: tgt, Attendee: a.Value, From: from, To: to})
+			}
+			break
+		}
+		if !found {
+			params := parameter.Container{}
+			for k, v := range a.Parameter {
+				params[k] = v
+			}
+			*s.attendees = append(*s.attendees, &property.Attendee{Parameter: params, Value: a.Value})
+			res = append(res, &AttendeeAdded{Target: tgt, Attendee: a.Value, Status: to})
+		}
+	}
+	return res
+}
+
+// participationStatus returns PARTSTAT in params, default is NEEDS-ACTION
+func participationStatus(params parameter.Container) parameter.ParticipationStatusType {
+	ps := params.GetParticipationStatus()
+	if ps == nil {
+		return parameter.ParticipationStatusTypeNeedsAction
+	}
+	if ps.Type == parameter.ParticipationStatusTypeXToken {
+		return parameter.ParticipationStatusType(ps.Value)
+	}
+	return ps.Type
+}
+
+// checkStale returns ErrStale if m is older than s
+func checkStale(s, m *entry) error {
+	ss, ms := s.sequenceNumber(), m.sequenceNumber()
+	if ms < ss {
+		return fmt.Errorf("%w: SEQUENCE %d is older than %d", ErrStale, ms, ss)
+	}
+	if mt, st := m.stampTime(), s.stampTime(); ms == ss && mt.Before(st) {
+		return fmt.Errorf("%w: DTSTAMP %s is older than %s", ErrStale, types.DateTime(mt), types.DateTime(st))
+	}
+	return nil
+}
+
+// checkReplyStale returns ErrStale if REPLY m is older than s or the last REPLY from its attendees in stamps
+func checkReplyStale(s, m *entry, stamps ReplyStamps) error {
+	ss, ms := s.sequenceNumber(), m.sequenceNumber()
+	if ms < ss {
+		return fmt.Errorf("%w: SEQUENCE %d is older than %d", ErrStale, ms, ss)
+	}
+	if ms > ss {
+		return nil
+	}
+	mt := m.stampTime()
+	for _, a := range *m.attendees {
+		if st, ok := stamps[replyKey(m, a.Value)]; ok && mt.Before(st) {
+			return fmt.Errorf("%w: DTSTAMP %s is older than the last reply %s from %s", ErrStale, types.DateTime(mt), types.DateTime(st), a.Value)
+		}
+	}
+	return nil
+}
+
+// override returns deep copy of recurring component c which overrides its instance at rid.
+// https://tools.ietf.org/html/rfc5546#section-3.2.3
+func override(c ical.CalenderComponent, rid *property.RecurrenceID) ical.CalenderComponent {
+	params := parameter.Container{}
+	for k, v := range rid.Parameter {
+		params[k] = v
+	}
+	delete(params, parameter.TypeNameRecurrenceIDRange)
+	switch v := c.(type) {
+	case *ical.Event:
+		o := deepCopy(reflect.ValueOf(v)).Interface().(*ical.Event)
+		o.RecurrenceID = &property.RecurrenceID{Parameter: params, Value: rid.Value}
+		if v.DateTimeStart != nil {
+			d := timeOf(rid.Value).Sub(timeOf(v.DateTimeStart.Value))
+			o.DateTimeStart = &property.DateTimeStart{Parameter: params, Value: rid.Value}
+			if v.DateTimeEnd != nil {
+				o.DateTimeEnd = &property.DateTimeEnd{Parameter: v.DateTimeEnd.Parameter, Value: shift(v.DateTimeEnd.Value, d)}
+			}
+		}
+		o.RecurrenceRule, o.RecurrenceDateTimes, o.ExceptionDateTimes = nil, nil, nil
+		o.RequestStatus = nil
+		o.PropertyOrder = nil
+		return o
+	case *ical.ToDo:
+		o := deepCopy(reflect.ValueOf(v)).Interface().(*ical.ToDo)
+		o.RecurrenceID = &property.RecurrenceID{Parameter: params, Value: rid.Value}
+		if v.DateTimeStart != nil {
+			d := timeOf(rid.Value).Sub(timeOf(v.DateTimeStart.Value))
+			o.DateTimeStart = &property.DateTimeStart{Parameter: params, Value: rid.Value}
+			if v.DateTimeDue != nil {
+				o.DateTimeDue = &property.DateTimeDue{Parameter: v.DateTimeDue.Parameter, Value: shift(v.DateTimeDue.Value, d)}
+			}
+		}
+		o.RecurrenceRule, o.RecurrenceDateTimes, o.ExceptionDateTimes = nil, nil, nil
+		o.RequestStatus = nil
+		o.PropertyOrder = nil
+		return o
+	}
+	return c
+}
+
+// deepCopy returns copy of v which shares no pointers, slices and maps with v.
+// unexported fields are copied as they are.
+func deepCopy(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		res := reflect.New(v.Type().Elem())
+		res.Elem().Set(deepCopy(v.Elem()))
+		return res
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		res := reflect.New(v.Type()).Elem()
+		res.Set(deepCopy(v.Elem()))
+		return res
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		res := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			res.Index(i).Set(deepCopy(v.Index(i)))
+		}
+		return res
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		res := reflect.MakeMapWithSize(v.Type(), v.Len())
+		for it := v.MapRange(); it.Next(); {
+			res.SetMapIndex(it.Key(), deepCopy(it.Value()))
+		}
+		return res
+	case reflect.Struct:
+		res := reflect.New(v.Type()).Elem()
+		res.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if f := res.Field(i); f.CanSet() {
+				f.Set(deepCopy(v.Field(i)))
+			}
+		}
+		return res
+	}
+	return v
+}
+
+// shift returns v moved by d
+func shift(v types.TimeValue, d time.Duration) types.TimeValue {
+	switch t := v.(type) {
+	case types.Date:
+		return types.Date(time.Time(t).Add(d))
+	case types.DateTime:
+		return types.DateTime(time.Time(t).Add(d))
+	}
+	return v
+}
+
+// find returns component in c which has the same UID and RECURRENCE-ID as m.
+// the recurring component is returned with false if m is for an instance which is not overridden.
+func find(c *ical.Calendar, m *entry) (*entry, bool, error) {
+	var master *entry
+	for _, cc := range c.Components {
+		e, ok := entryOf(cc)
+		if !ok || e.kind != m.kind || e.uid == nil || e.uid.Value != m.uid.Value {
+			continue
+		}
+		if sameRecurrenceID(e.recurrenceID, m.recurrenceID) {
+			return e, true, nil
+		}
+		if e.recurrenceID == nil {
+			master = e
+		}
+	}
+	if master != nil && m.recurrenceID != nil {
+		return master, false, nil
+	}
+	return nil, false, fmt.Errorf("%w: %s", ErrNotFound, m.uid.Value)
+}
+
+func sameRecurrenceID(a, b *property.RecurrenceID) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return timeOf(a.Value).Equal(timeOf(b.Value))
+}
+
+func timeOf(v types.TimeValue) time.Time {
+	switch t := v.(type) {
+	case types.Date:
+		return time.Time(t)
+	case types.DateTime:
+		return time.Time(t)
+	}
+	return time.Time{}
+}
+
+// entry is VEVENT or VTODO.
+// pointer fields refer to properties of the component to update it.
+type entry struct {
+	component             ical.CalenderComponent
+	kind                  component.Type
+	uid                   *property.UID
+	recurrenceID          *property.RecurrenceID
+	stamp                 **property.DateTimeStamp
+	sequence              **property.SequenceNumber
+	status                **property.Status
+	attendees             *[]*property.Attendee
+	requestStatus         *[]*property.RequestStatus
+	addExceptionDateTimes func(parameter.Container, []types.TimeValue) error
+}
+
+func entryOf(c ical.CalenderComponent) (*entry, bool) {
+	switch v := c.(type) {
+	case *ical.Event:
+		return &entry{
+			component:             v,
+			kind:                  component.TypeEvent,
+			uid:                   v.UID,
+			recurrenceID:          v.RecurrenceID,
+			stamp:                 &v.DateTimeStamp,
+			sequence:              &v.SequenceNumber,
+			status:                &v.Status,
+			attendees:             &v.Attendees,
+			requestStatus:         &v.RequestStatus,
+			addExceptionDateTimes: v.AddExceptionDateTimes,
+		}, v.UID != nil
+	case *ical.ToDo:
+		return &entry{
+			component:             v,
+			kind:                  component.TypeTODO,
+			uid:                   v.UID,
+			recurrenceID:          v.RecurrenceID,
+			stamp:                 &v.DateTimeStamp,
+			sequence:              &v.SequenceNumber,
+			status:                &v.Status,
+			attendees:             &v.Attendees,
+			requestStatus:         &v.RequestStatus,
+			addExceptionDateTimes: v.AddExceptionDateTimes,
+		}, v.UID != nil
+	}
+	return nil, false
+}
+
+func (e *entry) sequenceNumber() types.Integer {
+	if *e.sequence == nil {
+		return 0
+	}
+	return (*e.sequence).Value
+}
+
+func (e *entry) stampTime() time.Time {
+	if *e.stamp == nil {
+		return time.Time{}
+	}
+	return time.Time((*e.stamp).Value)
+}
+
+func (e *entry) target() Target {
+	t := Target{UID: e.uid.Value}
+	if e.recurrenceID != nil {
+		t.RecurrenceID = e.recurrenceID.Value
+	}
+	return t
+}
@@ -0,0 +1,418 @@
+package itip
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/knsh14/ical"
+	"github.com/knsh14/ical/component"
+	"github.com/knsh14/ical/parameter"
+	"github.com/knsh14/ical/property"
+	"github.com/knsh14/ical/types"
+)
+
+func TestProcess(t *testing.T) {
+	t.Parallel()
+	organizer := mustAddress(t, "mailto:alice@example.com")
+	bob := mustAddress(t, "mailto:bob@example.com")
+	dave := mustAddress(t, "mailto:dave@example.com")
+	utc := func(d, h int) time.Time {
+		return time.Date(2020, 1, d, h, 0, 0, 0, time.UTC)
+	}
+	// replied is DTSTAMP of REPLY, which is now in tests
+	replied := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	// newStored returns weekly event whose SEQUENCE is 1 and DTSTAMP is 2020-01-01
+	newStored := func(t *testing.T) *ical.Calendar {
+		t.Helper()
+		e := ical.NewEvent()
+		mustNoError(t, e.SetUID(parameter.Container{}, types.NewText("weekly@example.com")))
+		mustNoError(t, e.SetDateTimeStamp(parameter.Container{}, types.DateTime(utc(1, 0))))
+		mustNoError(t, e.SetDateTimeStart(parameter.Container{}, types.DateTime(utc(6, 10))))
+		mustNoError(t, e.SetSequenceNumber(parameter.Container{}, 1))
+		mustNoError(t, e.SetOrganizer(parameter.Container{}, organizer))
+		rule, err := types.NewRecurrenceRule("FREQ=WEEKLY;COUNT=4")
+		mustNoError(t, err)
+		mustNoError(t, e.SetRecurrenceRule(parameter.Container{}, rule))
+		rsvp, err := parameter.NewRSVP("TRUE")
+		mustNoError(t, err)
+		mustNoError(t, e.AddAttendee(parameter.Container{
+			parameter.TypeNameRSVP: []parameter.Base{rsvp},
+		}, bob))
+		c := ical.NewCalendar()
+		c.Components = append(c.Components, e)
+		return c
+	}
+	event := func(c *ical.Calendar) *ical.Event {
+		return c.Components[0].(*ical.Event)
+	}
+	instance := func(t *testing.T, e *ical.Event, at time.Time) *ical.Event {
+		t.Helper()
+		mustNoError(t, e.SetRecurrenceID(parameter.Container{}, types.DateTime(at)))
+		return e
+	}
+
+	testcases := map[string]struct {
+		stored       func(*testing.T) *ical.Calendar
+		msg          func(*testing.T, *ical.Calendar) *ical.Calendar
+		expect       *Report
+		expectStored []string
+		expectStamps ReplyStamps
+	}{
+		"reply": {
+			stored: newStored,
+			msg: func(t *testing.T, stored *ical.Calendar) *ical.Calendar {
+				msg, err := Reply(event(stored), bob, parameter.ParticipationStatusTypeAccepted)
+				mustNoError(t, err)
+				return msg
+			},
+			expect: &Report{
+				Method: property.MethodTypeReply,
+				Changes: []Change{
+					&ParticipationStatusChanged{
+						Target:   Target{UID: "weekly@example.com"},
+						Attendee: bob,
+						From:     parameter.ParticipationStatusTypeNeedsAction,
+						To:       parameter.ParticipationStatusTypeAccepted,
+					},
+				},
+			},
+			expectStored: []string{
+				"BEGIN:VEVENT",
+				"UID:weekly@example.com",
+				"DTSTAMP:20200101T000000Z",
+				"DTSTART:20200106T100000Z",
+				"ORGANIZER:mailto:alice@example.com",
+				"SEQUENCE:1",
+				"RRULE:FREQ=WEEKLY;COUNT=4",
+				"ATTENDEE;PARTSTAT=ACCEPTED;RSVP=TRUE:mailto:bob@example.com",
+				"END:VEVENT",
+			},
+			expectStamps: ReplyStamps{
+				{UID: "weekly@example.com", Attendee: "mailto:bob@example.com"}: replied,
+			},
+		},
+		"reply for instance which is not overridden": {
+			stored: newStored,
+			msg: func(t *testing.T, stored *ical.Calendar) *ical.Calendar {
+				msg, err := Reply(instance(t, event(newStored(t)), utc(13, 10)), bob, parameter.ParticipationStatusTypeDeclined)
+				mustNoError(t, err)
+				return msg
+			},
+			expect: &Report{
+				Method: property.MethodTypeReply,
+				Changes: []Change{
+					&ParticipationStatusChanged{
+						Target:   Target{UID: "weekly@example.com", RecurrenceID: types.DateTime(utc(13, 10))},
+						Attendee: bob,
+						From:     parameter.ParticipationStatusTypeNeedsAction,
+						To:       parameter.ParticipationStatusTypeDeclined,
+					},
+				},
+			},
+			expectStored: []string{
+				"BEGIN:VEVENT",
+				"UID:weekly@example.com",
+				"DTSTAMP:20200101T000000Z",
+				"DTSTART:20200106T100000Z",
+				"ORGANIZER:mailto:alice@example.com",
+				"SEQUENCE:1",
+				"RRULE:FREQ=WEEKLY;COUNT=4",
+				"ATTENDEE;RSVP=TRUE:mailto:bob@example.com",
+				"END:VEVENT",
+				"BEGIN:VEVENT",
+				"UID:weekly@example.com",
+				"DTSTAMP:20200101T000000Z",
+				"DTSTART:20200113T100000Z",
+				"ORGANIZER:mailto:alice@example.com",
+				"SEQUENCE:1",
+				"RECURRENCE-ID:20200113T100000Z",
+				"ATTENDEE;PARTSTAT=DECLINED;RSVP=TRUE:mailto:bob@example.com",
+				"END:VEVENT",
+			},
+			expectStamps: ReplyStamps{
+				{UID: "weekly@example.com", RecurrenceID: "20200113T100000Z", Attendee: "mailto:bob@example.com"}: replied,
+			},
+		},
+		"reply from uninvited attendee with request status": {
+			stored: newStored,
+			msg: func(t *testing.T, stored *ical.Calendar) *ical.Calendar {
+				msg, err := Reply(event(stored), dave, parameter.ParticipationStatusTypeTentative)
+				mustNoError(t, err)
+				mustNoError(t, event(msg).AddRequestStatus(parameter.Container{}, types.NewText("2.0;Success")))
+				return msg
+			},
+			expect: &Report{
+				Method: property.MethodTypeReply,
+				Changes: []Change{
+					&AttendeeAdded{
+						Target:   Target{UID: "weekly@example.com"},
+						Attendee: dave,
+						Status:   parameter.ParticipationStatusTypeTentative,
+					},
+					&RequestStatusRecorded{
+						Target: Target{UID: "weekly@example.com"},
+						Status: &property.RequestStatus{Parameter: parameter.Container{}, StatusCode: "2.0", StatusDescription: "Success"},
+					},
+				},
+			},
+			expectStored: []string{
+				"BEGIN:VEVENT",
+				"UID:weekly@example.com",
+				"DTSTAMP:20200101T000000Z",
+				"DTSTART:20200106T100000Z",
+				"ORGANIZER:mailto:alice@example.com",
+				"SEQUENCE:1",
+				"RRULE:FREQ=WEEKLY;COUNT=4",
+				"ATTENDEE;RSVP=TRUE:mailto:bob@example.com",
+				"ATTENDEE;PARTSTAT=TENTATIVE:mailto:dave@example.com",
+				"REQUEST-STATUS:2.0;Success",
+				"END:VEVENT",
+			},
+			expectStamps: ReplyStamps{
+				{UID: "weekly@example.com", Attendee: "mailto:dave@example.com"}: replied,
+			},
+		},
+		"cancel instance": {
+			stored: newStored,
+			msg: func(t *testing.T, stored *ical.Calendar) *ical.Calendar {
+				msg, err := Cancel(instance(t, event(newStored(t)), utc(13, 10)))
+				mustNoError(t, err)
+				return msg
+			},
+			expect: &Report{
+				Method: property.MethodTypeCancel,
+				Changes: []Change{
+					&Cancelled{Target: Target{UID: "weekly@example.com", RecurrenceID: types.DateTime(utc(13, 10))}},
+				},
+			},
+			expectStored: []string{
+				"BEGIN:VEVENT",
+				"UID:weekly@example.com",
+				"DTSTAMP:20200101T000000Z",
+				"DTSTART:20200106T100000Z",
+				"ORGANIZER:mailto:alice@example.com",
+				"SEQUENCE:1",
+				"RRULE:FREQ=WEEKLY;COUNT=4",
+				"ATTENDEE;RSVP=TRUE:mailto:bob@example.com",
+				"EXDATE:20200113T100000Z",
+				"END:VEVENT",
+			},
+		},
+		"cancel": {
+			stored: newStored,
+			msg: func(t *testing.T, stored *ical.Calendar) *ical.Calendar {
+				msg, err := Cancel(event(newStored(t)))
+				mustNoError(t, err)
+				return msg
+			},
+			expect: &Report{
+				Method: property.MethodTypeCancel,
+				Changes: []Change{
+					&Cancelled{Target: Target{UID: "weekly@example.com"}},
+				},
+			},
+			expectStored: []string{
+				"BEGIN:VEVENT",
+				"UID:weekly@example.com",
+				"DTSTAMP:20200102T030405Z",
+				"DTSTART:20200106T100000Z",
+				"ORGANIZER:mailto:alice@example.com",
+				"SEQUENCE:2",
+				"STATUS:CANCELLED",
+				"RRULE:FREQ=WEEKLY;COUNT=4",
+				"ATTENDEE;RSVP=TRUE:mailto:bob@example.com",
+				"END:VEVENT",
+			},
+		},
+		"refresh": {
+			stored: newStored,
+			msg: func(t *testing.T, stored *ical.Calendar) *ical.Calendar {
+				msg, err := Refresh(event(newStored(t)), bob)
+				mustNoError(t, err)
+				return msg
+			},
+			expect: &Report{
+				Method: property.MethodTypeRefresh,
+				Changes: []Change{
+					&RefreshRequested{Target: Target{UID: "weekly@example.com"}, Attendee: bob},
+				},
+			},
+		},
+		"decline counter": {
+			stored: newStored,
+			msg: func(t *testing.T, stored *ical.Calendar) *ical.Calendar {
+				msg, err := DeclineCounter(event(newStored(t)), bob)
+				mustNoError(t, err)
+				return msg
+			},
+			expect: &Report{
+				Method: property.MethodTypeDeclinecounter,
+				Changes: []Change{
+					&CounterDeclined{Target: Target{UID: "weekly@example.com"}, Attendee: bob},
+				},
+			},
+		},
+	}
+
+	for title, tt := range testcases {
+		tt := tt
+		t.Run(title, func(t *testing.T) {
+			t.Parallel()
+			stored := tt.stored(t)
+			stamps := ReplyStamps{}
+			got, err := Process(stored, tt.msg(t, stored), stamps)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.expect, got, cmp.AllowUnexported(types.DateTime{})); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+			if tt.expectStamps == nil {
+				tt.expectStamps = ReplyStamps{}
+			}
+			if diff := cmp.Diff(tt.expectStamps, stamps); diff != "" {
+				t.Errorf("stamps (-want, +got)\n%s", diff)
+			}
+			if tt.expectStored == nil {
+				return
+			}
+			b := &bytes.Buffer{}
+			for _, c := range stored.Components {
+				if err := c.Decode(b); err != nil {
+					t.Fatal(err)
+				}
+			}
+			// unfold long lines
+			s := strings.ReplaceAll(b.String(), "\r\n ", "")
+			lines := strings.Split(strings.TrimSuffix(s, "\r\n"), "\r\n")
+			if diff := cmp.Diff(tt.expectStored, lines); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestProcess_Error(t *testing.T) {
+	t.Parallel()
+	organizer := mustAddress(t, "mailto:alice@example.com")
+	bob := mustAddress(t, "mailto:bob@example.com")
+
+	newEvent := func(t *testing.T, uid string, seq types.Integer, stamp time.Time) *ical.Event {
+		t.Helper()
+		e := ical.NewEvent()
+		mustNoError(t, e.SetUID(parameter.Container{}, types.NewText(uid)))
+		mustNoError(t, e.SetDateTimeStamp(parameter.Container{}, types.DateTime(stamp)))
+		mustNoError(t, e.SetSequenceNumber(parameter.Container{}, seq))
+		mustNoError(t, e.SetOrganizer(parameter.Container{}, organizer))
+		mustNoError(t, e.AddAttendee(parameter.Container{}, bob))
+		return e
+	}
+	stored := func(e *ical.Event) *ical.Calendar {
+		c := ical.NewCalendar()
+		c.Components = append(c.Components, e)
+		return c
+	}
+	old := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	future := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	testcases := map[string]struct {
+		stored *ical.Calendar
+		stamps ReplyStamps
+		msg    func(*testing.T) (*ical.Calendar, error)
+		expect error
+	}{
+		"older sequence": {
+			stored: stored(newEvent(t, "a@example.com", 3, old)),
+			msg: func(t *testing.T) (*ical.Calendar, error) {
+				return Reply(newEvent(t, "a@example.com", 2, old), bob, parameter.ParticipationStatusTypeAccepted)
+			},
+			expect: ErrStale,
+		},
+		"older dtstamp": {
+			stored: stored(newEvent(t, "a@example.com", 3, future)),
+			msg: func(t *testing.T) (*ical.Calendar, error) {
+				return DeclineCounter(newEvent(t, "a@example.com", 3, old), bob)
+			},
+			expect: ErrStale,
+		},
+		"unknown uid": {
+			stored: stored(newEvent(t, "a@example.com", 0, old)),
+			msg: func(t *testing.T) (*ical.Calendar, error) {
+				return Reply(newEvent(t, "b@example.com", 0, old), bob, parameter.ParticipationStatusTypeAccepted)
+			},
+			expect: ErrNotFound,
+		},
+		"older dtstamp than the last reply": {
+			stored: stored(newEvent(t, "a@example.com", 3, old)),
+			stamps: ReplyStamps{{UID: "a@example.com", Attendee: "mailto:bob@example.com"}: future},
+			msg: func(t *testing.T) (*ical.Calendar, error) {
+				return Reply(newEvent(t, "a@example.com", 3, old), bob, parameter.ParticipationStatusTypeAccepted)
+			},
+			expect: ErrStale,
+		},
+	}
+
+	for title, tt := range testcases {
+		tt := tt
+		t.Run(title, func(t *testing.T) {
+			t.Parallel()
+			msg, err := tt.msg(t)
+			mustNoError(t, err)
+			_, err = Process(tt.stored, msg, tt.stamps)
+			if !errors.Is(err, tt.expect) {
+				t.Fatalf("expected %v, but got %v", tt.expect, err)
+			}
+		})
+	}
+}
+
+func TestProcess_RequestAfterReply(t *testing.T) {
+	t.Parallel()
+	organizer := mustAddress(t, "mailto:alice@example.com")
+	bob := mustAddress(t, "mailto:bob@example.com")
+	e := ical.NewEvent()
+	mustNoError(t, e.SetUID(parameter.Container{}, types.NewText("a@example.com")))
+	mustNoError(t, e.SetDateTimeStamp(parameter.Container{}, types.DateTime(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))))
+	mustNoError(t, e.SetDateTimeStart(parameter.Container{}, types.DateTime(time.Date(2020, 1, 6, 10, 0, 0, 0, time.UTC))))
+	mustNoError(t, e.SetOrganizer(parameter.Container{}, organizer))
+	mustNoError(t, e.AddAttendee(parameter.Container{}, bob))
+	stored := ical.NewCalendar()
+	stored.Components = append(stored.Components, e)
+
+	msg, err := Reply(e, bob, parameter.ParticipationStatusTypeAccepted)
+	mustNoError(t, err)
+	stamps := ReplyStamps{}
+	_, err = Process(stored, msg, stamps)
+	mustNoError(t, err)
+	req, err := Request(e, organizer)
+	mustNoError(t, err)
+	b := &bytes.Buffer{}
+	mustNoError(t, req.Decode(b))
+	if got := b.String(); !strings.Contains(got, "ATTENDEE;PARTSTAT=ACCEPTED:mailto:bob@example.com") || strings.Contains(got, "X-REPLY-DTSTAMP") {
+		t.Errorf("unexpected REQUEST after REPLY\n%s", got)
+	}
+}
+
+func TestOverride(t *testing.T) {
+	t.Parallel()
+	e := ical.NewEvent()
+	mustNoError(t, e.SetUID(parameter.Container{}, types.NewText("a@example.com")))
+	mustNoError(t, e.SetDateTimeStart(parameter.Container{}, types.DateTime(time.Date(2020, 1, 6, 10, 0, 0, 0, time.UTC))))
+	mustNoError(t, e.AddAttendee(parameter.Container{}, mustAddress(t, "mailto:bob@example.com")))
+	mustNoError(t, e.AddCategories(parameter.Container{}, []types.Text{"MEETING"}))
+	rid := &property.RecurrenceID{Parameter: parameter.Container{}, Value: types.DateTime(time.Date(2020, 1, 13, 10, 0, 0, 0, time.UTC))}
+
+	o := override(e, rid).(*ical.Event)
+	ps, err := parameter.NewParticipationStatus(string(parameter.ParticipationStatusTypeAccepted), component.TypeEvent)
+	mustNoError(t, err)
+	o.Attendees[0].Parameter[parameter.TypeNameParticipationStatus] = []parameter.Base{ps}
+	o.Categories[0].Values[0] = "CHANGED"
+	if len(e.Attendees[0].Parameter) != 0 || e.Categories[0].Values[0] != "MEETING" {
+		t.Errorf("override shares properties with the recurring component")
+	}
+}
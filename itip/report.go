@@ -0,0 +1,105 @@
+package itip
+
+import (
+	"fmt"
+
+	"github.com/knsh14/ical/parameter"
+	"github.com/knsh14/ical/property"
+	"github.com/knsh14/ical/types"
+)
+
+// Report is changes which Process applied to the stored calendar
+type Report struct {
+	Method  property.MethodType
+	Changes []Change
+}
+
+// Change is one of ParticipationStatusChanged, AttendeeAdded, RequestStatusRecorded,
+// Cancelled, RefreshRequested and CounterDeclined.
+// String returns description for users.
+type Change interface {
+	implementChange()
+	String() string
+}
+
+// Target is the component which message is for
+type Target struct {
+	UID types.Text
+	// RecurrenceID is nil if message is for all instances
+	RecurrenceID types.TimeValue
+}
+
+func (t Target) String() string {
+	if t.RecurrenceID == nil {
+		return string(t.UID)
+	}
+	return fmt.Sprintf("%s (instance %s)", t.UID, t.RecurrenceID)
+}
+
+// ParticipationStatusChanged is PARTSTAT of Attendee which REPLY changed
+type ParticipationStatusChanged struct {
+	Target
+	Attendee types.CalenderUserAddress
+	From     parameter.ParticipationStatusType
+	To       parameter.ParticipationStatusType
+}
+
+func (c *ParticipationStatusChanged) implementChange() {}
+func (c *ParticipationStatusChanged) String() string {
+	return fmt.Sprintf("%s: %s changed participation status from %s to %s", c.Target, c.Attendee, c.From, c.To)
+}
+
+// AttendeeAdded is Attendee who is not in the stored component but sent REPLY
+type AttendeeAdded struct {
+	Target
+	Attendee types.CalenderUserAddress
+	Status   parameter.ParticipationStatusType
+}
+
+func (c *AttendeeAdded) implementChange() {}
+func (c *AttendeeAdded) String() string {
+	return fmt.Sprintf("%s: %s was added with participation status %s", c.Target, c.Attendee, c.Status)
+}
+
+// RequestStatusRecorded is REQUEST-STATUS of message which is added to the stored component
+type RequestStatusRecorded struct {
+	Target
+	Status *property.RequestStatus
+}
+
+func (c *RequestStatusRecorded) implementChange() {}
+func (c *RequestStatusRecorded) String() string {
+	return fmt.Sprintf("%s: request status %s %s", c.Target, c.Status.StatusCode, c.Status.StatusDescription)
+}
+
+// Cancelled is the component or its instance which CANCEL cancelled
+type Cancelled struct {
+	Target
+}
+
+func (c *Cancelled) implementChange() {}
+func (c *Cancelled) String() string {
+	return fmt.Sprintf("%s: cancelled", c.Target)
+}
+
+// RefreshRequested is Attendee who requested the latest version by REFRESH
+type RefreshRequested struct {
+	Target
+	Attendee types.CalenderUserAddress
+}
+
+func (c *RefreshRequested) implementChange() {}
+func (c *RefreshRequested) String() string {
+	return fmt.Sprintf("%s: %s requested the latest version", c.Target, c.Attendee)
+}
+
+// CounterDeclined is Attendee whose COUNTER was declined by DECLINECOUNTER
+type CounterDeclined struct {
+	Target
+	Attendee types.CalenderUserAddress
+}
+
+func (c *CounterDeclined) implementChange() {}
+func (c *CounterDeclined) String() string {
+	return fmt.Sprintf("%s: counter proposal of %s was declined", c.Target, c.Attendee)
+}
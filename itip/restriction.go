@@ -0,0 +1,216 @@
+package itip
+
+import (
+	"github.com/knsh14/ical"
+	"github.com/knsh14/ical/component"
+	"github.com/knsh14/ical/property"
+)
+
+// filterEvent returns copy of e which has only properties p allows
+func filterEvent(e *ical.Event, p ical.Profile) *ical.Event {
+	allows := func(n property.Name) bool { return p.Allows(component.TypeEvent, n) }
+	res := ical.NewEvent()
+	if allows(property.NameUID) {
+		res.UID = e.UID
+	}
+	if allows(property.NameDateTimeStamp) {
+		res.DateTimeStamp = e.DateTimeStamp
+	}
+	if allows(property.NameDateTimeStart) {
+		res.DateTimeStart = e.DateTimeStart
+	}
+	if allows(property.NameClass) {
+		res.Class = e.Class
+	}
+	if allows(property.NameDateTimeCreated) {
+		res.DateTimeCreated = e.DateTimeCreated
+	}
+	if allows(property.NameDescription) {
+		res.Description = e.Description
+	}
+	if allows(property.NameGeo) {
+		res.Geo = e.Geo
+	}
+	if allows(property.NameLastModified) {
+		res.LastModified = e.LastModified
+	}
+	if allows(property.NameLocation) {
+		res.Location = e.Location
+	}
+	if allows(property.NameOrganizer) {
+		res.Organizer = e.Organizer
+	}
+	if allows(property.NamePriority) {
+		res.Priority = e.Priority
+	}
+	if allows(property.NameSequenceNumber) {
+		res.SequenceNumber = e.SequenceNumber
+	}
+	if allows(property.NameStatus) {
+		res.Status = e.Status
+	}
+	if allows(property.NameSummary) {
+		res.Summary = e.Summary
+	}
+	if allows(property.NameTimeTransparency) {
+		res.TimeTransparency = e.TimeTransparency
+	}
+	if allows(property.NameURL) {
+		res.URL = e.URL
+	}
+	if allows(property.NameRecurrenceID) {
+		res.RecurrenceID = e.RecurrenceID
+	}
+	if allows(property.NameRecurrenceRule) {
+		res.RecurrenceRule = e.RecurrenceRule
+	}
+	if allows(property.NameDateTimeEnd) {
+		res.DateTimeEnd = e.DateTimeEnd
+	}
+	if allows(property.NameDuration) {
+		res.Duration = e.Duration
+	}
+	if allows(property.NameAttachment) {
+		res.Attachments = append(res.Attachments, e.Attachments...)
+	}
+	if allows(property.NameAttendee) {
+		res.Attendees = append(res.Attendees, e.Attendees...)
+	}
+	if allows(property.NameCategories) {
+		res.Categories = append(res.Categories, e.Categories...)
+	}
+	if allows(property.NameComment) {
+		res.Comments = append(res.Comments, e.Comments...)
+	}
+	if allows(property.NameContact) {
+		res.Contacts = append(res.Contacts, e.Contacts...)
+	}
+	if allows(property.NameExceptionDateTimes) {
+		res.ExceptionDateTimes = append(res.ExceptionDateTimes, e.ExceptionDateTimes...)
+	}
+	if allows(property.NameRequestStatus) {
+		res.RequestStatus = append(res.RequestStatus, e.RequestStatus...)
+	}
+	if allows(property.NameRelatedTo) {
+		res.RelatedTos = append(res.RelatedTos, e.RelatedTos...)
+	}
+	if allows(property.NameResources) {
+		res.Resources = append(res.Resources, e.Resources...)
+	}
+	if allows(property.NameRecurrenceDateTimes) {
+		res.RecurrenceDateTimes = append(res.RecurrenceDateTimes, e.RecurrenceDateTimes...)
+	}
+	if p.AllowsAlarms(component.TypeEvent) {
+		res.Alarms = append(res.Alarms, e.Alarms...)
+	}
+	res.XProperties = append(res.XProperties, e.XProperties...)
+	res.IANAProperties = append(res.IANAProperties, e.IANAProperties...)
+	res.PropertyOrder = e.PropertyOrder
+	return res
+}
+
+// filterToDo returns copy of todo which has only properties p allows
+func filterToDo(todo *ical.ToDo, p ical.Profile) *ical.ToDo {
+	allows := func(n property.Name) bool { return p.Allows(component.TypeTODO, n) }
+	res := ical.NewToDo()
+	if allows(property.NameUID) {
+		res.UID = todo.UID
+	}
+	if allows(property.NameDateTimeStamp) {
+		res.DateTimeStamp = todo.DateTimeStamp
+	}
+	if allows(property.NameClass) {
+		res.Class = todo.Class
+	}
+	if allows(property.NameDateTimeCompleted) {
+		res.DateTimeCompleted = todo.DateTimeCompleted
+	}
+	if allows(property.NameDateTimeCreated) {
+		res.DateTimeCreated = todo.DateTimeCreated
+	}
+	if allows(property.NameDescription) {
+		res.Description = todo.Description
+	}
+	if allows(property.NameDateTimeStart) {
+		res.DateTimeStart = todo.DateTimeStart
+	}
+	if allows(property.NameGeo) {
+		res.Geo = todo.Geo
+	}
+	if allows(property.NameLastModified) {
+		res.LastModified = todo.LastModified
+	}
+	if allows(property.NameLocation) {
+		res.Location = todo.Location
+	}
+	if allows(property.NameOrganizer) {
+		res.Organizer = todo.Organizer
+	}
+	if allows(property.NamePercentComplete) {
+		res.PercentComplete = todo.PercentComplete
+	}
+	if allows(property.NamePriority) {
+		res.Priority = todo.Priority
+	}
+	if allows(property.NameRecurrenceID) {
+		res.RecurrenceID = todo.RecurrenceID
+	}
+	if allows(property.NameSequenceNumber) {
+		res.SequenceNumber = todo.SequenceNumber
+	}
+	if allows(property.NameStatus) {
+		res.Status = todo.Status
+	}
+	if allows(property.NameSummary) {
+		res.Summary = todo.Summary
+	}
+	if allows(property.NameURL) {
+		res.URL = todo.URL
+	}
+	if allows(property.NameRecurrenceRule) {
+		res.RecurrenceRule = todo.RecurrenceRule
+	}
+	if allows(property.NameDateTimeDue) {
+		res.DateTimeDue = todo.DateTimeDue
+	}
+	if allows(property.NameDuration) {
+		res.Duration = todo.Duration
+	}
+	if allows(property.NameAttachment) {
+		res.Attachments = append(res.Attachments, todo.Attachments...)
+	}
+	if allows(property.NameAttendee) {
+		res.Attendees = append(res.Attendees, todo.Attendees...)
+	}
+	if allows(property.NameCategories) {
+		res.Categories = append(res.Categories, todo.Categories...)
+	}
+	if allows(property.NameComment) {
+		res.Comments = append(res.Comments, todo.Comments...)
+	}
+	if allows(property.NameContact) {
+		res.Contacts = append(res.Contacts, todo.Contacts...)
+	}
+	if allows(property.NameExceptionDateTimes) {
+		res.ExceptionDateTimes = append(res.ExceptionDateTimes, todo.ExceptionDateTimes...)
+	}
+	if allows(property.NameRequestStatus) {
+		res.RequestStatus = append(res.RequestStatus, todo.RequestStatus...)
+	}
+	if allows(property.NameRelatedTo) {
+		res.RelatedTos = append(res.RelatedTos, todo.RelatedTos...)
+	}
+	if allows(property.NameResources) {
+		res.Resources = append(res.Resources, todo.Resources...)
+	}
+	if allows(property.NameRecurrenceDateTimes) {
+		res.RecurrenceDateTimes = append(res.RecurrenceDateTimes, todo.RecurrenceDateTimes...)
+	}
+	if p.AllowsAlarms(component.TypeTODO) {
+		res.Alarms = append(res.Alarms, todo.Alarms...)
+	}
+	res.XProperties = append(res.XProperties, todo.XProperties...)
+	res.IANAProperties = append(res.IANAProperties, todo.IANAProperties...)
+	res.PropertyOrder = todo.PropertyOrder
+	return res
+}
@@ -0,0 +1,437 @@
+package jcal
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/knsh14/ical"
+	"github.com/knsh14/ical/contentline"
+	"github.com/knsh14/ical/lexer"
+	"github.com/knsh14/ical/parameter"
+	"github.com/knsh14/ical/parser"
+	"github.com/knsh14/ical/property"
+	"github.com/knsh14/ical/types"
+)
+
+// Marshal returns jCal of c.
+// each property is [name, parameters, type, values...] and each component is [name, properties, components].
+// https://tools.ietf.org/html/rfc7265#section-3
+func Marshal(c *ical.Calendar) ([]byte, error) {
+	v, err := ToJSON(c)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// ToJSON returns jCal of c as value which encoding/json can marshal
+func ToJSON(c *ical.Calendar) ([]interface{}, error) {
+	b := &bytes.Buffer{}
+	if err := ical.NewEncoder(b).Encode(c); err != nil {
+		return nil, fmt.Errorf("encode calendar: %w", err)
+	}
+	lines, err := contentline.Unfold(b)
+	if err != nil {
+		return nil, err
+	}
+
+	type component struct {
+		name       string
+		properties []interface{}
+		components []interface{}
+	}
+	array := func(c *component) []interface{} {
+		return []interface{}{c.name, c.properties, c.components}
+	}
+	var (
+		stack []*component
+		root  []interface{}
+	)
+	for _, l := range lines {
+		cl, err := contentline.ConvertContentLine(lexer.New(l))
+		if err != nil {
+			return nil, fmt.Errorf("convert content line %q: %w", l, err)
+		}
+		switch property.Name(strings.ToUpper(cl.Name)) {
+		case property.NameBegin:
+			stack = append(stack, &component{
+				name:       strings.ToLower(strings.Join(cl.Values, ",")),
+				properties: []interface{}{},
+				components: []interface{}{},
+			})
+		case property.NameEnd:
+			n := len(stack)
+			if n == 0 {
+				return nil, fmt.Errorf("END without BEGIN")
+			}
+			c := array(stack[n-1])
+			stack = stack[:n-1]
+			if n == 1 {
+				root = c
+				continue
+			}
+			stack[n-2].components = append(stack[n-2].components, c)
+		default:
+			if len(stack) == 0 {
+				return nil, fmt.Errorf("property %s is out of component", cl.Name)
+			}
+			p, err := marshalProperty(cl)
+			if err != nil {
+				return nil, fmt.Errorf("property %s: %w", cl.Name, err)
+			}
+			top := stack[len(stack)-1]
+			top.properties = append(top.properties, p)
+		}
+	}
+	if root == nil {
+		return nil, fmt.Errorf("VCALENDAR is not closed")
+	}
+	return root, nil
+}
+
+func marshalProperty(cl *contentline.ContentLine) ([]interface{}, error) {
+	name := property.Name(strings.ToUpper(cl.Name))
+	t := property.DefaultValueType(name)
+	params := map[string]interface{}{}
+	for _, p := range cl.Parameters {
+		if strings.EqualFold(p.Name, "VALUE") && len(p.Values) == 1 {
+			t = types.ValueType(strings.ToUpper(p.Values[0]))
+			continue
+		}
+		if len(p.Values) == 1 {
+			params[strings.ToLower(p.Name)] = p.Values[0]
+			continue
+		}
+		values := make([]interface{}, 0, len(p.Values))
+		for _, v := range p.Values {
+			values = append(values, v)
+		}
+		params[strings.ToLower(p.Name)] = values
+	}
+	res := []interface{}{strings.ToLower(string(name)), params, strings.ToLower(string(t))}
+
+	raw := strings.Join(cl.Values, ",")
+	switch {
+	case t == types.ValueTypeUnknown:
+		return append(res, raw), nil
+	case t == types.ValueTypeRecurrenceRule:
+		v, err := marshalRecurrenceRule(raw)
+		if err != nil {
+			return nil, err
+		}
+		return append(res, v), nil
+	case isStructured(name):
+		var values []interface{}
+		for _, s := range property.SplitEscaped(raw, ';', -1) {
+			v, err := marshalValue(t, s)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, v)
+		}
+		return append(res, values), nil
+	}
+	for _, s := range cl.Values {
+		v, err := marshalValue(t, s)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, v)
+	}
+	return res, nil
+}
+
+// marshalValue returns JSON value of v in iCalendar format
+// https://tools.ietf.org/html/rfc7265#section-3.6
+func marshalValue(t types.ValueType, v string) (interface{}, error) {
+	switch t {
+	case types.ValueTypeText:
+		return string(types.UnescapeText(v)), nil
+	case types.ValueTypeBoolean:
+		return strings.EqualFold(v, "TRUE"), nil
+	case types.ValueTypeInteger:
+		if _, err := strconv.ParseInt(v, 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid INTEGER %s: %w", v, err)
+		}
+		return json.Number(strings.TrimPrefix(v, "+")), nil
+	case types.ValueTypeFloat:
+		if _, err := strconv.ParseFloat(v, 64); err != nil {
+			return nil, fmt.Errorf("invalid FLOAT %s: %w", v, err)
+		}
+		return json.Number(strings.TrimPrefix(v, "+")), nil
+	}
+	return types.ToExtended(t, v)
+}
+
+// recurrenceRuleNumbers is rule parts whose values are integer
+var recurrenceRuleNumbers = map[string]bool{
+	"COUNT":      true,
+	"INTERVAL":   true,
+	"BYSECOND":   true,
+	"BYMINUTE":   true,
+	"BYHOUR":     true,
+	"BYMONTHDAY": true,
+	"BYYEARDAY":  true,
+	"BYWEEKNO":   true,
+	"BYMONTH":    true,
+	"BYSETPOS":   true,
+}
+
+// marshalRecurrenceRule returns RECUR value as JSON object.
+// rule part which has multiple values is array.
+// https://tools.ietf.org/html/rfc7265#section-3.6.10
+func marshalRecurrenceRule(v string) (map[string]interface{}, error) {
+	res := map[string]interface{}{}
+	for _, part := range strings.Split(v, ";") {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid rule part %s", part)
+		}
+		key := strings.ToUpper(kv[0])
+		var values []interface{}
+		for _, s := range strings.Split(kv[1], ",") {
+			switch {
+			case recurrenceRuleNumbers[key]:
+				n, err := strconv.Atoi(s)
+				if err != nil {
+					return nil, fmt.Errorf("invalid %s %s: %w", key, s, err)
+				}
+				values = append(values, n)
+			case key == "UNTIL":
+				t := types.ValueTypeDateTime
+				if !strings.Contains(s, "T") {
+					t = types.ValueTypeDate
+				}
+				u, err := types.ToExtended(t, s)
+				if err != nil {
+					return nil, err
+				}
+				values = append(values, u)
+			default:
+				values = append(values, s)
+			}
+		}
+		if len(values) == 1 {
+			res[strings.ToLower(key)] = values[0]
+			continue
+		}
+		res[strings.ToLower(key)] = values
+	}
+	return res, nil
+}
+
+// Unmarshal parses jCal b by parser.Parse
+func Unmarshal(b []byte, opts ...parser.Option) (*ical.Calendar, error) {
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	var v interface{}
+	if err := d.Decode(&v); err != nil {
+		return nil, fmt.Errorf("decode json: %w", err)
+	}
+	return FromJSON(v, opts...)
+}
+
+// FromJSON parses jCal v which is decoded by encoding/json
+func FromJSON(v interface{}, opts ...parser.Option) (*ical.Calendar, error) {
+	buf := &bytes.Buffer{}
+	if err := writeComponent(buf, v); err != nil {
+		return nil, err
+	}
+	return parser.Parse(buf, opts...)
+}
+
+func writeComponent(buf *bytes.Buffer, v interface{}) error {
+	c, ok := v.([]interface{})
+	if !ok || len(c) != 3 {
+		return fmt.Errorf("component must be array of name, properties and components, but %v", v)
+	}
+	name, ok := c[0].(string)
+	if !ok {
+		return fmt.Errorf("component name must be string, but %v", c[0])
+	}
+	props, ok := c[1].([]interface{})
+	if !ok {
+		return fmt.Errorf("properties of %s must be array, but %v", name, c[1])
+	}
+	components, ok := c[2].([]interface{})
+	if !ok {
+		return fmt.Errorf("components of %s must be array, but %v", name, c[2])
+	}
+
+	name = strings.ToUpper(name)
+	buf.WriteString(contentline.Fold(fmt.Sprintf("%s:%s", property.NameBegin, name)))
+	for _, p := range props {
+		l, err := unmarshalProperty(p)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		buf.WriteString(contentline.Fold(l))
+	}
+	for _, sub := range components {
+		if err := writeComponent(buf, sub); err != nil {
+			return err
+		}
+	}
+	buf.WriteString(contentline.Fold(fmt.Sprintf("%s:%s", property.NameEnd, name)))
+	return nil
+}
+
+// unmarshalProperty returns content line of property p
+func unmarshalProperty(v interface{}) (string, error) {
+	p, ok := v.([]interface{})
+	if !ok || len(p) < 4 {
+		return "", fmt.Errorf("property must be array of name, parameters, type and values, but %v", v)
+	}
+	n, ok := p[0].(string)
+	if !ok {
+		return "", fmt.Errorf("property name must be string, but %v", p[0])
+	}
+	name := property.Name(strings.ToUpper(n))
+	params, ok := p[1].(map[string]interface{})
+	if !ok {
+		return "", fmt.Errorf("parameters of %s must be object, but %v", name, p[1])
+	}
+	tn, ok := p[2].(string)
+	if !ok {
+		return "", fmt.Errorf("type of %s must be string, but %v", name, p[2])
+	}
+	t := types.ValueType(strings.ToUpper(tn))
+
+	var b strings.Builder
+	b.WriteString(string(name))
+	// VALUE is the first parameter, position of it is not kept in jCal
+	if t != property.DefaultValueType(name) && t != types.ValueTypeUnknown {
+		fmt.Fprintf(&b, ";VALUE=%s", t)
+	}
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		var values []string
+		switch pv := params[k].(type) {
+		case string:
+			v, err := parameter.QuoteIfNeeded(pv)
+			if err != nil {
+				return "", fmt.Errorf("parameter %s of %s: %w", k, name, err)
+			}
+			values = append(values, v)
+		case []interface{}:
+			for _, e := range pv {
+				s, ok := e.(string)
+				if !ok {
+					return "", fmt.Errorf("parameter %s of %s must be string, but %v", k, name, e)
+				}
+				v, err := parameter.QuoteIfNeeded(s)
+				if err != nil {
+					return "", fmt.Errorf("parameter %s of %s: %w", k, name, err)
+				}
+				values = append(values, v)
+			}
+		default:
+			return "", fmt.Errorf("parameter %s of %s must be string or array, but %v", k, name, pv)
+		}
+		fmt.Fprintf(&b, ";%s=%s", strings.ToUpper(k), strings.Join(values, ","))
+	}
+	b.WriteString(":")
+
+	var values []string
+	for _, pv := range p[3:] {
+		s, err := unmarshalValue(t, pv)
+		if err != nil {
+			return "", fmt.Errorf("value of %s: %w", name, err)
+		}
+		values = append(values, s)
+	}
+	b.WriteString(strings.Join(values, ","))
+	return b.String(), nil
+}
+
+// unmarshalValue returns v in iCalendar format
+func unmarshalValue(t types.ValueType, v interface{}) (string, error) {
+	switch tv := v.(type) {
+	case []interface{}:
+		// structured value
+		var parts []string
+		for _, e := range tv {
+			s, err := unmarshalValue(t, e)
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, s)
+		}
+		return strings.Join(parts, ";"), nil
+	case map[string]interface{}:
+		if t != types.ValueTypeRecurrenceRule {
+			return "", fmt.Errorf("object is allowed only for RECUR, but %s", t)
+		}
+		return unmarshalRecurrenceRule(tv)
+	case json.Number:
+		return tv.String(), nil
+	case float64:
+		return strconv.FormatFloat(tv, 'f', -1, 64), nil
+	case bool:
+		if tv {
+			return "TRUE", nil
+		}
+		return "FALSE", nil
+	case string:
+		switch t {
+		case types.ValueTypeText:
+			return types.NewText(tv).Escape(), nil
+		case types.ValueTypeUnknown:
+			return tv, nil
+		}
+		return types.FromExtended(t, tv), nil
+	}
+	return "", fmt.Errorf("invalid value %v", v)
+}
+
+// unmarshalRecurrenceRule returns RECUR value of JSON object, FREQ is the first rule part
+func unmarshalRecurrenceRule(v map[string]interface{}) (string, error) {
+	keys := make([]string, 0, len(v))
+	for k := range v {
+		keys = append(keys, strings.ToUpper(k))
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i] == "FREQ" || keys[j] == "FREQ" {
+			return keys[i] == "FREQ"
+		}
+		return keys[i] < keys[j]
+	})
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		values, ok := v[strings.ToLower(k)].([]interface{})
+		if !ok {
+			values = []interface{}{v[strings.ToLower(k)]}
+		}
+		var s []string
+		for _, e := range values {
+			switch ev := e.(type) {
+			case string:
+				if k == "UNTIL" {
+					ev = types.FromExtended(types.ValueTypeDateTime, ev)
+				}
+				s = append(s, ev)
+			case json.Number:
+				s = append(s, ev.String())
+			case float64:
+				s = append(s, strconv.FormatFloat(ev, 'f', -1, 64))
+			default:
+				return "", fmt.Errorf("invalid value of rule part %s: %v", k, e)
+			}
+		}
+		parts = append(parts, k+"="+strings.Join(s, ","))
+	}
+	return strings.Join(parts, ";"), nil
+}
+
+// isStructured returns true if value of property n has components separated by SEMICOLON
+func isStructured(n property.Name) bool {
+	return n == property.NameGeo || n == property.NameRequestStatus
+}
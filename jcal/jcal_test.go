@@ -0,0 +1,181 @@
+package jcal
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/knsh14/ical"
+	"github.com/knsh14/ical/contentline"
+	"github.com/knsh14/ical/parser"
+)
+
+func TestMarshal(t *testing.T) {
+	t.Parallel()
+	input := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"PRODID:-//Example Inc.//Example Calendar//EN",
+		"VERSION:2.0",
+		"BEGIN:VEVENT",
+		"UID:4088E990AD89CB3DBB484909",
+		"DTSTAMP:20080205T191224Z",
+		"DTSTART;VALUE=DATE:20081006",
+		"SUMMARY:Planning meeting\\, room 1",
+		"GEO:37.386013;-122.082932",
+		"CATEGORIES:MEETING,PLAN\\,NING",
+		"RRULE:FREQ=WEEKLY;UNTIL=20081231T000000Z;BYDAY=MO,WE;INTERVAL=2",
+		"ATTENDEE;CN=\"Doe, John\";DELEGATED-FROM=\"mailto:a@example.com\",\"mailto:b@example.com\":mailto:john@example.com",
+		"REQUEST-STATUS:2.0;Success",
+		"X-EXAMPLE;X-PARAM=1:raw,values",
+		"END:VEVENT",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n")
+	expected := `["vcalendar",
+	[
+		["prodid", {}, "text", "-//Example Inc.//Example Calendar//EN"],
+		["version", {}, "text", "2.0"]
+	],
+	[
+		["vevent",
+			[
+				["uid", {}, "text", "4088E990AD89CB3DBB484909"],
+				["dtstamp", {}, "date-time", "2008-02-05T19:12:24Z"],
+				["dtstart", {}, "date", "2008-10-06"],
+				["summary", {}, "text", "Planning meeting, room 1"],
+				["geo", {}, "float", [37.386013, -122.082932]],
+				["categories", {}, "text", "MEETING", "PLAN,NING"],
+				["rrule", {}, "recur", {"freq": "WEEKLY", "until": "2008-12-31T00:00:00Z", "byday": ["MO", "WE"], "interval": 2}],
+				["attendee", {"cn": "Doe, John", "delegated-from": ["mailto:a@example.com", "mailto:b@example.com"]}, "cal-address", "mailto:john@example.com"],
+				["request-status", {}, "text", ["2.0", "Success"]],
+				["x-example", {"x-param": "1"}, "unknown", "raw,values"]
+			],
+			[]
+		]
+	]
+]`
+
+	c, err := parser.Parse(strings.NewReader(input), parser.WithRoundTrip())
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want, gotValue interface{}
+	mustNoError(t, json.Unmarshal([]byte(expected), &want))
+	mustNoError(t, json.Unmarshal(got, &gotValue))
+	if diff := cmp.Diff(want, gotValue); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	back, err := Unmarshal(got, parser.WithRoundTrip())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(encode(t, c), encode(t, back)); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	t.Parallel()
+	files, err := filepath.Glob(filepath.Join("..", "parser", "testdata", "roundtrip", "*.ics"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("no test data")
+	}
+	for _, file := range files {
+		file := file
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			t.Parallel()
+			c, err := parser.ParseFile(file, parser.WithRoundTrip())
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := Marshal(c)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := Unmarshal(b, parser.WithRoundTrip())
+			if err != nil {
+				t.Fatal(err)
+			}
+			// jCal parameters are JSON object which does not keep order of them
+			if diff := cmp.Diff(sortParameters(t, encode(t, c)), sortParameters(t, encode(t, got))); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+// sortParameters returns unfolded content lines of s whose parameters are sorted
+func sortParameters(t *testing.T, s string) []string {
+	t.Helper()
+	lines, err := contentline.Unfold(strings.NewReader(s))
+	mustNoError(t, err)
+	for i, l := range lines {
+		var parts []string
+		quoted, start := false, 0
+	scan:
+		for j, r := range l {
+			switch {
+			case r == '"':
+				quoted = !quoted
+			case quoted:
+			case r == ';':
+				parts = append(parts, l[start:j])
+				start = j + 1
+			case r == ':':
+				parts = append(parts, l[start:j])
+				start = j
+				break scan
+			}
+		}
+		sort.Strings(parts[1:])
+		lines[i] = strings.Join(parts, ";") + l[start:]
+	}
+	return lines
+}
+
+func TestUnmarshal_Error(t *testing.T) {
+	t.Parallel()
+	testcases := map[string]string{
+		"not array":           `{"vcalendar": []}`,
+		"no components":       `["vcalendar", []]`,
+		"property is short":   `["vcalendar", [["version", {}, "text"]], []]`,
+		"invalid parameters":  `["vcalendar", [["version", [], "text", "2.0"]], []]`,
+		"object for text":     `["vcalendar", [["version", {}, "text", {"a": 1}]], []]`,
+		"dquote in parameter": `["vcalendar", [["version", {"x-a": "a\\"b"}, "text", "2.0"]], []]`,
+	}
+	for title, input := range testcases {
+		input := input
+		t.Run(title, func(t *testing.T) {
+			t.Parallel()
+			if _, err := Unmarshal([]byte(input)); err == nil {
+				t.Fatal("expected error but got nil")
+			}
+		})
+	}
+}
+
+func encode(t *testing.T, c *ical.Calendar) string {
+	t.Helper()
+	b := &bytes.Buffer{}
+	mustNoError(t, ical.NewEncoder(b).Encode(c))
+	return b.String()
+}
+
+func mustNoError(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,402 @@
+package ical
+
+import (
+	"io"
+
+	"github.com/knsh14/ical/component"
+	"github.com/knsh14/ical/parameter"
+	"github.com/knsh14/ical/property"
+	"github.com/knsh14/ical/types"
+)
+
+func NewJournal() *Journal {
+	return &Journal{}
+}
+
+// Journal is VJOURNAL
+// https://tools.ietf.org/html/rfc5545#section-3.6.3
+type Journal struct {
+	// required fields
+	UID           *property.UID
+	DateTimeStamp *property.DateTimeStamp
+
+	Class           *property.Class
+	DateTimeCreated *property.DateTimeCreated
+	DateTimeStart   *property.DateTimeStart
+	LastModified    *property.LastModified
+	Organizer       *property.Organizer
+	RecurrenceID    *property.RecurrenceID
+	SequenceNumber  *property.SequenceNumber
+	Status          *property.Status
+	Summary         *property.Summary
+	URL             *property.URL
+
+	// The following is OPTIONAL,
+	// but SHOULD NOT occur more than once.
+	RecurrenceRule *property.RecurrenceRule
+
+	Attachments         []*property.Attachment
+	Attendees           []*property.Attendee
+	Categories          []*property.Categories
+	Comments            []*property.Comment
+	Contacts            []*property.Contact
+	Descriptions        []*property.Description
+	ExceptionDateTimes  []*property.ExceptionDateTimes
+	RelatedTos          []*property.RelatedTo
+	RecurrenceDateTimes []*property.RecurrenceDateTimes
+	RequestStatus       []*property.RequestStatus
+
+	XProperties    []*property.NonStandard
+	IANAProperties []*property.IANA
+
+	// PropertyOrder is order of property names in parsed stream.
+	// Decode writes properties in this order if it is set.
+	PropertyOrder []property.Name
+}
+
+func (j *Journal) implementCalender() {}
+
+func (j *Journal) Decode(w io.Writer) error {
+	if err := encodeBegin(w, component.TypeJournal); err != nil {
+		return err
+	}
+	pw := newPropertyWriter(w, j.PropertyOrder)
+	if j.UID != nil {
+		pw.add(property.NameUID, j.UID)
+	}
+	if j.DateTimeStamp != nil {
+		pw.add(property.NameDateTimeStamp, j.DateTimeStamp)
+	}
+	if j.Class != nil {
+		pw.add(property.NameClass, j.Class)
+	}
+	if j.DateTimeCreated != nil {
+		pw.add(property.NameDateTimeCreated, j.DateTimeCreated)
+	}
+	if j.DateTimeStart != nil {
+		pw.add(property.NameDateTimeStart, j.DateTimeStart)
+	}
+	if j.LastModified != nil {
+		pw.add(property.NameLastModified, j.LastModified)
+	}
+	if j.Organizer != nil {
+		pw.add(property.NameOrganizer, j.Organizer)
+	}
+	if j.RecurrenceID != nil {
+		pw.add(property.NameRecurrenceID, j.RecurrenceID)
+	}
+	if j.SequenceNumber != nil {
+		pw.add(property.NameSequenceNumber, j.SequenceNumber)
+	}
+	if j.Status != nil {
+		pw.add(property.NameStatus, j.Status)
+	}
+	if j.Summary != nil {
+		pw.add(property.NameSummary, j.Summary)
+	}
+	if j.URL != nil {
+		pw.add(property.NameURL, j.URL)
+	}
+	if j.RecurrenceRule != nil {
+		pw.add(property.NameRecurrenceRule, j.RecurrenceRule)
+	}
+	for _, v := range j.Attachments {
+		pw.add(property.NameAttachment, v)
+	}
+	for _, v := range j.Attendees {
+		pw.add(property.NameAttendee, v)
+	}
+	for _, v := range j.Categories {
+		pw.add(property.NameCategories, v)
+	}
+	for _, v := range j.Comments {
+		pw.add(property.NameComment, v)
+	}
+	for _, v := range j.Contacts {
+		pw.add(property.NameContact, v)
+	}
+	for _, v := range j.Descriptions {
+		pw.add(property.NameDescription, v)
+	}
+	for _, v := range j.ExceptionDateTimes {
+		pw.add(property.NameExceptionDateTimes, v)
+	}
+	for _, v := range j.RelatedTos {
+		pw.add(property.NameRelatedTo, v)
+	}
+	for _, v := range j.RecurrenceDateTimes {
+		pw.add(property.NameRecurrenceDateTimes, v)
+	}
+	for _, v := range j.RequestStatus {
+		pw.add(property.NameRequestStatus, v)
+	}
+	for _, v := range j.XProperties {
+		pw.add(property.Name(v.Name), v)
+	}
+	for _, v := range j.IANAProperties {
+		pw.add(property.Name(v.Name), v)
+	}
+	if err := pw.flush(); err != nil {
+		return err
+	}
+	return encodeEnd(w, component.TypeJournal)
+}
+
+func (j *Journal) Validate() error {
+	if j.UID == nil {
+		return NewValidationError(component.TypeJournal, property.NameUID, "must not to be nil")
+	}
+	if j.UID.Value == "" {
+		return NewValidationError(component.TypeJournal, property.NameUID, "must not to be empty")
+	}
+	if j.DateTimeStamp == nil {
+		return NewValidationError(component.TypeJournal, property.NameDateTimeStamp, "must not to be nil")
+	}
+	return nil
+}
+
+func (j *Journal) SetUID(params parameter.Container, value types.Text) error {
+	if j.UID != nil {
+		return j.UID.SetUID(params, value)
+	}
+	uid := &property.UID{}
+	if err := uid.SetUID(params, value); err != nil {
+		return err
+	}
+	j.UID = uid
+	return nil
+}
+
+func (j *Journal) SetDateTimeStamp(params parameter.Container, value types.DateTime) error {
+	if j.DateTimeStamp != nil {
+		return j.DateTimeStamp.SetDateTimeStamp(params, value)
+	}
+	dts := &property.DateTimeStamp{}
+	if err := dts.SetDateTimeStamp(params, value); err != nil {
+		return err
+	}
+	j.DateTimeStamp = dts
+	return nil
+}
+
+func (j *Journal) SetDateTimeStart(params parameter.Container, value types.TimeValue) error {
+	if j.DateTimeStart != nil {
+		return j.DateTimeStart.SetStart(params, value)
+	}
+	dts := &property.DateTimeStart{}
+	if err := dts.SetStart(params, value); err != nil {
+		return err
+	}
+	j.DateTimeStart = dts
+	return nil
+}
+
+func (j *Journal) SetClass(params parameter.Container, value types.Text) error {
+	if j.Class != nil {
+		return j.Class.SetClass(params, value)
+	}
+	c := &property.Class{}
+	if err := c.SetClass(params, value); err != nil {
+		return err
+	}
+	j.Class = c
+	return nil
+}
+
+func (j *Journal) SetDateTimeCreated(params parameter.Container, value types.DateTime) error {
+	if j.DateTimeCreated != nil {
+		return j.DateTimeCreated.SetDateTimeCreated(params, value)
+	}
+	dtc := &property.DateTimeCreated{}
+	if err := dtc.SetDateTimeCreated(params, value); err != nil {
+		return err
+	}
+	j.DateTimeCreated = dtc
+	return nil
+}
+
+func (j *Journal) SetLastModified(params parameter.Container, value types.DateTime) error {
+	if j.LastModified != nil {
+		return j.LastModified.SetLastModified(params, value)
+	}
+	lm := &property.LastModified{}
+	if err := lm.SetLastModified(params, value); err != nil {
+		return err
+	}
+	j.LastModified = lm
+	return nil
+}
+
+func (j *Journal) SetOrganizer(params parameter.Container, value types.CalenderUserAddress) error {
+	if j.Organizer != nil {
+		return j.Organizer.SetOrganizer(params, value)
+	}
+	o := &property.Organizer{}
+	if err := o.SetOrganizer(params, value); err != nil {
+		return err
+	}
+	j.Organizer = o
+	return nil
+}
+
+func (j *Journal) SetRecurrenceID(params parameter.Container, value types.TimeValue) error {
+	if j.RecurrenceID != nil {
+		return j.RecurrenceID.SetRecurrenceID(params, value)
+	}
+	rid := &property.RecurrenceID{}
+	if err := rid.SetRecurrenceID(params, value); err != nil {
+		return err
+	}
+	j.RecurrenceID = rid
+	return nil
+}
+
+func (j *Journal) SetSequenceNumber(params parameter.Container, value types.Integer) error {
+	if j.SequenceNumber != nil {
+		return j.SequenceNumber.SetSequenceNumber(params, value)
+	}
+	sn := &property.SequenceNumber{}
+	if err := sn.SetSequenceNumber(params, value); err != nil {
+		return err
+	}
+	j.SequenceNumber = sn
+	return nil
+}
+
+func (j *Journal) SetStatus(params parameter.Container, value types.Text) error {
+	if j.Status != nil {
+		return j.Status.SetStatus(params, value, component.TypeJournal)
+	}
+	s := &property.Status{}
+	if err := s.SetStatus(params, value, component.TypeJournal); err != nil {
+		return err
+	}
+	j.Status = s
+	return nil
+}
+
+func (j *Journal) SetSummary(params parameter.Container, value types.Text) error {
+	if j.Summary != nil {
+		return j.Summary.SetSummary(params, value)
+	}
+	s := &property.Summary{}
+	if err := s.SetSummary(params, value); err != nil {
+		return err
+	}
+	j.Summary = s
+	return nil
+}
+
+func (j *Journal) SetURL(params parameter.Container, value types.URI) error {
+	if j.URL != nil {
+		return j.URL.SetURL(params, value)
+	}
+	url := &property.URL{}
+	if err := url.SetURL(params, value); err != nil {
+		return err
+	}
+	j.URL = url
+	return nil
+}
+
+func (j *Journal) SetRecurrenceRule(params parameter.Container, value types.RecurrenceRule) error {
+	if j.RecurrenceRule != nil {
+		return j.RecurrenceRule.SetRecurrenceRule(params, value)
+	}
+	rr := &property.RecurrenceRule{}
+	if err := rr.SetRecurrenceRule(params, value); err != nil {
+		return err
+	}
+	j.RecurrenceRule = rr
+	return nil
+}
+
+func (j *Journal) AddAttachment(params parameter.Container, value types.AttachmentValue) error {
+	a := &property.Attachment{}
+	if err := a.SetAttachment(params, value); err != nil {
+		return err
+	}
+	j.Attachments = append(j.Attachments, a)
+	return nil
+}
+
+func (j *Journal) AddAttendee(params parameter.Container, value types.CalenderUserAddress) error {
+	a := &property.Attendee{}
+	if err := a.SetAttendee(params, value); err != nil {
+		return err
+	}
+	j.Attendees = append(j.Attendees, a)
+	return nil
+}
+
+func (j *Journal) AddCategories(params parameter.Container, values []types.Text) error {
+	c := &property.Categories{}
+	if err := c.SetCategories(params, values); err != nil {
+		return err
+	}
+	j.Categories = append(j.Categories, c)
+	return nil
+}
+
+func (j *Journal) AddComment(params parameter.Container, value types.Text) error {
+	c := &property.Comment{}
+	if err := c.SetComment(params, value); err != nil {
+		return err
+	}
+	j.Comments = append(j.Comments, c)
+	return nil
+}
+
+func (j *Journal) AddContact(params parameter.Container, value types.Text) error {
+	c := &property.Contact{}
+	if err := c.SetContact(params, value); err != nil {
+		return err
+	}
+	j.Contacts = append(j.Contacts, c)
+	return nil
+}
+
+func (j *Journal) AddDescription(params parameter.Container, value types.Text) error {
+	d := &property.Description{}
+	if err := d.SetDescription(params, value); err != nil {
+		return err
+	}
+	j.Descriptions = append(j.Descriptions, d)
+	return nil
+}
+
+func (j *Journal) AddExceptionDateTimes(params parameter.Container, values []types.TimeValue) error {
+	edt := &property.ExceptionDateTimes{}
+	if err := edt.SetExceptionDateTimes(params, values); err != nil {
+		return err
+	}
+	j.ExceptionDateTimes = append(j.ExceptionDateTimes, edt)
+	return nil
+}
+
+func (j *Journal) AddRelatedTo(params parameter.Container, value types.Text) error {
+	rt := &property.RelatedTo{}
+	if err := rt.SetRelatedTo(params, value); err != nil {
+		return err
+	}
+	j.RelatedTos = append(j.RelatedTos, rt)
+	return nil
+}
+
+func (j *Journal) AddRecurrenceDateTimes(params parameter.Container, values []types.RecurrenceDateTimeValue) error {
+	rdt := &property.RecurrenceDateTimes{}
+	if err := rdt.SetRecurrenceDateTimes(params, values); err != nil {
+		return err
+	}
+	j.RecurrenceDateTimes = append(j.RecurrenceDateTimes, rdt)
+	return nil
+}
+
+func (j *Journal) AddRequestStatus(params parameter.Container, value types.Text) error {
+	rs := &property.RequestStatus{}
+	if err := rs.SetRequestStatus(params, value); err != nil {
+		return err
+	}
+	j.RequestStatus = append(j.RequestStatus, rs)
+	return nil
+}
@@ -0,0 +1,862 @@
+package jscalendar
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/knsh14/ical/component"
+	"github.com/knsh14/ical/contentline"
+	"github.com/knsh14/ical/lexer"
+	"github.com/knsh14/ical/parameter"
+	"github.com/knsh14/ical/property"
+	"github.com/knsh14/ical/types"
+)
+
+const (
+	// propertyNameJSProperty is property which has JSCalendar property iCalendar can not represent
+	// and parameterNameJSONPointer is its JSON pointer.
+	// https://www.rfc-editor.org/rfc/rfc9555#section-4.1
+	propertyNameJSProperty   property.Name      = "JSPROP"
+	parameterNameJSONPointer parameter.TypeName = "JSPTR"
+
+	// https://tools.ietf.org/html/rfc7986#section-5.9
+	propertyNameColor property.Name = "COLOR"
+
+	timeZoneUTC                 = "Etc/UTC"
+	recurrenceOverridesProperty = "recurrenceOverrides"
+	// endLocationID is id of location which has time zone of the end
+	endLocationID = "end"
+)
+
+// node is component in content lines
+type node struct {
+	name     component.Type
+	lines    []*line
+	children []*node
+	// raw is all lines of the component including BEGIN and END
+	raw []string
+}
+
+type line struct {
+	raw string
+	*contentline.ContentLine
+}
+
+func (l *line) name() property.Name {
+	return property.Name(strings.ToUpper(l.Name))
+}
+
+func (l *line) value() string {
+	return strings.Join(l.Values, ",")
+}
+
+// params returns parameters of l if l does not have other parameters than names
+func (l *line) params(names ...parameter.TypeName) (map[parameter.TypeName]string, bool) {
+	res := map[parameter.TypeName]string{}
+	for _, p := range l.Parameters {
+		n := parameter.TypeName(strings.ToUpper(p.Name))
+		found := false
+		for _, name := range names {
+			found = found || n == name
+		}
+		if !found || len(p.Values) != 1 {
+			return nil, false
+		}
+		res[n] = p.Values[0]
+	}
+	return res, true
+}
+
+// parseNodes returns components in unfolded content lines
+func parseNodes(lines []string) ([]*node, error) {
+	var (
+		stack []*node
+		res   []*node
+	)
+	for _, l := range lines {
+		cl, err := contentline.ConvertContentLine(lexer.New(l))
+		if err != nil {
+			return nil, fmt.Errorf("convert content line %q: %w", l, err)
+		}
+		for _, n := range stack {
+			n.raw = append(n.raw, l)
+		}
+		switch property.Name(strings.ToUpper(cl.Name)) {
+		case property.NameBegin:
+			n := &node{name: component.Type(strings.ToUpper(strings.Join(cl.Values, ","))), raw: []string{l}}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, n)
+			} else {
+				res = append(res, n)
+			}
+			stack = append(stack, n)
+		case property.NameEnd:
+			if len(stack) == 0 {
+				return nil, fmt.Errorf("END without BEGIN")
+			}
+			stack = stack[:len(stack)-1]
+		default:
+			if len(stack) == 0 {
+				return nil, fmt.Errorf("property %s is out of component", cl.Name)
+			}
+			top := stack[len(stack)-1]
+			top.lines = append(top.lines, &line{raw: l, ContentLine: cl})
+		}
+	}
+	if len(stack) > 0 {
+		return nil, fmt.Errorf("%s is not closed", stack[0].name)
+	}
+	return res, nil
+}
+
+// zone is time zone and whether the value is DATE
+type zone struct {
+	timeZone string
+	date     bool
+}
+
+// localDateTime returns LocalDateTime of DATE or DATE-TIME value and its zone
+// https://tools.ietf.org/html/rfc8984#section-1.4.4
+func localDateTime(l *line) (string, zone, bool) {
+	params, ok := l.params(parameter.TypeNameValueType, parameter.TypeNameReferenceTimezone)
+	if !ok || len(l.Values) != 1 {
+		return "", zone{}, false
+	}
+	return localDateTimeValue(l.Values[0], params[parameter.TypeNameReferenceTimezone])
+}
+
+func localDateTimeValue(v, tzid string) (string, zone, bool) {
+	z := zone{timeZone: tzid}
+	if len(v) == 8 {
+		d, err := types.ToExtended(types.ValueTypeDate, v)
+		if err != nil || tzid != "" {
+			return "", zone{}, false
+		}
+		z.date = true
+		return d + "T00:00:00", z, true
+	}
+	if strings.HasSuffix(v, "Z") {
+		if tzid != "" {
+			return "", zone{}, false
+		}
+		z.timeZone = timeZoneUTC
+		v = strings.TrimSuffix(v, "Z")
+	}
+	dt, err := types.ToExtended(types.ValueTypeDateTime, v)
+	if err != nil {
+		return "", zone{}, false
+	}
+	return dt, z, true
+}
+
+// location returns time.Location of time zone. floating time is treated as UTC.
+func (z zone) location() (*time.Location, error) {
+	if z.timeZone == "" || z.timeZone == timeZoneUTC {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(z.timeZone)
+}
+
+// parse returns time of LocalDateTime v in z
+func (z zone) parse(v string) (time.Time, error) {
+	loc, err := z.location()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.ParseInLocation(localDateTimeLayout, v, loc)
+}
+
+const localDateTimeLayout = "2006-01-02T15:04:05"
+
+// fromICalendar converts VEVENT or VTODO to JSCalendar object
+type fromICalendar struct {
+	object map[string]interface{}
+	patch  PatchObject
+	raw    []string
+	zone   zone
+
+	// participantIDs is id of participants by lower cased address
+	participantIDs map[string]string
+}
+
+// fromComponents returns JSCalendar object of master component and its overridden instances
+func fromComponents(nodes []*node) (map[string]interface{}, error) {
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("component is not found")
+	}
+	master, patch, err := fromNode(nodes[0])
+	if err != nil {
+		return nil, err
+	}
+	if err := normalize(&master); err != nil {
+		return nil, err
+	}
+	if len(nodes) > 1 {
+		overrides, _ := master[recurrenceOverridesProperty].(map[string]interface{})
+		if overrides == nil {
+			overrides = map[string]interface{}{}
+		}
+		for _, n := range nodes[1:] {
+			o, p, err := fromNode(n)
+			if err != nil {
+				return nil, err
+			}
+			if err := apply(o, p); err != nil {
+				return nil, err
+			}
+			if err := normalize(&o); err != nil {
+				return nil, err
+			}
+			id, ok := o["recurrenceId"].(string)
+			if !ok {
+				return nil, fmt.Errorf("overridden instance of %v does not have RECURRENCE-ID which JSCalendar can represent", master["uid"])
+			}
+			delete(o, "recurrenceId")
+			delete(o, "recurrenceIdTimeZone")
+			overrides[id] = map[string]interface{}(diff(o, instance(master, id)))
+		}
+		master[recurrenceOverridesProperty] = overrides
+	}
+	if err := apply(master, patch); err != nil {
+		return nil, err
+	}
+	return master, normalize(&master)
+}
+
+// instance returns object of recurrence instance id of master before patched
+func instance(master map[string]interface{}, id string) map[string]interface{} {
+	res := map[string]interface{}{}
+	for k, v := range master {
+		switch k {
+		case "recurrenceRules", "excludedRecurrenceRules", recurrenceOverridesProperty:
+		default:
+			res[k] = v
+		}
+	}
+	if _, ok := master["start"]; ok {
+		res["start"] = id
+	}
+	return res
+}
+
+func fromNode(n *node) (map[string]interface{}, PatchObject, error) {
+	f := &fromICalendar{
+		object:         map[string]interface{}{},
+		patch:          PatchObject{},
+		participantIDs: map[string]string{},
+	}
+	switch n.name {
+	case component.TypeEvent:
+		f.object["@type"] = "Event"
+	case component.TypeTODO:
+		f.object["@type"] = "Task"
+	default:
+		return nil, nil, fmt.Errorf("%s can not be converted to JSCalendar", n.name)
+	}
+
+	// DTSTART or DUE decides time zone of other date-times
+	for _, name := range []property.Name{property.NameDateTimeStart, property.NameDateTimeDue} {
+		if l := find(n, name); l != nil {
+			if _, z, ok := localDateTime(l); ok {
+				f.zone = z
+				break
+			}
+		}
+	}
+
+	for _, l := range n.lines {
+		ok, err := f.property(n.name, l)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", l.name(), err)
+		}
+		if !ok {
+			f.raw = append(f.raw, l.raw)
+		}
+	}
+	for _, c := range n.children {
+		if c.name != component.TypeAlarm || !f.alarm(c) {
+			f.raw = append(f.raw, c.raw...)
+		}
+	}
+	if len(f.raw) > 0 {
+		f.object[VendorProperty] = f.raw
+	}
+	return f.object, f.patch, nil
+}
+
+func find(n *node, name property.Name) *line {
+	for _, l := range n.lines {
+		if l.name() == name {
+			return l
+		}
+	}
+	return nil
+}
+
+// property sets JSCalendar property of l and returns false if l can not be represented
+// https://www.rfc-editor.org/rfc/rfc9555#section-4
+func (f *fromICalendar) property(kind component.Type, l *line) (bool, error) {
+	o := f.object
+	switch l.name() {
+	case property.NameUID:
+		return f.set("uid", l, string(types.UnescapeText(l.value())))
+	case property.NameDateTimeStamp:
+		return f.setUTC("updated", l)
+	case property.NameDateTimeCreated:
+		return f.setUTC("created", l)
+	case property.NameSequenceNumber:
+		return f.setInt("sequence", l)
+	case property.NamePriority:
+		return f.setInt("priority", l)
+	case property.NamePercentComplete:
+		if kind != component.TypeTODO {
+			return false, nil
+		}
+		return f.setInt("percentComplete", l)
+	case property.NameSummary:
+		return f.setText("title", l)
+	case property.NameDescription:
+		return f.setText("description", l)
+	case propertyNameColor:
+		return f.set("color", l, l.value())
+	case property.NameClass:
+		privacy := map[string]string{"PUBLIC": "public", "PRIVATE": "private", "CONFIDENTIAL": "secret"}
+		return f.setEnum("privacy", l, privacy)
+	case property.NameTimeTransparency:
+		return f.setEnum("freeBusyStatus", l, map[string]string{"OPAQUE": "busy", "TRANSPARENT": "free"})
+	case property.NameStatus:
+		if kind == component.TypeTODO {
+			progress := map[string]string{"NEEDS-ACTION": "needs-action", "IN-PROCESS": "in-process", "COMPLETED": "completed", "CANCELLED": "cancelled"}
+			return f.setEnum("progress", l, progress)
+		}
+		status := map[string]string{"CONFIRMED": "confirmed", "TENTATIVE": "tentative", "CANCELLED": "cancelled"}
+		return f.setEnum("status", l, status)
+	case property.NameLocation:
+		if _, ok := l.params(); !ok {
+			return false, nil
+		}
+		if f.location()["name"] != nil {
+			return false, nil
+		}
+		f.location()["name"] = string(types.UnescapeText(l.value()))
+		return true, nil
+	case property.NameGeo:
+		parts := strings.Split(l.value(), ";")
+		if _, ok := l.params(); !ok || len(parts) != 2 || f.location()["coordinates"] != nil {
+			return false, nil
+		}
+		f.location()["coordinates"] = fmt.Sprintf("geo:%s,%s", parts[0], parts[1])
+		return true, nil
+	case property.NameURL:
+		if _, ok := l.params(); !ok {
+			return false, nil
+		}
+		f.addID("links", map[string]interface{}{"@type": "Link", "href": l.value()})
+		return true, nil
+	case property.NameAttachment:
+		params, ok := l.params(parameter.TypeNameFormatType)
+		if !ok {
+			return false, nil
+		}
+		link := map[string]interface{}{"@type": "Link", "href": l.value(), "rel": "enclosure"}
+		if ct, ok := params[parameter.TypeNameFormatType]; ok {
+			link["contentType"] = ct
+		}
+		f.addID("links", link)
+		return true, nil
+	case property.NameCategories:
+		if _, ok := l.params(); !ok {
+			return false, nil
+		}
+		keywords := f.child("keywords")
+		for _, v := range l.Values {
+			keywords[string(types.UnescapeText(v))] = true
+		}
+		return true, nil
+	case property.NameRelatedTo:
+		params, ok := l.params(parameter.TypeNameRelationshipType)
+		if !ok {
+			return false, nil
+		}
+		reltype := "PARENT"
+		if v, ok := params[parameter.TypeNameRelationshipType]; ok {
+			reltype = v
+		}
+		uid := string(types.UnescapeText(l.value()))
+		related := f.child("relatedTo")
+		r, ok := related[uid].(map[string]interface{})
+		if !ok {
+			r = map[string]interface{}{"@type": "Relation", "relation": map[string]interface{}{}}
+			related[uid] = r
+		}
+		r["relation"].(map[string]interface{})[strings.ToLower(reltype)] = true
+		return true, nil
+	case property.NameDateTimeStart:
+		v, z, ok := localDateTime(l)
+		if !ok || z != f.zone {
+			return false, nil
+		}
+		o["start"] = v
+		if z.timeZone != "" {
+			o["timeZone"] = z.timeZone
+		}
+		if z.date {
+			o["showWithoutTime"] = true
+		}
+		return true, nil
+	case property.NameDateTimeDue:
+		v, z, ok := localDateTime(l)
+		if !ok || z != f.zone || kind != component.TypeTODO {
+			return false, nil
+		}
+		o["due"] = v
+		if _, ok := o["start"]; !ok {
+			if z.timeZone != "" {
+				o["timeZone"] = z.timeZone
+			}
+			if z.date {
+				o["showWithoutTime"] = true
+			}
+		}
+		return true, nil
+	case property.NameDateTimeEnd:
+		return f.setDuration(l)
+	case property.NameDuration:
+		if _, ok := l.params(); !ok || kind != component.TypeEvent {
+			return false, nil
+		}
+		o["duration"] = l.value()
+		return true, nil
+	case property.NameRecurrenceID:
+		v, z, ok := localDateTime(l)
+		if !ok {
+			return false, nil
+		}
+		o["recurrenceId"] = v
+		if z.timeZone != "" {
+			o["recurrenceIdTimeZone"] = z.timeZone
+		}
+		return true, nil
+	case property.NameRecurrenceRule:
+		if _, ok := l.params(); !ok || o["recurrenceRules"] != nil {
+			return false, nil
+		}
+		r, ok := f.recurrenceRule(l.value())
+		if !ok {
+			return false, nil
+		}
+		o["recurrenceRules"] = []interface{}{r}
+		return true, nil
+	case property.NameRecurrenceDateTimes, property.NameExceptionDateTimes:
+		params, ok := l.params(parameter.TypeNameValueType, parameter.TypeNameReferenceTimezone)
+		if !ok {
+			return false, nil
+		}
+		var ids []string
+		for _, v := range l.Values {
+			id, z, ok := localDateTimeValue(v, params[parameter.TypeNameReferenceTimezone])
+			if !ok || z != f.zone {
+				return false, nil
+			}
+			ids = append(ids, id)
+		}
+		overrides := f.child(recurrenceOverridesProperty)
+		for _, id := range ids {
+			if l.name() == property.NameExceptionDateTimes {
+				overrides[id] = map[string]interface{}{"excluded": true}
+			} else {
+				overrides[id] = map[string]interface{}{}
+			}
+		}
+		return true, nil
+	case property.NameOrganizer:
+		params, ok := l.params(parameter.TypeNameCommonName)
+		if !ok {
+			return false, nil
+		}
+		p := f.participant(l.value())
+		p["roles"].(map[string]interface{})["owner"] = true
+		if cn, ok := params[parameter.TypeNameCommonName]; ok {
+			p["name"] = cn
+		}
+		o["replyTo"] = map[string]interface{}{"imip": l.value()}
+		return true, nil
+	case property.NameAttendee:
+		return f.attendee(l)
+	case propertyNameJSProperty:
+		params, ok := l.params(parameterNameJSONPointer)
+		if !ok {
+			return false, nil
+		}
+		var v interface{}
+		if err := json.Unmarshal([]byte(types.UnescapeText(l.value())), &v); err != nil {
+			return false, fmt.Errorf("unmarshal value of %s: %w", params[parameterNameJSONPointer], err)
+		}
+		f.patch[params[parameterNameJSONPointer]] = v
+		return true, nil
+	}
+	return false, nil
+}
+
+func (f *fromICalendar) set(key string, l *line, v interface{}) (bool, error) {
+	if _, ok := l.params(); !ok {
+		return false, nil
+	}
+	if _, ok := f.object[key]; ok {
+		return false, nil
+	}
+	f.object[key] = v
+	return true, nil
+}
+
+// setUTC sets UTCDateTime
+// https://tools.ietf.org/html/rfc8984#section-1.4.4
+func (f *fromICalendar) setUTC(key string, l *line) (bool, error) {
+	if !strings.HasSuffix(l.value(), "Z") {
+		return false, nil
+	}
+	v, err := types.ToExtended(types.ValueTypeDateTime, l.value())
+	if err != nil {
+		return false, nil
+	}
+	return f.set(key, l, v)
+}
+
+func (f *fromICalendar) setInt(key string, l *line) (bool, error) {
+	i, err := strconv.Atoi(l.value())
+	if err != nil {
+		return false, nil
+	}
+	return f.set(key, l, i)
+}
+
+func (f *fromICalendar) setEnum(key string, l *line, values map[string]string) (bool, error) {
+	v, ok := values[strings.ToUpper(l.value())]
+	if !ok {
+		return false, nil
+	}
+	return f.set(key, l, v)
+}
+
+// setText sets text and locale from LANGUAGE parameter
+func (f *fromICalendar) setText(key string, l *line) (bool, error) {
+	params, ok := l.params(parameter.TypeNameLanguage)
+	if !ok {
+		return false, nil
+	}
+	if lang, ok := params[parameter.TypeNameLanguage]; ok {
+		if locale, ok := f.object["locale"]; ok && locale != lang {
+			return false, nil
+		}
+		f.object["locale"] = lang
+	}
+	if _, ok := f.object[key]; ok {
+		return false, nil
+	}
+	f.object[key] = string(types.UnescapeText(l.value()))
+	return true, nil
+}
+
+// setDuration sets duration from DTSTART to DTEND.
+// DTEND in other time zone than DTSTART is duration between the instants and location of the end in its time zone.
+// https://www.rfc-editor.org/rfc/rfc9555#section-4
+func (f *fromICalendar) setDuration(l *line) (bool, error) {
+	end, z, ok := localDateTime(l)
+	start, hasStart := f.object["start"].(string)
+	if !ok || !hasStart || z.date != f.zone.date {
+		return false, nil
+	}
+	if z != f.zone && (z.timeZone == "" || f.zone.timeZone == "") {
+		// floating time can not be compared with time in a time zone
+		return false, nil
+	}
+	s, err := f.zone.parse(start)
+	if err != nil {
+		return false, nil
+	}
+	e, err := z.parse(end)
+	if err != nil || e.Before(s) {
+		return false, nil
+	}
+	var d types.Duration
+	if z.date {
+		d.Day = days(s, e)
+	} else {
+		d.HourDuration = e.Sub(s)
+	}
+	f.object["duration"] = d.String()
+	if z != f.zone {
+		f.child("locations")[endLocationID] = map[string]interface{}{"@type": "Location", "relativeTo": "end", "timeZone": z.timeZone}
+	}
+	return true, nil
+}
+
+// days returns number of calendar days from s to e
+func days(s, e time.Time) int64 {
+	sd := time.Date(s.Year(), s.Month(), s.Day(), 0, 0, 0, 0, time.UTC)
+	ed := time.Date(e.Year(), e.Month(), e.Day(), 0, 0, 0, 0, time.UTC)
+	return int64(ed.Sub(sd) / (24 * time.Hour))
+}
+
+// child returns object of key, creating it if it does not exist
+func (f *fromICalendar) child(key string) map[string]interface{} {
+	m, ok := f.object[key].(map[string]interface{})
+	if !ok {
+		m = map[string]interface{}{}
+		f.object[key] = m
+	}
+	return m
+}
+
+// addID adds v to map of key with new id
+func (f *fromICalendar) addID(key string, v interface{}) string {
+	m := f.child(key)
+	id := strconv.Itoa(len(m) + 1)
+	m[id] = v
+	return id
+}
+
+// location returns the location which LOCATION and GEO are set
+func (f *fromICalendar) location() map[string]interface{} {
+	locations := f.child("locations")
+	l, ok := locations["1"].(map[string]interface{})
+	if !ok {
+		l = map[string]interface{}{"@type": "Location"}
+		locations["1"] = l
+	}
+	return l
+}
+
+// participant returns participant of address, creating it if it does not exist
+func (f *fromICalendar) participant(address string) map[string]interface{} {
+	key := strings.ToLower(address)
+	if id, ok := f.participantIDs[key]; ok {
+		return f.child("participants")[id].(map[string]interface{})
+	}
+	p := map[string]interface{}{
+		"@type":  "Participant",
+		"sendTo": map[string]interface{}{"imip": address},
+		"roles":  map[string]interface{}{},
+	}
+	f.participantIDs[key] = f.addID("participants", p)
+	return p
+}
+
+var (
+	calendarUserTypes = map[string]string{"INDIVIDUAL": "individual", "GROUP": "group", "RESOURCE": "resource", "ROOM": "location"}
+	participantRoles  = map[string][]string{
+		"REQ-PARTICIPANT": {"attendee"},
+		"OPT-PARTICIPANT": {"attendee", "optional"},
+		"NON-PARTICIPANT": {"informational"},
+		"CHAIR":           {"attendee", "chair"},
+	}
+	participationStatuses = map[string]string{"NEEDS-ACTION": "needs-action", "ACCEPTED": "accepted", "DECLINED": "declined", "TENTATIVE": "tentative", "DELEGATED": "delegated"}
+)
+
+// attendee sets participant of ATTENDEE
+// https://www.rfc-editor.org/rfc/rfc9555#section-4.4.1
+func (f *fromICalendar) attendee(l *line) (bool, error) {
+	params, ok := l.params(parameter.TypeNameCommonName, parameter.TypeNameCalenderUserType, parameter.TypeNameParticipationRole, parameter.TypeNameParticipationStatus, parameter.TypeNameRSVP)
+	if !ok {
+		return false, nil
+	}
+	roles := []string{"attendee"}
+	if v, ok := params[parameter.TypeNameParticipationRole]; ok {
+		if roles, ok = participantRoles[strings.ToUpper(v)]; !ok {
+			return false, nil
+		}
+	}
+	var kind, status string
+	if v, ok := params[parameter.TypeNameCalenderUserType]; ok {
+		if kind, ok = calendarUserTypes[strings.ToUpper(v)]; !ok {
+			return false, nil
+		}
+	}
+	if v, ok := params[parameter.TypeNameParticipationStatus]; ok {
+		if status, ok = participationStatuses[strings.ToUpper(v)]; !ok {
+			return false, nil
+		}
+	}
+	rsvp, hasRSVP := params[parameter.TypeNameRSVP]
+	if hasRSVP && !strings.EqualFold(rsvp, "TRUE") && !strings.EqualFold(rsvp, "FALSE") {
+		return false, nil
+	}
+
+	p := f.participant(l.value())
+	for _, r := range roles {
+		p["roles"].(map[string]interface{})[r] = true
+	}
+	if cn, ok := params[parameter.TypeNameCommonName]; ok {
+		p["name"] = cn
+	}
+	if kind != "" {
+		p["kind"] = kind
+	}
+	if status != "" {
+		p["participationStatus"] = status
+	}
+	if strings.EqualFold(rsvp, "TRUE") {
+		p["expectReply"] = true
+	}
+	return true, nil
+}
+
+// alarm sets alert of VALARM and returns false if JSCalendar can not represent it
+// https://www.rfc-editor.org/rfc/rfc9555#section-4.5
+func (f *fromICalendar) alarm(n *node) bool {
+	alert := map[string]interface{}{"@type": "Alert"}
+	var raw []string
+	for _, l := range n.lines {
+		switch l.name() {
+		case property.NameAction:
+			action := strings.ToLower(l.value())
+			if _, ok := l.params(); !ok || (action != "display" && action != "email") {
+				return false
+			}
+			alert["action"] = action
+		case property.NameTrigger:
+			params, ok := l.params(parameter.TypeNameValueType, parameter.TypeNameAlarmTriggerRelationship)
+			if !ok {
+				return false
+			}
+			if strings.EqualFold(params[parameter.TypeNameValueType], string(types.ValueTypeDateTime)) {
+				when, err := types.ToExtended(types.ValueTypeDateTime, l.value())
+				if err != nil || !strings.HasSuffix(when, "Z") {
+					return false
+				}
+				alert["trigger"] = map[string]interface{}{"@type": "AbsoluteTrigger", "when": when}
+				continue
+			}
+			trigger := map[string]interface{}{"@type": "OffsetTrigger", "offset": l.value()}
+			if strings.EqualFold(params[parameter.TypeNameAlarmTriggerRelationship], "END") {
+				trigger["relativeTo"] = "end"
+			}
+			alert["trigger"] = trigger
+		default:
+			raw = append(raw, l.raw)
+		}
+	}
+	for _, c := range n.children {
+		raw = append(raw, c.raw...)
+	}
+	if alert["action"] == nil || alert["trigger"] == nil {
+		return false
+	}
+	if len(raw) > 0 {
+		alert[VendorProperty] = raw
+	}
+	f.addID("alerts", alert)
+	return true
+}
+
+var weekDayNumRe = regexp.MustCompile(`^([+-]?\d{1,2})?(SU|MO|TU|WE|TH|FR|SA)$`)
+
+// recurrenceRuleNumbers is rule parts of RECUR which have numbers
+var recurrenceRuleNumbers = map[string]string{
+	"BYSECOND":   "bySecond",
+	"BYMINUTE":   "byMinute",
+	"BYHOUR":     "byHour",
+	"BYMONTHDAY": "byMonthDay",
+	"BYYEARDAY":  "byYearDay",
+	"BYWEEKNO":   "byWeekNo",
+	"BYSETPOS":   "bySetPosition",
+}
+
+// recurrenceRule returns RecurrenceRule of RRULE
+// https://www.rfc-editor.org/rfc/rfc9555#section-4.3.2
+func (f *fromICalendar) recurrenceRule(v string) (map[string]interface{}, bool) {
+	rule := map[string]interface{}{"@type": "RecurrenceRule"}
+	for _, part := range strings.Split(v, ";") {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return nil, false
+		}
+		key, value := strings.ToUpper(kv[0]), kv[1]
+		switch key {
+		case "FREQ":
+			rule["frequency"] = strings.ToLower(value)
+		case "RSCALE":
+			rule["rscale"] = strings.ToLower(value)
+		case "SKIP":
+			rule["skip"] = strings.ToLower(value)
+		case "WKST":
+			rule["firstDayOfWeek"] = strings.ToLower(value)
+		case "INTERVAL", "COUNT":
+			i, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, false
+			}
+			rule[strings.ToLower(key)] = i
+		case "UNTIL":
+			until, ok := f.until(value)
+			if !ok {
+				return nil, false
+			}
+			rule["until"] = until
+		case "BYMONTH":
+			var months []interface{}
+			for _, m := range strings.Split(value, ",") {
+				months = append(months, m)
+			}
+			rule["byMonth"] = months
+		case "BYDAY":
+			var days []interface{}
+			for _, d := range strings.Split(value, ",") {
+				m := weekDayNumRe.FindStringSubmatch(strings.ToUpper(d))
+				if m == nil {
+					return nil, false
+				}
+				day := map[string]interface{}{"@type": "NDay", "day": strings.ToLower(m[2])}
+				if m[1] != "" {
+					n, err := strconv.Atoi(m[1])
+					if err != nil {
+						return nil, false
+					}
+					day["nthOfPeriod"] = n
+				}
+				days = append(days, day)
+			}
+			rule["byDay"] = days
+		default:
+			name, ok := recurrenceRuleNumbers[key]
+			if !ok {
+				return nil, false
+			}
+			var numbers []interface{}
+			for _, s := range strings.Split(value, ",") {
+				n, err := strconv.Atoi(s)
+				if err != nil {
+					return nil, false
+				}
+				numbers = append(numbers, n)
+			}
+			rule[name] = numbers
+		}
+	}
+	return rule, rule["frequency"] != nil
+}
+
+// until returns UNTIL as LocalDateTime in time zone of start
+func (f *fromICalendar) until(v string) (string, bool) {
+	if len(v) == 8 {
+		d, err := types.ToExtended(types.ValueTypeDate, v)
+		return d + "T00:00:00", err == nil && f.zone.date
+	}
+	if !strings.HasSuffix(v, "Z") || f.zone.date {
+		return "", false
+	}
+	t, err := time.ParseInLocation("20060102T150405Z", v, time.UTC)
+	if err != nil {
+		return "", false
+	}
+	loc, err := f.zone.location()
+	if err != nil {
+		return "", false
+	}
+	return t.In(loc).Format(localDateTimeLayout), true
+}
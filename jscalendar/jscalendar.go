@@ -0,0 +1,824 @@
+package jscalendar
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/knsh14/ical"
+	"github.com/knsh14/ical/component"
+	"github.com/knsh14/ical/contentline"
+	"github.com/knsh14/ical/parameter"
+	"github.com/knsh14/ical/parser"
+	"github.com/knsh14/ical/property"
+	"github.com/knsh14/ical/types"
+)
+
+// prodID is PRODID of calendar which wraps converted components
+const prodID = "-//knsh14//ical//EN"
+
+type decoder interface {
+	Decode(w io.Writer) error
+}
+
+// FromEvent returns JSCalendar Event of master VEVENT and its overridden instances.
+// iCalendar properties JSCalendar can not represent are kept in VendorProperty.
+// https://www.rfc-editor.org/rfc/rfc9555
+func FromEvent(master *ical.Event, overrides ...*ical.Event) (*Event, error) {
+	if master == nil {
+		return nil, fmt.Errorf("master event is nil")
+	}
+	components := []decoder{master}
+	for _, o := range overrides {
+		components = append(components, o)
+	}
+	var e Event
+	if err := fromICal(components, &e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// FromToDo returns JSCalendar Task of master VTODO and its overridden instances.
+// iCalendar properties JSCalendar can not represent are kept in VendorProperty.
+// https://www.rfc-editor.org/rfc/rfc9555
+func FromToDo(master *ical.ToDo, overrides ...*ical.ToDo) (*Task, error) {
+	if master == nil {
+		return nil, fmt.Errorf("master todo is nil")
+	}
+	components := []decoder{master}
+	for _, o := range overrides {
+		components = append(components, o)
+	}
+	var t Task
+	if err := fromICal(components, &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// ToEvent returns VEVENT of e followed by VEVENTs of overridden instances in recurrenceOverrides.
+// JSCalendar properties iCalendar can not represent are kept in JSPROP properties.
+func ToEvent(e *Event) ([]*ical.Event, error) {
+	components, err := toICal(e, "Event")
+	if err != nil {
+		return nil, err
+	}
+	var res []*ical.Event
+	for _, c := range components {
+		v, ok := c.(*ical.Event)
+		if !ok {
+			return nil, fmt.Errorf("converted component is %T, not event", c)
+		}
+		res = append(res, v)
+	}
+	return res, nil
+}
+
+// ToToDo returns VTODO of t followed by VTODOs of overridden instances in recurrenceOverrides.
+// JSCalendar properties iCalendar can not represent are kept in JSPROP properties.
+func ToToDo(t *Task) ([]*ical.ToDo, error) {
+	components, err := toICal(t, "Task")
+	if err != nil {
+		return nil, err
+	}
+	var res []*ical.ToDo
+	for _, c := range components {
+		v, ok := c.(*ical.ToDo)
+		if !ok {
+			return nil, fmt.Errorf("converted component is %T, not todo", c)
+		}
+		res = append(res, v)
+	}
+	return res, nil
+}
+
+func fromICal(components []decoder, v interface{}) error {
+	m, err := fromDecoders(components)
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+func fromDecoders(components []decoder) (map[string]interface{}, error) {
+	b := &bytes.Buffer{}
+	for _, c := range components {
+		if err := c.Decode(b); err != nil {
+			return nil, fmt.Errorf("encode component: %w", err)
+		}
+	}
+	lines, err := contentline.Unfold(b)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := parseNodes(lines)
+	if err != nil {
+		return nil, err
+	}
+	return fromComponents(nodes)
+}
+
+func toICal(v interface{}, kind string) ([]ical.CalenderComponent, error) {
+	var m map[string]interface{}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	if t, _ := m["@type"].(string); t != "" && t != kind {
+		return nil, fmt.Errorf("@type must be %s, but %s", kind, t)
+	}
+	setDefaultTypes(m, kind)
+
+	master, overrides, err := toLines(m)
+	if err != nil {
+		return nil, err
+	}
+	cal, err := parseLines(master, overrides)
+	if err != nil {
+		return nil, err
+	}
+	var components []decoder
+	for _, c := range cal.Components {
+		components = append(components, c)
+	}
+	back, err := fromDecoders(components)
+	if err != nil {
+		return nil, err
+	}
+	patch := diff(m, back)
+	if len(patch) == 0 {
+		return cal.Components, nil
+	}
+
+	// JSCalendar properties which are lost are kept as patch
+	pointers := make([]string, 0, len(patch))
+	for p := range patch {
+		pointers = append(pointers, p)
+	}
+	sort.Strings(pointers)
+	end := master[len(master)-1]
+	master = master[:len(master)-1]
+	for _, p := range pointers {
+		v, err := json.Marshal(patch[p])
+		if err != nil {
+			return nil, err
+		}
+		pointer, err := parameter.QuoteIfNeeded(p)
+		if err != nil {
+			return nil, fmt.Errorf("patch %s: %w", p, err)
+		}
+		master = append(master, fmt.Sprintf("%s;%s=%s:%s", propertyNameJSProperty, parameterNameJSONPointer, pointer, types.NewText(string(v)).Escape()))
+	}
+	master = append(master, end)
+	cal, err = parseLines(master, overrides)
+	if err != nil {
+		return nil, err
+	}
+	return cal.Components, nil
+}
+
+func parseLines(master []string, overrides [][]string) (*ical.Calendar, error) {
+	var b strings.Builder
+	b.WriteString(contentline.Fold(fmt.Sprintf("%s:%s", property.NameBegin, component.TypeCalendar)))
+	b.WriteString(contentline.Fold(fmt.Sprintf("%s:2.0", property.NameVersion)))
+	b.WriteString(contentline.Fold(fmt.Sprintf("%s:%s", property.NameProdID, prodID)))
+	for _, ls := range append([][]string{master}, overrides...) {
+		for _, l := range ls {
+			b.WriteString(contentline.Fold(l))
+		}
+	}
+	b.WriteString(contentline.Fold(fmt.Sprintf("%s:%s", property.NameEnd, component.TypeCalendar)))
+	cal, err := parser.Parse(strings.NewReader(b.String()), parser.WithRoundTrip())
+	if err != nil {
+		return nil, fmt.Errorf("parse converted calendar: %w", err)
+	}
+	return cal, nil
+}
+
+// normalize converts m to values of encoding/json so that reflect.DeepEqual can compare them
+func normalize(m *map[string]interface{}) error {
+	b, err := json.Marshal(*m)
+	if err != nil {
+		return err
+	}
+	*m = nil
+	return json.Unmarshal(b, m)
+}
+
+// setDefaultTypes sets @type of objects which are not set
+func setDefaultTypes(m map[string]interface{}, kind string) {
+	setType(m, kind)
+	objectTypes := map[string]string{
+		"relatedTo":        "Relation",
+		"locations":        "Location",
+		"virtualLocations": "VirtualLocation",
+		"links":            "Link",
+		"participants":     "Participant",
+		"alerts":           "Alert",
+	}
+	for key, t := range objectTypes {
+		objects, _ := m[key].(map[string]interface{})
+		for _, o := range objects {
+			if o, ok := o.(map[string]interface{}); ok {
+				setType(o, t)
+			}
+		}
+	}
+	alerts, _ := m["alerts"].(map[string]interface{})
+	for _, a := range alerts {
+		a, _ := a.(map[string]interface{})
+		if trigger, ok := a["trigger"].(map[string]interface{}); ok {
+			if _, ok := trigger["when"]; ok {
+				setType(trigger, "AbsoluteTrigger")
+			} else {
+				setType(trigger, "OffsetTrigger")
+			}
+		}
+	}
+	for _, key := range []string{"recurrenceRules", "excludedRecurrenceRules"} {
+		rules, _ := m[key].([]interface{})
+		for _, r := range rules {
+			r, ok := r.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			setType(r, "RecurrenceRule")
+			days, _ := r["byDay"].([]interface{})
+			for _, d := range days {
+				if d, ok := d.(map[string]interface{}); ok {
+					setType(d, "NDay")
+				}
+			}
+		}
+	}
+}
+
+func setType(o map[string]interface{}, t string) {
+	if v, _ := o["@type"].(string); v == "" {
+		o["@type"] = t
+	}
+}
+
+func str(o map[string]interface{}, key string) string {
+	v, _ := o[key].(string)
+	return v
+}
+
+func num(o map[string]interface{}, key string) (int, bool) {
+	v, ok := o[key].(float64)
+	return int(v), ok
+}
+
+func obj(o map[string]interface{}, key string) map[string]interface{} {
+	v, _ := o[key].(map[string]interface{})
+	return v
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// toLines returns content lines of master component and overridden instances
+func toLines(m map[string]interface{}) ([]string, [][]string, error) {
+	master, err := toComponent(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	var res [][]string
+	overrides := obj(m, recurrenceOverridesProperty)
+	for _, id := range sortedKeys(overrides) {
+		patch := obj(overrides, id)
+		if len(patch) == 0 || (len(patch) == 1 && patch["excluded"] == true) {
+			// RDATE or EXDATE of master
+			continue
+		}
+		o := copyValue(instance(m, id)).(map[string]interface{})
+		if err := apply(o, patch); err != nil {
+			return nil, nil, fmt.Errorf("patch %s: %w", id, err)
+		}
+		o["recurrenceId"] = id
+		if tz := str(m, "timeZone"); tz != "" {
+			o["recurrenceIdTimeZone"] = tz
+		}
+		lines, err := toComponent(o)
+		if err != nil {
+			return nil, nil, fmt.Errorf("override %s: %w", id, err)
+		}
+		res = append(res, lines)
+	}
+	return master, res, nil
+}
+
+func copyValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			res[k] = copyValue(e)
+		}
+		return res
+	case []interface{}:
+		res := make([]interface{}, len(v))
+		for i, e := range v {
+			res[i] = copyValue(e)
+		}
+		return res
+	}
+	return v
+}
+
+// dateTime returns parameters and value of LocalDateTime v in z
+func dateTime(v string, z zone) (string, string, error) {
+	if _, err := time.Parse(localDateTimeLayout, v); err != nil {
+		return "", "", fmt.Errorf("invalid LocalDateTime %s", v)
+	}
+	if z.date && strings.HasSuffix(v, "T00:00:00") {
+		return fmt.Sprintf(";%s=%s", parameter.TypeNameValueType, types.ValueTypeDate), types.FromExtended(types.ValueTypeDate, v[:10]), nil
+	}
+	s := types.FromExtended(types.ValueTypeDateTime, v)
+	switch z.timeZone {
+	case "":
+		return "", s, nil
+	case timeZoneUTC, "UTC":
+		return "", s + "Z", nil
+	}
+	tzid, err := parameter.QuoteIfNeeded(z.timeZone)
+	if err != nil {
+		return "", "", fmt.Errorf("timeZone: %w", err)
+	}
+	return fmt.Sprintf(";%s=%s", parameter.TypeNameReferenceTimezone, tzid), s, nil
+}
+
+// endDateTime returns parameters and value of DTEND after duration from start of o in time zone of the end location.
+// ok is false if o does not have location of the end in other time zone than z.
+// https://www.rfc-editor.org/rfc/rfc9555#section-4
+func endDateTime(o map[string]interface{}, duration string, z zone) (string, string, bool, error) {
+	var tz string
+	locations := obj(o, "locations")
+	for _, id := range sortedKeys(locations) {
+		if l := obj(locations, id); str(l, "relativeTo") == "end" && str(l, "timeZone") != "" {
+			tz = str(l, "timeZone")
+			break
+		}
+	}
+	start := str(o, "start")
+	if tz == "" || tz == z.timeZone || z.timeZone == "" || z.date || start == "" {
+		return "", "", false, nil
+	}
+	s, err := z.parse(start)
+	if err != nil {
+		return "", "", false, fmt.Errorf("invalid start %s in %s", start, z.timeZone)
+	}
+	d, err := types.NewDuration(duration)
+	if err != nil || d.Direction == "-" {
+		return "", "", false, fmt.Errorf("invalid Duration %s", duration)
+	}
+	end := zone{timeZone: tz}
+	loc, err := end.location()
+	if err != nil {
+		return "", "", false, fmt.Errorf("timeZone of end: %w", err)
+	}
+	e := s.AddDate(0, 0, int(d.Week*7+d.Day)).Add(d.HourDuration).In(loc)
+	params, value, err := dateTime(e.Format(localDateTimeLayout), end)
+	if err != nil {
+		return "", "", false, err
+	}
+	return params, value, true, nil
+}
+
+// utcDateTime returns DATE-TIME value of UTCDateTime v
+func utcDateTime(v string) (string, error) {
+	if _, err := time.Parse(time.RFC3339, v); err != nil || !strings.HasSuffix(v, "Z") {
+		return "", fmt.Errorf("invalid UTCDateTime %s", v)
+	}
+	return types.FromExtended(types.ValueTypeDateTime, v), nil
+}
+
+var (
+	privacies        = map[string]string{"public": "PUBLIC", "private": "PRIVATE", "secret": "CONFIDENTIAL"}
+	freeBusyStatuses = map[string]string{"busy": "OPAQUE", "free": "TRANSPARENT"}
+	participantKinds = map[string]string{"individual": "INDIVIDUAL", "group": "GROUP", "resource": "RESOURCE", "location": "ROOM"}
+)
+
+// toComponent returns content lines of VEVENT or VTODO of o.
+// it does not have overridden instances.
+func toComponent(o map[string]interface{}) ([]string, error) {
+	kind := component.TypeEvent
+	if str(o, "@type") == "Task" {
+		kind = component.TypeTODO
+	}
+	z := zone{timeZone: str(o, "timeZone"), date: o["showWithoutTime"] == true}
+	var lines []string
+	add := func(name property.Name, params, value string) {
+		lines = append(lines, fmt.Sprintf("%s%s:%s", name, params, value))
+	}
+
+	add(property.NameBegin, "", string(kind))
+	if v := str(o, "uid"); v != "" {
+		add(property.NameUID, "", types.NewText(v).Escape())
+	}
+	for key, name := range map[string]property.Name{"updated": property.NameDateTimeStamp, "created": property.NameDateTimeCreated} {
+		if v := str(o, key); v != "" {
+			s, err := utcDateTime(v)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			add(name, "", s)
+		}
+	}
+	if v, ok := num(o, "sequence"); ok {
+		add(property.NameSequenceNumber, "", strconv.Itoa(v))
+	}
+	if v := str(o, "start"); v != "" {
+		params, value, err := dateTime(v, z)
+		if err != nil {
+			return nil, fmt.Errorf("start: %w", err)
+		}
+		add(property.NameDateTimeStart, params, value)
+	}
+	if v := str(o, "duration"); v != "" && kind == component.TypeEvent {
+		params, value, ok, err := endDateTime(o, v, z)
+		if err != nil {
+			return nil, fmt.Errorf("duration: %w", err)
+		}
+		if ok {
+			add(property.NameDateTimeEnd, params, value)
+		} else {
+			add(property.NameDuration, "", v)
+		}
+	}
+	if v := str(o, "due"); v != "" && kind == component.TypeTODO {
+		params, value, err := dateTime(v, z)
+		if err != nil {
+			return nil, fmt.Errorf("due: %w", err)
+		}
+		add(property.NameDateTimeDue, params, value)
+	}
+	if v := str(o, "recurrenceId"); v != "" {
+		rz := zone{timeZone: str(o, "recurrenceIdTimeZone"), date: z.date}
+		params, value, err := dateTime(v, rz)
+		if err != nil {
+			return nil, fmt.Errorf("recurrenceId: %w", err)
+		}
+		add(property.NameRecurrenceID, params, value)
+	}
+
+	var language string
+	if v := str(o, "locale"); v != "" {
+		lang, err := parameter.QuoteIfNeeded(v)
+		if err != nil {
+			return nil, fmt.Errorf("locale: %w", err)
+		}
+		language = fmt.Sprintf(";%s=%s", parameter.TypeNameLanguage, lang)
+	}
+	if v := str(o, "title"); v != "" {
+		add(property.NameSummary, language, types.NewText(v).Escape())
+	}
+	if v := str(o, "description"); v != "" {
+		add(property.NameDescription, language, types.NewText(v).Escape())
+	}
+
+	locations := obj(o, "locations")
+	var hasName, hasCoordinates bool
+	for _, id := range sortedKeys(locations) {
+		l := obj(locations, id)
+		if v := str(l, "name"); v != "" && !hasName {
+			hasName = true
+			add(property.NameLocation, "", types.NewText(v).Escape())
+		}
+		if v := str(l, "coordinates"); strings.HasPrefix(v, "geo:") && !hasCoordinates {
+			latlon := strings.SplitN(strings.TrimPrefix(v, "geo:"), ",", 2)
+			if len(latlon) == 2 {
+				hasCoordinates = true
+				add(property.NameGeo, "", latlon[0]+";"+latlon[1])
+			}
+		}
+	}
+
+	links := obj(o, "links")
+	var hasURL bool
+	for _, id := range sortedKeys(links) {
+		l := obj(links, id)
+		href := str(l, "href")
+		switch {
+		case href == "":
+		case str(l, "rel") == "enclosure":
+			var params string
+			if ct := str(l, "contentType"); ct != "" {
+				fmttype, err := parameter.QuoteIfNeeded(ct)
+				if err != nil {
+					return nil, fmt.Errorf("contentType: %w", err)
+				}
+				params = fmt.Sprintf(";%s=%s", parameter.TypeNameFormatType, fmttype)
+			}
+			add(property.NameAttachment, params, href)
+		case !hasURL:
+			hasURL = true
+			add(property.NameURL, "", href)
+		}
+	}
+
+	if v, ok := privacies[str(o, "privacy")]; ok {
+		add(property.NameClass, "", v)
+	}
+	if v, ok := freeBusyStatuses[str(o, "freeBusyStatus")]; ok {
+		add(property.NameTimeTransparency, "", v)
+	}
+	if v, ok := num(o, "priority"); ok {
+		add(property.NamePriority, "", strconv.Itoa(v))
+	}
+	status := str(o, "status")
+	if kind == component.TypeTODO {
+		status = str(o, "progress")
+	}
+	if status != "" {
+		add(property.NameStatus, "", strings.ToUpper(status))
+	}
+	if v, ok := num(o, "percentComplete"); ok && kind == component.TypeTODO {
+		add(property.NamePercentComplete, "", strconv.Itoa(v))
+	}
+	if v := str(o, "color"); v != "" {
+		add(propertyNameColor, "", types.NewText(v).Escape())
+	}
+
+	var keywords []string
+	for _, k := range sortedKeys(obj(o, "keywords")) {
+		keywords = append(keywords, types.NewText(k).Escape())
+	}
+	if len(keywords) > 0 {
+		add(property.NameCategories, "", strings.Join(keywords, ","))
+	}
+
+	related := obj(o, "relatedTo")
+	for _, uid := range sortedKeys(related) {
+		relations := sortedKeys(obj(obj(related, uid), "relation"))
+		if len(relations) == 0 {
+			relations = []string{"parent"}
+		}
+		for _, r := range relations {
+			var params string
+			if r != "parent" {
+				params = fmt.Sprintf(";%s=%s", parameter.TypeNameRelationshipType, strings.ToUpper(r))
+			}
+			add(property.NameRelatedTo, params, types.NewText(uid).Escape())
+		}
+	}
+
+	ps, err := participants(o)
+	if err != nil {
+		return nil, err
+	}
+	lines = append(lines, ps...)
+
+	if rules, _ := o["recurrenceRules"].([]interface{}); len(rules) > 0 {
+		if r, ok := rules[0].(map[string]interface{}); ok {
+			v, err := recurrenceRule(r, z)
+			if err != nil {
+				return nil, fmt.Errorf("recurrenceRules: %w", err)
+			}
+			add(property.NameRecurrenceRule, "", v)
+		}
+	}
+
+	overrides := obj(o, recurrenceOverridesProperty)
+	for _, id := range sortedKeys(overrides) {
+		patch := obj(overrides, id)
+		name := property.NameRecurrenceDateTimes
+		switch {
+		case len(patch) == 0:
+		case len(patch) == 1 && patch["excluded"] == true:
+			name = property.NameExceptionDateTimes
+		default:
+			continue
+		}
+		params, value, err := dateTime(id, z)
+		if err != nil {
+			return nil, fmt.Errorf("recurrenceOverrides: %w", err)
+		}
+		add(name, params, value)
+	}
+
+	raw, _ := o[VendorProperty].([]interface{})
+	for _, l := range raw {
+		if s, ok := l.(string); ok {
+			lines = append(lines, s)
+		}
+	}
+
+	alerts := obj(o, "alerts")
+	for _, id := range sortedKeys(alerts) {
+		ls, err := alarm(obj(alerts, id))
+		if err != nil {
+			return nil, fmt.Errorf("alert %s: %w", id, err)
+		}
+		lines = append(lines, ls...)
+	}
+	add(property.NameEnd, "", string(kind))
+	return lines, nil
+}
+
+// participants returns ORGANIZER and ATTENDEE of participants
+// https://www.rfc-editor.org/rfc/rfc9555#section-4.4
+func participants(o map[string]interface{}) ([]string, error) {
+	var (
+		organizer string
+		attendees []string
+	)
+	ps := obj(o, "participants")
+	for _, id := range sortedKeys(ps) {
+		p := obj(ps, id)
+		roles := obj(p, "roles")
+		address := str(obj(p, "sendTo"), "imip")
+		if address == "" && str(p, "email") != "" {
+			address = "mailto:" + str(p, "email")
+		}
+		var cn string
+		if v := str(p, "name"); v != "" {
+			name, err := parameter.QuoteIfNeeded(v)
+			if err != nil {
+				return nil, fmt.Errorf("name of participant %s: %w", id, err)
+			}
+			cn = fmt.Sprintf(";%s=%s", parameter.TypeNameCommonName, name)
+		}
+		if roles["owner"] == true && organizer == "" {
+			if v := str(obj(o, "replyTo"), "imip"); v != "" {
+				address = v
+			}
+			if address != "" {
+				organizer = fmt.Sprintf("%s%s:%s", property.NameOrganizer, cn, address)
+			}
+			if len(roles) == 1 {
+				continue
+			}
+		}
+		if address == "" {
+			continue
+		}
+		params := cn
+		if v, ok := participantKinds[str(p, "kind")]; ok {
+			params += fmt.Sprintf(";%s=%s", parameter.TypeNameCalenderUserType, v)
+		}
+		switch {
+		case roles["chair"] == true:
+			params += fmt.Sprintf(";%s=CHAIR", parameter.TypeNameParticipationRole)
+		case roles["informational"] == true && roles["attendee"] != true:
+			params += fmt.Sprintf(";%s=NON-PARTICIPANT", parameter.TypeNameParticipationRole)
+		case roles["optional"] == true:
+			params += fmt.Sprintf(";%s=OPT-PARTICIPANT", parameter.TypeNameParticipationRole)
+		}
+		if v := str(p, "participationStatus"); v != "" {
+			params += fmt.Sprintf(";%s=%s", parameter.TypeNameParticipationStatus, strings.ToUpper(v))
+		}
+		if p["expectReply"] == true {
+			params += fmt.Sprintf(";%s=TRUE", parameter.TypeNameRSVP)
+		}
+		attendees = append(attendees, fmt.Sprintf("%s%s:%s", property.NameAttendee, params, address))
+	}
+	if organizer != "" {
+		return append([]string{organizer}, attendees...), nil
+	}
+	return attendees, nil
+}
+
+// alarm returns content lines of VALARM of alert a
+// https://www.rfc-editor.org/rfc/rfc9555#section-4.5
+func alarm(a map[string]interface{}) ([]string, error) {
+	action := str(a, "action")
+	if action == "" {
+		action = "display"
+	}
+	lines := []string{
+		fmt.Sprintf("%s:%s", property.NameBegin, component.TypeAlarm),
+		fmt.Sprintf("%s:%s", property.NameAction, strings.ToUpper(action)),
+	}
+	trigger := obj(a, "trigger")
+	switch str(trigger, "@type") {
+	case "AbsoluteTrigger":
+		when, err := utcDateTime(str(trigger, "when"))
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, fmt.Sprintf("%s;%s=%s:%s", property.NameTrigger, parameter.TypeNameValueType, types.ValueTypeDateTime, when))
+	default:
+		offset := str(trigger, "offset")
+		if offset == "" {
+			offset = "PT0S"
+		}
+		var params string
+		if str(trigger, "relativeTo") == "end" {
+			params = fmt.Sprintf(";%s=END", parameter.TypeNameAlarmTriggerRelationship)
+		}
+		lines = append(lines, fmt.Sprintf("%s%s:%s", property.NameTrigger, params, offset))
+	}
+	raw, _ := a[VendorProperty].([]interface{})
+	for _, l := range raw {
+		if s, ok := l.(string); ok {
+			lines = append(lines, s)
+		}
+	}
+	return append(lines, fmt.Sprintf("%s:%s", property.NameEnd, component.TypeAlarm)), nil
+}
+
+// recurrenceRule returns RECUR value of RecurrenceRule r
+// https://www.rfc-editor.org/rfc/rfc9555#section-4.3.2
+func recurrenceRule(r map[string]interface{}, z zone) (string, error) {
+	freq := str(r, "frequency")
+	if freq == "" {
+		return "", fmt.Errorf("frequency is required")
+	}
+	parts := []string{"FREQ=" + strings.ToUpper(freq)}
+	if v := str(r, "until"); v != "" {
+		until, err := untilValue(v, z)
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, "UNTIL="+until)
+	}
+	for _, key := range []string{"count", "interval"} {
+		if v, ok := num(r, key); ok {
+			parts = append(parts, fmt.Sprintf("%s=%d", strings.ToUpper(key), v))
+		}
+	}
+	numbers := func(key string) {
+		values, _ := r[recurrenceRuleNumbers[key]].([]interface{})
+		var s []string
+		for _, v := range values {
+			if f, ok := v.(float64); ok {
+				s = append(s, strconv.Itoa(int(f)))
+			}
+		}
+		if len(s) > 0 {
+			parts = append(parts, key+"="+strings.Join(s, ","))
+		}
+	}
+	for _, key := range []string{"BYSECOND", "BYMINUTE", "BYHOUR"} {
+		numbers(key)
+	}
+	if days, _ := r["byDay"].([]interface{}); len(days) > 0 {
+		var s []string
+		for _, d := range days {
+			d, _ := d.(map[string]interface{})
+			day := strings.ToUpper(str(d, "day"))
+			if n, ok := num(d, "nthOfPeriod"); ok && n != 0 {
+				day = strconv.Itoa(n) + day
+			}
+			s = append(s, day)
+		}
+		parts = append(parts, "BYDAY="+strings.Join(s, ","))
+	}
+	for _, key := range []string{"BYMONTHDAY", "BYYEARDAY", "BYWEEKNO"} {
+		numbers(key)
+	}
+	if months, _ := r["byMonth"].([]interface{}); len(months) > 0 {
+		var s []string
+		for _, m := range months {
+			if m, ok := m.(string); ok {
+				s = append(s, strings.ToUpper(m))
+			}
+		}
+		parts = append(parts, "BYMONTH="+strings.Join(s, ","))
+	}
+	numbers("BYSETPOS")
+	if v := str(r, "firstDayOfWeek"); v != "" {
+		parts = append(parts, "WKST="+strings.ToUpper(v))
+	}
+	return strings.Join(parts, ";"), nil
+}
+
+// untilValue returns UNTIL of LocalDateTime v, which is UTC if start has time zone
+func untilValue(v string, z zone) (string, error) {
+	t, err := time.Parse(localDateTimeLayout, v)
+	if err != nil {
+		return "", fmt.Errorf("invalid until %s", v)
+	}
+	if z.date {
+		return t.Format("20060102"), nil
+	}
+	if z.timeZone == "" {
+		return t.Format("20060102T150405"), nil
+	}
+	loc, err := z.location()
+	if err != nil {
+		return "", fmt.Errorf("load time zone of until: %w", err)
+	}
+	local := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, loc)
+	return local.UTC().Format("20060102T150405Z"), nil
+}
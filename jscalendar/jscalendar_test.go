@@ -0,0 +1,433 @@
+package jscalendar
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/knsh14/ical"
+	"github.com/knsh14/ical/parser"
+)
+
+func TestFromEvent(t *testing.T) {
+	t.Parallel()
+	input := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"PRODID:-//Example Inc.//Example Calendar//EN",
+		"VERSION:2.0",
+		"BEGIN:VEVENT",
+		"UID:123@example.com",
+		"DTSTAMP:20200101T000000Z",
+		"DTSTART;TZID=Asia/Tokyo:20200106T100000",
+		"DTEND;TZID=Asia/Tokyo:20200106T113000",
+		"SUMMARY;LANGUAGE=en:Weekly meeting\\, room 1",
+		"LOCATION:Room 1",
+		"GEO:35.6;139.7",
+		"CATEGORIES:MEETING",
+		"TRANSP:OPAQUE",
+		"RRULE:FREQ=WEEKLY;UNTIL=20200131T010000Z;BYDAY=MO",
+		"EXDATE;TZID=Asia/Tokyo:20200113T100000",
+		"ORGANIZER;CN=Alice:mailto:alice@example.com",
+		"ATTENDEE;CN=Bob;ROLE=OPT-PARTICIPANT;PARTSTAT=ACCEPTED:mailto:bob@example.com",
+		"COMMENT:kept as it is",
+		"BEGIN:VALARM",
+		"ACTION:DISPLAY",
+		"TRIGGER:-PT15M",
+		"DESCRIPTION:Reminder",
+		"END:VALARM",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:123@example.com",
+		"DTSTAMP:20200101T000000Z",
+		"RECURRENCE-ID;TZID=Asia/Tokyo:20200120T100000",
+		"DTSTART;TZID=Asia/Tokyo:20200120T110000",
+		"DTEND;TZID=Asia/Tokyo:20200120T123000",
+		"SUMMARY;LANGUAGE=en:Weekly meeting\\, room 1",
+		"LOCATION:Room 1",
+		"GEO:35.6;139.7",
+		"CATEGORIES:MEETING",
+		"TRANSP:OPAQUE",
+		"ORGANIZER;CN=Alice:mailto:alice@example.com",
+		"ATTENDEE;CN=Bob;ROLE=OPT-PARTICIPANT;PARTSTAT=DECLINED:mailto:bob@example.com",
+		"COMMENT:kept as it is",
+		"BEGIN:VALARM",
+		"ACTION:DISPLAY",
+		"TRIGGER:-PT15M",
+		"DESCRIPTION:Reminder",
+		"END:VALARM",
+		"END:VEVENT",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n")
+	c, err := parser.Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := FromEvent(c.Components[0].(*ical.Event), c.Components[1].(*ical.Event))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &Event{
+		Common: Common{
+			Type:     "Event",
+			UID:      "123@example.com",
+			Updated:  "2020-01-01T00:00:00Z",
+			Title:    "Weekly meeting, room 1",
+			Locale:   "en",
+			TimeZone: "Asia/Tokyo",
+			Locations: map[string]*Location{
+				"1": {Type: "Location", Name: "Room 1", Coordinates: "geo:35.6,139.7"},
+			},
+			Keywords:       map[string]bool{"MEETING": true},
+			FreeBusyStatus: "busy",
+			RecurrenceRules: []*RecurrenceRule{
+				{
+					Type:      "RecurrenceRule",
+					Frequency: "weekly",
+					Until:     "2020-01-31T10:00:00",
+					ByDay:     []*NDay{{Type: "NDay", Day: "mo"}},
+				},
+			},
+			RecurrenceOverrides: map[string]PatchObject{
+				"2020-01-13T10:00:00": {"excluded": true},
+				"2020-01-20T10:00:00": {
+					"start": "2020-01-20T11:00:00",
+					"participants/2": map[string]interface{}{
+						"@type":               "Participant",
+						"name":                "Bob",
+						"sendTo":              map[string]interface{}{"imip": "mailto:bob@example.com"},
+						"roles":               map[string]interface{}{"attendee": true, "optional": true},
+						"participationStatus": "declined",
+					},
+				},
+			},
+			ReplyTo: map[string]string{"imip": "mailto:alice@example.com"},
+			Participants: map[string]*Participant{
+				"1": {
+					Type:   "Participant",
+					Name:   "Alice",
+					SendTo: map[string]string{"imip": "mailto:alice@example.com"},
+					Roles:  map[string]bool{"owner": true},
+				},
+				"2": {
+					Type:                "Participant",
+					Name:                "Bob",
+					SendTo:              map[string]string{"imip": "mailto:bob@example.com"},
+					Roles:               map[string]bool{"attendee": true, "optional": true},
+					ParticipationStatus: "accepted",
+				},
+			},
+			Alerts: map[string]*Alert{
+				"1": {
+					Type:      "Alert",
+					Trigger:   &Trigger{Type: "OffsetTrigger", Offset: "-PT15M"},
+					Action:    "display",
+					ICalendar: []string{"DESCRIPTION:Reminder"},
+				},
+			},
+			ICalendar: []string{"COMMENT:kept as it is"},
+		},
+		Start:    "2020-01-06T10:00:00",
+		Duration: "PT1H30M",
+	}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	events, err := ToEvent(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected master and 1 overridden instance, but %d events", len(events))
+	}
+	back, err := FromEvent(events[0], events[1:]...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(got, back); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestFromEvent_End(t *testing.T) {
+	t.Parallel()
+	testcases := map[string]struct {
+		start, end string
+		expected   *Event
+		// dtend is DTEND of the event converted back, it is empty if the event has DURATION instead
+		dtend string
+	}{
+		"other time zone": {
+			start: "DTSTART;TZID=Asia/Tokyo:20200106T100000",
+			end:   "DTEND;TZID=Europe/Berlin:20200106T043000",
+			expected: &Event{
+				Common: Common{
+					TimeZone: "Asia/Tokyo",
+					Locations: map[string]*Location{
+						"end": {Type: "Location", RelativeTo: "end", TimeZone: "Europe/Berlin"},
+					},
+				},
+				Start:    "2020-01-06T10:00:00",
+				Duration: "PT2H30M",
+			},
+			dtend: "DTEND;TZID=Europe/Berlin:20200106T043000",
+		},
+		"UTC": {
+			start: "DTSTART;TZID=America/New_York:20200307T230000",
+			end:   "DTEND:20200308T070000Z",
+			expected: &Event{
+				Common: Common{
+					TimeZone: "America/New_York",
+					Locations: map[string]*Location{
+						"end": {Type: "Location", RelativeTo: "end", TimeZone: "Etc/UTC"},
+					},
+				},
+				Start:    "2020-03-07T23:00:00",
+				Duration: "PT3H",
+			},
+			dtend: "DTEND:20200308T070000Z",
+		},
+		"dates over daylight saving time": {
+			start: "DTSTART;VALUE=DATE:20200307",
+			end:   "DTEND;VALUE=DATE:20200309",
+			expected: &Event{
+				Common: Common{
+					ShowWithoutTime: true,
+				},
+				Start:    "2020-03-07T00:00:00",
+				Duration: "P2D",
+			},
+		},
+		"floating end": {
+			start: "DTSTART;TZID=Asia/Tokyo:20200106T100000",
+			end:   "DTEND:20200106T113000",
+			expected: &Event{
+				Common: Common{
+					TimeZone:  "Asia/Tokyo",
+					ICalendar: []string{"DTEND:20200106T113000"},
+				},
+				Start: "2020-01-06T10:00:00",
+			},
+			dtend: "DTEND:20200106T113000",
+		},
+	}
+	for title, tt := range testcases {
+		tt := tt
+		t.Run(title, func(t *testing.T) {
+			t.Parallel()
+			input := strings.Join([]string{
+				"BEGIN:VCALENDAR",
+				"PRODID:-//Example Inc.//Example Calendar//EN",
+				"VERSION:2.0",
+				"BEGIN:VEVENT",
+				"UID:123@example.com",
+				"DTSTAMP:20200101T000000Z",
+				tt.start,
+				tt.end,
+				"END:VEVENT",
+				"END:VCALENDAR",
+				"",
+			}, "\r\n")
+			c, err := parser.Parse(strings.NewReader(input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := FromEvent(c.Components[0].(*ical.Event))
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.expected.Type, tt.expected.UID, tt.expected.Updated = "Event", "123@example.com", "2020-01-01T00:00:00Z"
+			if diff := cmp.Diff(tt.expected, got); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+
+			events, err := ToEvent(got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var dtend string
+			if events[0].DateTimeEnd != nil {
+				var b strings.Builder
+				if err := events[0].DateTimeEnd.Decode(&b); err != nil {
+					t.Fatal(err)
+				}
+				dtend = strings.TrimSpace(b.String())
+			}
+			if dtend != tt.dtend {
+				t.Errorf("expect:%s\nactual:%s", tt.dtend, dtend)
+			}
+		})
+	}
+}
+
+func TestToEvent(t *testing.T) {
+	t.Parallel()
+	percent := 0
+	testcases := map[string]interface{}{
+		"event": &Event{
+			Common: Common{
+				Type:            "Event",
+				UID:             "a8df6573-0474-496d-8496-033ad45d7fea",
+				Updated:         "2020-01-02T18:23:04Z",
+				Title:           "Some event",
+				ShowWithoutTime: true,
+				Privacy:         "secret",
+				VirtualLocations: map[string]*VirtualLocation{
+					"vl1": {Type: "VirtualLocation", Name: "Video", URI: "https://example.com/room"},
+				},
+				Links: map[string]*Link{
+					"l1": {Type: "Link", Href: "https://example.com/agenda.pdf", ContentType: "application/pdf", Rel: "enclosure"},
+				},
+				Localizations: map[string]PatchObject{
+					"de": {"title": "Eine Veranstaltung"},
+				},
+				RecurrenceRules: []*RecurrenceRule{
+					{Type: "RecurrenceRule", Frequency: "monthly", Count: 3, ByMonthDay: []int{-1}},
+				},
+				RecurrenceOverrides: map[string]PatchObject{
+					"2020-02-29T00:00:00": {"title": "Leap day"},
+					"2020-04-01T00:00:00": {},
+				},
+				Alerts: map[string]*Alert{
+					"a1": {
+						Type:    "Alert",
+						Trigger: &Trigger{Type: "AbsoluteTrigger", When: "2020-01-30T09:00:00Z"},
+						Action:  "email",
+					},
+				},
+				Vendor: map[string]interface{}{"example.com:rank": 3.0},
+			},
+			Start:    "2020-01-31T00:00:00",
+			Duration: "P1D",
+		},
+		"task": &Task{
+			Common: Common{
+				Type:        "Task",
+				UID:         "2a358cee-6489-4f14-a57f-c104db4dc357",
+				Updated:     "2020-01-09T14:32:01Z",
+				Title:       "Do something",
+				TimeZone:    "Europe/Vienna",
+				Description: "multi\nline",
+				RelatedTo: map[string]*Relation{
+					"parent-task": {Type: "Relation", Relation: map[string]bool{"parent": true}},
+				},
+				Participants: map[string]*Participant{
+					"p1": {
+						Type:        "Participant",
+						Email:       "carol@example.com",
+						Roles:       map[string]bool{"attendee": true, "chair": true},
+						ExpectReply: true,
+						Kind:        "individual",
+					},
+				},
+				UseDefaultAlerts: true,
+			},
+			Due:             "2020-01-19T18:00:00",
+			PercentComplete: &percent,
+			Progress:        "in-process",
+		},
+	}
+	for title, input := range testcases {
+		input := input
+		t.Run(title, func(t *testing.T) {
+			t.Parallel()
+			var got interface{}
+			switch input := input.(type) {
+			case *Event:
+				events, err := ToEvent(input)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got, err = FromEvent(events[0], events[1:]...)
+				if err != nil {
+					t.Fatal(err)
+				}
+			case *Task:
+				todos, err := ToToDo(input)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got, err = FromToDo(todos[0], todos[1:]...)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			if diff := cmp.Diff(input, got); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	t.Parallel()
+	testcases := []string{"event.ics", "todo.ics"}
+	for _, file := range testcases {
+		file := file
+		t.Run(file, func(t *testing.T) {
+			t.Parallel()
+			c, err := parser.ParseFile(filepath.Join("..", "parser", "testdata", "roundtrip", file), parser.WithRoundTrip())
+			if err != nil {
+				t.Fatal(err)
+			}
+			switch v := c.Components[0].(type) {
+			case *ical.Event:
+				e, err := FromEvent(v)
+				if err != nil {
+					t.Fatal(err)
+				}
+				events, err := ToEvent(e)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got, err := FromEvent(events[0])
+				if err != nil {
+					t.Fatal(err)
+				}
+				if diff := cmp.Diff(e, got); diff != "" {
+					t.Errorf("(-want, +got)\n%s", diff)
+				}
+			case *ical.ToDo:
+				task, err := FromToDo(v)
+				if err != nil {
+					t.Fatal(err)
+				}
+				todos, err := ToToDo(task)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got, err := FromToDo(todos[0])
+				if err != nil {
+					t.Fatal(err)
+				}
+				if diff := cmp.Diff(task, got); diff != "" {
+					t.Errorf("(-want, +got)\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestToEvent_Error(t *testing.T) {
+	t.Parallel()
+	testcases := map[string]*Event{
+		"wrong type":       {Common: Common{Type: "Task", UID: "a"}},
+		"invalid start":    {Common: Common{UID: "a"}, Start: "2020-01-01"},
+		"dquote in locale": {Common: Common{UID: "a", Locale: `en"`}, Start: "2020-01-01T00:00:00"},
+		"no frequency": {
+			Common: Common{UID: "a", RecurrenceRules: []*RecurrenceRule{{Count: 1}}},
+			Start:  "2020-01-01T00:00:00",
+		},
+	}
+	for title, input := range testcases {
+		input := input
+		t.Run(title, func(t *testing.T) {
+			t.Parallel()
+			if _, err := ToEvent(input); err == nil {
+				t.Fatal("expected error but got nil")
+			}
+		})
+	}
+}
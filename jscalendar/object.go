@@ -0,0 +1,285 @@
+package jscalendar
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// VendorProperty is vendor property which has iCalendar content lines JSCalendar can not represent
+// https://tools.ietf.org/html/rfc8984#section-3.3
+const VendorProperty = "knsh14.github.io:icalendar"
+
+// PatchObject is set of JSON pointer and value to patch an object
+// https://tools.ietf.org/html/rfc8984#section-1.4.9
+type PatchObject map[string]interface{}
+
+// Common is properties of both Event and Task
+// https://tools.ietf.org/html/rfc8984#section-4
+type Common struct {
+	Type                    string                      `json:"@type"`
+	UID                     string                      `json:"uid"`
+	RelatedTo               map[string]*Relation        `json:"relatedTo,omitempty"`
+	ProdID                  string                      `json:"prodId,omitempty"`
+	Created                 string                      `json:"created,omitempty"`
+	Updated                 string                      `json:"updated,omitempty"`
+	Sequence                int                         `json:"sequence,omitempty"`
+	Method                  string                      `json:"method,omitempty"`
+	Title                   string                      `json:"title,omitempty"`
+	Description             string                      `json:"description,omitempty"`
+	DescriptionContentType  string                      `json:"descriptionContentType,omitempty"`
+	ShowWithoutTime         bool                        `json:"showWithoutTime,omitempty"`
+	Locations               map[string]*Location        `json:"locations,omitempty"`
+	VirtualLocations        map[string]*VirtualLocation `json:"virtualLocations,omitempty"`
+	Links                   map[string]*Link            `json:"links,omitempty"`
+	Locale                  string                      `json:"locale,omitempty"`
+	Keywords                map[string]bool             `json:"keywords,omitempty"`
+	Categories              map[string]bool             `json:"categories,omitempty"`
+	Color                   string                      `json:"color,omitempty"`
+	RecurrenceID            string                      `json:"recurrenceId,omitempty"`
+	RecurrenceIDTimeZone    string                      `json:"recurrenceIdTimeZone,omitempty"`
+	RecurrenceRules         []*RecurrenceRule           `json:"recurrenceRules,omitempty"`
+	ExcludedRecurrenceRules []*RecurrenceRule           `json:"excludedRecurrenceRules,omitempty"`
+	RecurrenceOverrides     map[string]PatchObject      `json:"recurrenceOverrides,omitempty"`
+	Excluded                bool                        `json:"excluded,omitempty"`
+	Priority                int                         `json:"priority,omitempty"`
+	FreeBusyStatus          string                      `json:"freeBusyStatus,omitempty"`
+	Privacy                 string                      `json:"privacy,omitempty"`
+	ReplyTo                 map[string]string           `json:"replyTo,omitempty"`
+	SentBy                  string                      `json:"sentBy,omitempty"`
+	Participants            map[string]*Participant     `json:"participants,omitempty"`
+	UseDefaultAlerts        bool                        `json:"useDefaultAlerts,omitempty"`
+	Alerts                  map[string]*Alert           `json:"alerts,omitempty"`
+	Localizations           map[string]PatchObject      `json:"localizations,omitempty"`
+	TimeZone                string                      `json:"timeZone,omitempty"`
+	TimeZones               map[string]interface{}      `json:"timeZones,omitempty"`
+
+	// ICalendar is content lines of iCalendar which JSCalendar can not represent
+	ICalendar []string `json:"knsh14.github.io:icalendar,omitempty"`
+
+	// Vendor is other properties which are not defined in this package
+	Vendor map[string]interface{} `json:"-"`
+}
+
+// Event is JSCalendar Event object
+// https://tools.ietf.org/html/rfc8984#section-2.1
+type Event struct {
+	Common
+	Start    string `json:"start,omitempty"`
+	Duration string `json:"duration,omitempty"`
+	Status   string `json:"status,omitempty"`
+}
+
+// Task is JSCalendar Task object
+// https://tools.ietf.org/html/rfc8984#section-2.2
+type Task struct {
+	Common
+	Due               string `json:"due,omitempty"`
+	Start             string `json:"start,omitempty"`
+	EstimatedDuration string `json:"estimatedDuration,omitempty"`
+	PercentComplete   *int   `json:"percentComplete,omitempty"`
+	Progress          string `json:"progress,omitempty"`
+	ProgressUpdated   string `json:"progressUpdated,omitempty"`
+}
+
+// Relation is relation to other object
+// https://tools.ietf.org/html/rfc8984#section-1.4.10
+type Relation struct {
+	Type     string          `json:"@type"`
+	Relation map[string]bool `json:"relation,omitempty"`
+}
+
+// Location is physical location
+// https://tools.ietf.org/html/rfc8984#section-4.2.5
+type Location struct {
+	Type        string `json:"@type"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	Coordinates string `json:"coordinates,omitempty"`
+	RelativeTo  string `json:"relativeTo,omitempty"`
+	TimeZone    string `json:"timeZone,omitempty"`
+}
+
+// VirtualLocation is virtual location such as video conference
+// https://tools.ietf.org/html/rfc8984#section-4.2.6
+type VirtualLocation struct {
+	Type        string `json:"@type"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	URI         string `json:"uri"`
+}
+
+// Link is link to external resource
+// https://tools.ietf.org/html/rfc8984#section-1.4.11
+type Link struct {
+	Type        string `json:"@type"`
+	Href        string `json:"href"`
+	ContentType string `json:"contentType,omitempty"`
+	Size        int    `json:"size,omitempty"`
+	Rel         string `json:"rel,omitempty"`
+	Title       string `json:"title,omitempty"`
+}
+
+// RecurrenceRule is rule of recurrence
+// https://tools.ietf.org/html/rfc8984#section-4.3.3
+type RecurrenceRule struct {
+	Type           string   `json:"@type"`
+	Frequency      string   `json:"frequency"`
+	Interval       int      `json:"interval,omitempty"`
+	RScale         string   `json:"rscale,omitempty"`
+	Skip           string   `json:"skip,omitempty"`
+	FirstDayOfWeek string   `json:"firstDayOfWeek,omitempty"`
+	ByDay          []*NDay  `json:"byDay,omitempty"`
+	ByMonthDay     []int    `json:"byMonthDay,omitempty"`
+	ByMonth        []string `json:"byMonth,omitempty"`
+	ByYearDay      []int    `json:"byYearDay,omitempty"`
+	ByWeekNo       []int    `json:"byWeekNo,omitempty"`
+	ByHour         []int    `json:"byHour,omitempty"`
+	ByMinute       []int    `json:"byMinute,omitempty"`
+	BySecond       []int    `json:"bySecond,omitempty"`
+	BySetPosition  []int    `json:"bySetPosition,omitempty"`
+	Count          int      `json:"count,omitempty"`
+	Until          string   `json:"until,omitempty"`
+}
+
+// NDay is day of the week with optional position
+// https://tools.ietf.org/html/rfc8984#section-4.3.3
+type NDay struct {
+	Type        string `json:"@type"`
+	Day         string `json:"day"`
+	NthOfPeriod int    `json:"nthOfPeriod,omitempty"`
+}
+
+// Participant is participant of the object
+// https://tools.ietf.org/html/rfc8984#section-4.4.6
+type Participant struct {
+	Type                 string            `json:"@type"`
+	Name                 string            `json:"name,omitempty"`
+	Email                string            `json:"email,omitempty"`
+	Description          string            `json:"description,omitempty"`
+	SendTo               map[string]string `json:"sendTo,omitempty"`
+	Kind                 string            `json:"kind,omitempty"`
+	Roles                map[string]bool   `json:"roles,omitempty"`
+	LocationID           string            `json:"locationId,omitempty"`
+	Language             string            `json:"language,omitempty"`
+	ParticipationStatus  string            `json:"participationStatus,omitempty"`
+	ParticipationComment string            `json:"participationComment,omitempty"`
+	ExpectReply          bool              `json:"expectReply,omitempty"`
+	ScheduleAgent        string            `json:"scheduleAgent,omitempty"`
+	ScheduleSequence     int               `json:"scheduleSequence,omitempty"`
+	DelegatedTo          map[string]bool   `json:"delegatedTo,omitempty"`
+	DelegatedFrom        map[string]bool   `json:"delegatedFrom,omitempty"`
+	MemberOf             map[string]bool   `json:"memberOf,omitempty"`
+	InvitedBy            string            `json:"invitedBy,omitempty"`
+}
+
+// Alert is alert of the object
+// https://tools.ietf.org/html/rfc8984#section-4.5.2
+type Alert struct {
+	Type         string   `json:"@type"`
+	Trigger      *Trigger `json:"trigger"`
+	Acknowledged string   `json:"acknowledged,omitempty"`
+	Action       string   `json:"action,omitempty"`
+
+	// ICalendar is content lines of VALARM which JSCalendar can not represent
+	ICalendar []string `json:"knsh14.github.io:icalendar,omitempty"`
+}
+
+// Trigger is OffsetTrigger or AbsoluteTrigger
+// https://tools.ietf.org/html/rfc8984#section-4.5.2
+type Trigger struct {
+	Type       string `json:"@type"`
+	Offset     string `json:"offset,omitempty"`
+	RelativeTo string `json:"relativeTo,omitempty"`
+	When       string `json:"when,omitempty"`
+}
+
+func (e Event) MarshalJSON() ([]byte, error) {
+	type event Event
+	return marshalWithVendor(event(e), e.Vendor)
+}
+
+func (e *Event) UnmarshalJSON(b []byte) error {
+	type event Event
+	var v event
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	vendor, err := unknownFields(b, reflect.TypeOf(v))
+	if err != nil {
+		return err
+	}
+	*e = Event(v)
+	e.Vendor = vendor
+	return nil
+}
+
+func (t Task) MarshalJSON() ([]byte, error) {
+	type task Task
+	return marshalWithVendor(task(t), t.Vendor)
+}
+
+func (t *Task) UnmarshalJSON(b []byte) error {
+	type task Task
+	var v task
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	vendor, err := unknownFields(b, reflect.TypeOf(v))
+	if err != nil {
+		return err
+	}
+	*t = Task(v)
+	t.Vendor = vendor
+	return nil
+}
+
+// marshalWithVendor returns JSON of v which has properties of vendor too
+func marshalWithVendor(v interface{}, vendor map[string]interface{}) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil || len(vendor) == 0 {
+		return b, err
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	for k, v := range vendor {
+		if _, ok := m[k]; !ok {
+			m[k] = v
+		}
+	}
+	return json.Marshal(m)
+}
+
+// unknownFields returns properties in b which are not fields of t
+func unknownFields(b []byte, t reflect.Type) (map[string]interface{}, error) {
+	var m map[string]interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	for _, name := range jsonNames(t) {
+		delete(m, name)
+	}
+	if len(m) == 0 {
+		return nil, nil
+	}
+	return m, nil
+}
+
+// jsonNames returns JSON property names of struct t including embedded structs
+func jsonNames(t reflect.Type) []string {
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous {
+			names = append(names, jsonNames(f.Type)...)
+			continue
+		}
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name != "" && name != "-" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
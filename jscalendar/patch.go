@@ -0,0 +1,106 @@
+package jscalendar
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// idMaps is properties whose value is map of id and object.
+// diff compares them for each id.
+var idMaps = map[string]bool{
+	"relatedTo":           true,
+	"locations":           true,
+	"virtualLocations":    true,
+	"links":               true,
+	"keywords":            true,
+	"categories":          true,
+	"recurrenceOverrides": true,
+	"replyTo":             true,
+	"participants":        true,
+	"alerts":              true,
+	"localizations":       true,
+	"timeZones":           true,
+}
+
+// diff returns patch which makes got to want
+func diff(want, got map[string]interface{}) PatchObject {
+	patch := PatchObject{}
+	for _, k := range unionKeys(want, got) {
+		w, wok := want[k]
+		g, gok := got[k]
+		if wok && gok && reflect.DeepEqual(w, g) {
+			continue
+		}
+		wm, wIsMap := w.(map[string]interface{})
+		gm, gIsMap := g.(map[string]interface{})
+		if !idMaps[k] || !wIsMap || !gIsMap {
+			patch[escapePointer(k)] = w
+			continue
+		}
+		for _, id := range unionKeys(wm, gm) {
+			if v, ok := wm[id]; !ok || !reflect.DeepEqual(v, gm[id]) {
+				patch[escapePointer(k)+"/"+escapePointer(id)] = v
+			}
+		}
+	}
+	return patch
+}
+
+// apply applies patch to m
+// https://tools.ietf.org/html/rfc8984#section-1.4.9
+func apply(m map[string]interface{}, patch PatchObject) error {
+	pointers := make([]string, 0, len(patch))
+	for p := range patch {
+		pointers = append(pointers, p)
+	}
+	// parent is patched before its children
+	sort.Strings(pointers)
+	for _, p := range pointers {
+		keys := strings.Split(strings.TrimPrefix(p, "/"), "/")
+		target := m
+		for _, k := range keys[:len(keys)-1] {
+			k = unescapePointer(k)
+			next, ok := target[k].(map[string]interface{})
+			if !ok {
+				if _, exists := target[k]; exists {
+					return fmt.Errorf("%s is not object in %s", k, p)
+				}
+				next = map[string]interface{}{}
+				target[k] = next
+			}
+			target = next
+		}
+		last := unescapePointer(keys[len(keys)-1])
+		if v := patch[p]; v != nil {
+			target[last] = v
+		} else {
+			delete(target, last)
+		}
+	}
+	return nil
+}
+
+func unionKeys(a, b map[string]interface{}) []string {
+	var keys []string
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// https://tools.ietf.org/html/rfc6901#section-3
+func escapePointer(s string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
+}
+
+func unescapePointer(s string) string {
+	return strings.NewReplacer("~1", "/", "~0", "~").Replace(s)
+}
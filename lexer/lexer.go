@@ -14,6 +14,8 @@ type Lexer struct {
 	ch           rune
 
 	checkFunc func(rune) bool
+	// inValue is true after COLON, value part can contain any characters except COMMA
+	inValue bool
 }
 
 // New returns lexer
@@ -36,8 +38,13 @@ func (l *Lexer) readChar() {
 func (l *Lexer) NextToken() token.Token {
 	var tok token.Token
 
+	if l.inValue {
+		return l.nextValueToken()
+	}
+
 	l.skipWhitespace()
 
+	position := l.position
 	switch l.ch {
 	case '=':
 		tok = newToken(token.ASSIGN, l.ch)
@@ -53,6 +60,7 @@ func (l *Lexer) NextToken() token.Token {
 	case ':':
 		tok = newToken(token.COLON, l.ch)
 		l.checkFunc = isValue
+		l.inValue = true
 	case 0:
 		tok.Value = ""
 		tok.Type = token.EOF
@@ -60,15 +68,45 @@ func (l *Lexer) NextToken() token.Token {
 		if l.checkFunc(l.ch) {
 			tok.Value = l.readIdentifier()
 			tok.Type = token.IDENT
+			tok.Pos = position
 			return tok
 		}
 		tok = newToken(token.ILLEGAL, l.ch)
 	}
 
+	tok.Pos = position
 	l.readChar()
 	return tok
 }
 
+// nextValueToken reads value part of content line.
+// backslash escaped characters such as "\," are kept in the value as it is.
+func (l *Lexer) nextValueToken() token.Token {
+	position := l.position
+	switch l.ch {
+	case ',':
+		tok := newToken(token.COMMA, l.ch)
+		tok.Pos = position
+		l.readChar()
+		return tok
+	case 0:
+		return token.Token{Type: token.EOF, Value: "", Pos: position}
+	}
+	if !isValue(l.ch) {
+		tok := newToken(token.ILLEGAL, l.ch)
+		tok.Pos = position
+		l.readChar()
+		return tok
+	}
+	for isValue(l.ch) {
+		if l.ch == '\\' && l.readPosition < len(l.input) {
+			l.readChar()
+		}
+		l.readChar()
+	}
+	return token.Token{Type: token.IDENT, Value: string(l.input[position:l.position]), Pos: position}
+}
+
 func newToken(tokenType token.Type, ch rune) token.Token {
 	return token.Token{Type: tokenType, Value: string(ch)}
 }
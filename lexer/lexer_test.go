@@ -181,6 +181,26 @@ this is test`},
 				{Type: token.EOF, Value: ""},
 			},
 		},
+		{
+			input: `SUMMARY:Lunch\, dinner\; and tea,second`,
+			expect: []token.Token{
+				{Type: token.IDENT, Value: "SUMMARY"},
+				{Type: token.COLON, Value: ":"},
+				{Type: token.IDENT, Value: `Lunch\, dinner\; and tea`},
+				{Type: token.COMMA, Value: ","},
+				{Type: token.IDENT, Value: "second"},
+				{Type: token.EOF, Value: ""},
+			},
+		},
+		{
+			input: `DESCRIPTION:;"quoted" = value`,
+			expect: []token.Token{
+				{Type: token.IDENT, Value: "DESCRIPTION"},
+				{Type: token.COLON, Value: ":"},
+				{Type: token.IDENT, Value: `;"quoted" = value`},
+				{Type: token.EOF, Value: ""},
+			},
+		},
 	}
 
 	for i, tt := range tests {
@@ -200,3 +220,15 @@ this is test`},
 		})
 	}
 }
+
+func TestLexer_Pos(t *testing.T) {
+	t.Parallel()
+	lexer := New(`DTSTART;TZID="Asia/Tokyo":20200301T100000,20200302T100000`)
+	expect := []int{0, 7, 8, 12, 13, 25, 26, 41, 42, 57}
+	for i, pos := range expect {
+		tok := lexer.NextToken()
+		if tok.Pos != pos {
+			t.Fatalf("token %d %v: expect=%d, got=%d", i, tok, pos, tok.Pos)
+		}
+	}
+}
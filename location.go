@@ -0,0 +1,608 @@
+package ical
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/knsh14/ical/component"
+	"github.com/knsh14/ical/errcode"
+	"github.com/knsh14/ical/parameter"
+	"github.com/knsh14/ical/property"
+	"github.com/knsh14/ical/types"
+)
+
+// locationEndYear is the last year of transitions which Location calculates from RRULE
+const locationEndYear = 2200
+
+// maxTransitions is limit of transitions of an observance.
+// it protects Location from RRULE which has too many occurrences until locationEndYear.
+const maxTransitions = 10000
+
+// Location returns *time.Location which follows STANDARD and DAYLIGHT rules of tz.
+// name of the location is TZID of tz.
+// https://tools.ietf.org/html/rfc5545#section-3.6.5
+func (tz *Timezone) Location() (*time.Location, error) {
+	if tz.TimezoneIdentifier == nil {
+		return nil, NewValidationError(component.TypeTimezone, property.NameTimezoneIdentifier, "must not to be nil")
+	}
+	var ts []transition
+	for _, s := range tz.Standards {
+		t, err := transitions(component.TypeStandard, s.DateTimeStart, s.TimezoneOffsetFrom, s.TimezoneOffsetTo, s.RecurrenceRule, s.RecurrenceDateTimes, s.TimezoneName, false)
+		if err != nil {
+			return nil, err
+		}
+		ts = append(ts, t...)
+	}
+	for _, d := range tz.Daylights {
+		t, err := transitions(component.TypeDaylight, d.DateTimeStart, d.TimezoneOffsetFrom, d.TimezoneOffsetTo, d.RecurrenceRule, d.RecurrenceDateTimes, d.TimezoneName, true)
+		if err != nil {
+			return nil, err
+		}
+		ts = append(ts, t...)
+	}
+	if len(ts) == 0 {
+		return nil, fmt.Errorf("%s has no %s or %s", component.TypeTimezone, component.TypeStandard, component.TypeDaylight)
+	}
+	sort.SliceStable(ts, func(i, j int) bool { return ts[i].at < ts[j].at })
+	name := string(tz.TimezoneIdentifier.Value)
+	return time.LoadLocationFromTZData(name, tzData(ts))
+}
+
+// transition is an onset of STANDARD or DAYLIGHT
+type transition struct {
+	at     int64 // unix time
+	from   int   // offset before the onset in seconds
+	offset int   // offset after the onset in seconds
+	isDST  bool
+	name   string
+}
+
+// transitions returns onsets of an observance.
+// DTSTART, RRULE and RDATE of the observance are local time in TZOFFSETFROM.
+func transitions(ct component.Type, start *property.DateTimeStart, from *property.TimezoneOffsetFrom, to *property.TimezoneOffsetTo, rule *property.RecurrenceRule, dates *property.RecurrenceDateTimes, name *property.TimezoneName, isDST bool) ([]transition, error) {
+	if start == nil {
+		return nil, NewValidationError(ct, property.NameDateTimeStart, "must not to be nil")
+	}
+	if from == nil {
+		return nil, NewValidationError(ct, property.NameTimezoneOffsetFrom, "must not to be nil")
+	}
+	if to == nil {
+		return nil, NewValidationError(ct, property.NameTimezoneOffsetTo, "must not to be nil")
+	}
+	base := transition{
+		from:   from.Value.Seconds(),
+		offset: to.Value.Seconds(),
+		isDST:  isDST,
+		name:   to.Value.String(),
+	}
+	if name != nil && name.Value != "" {
+		base.name = string(name.Value)
+	}
+	loc := time.FixedZone("", base.from)
+	wall := func(t time.Time) time.Time {
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, loc)
+	}
+
+	var res []transition
+	add := func(t time.Time) {
+		tr := base
+		tr.at = t.Unix()
+		res = append(res, tr)
+	}
+	dtstart := wall(timeOf(start.Value))
+	add(dtstart)
+	if rule != nil {
+		// onsets of observance occur once a year at most
+		if rule.Value.Frequency != types.FrequencyPatternYearly {
+			return nil, errcode.Errorf(errcode.Invalid, "%s of %s must be FREQ=%s, but %s", property.NameRecurrenceRule, ct, types.FrequencyPatternYearly, rule.Value.Frequency)
+		}
+		it := rule.Value.Iterator(dtstart)
+		for t, ok := it.Next(); ok && t.Year() <= locationEndYear; t, ok = it.Next() {
+			if len(res) >= maxTransitions {
+				return nil, errcode.Errorf(errcode.Invalid, "%s of %s has more than %d onsets", property.NameRecurrenceRule, ct, maxTransitions)
+			}
+			if !t.Equal(dtstart) {
+				add(t)
+			}
+		}
+	}
+	if dates != nil {
+		for _, v := range dates.Values {
+			switch rd := v.(type) {
+			case types.DateTime:
+				t := time.Time(rd)
+				if t.Location() != time.UTC {
+					t = wall(t)
+				}
+				add(t)
+			case types.Date:
+				add(wall(time.Time(rd)))
+			case types.Period:
+				add(wall(time.Time(rd.Start)))
+			}
+		}
+	}
+	return res, nil
+}
+
+// tzData returns TZif version 2 data of ts.
+// ts must be sorted by onset.
+// https://tools.ietf.org/html/rfc8536
+func tzData(ts []transition) []byte {
+	type zone struct {
+		offset int
+		isDST  bool
+		name   string
+	}
+	// first zone is offset before first onset, it is not referred by transitions
+	zones := []zone{{offset: ts[0].from, name: utcOffsetName(ts[0].from)}}
+	for _, t := range ts {
+		if t.offset == ts[0].from {
+			zones[0].isDST = t.isDST
+			zones[0].name = t.name
+			break
+		}
+	}
+	var times []int64
+	var indexes []byte
+	for i, t := range ts {
+		if i > 0 && t.at == ts[i-1].at {
+			continue
+		}
+		z := zone{offset: t.offset, isDST: t.isDST, name: t.name}
+		idx := -1
+		for i := 1; i < len(zones); i++ {
+			if zones[i] == z {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			idx = len(zones)
+			zones = append(zones, z)
+		}
+		times = append(times, t.at)
+		indexes = append(indexes, byte(idx))
+	}
+
+	var chars []byte
+	abbrs := map[string]int{}
+	for _, z := range zones {
+		if _, ok := abbrs[z.name]; !ok {
+			abbrs[z.name] = len(chars)
+			chars = append(chars, z.name...)
+			chars = append(chars, 0)
+		}
+	}
+
+	var buf bytes.Buffer
+	put := func(v interface{}) {
+		// writing to bytes.Buffer never fails
+		_ = binary.Write(&buf, binary.BigEndian, v)
+	}
+	header := func(timecnt int) {
+		buf.WriteString("TZif2")
+		buf.Write(make([]byte, 15))
+		// isutcnt, isstdcnt, leapcnt, timecnt, typecnt, charcnt
+		put([]uint32{0, 0, 0, uint32(timecnt), uint32(len(zones)), uint32(len(chars))})
+	}
+	body := func(indexes []byte) {
+		buf.Write(indexes)
+		for _, z := range zones {
+			put(int32(z.offset))
+			if z.isDST {
+				buf.WriteByte(1)
+			} else {
+				buf.WriteByte(0)
+			}
+			buf.WriteByte(byte(abbrs[z.name]))
+		}
+		buf.Write(chars)
+	}
+
+	// version 1 data has no transitions, readers use version 2 data
+	header(0)
+	body(nil)
+	// version 2 data
+	header(len(times))
+	put(times)
+	body(indexes)
+	// no footer
+	buf.WriteString("\n\n")
+	return buf.Bytes()
+}
+
+func utcOffsetName(offset int) string {
+	sign := "+"
+	if offset < 0 {
+		sign = "-"
+		offset = -offset
+	}
+	return fmt.Sprintf("%s%02d%02d", sign, offset/3600, offset/60%60)
+}
+
+// NewTimezoneFromLocation returns VTIMEZONE which follows loc from from to to.
+// STANDARD and DAYLIGHT are derived from transitions of loc,
+// yearly transitions on the same rule are compacted to RRULE.
+// https://tools.ietf.org/html/rfc5545#section-3.6.5
+func NewTimezoneFromLocation(loc *time.Location, from, to time.Time) (*Timezone, error) {
+	if loc == nil {
+		return nil, fmt.Errorf("location must not be nil")
+	}
+	if to.Before(from) {
+		return nil, fmt.Errorf("to %v is before from %v", to, from)
+	}
+	tz := NewTimezone()
+	if err := tz.SetTimezoneID(parameter.Container{}, types.NewText(loc.String())); err != nil {
+		return nil, err
+	}
+
+	// a transition before from defines offset at from
+	ts := locationTransitions(loc, from.AddDate(-1, 0, 0), to)
+	for len(ts) > 1 && ts[1].at <= from.Unix() {
+		ts = ts[1:]
+	}
+	if len(ts) == 0 || ts[0].at > from.Unix() {
+		name, offset := from.In(loc).Zone()
+		first := transition{at: from.Unix(), from: offset, offset: offset, name: name}
+		ts = append([]transition{first}, ts...)
+	}
+
+	var obs []*observance
+	singles := map[transition]*observance{}
+	for _, r := range yearlyRuns(ts) {
+		key := r.transitions[0]
+		key.at = 0
+		if len(r.transitions) > 1 {
+			obs = append(obs, &observance{transition: key, onsets: []time.Time{onset(r.transitions[0])}, run: r})
+			continue
+		}
+		// transitions which are not yearly are put together as RDATE
+		if o, ok := singles[key]; ok {
+			o.onsets = append(o.onsets, onset(r.transitions[0]))
+			continue
+		}
+		o := &observance{transition: key, onsets: []time.Time{onset(r.transitions[0])}}
+		singles[key] = o
+		obs = append(obs, o)
+	}
+	for _, o := range obs {
+		// rule which lasts until the end of the range is regarded as current one
+		current := o.run != nil && o.run.transitions[len(o.run.transitions)-1].at > to.AddDate(-1, 0, 0).Unix()
+		if err := o.addTo(tz, current); err != nil {
+			return nil, err
+		}
+	}
+	return tz, nil
+}
+
+// AddMissingTimezones adds VTIMEZONE for each TZID which is referred in c but not defined.
+// VTIMEZONEs follow time zones from from to to.
+// location of TZID is location of the referring value, or one in IANA time zone database.
+func (c *Calendar) AddMissingTimezones(from, to time.Time) error {
+	defined := c.definedTimezones()
+	locs := map[string]*time.Location{}
+	var tzids []string
+	for _, ref := range c.timezoneReferences() {
+		if defined[ref.tzid] {
+			continue
+		}
+		if _, ok := locs[ref.tzid]; !ok {
+			tzids = append(tzids, ref.tzid)
+			locs[ref.tzid] = nil
+		}
+		if ref.t.Location().String() == ref.tzid {
+			locs[ref.tzid] = ref.t.Location()
+		}
+	}
+
+	var tzs []CalenderComponent
+	for _, tzid := range tzids {
+		loc := locs[tzid]
+		if loc == nil {
+			l, err := time.LoadLocation(tzid)
+			if err != nil {
+				return fmt.Errorf("get location of TZID %s: %w", tzid, err)
+			}
+			loc = l
+		}
+		tz, err := NewTimezoneFromLocation(loc, from, to)
+		if err != nil {
+			return fmt.Errorf("create %s of TZID %s: %w", component.TypeTimezone, tzid, err)
+		}
+		if err := tz.SetTimezoneID(parameter.Container{}, types.NewText(tzid)); err != nil {
+			return err
+		}
+		tzs = append(tzs, tz)
+	}
+	c.Components = append(tzs, c.Components...)
+	return nil
+}
+
+// definedTimezones returns TZIDs of VTIMEZONEs in c
+func (c *Calendar) definedTimezones() map[string]bool {
+	defined := map[string]bool{}
+	for _, cc := range c.Components {
+		if tz, ok := cc.(*Timezone); ok && tz.TimezoneIdentifier != nil {
+			defined[string(tz.TimezoneIdentifier.Value)] = true
+		}
+	}
+	return defined
+}
+
+// timezoneReference is a value which refers TZID
+type timezoneReference struct {
+	kind component.Type
+	name property.Name
+	tzid string
+	t    time.Time
+}
+
+// timezoneReferences returns values with TZID parameter in components of c
+func (c *Calendar) timezoneReferences() []timezoneReference {
+	var res []timezoneReference
+	for _, cc := range c.Components {
+		var r recurrence
+		refer := func(name property.Name, params parameter.Container, t time.Time) {
+			if tzid := params.GetTimezone(); tzid != "" {
+				res = append(res, timezoneReference{kind: r.kind, name: name, tzid: tzid, t: t})
+			}
+		}
+		switch v := cc.(type) {
+		case *Event:
+			r = v.recurrence()
+			if v.DateTimeEnd != nil {
+				refer(property.NameDateTimeEnd, v.DateTimeEnd.Parameter, timeOf(v.DateTimeEnd.Value))
+			}
+		case *ToDo:
+			r = v.recurrence()
+			if v.DateTimeDue != nil {
+				refer(property.NameDateTimeDue, v.DateTimeDue.Parameter, timeOf(v.DateTimeDue.Value))
+			}
+		case *Journal:
+			r = recurrence{
+				kind:         component.TypeJournal,
+				start:        v.DateTimeStart,
+				dates:        v.RecurrenceDateTimes,
+				exceptions:   v.ExceptionDateTimes,
+				recurrenceID: v.RecurrenceID,
+			}
+		default:
+			continue
+		}
+		if r.start != nil {
+			refer(property.NameDateTimeStart, r.start.Parameter, timeOf(r.start.Value))
+		}
+		if r.recurrenceID != nil {
+			refer(property.NameRecurrenceID, r.recurrenceID.Parameter, timeOf(r.recurrenceID.Value))
+		}
+		for _, exdt := range r.exceptions {
+			for _, v := range exdt.Values {
+				refer(property.NameExceptionDateTimes, exdt.Parameter, timeOf(v))
+			}
+		}
+		for _, rdt := range r.dates {
+			for _, v := range rdt.Values {
+				switch rd := v.(type) {
+				case types.DateTime:
+					refer(property.NameRecurrenceDateTimes, rdt.Parameter, time.Time(rd))
+				case types.Period:
+					refer(property.NameRecurrenceDateTimes, rdt.Parameter, time.Time(rd.Start))
+				}
+			}
+		}
+	}
+	return res
+}
+
+// locationTransitions returns changes of offset or abbreviation of loc between from and to.
+// a change to larger offset is regarded as DAYLIGHT.
+func locationTransitions(loc *time.Location, from, to time.Time) []transition {
+	// changes of time zone are far longer than a day apart
+	const step = 24 * 60 * 60
+	var res []transition
+	prev := from.Unix()
+	prevName, prevOffset := time.Unix(prev, 0).In(loc).Zone()
+	for prev < to.Unix() {
+		next := prev + step
+		name, offset := time.Unix(next, 0).In(loc).Zone()
+		if name == prevName && offset == prevOffset {
+			prev = next
+			continue
+		}
+		// the change is in (prev, next]
+		lo, hi := prev, next
+		for hi-lo > 1 {
+			mid := lo + (hi-lo)/2
+			n, o := time.Unix(mid, 0).In(loc).Zone()
+			if n == prevName && o == prevOffset {
+				lo = mid
+			} else {
+				hi = mid
+			}
+		}
+		name, offset = time.Unix(hi, 0).In(loc).Zone()
+		res = append(res, transition{at: hi, from: prevOffset, offset: offset, isDST: offset > prevOffset, name: name})
+		prev, prevName, prevOffset = hi, name, offset
+	}
+	return res
+}
+
+// onset returns local time of t in TZOFFSETFROM
+func onset(t transition) time.Time {
+	return time.Unix(t.at, 0).In(time.FixedZone("", t.from))
+}
+
+// yearlyRun is transitions which occur every year on the same rule
+type yearlyRun struct {
+	transitions []transition
+	// candidates of rule which all transitions match
+	last     bool
+	week     bool
+	monthDay bool
+}
+
+func (r *yearlyRun) rule(t transition) (last, week, monthDay bool) {
+	first, o := onset(r.transitions[0]), onset(t)
+	if o.Month() != first.Month() || o.Weekday() != first.Weekday() && o.Day() != first.Day() {
+		return false, false, false
+	}
+	if h, m, s := o.Clock(); h != first.Hour() || m != first.Minute() || s != first.Second() {
+		return false, false, false
+	}
+	last = r.last && isLastWeekDay(o) && o.Weekday() == first.Weekday()
+	week = r.week && (o.Day()-1)/7 == (first.Day()-1)/7 && o.Weekday() == first.Weekday()
+	monthDay = r.monthDay && o.Day() == first.Day()
+	return last, week, monthDay
+}
+
+// yearlyRuns splits ts into runs of yearly transitions
+func yearlyRuns(ts []transition) []*yearlyRun {
+	var runs []*yearlyRun
+	for _, t := range ts {
+		var joined bool
+		for i := len(runs) - 1; i >= 0 && !joined; i-- {
+			r := runs[i]
+			prev := r.transitions[len(r.transitions)-1]
+			if prev.from != t.from || prev.offset != t.offset || prev.isDST != t.isDST || prev.name != t.name {
+				continue
+			}
+			if onset(t).Year() != onset(prev).Year()+1 {
+				continue
+			}
+			last, week, monthDay := r.rule(t)
+			if !last && !week && !monthDay {
+				continue
+			}
+			r.transitions = append(r.transitions, t)
+			r.last, r.week, r.monthDay = last, week, monthDay
+			joined = true
+		}
+		if !joined {
+			o := onset(t)
+			runs = append(runs, &yearlyRun{transitions: []transition{t}, last: isLastWeekDay(o), week: true, monthDay: true})
+		}
+	}
+	return runs
+}
+
+func isLastWeekDay(t time.Time) bool {
+	return t.AddDate(0, 0, 7).Month() != t.Month()
+}
+
+var weekDayPatterns = map[time.Weekday]types.WeekDayPattern{
+	time.Sunday:    types.WeekDayPatternSunday,
+	time.Monday:    types.WeekDayPatternMonday,
+	time.Tuesday:   types.WeekDayPatternTuesday,
+	time.Wednesday: types.WeekDayPatternWednesday,
+	time.Thursday:  types.WeekDayPatternThursday,
+	time.Friday:    types.WeekDayPatternFriday,
+	time.Saturday:  types.WeekDayPatternSaturday,
+}
+
+// observance is STANDARD or DAYLIGHT to be created
+type observance struct {
+	transition
+	onsets []time.Time
+	run    *yearlyRun
+}
+
+// addTo adds o to tz.
+// RRULE has no UNTIL if o is current observance.
+func (o *observance) addTo(tz *Timezone, current bool) error {
+	var rule *types.RecurrenceRule
+	if o.run != nil {
+		first := o.onsets[0]
+		rr := types.RecurrenceRule{
+			Frequency: types.FrequencyPatternYearly,
+			ByMonth:   []int64{int64(first.Month())},
+		}
+		switch {
+		case o.run.last:
+			rr.ByDay = []types.WeekDay{{Week: -1, Day: weekDayPatterns[first.Weekday()]}}
+		case o.run.week:
+			rr.ByDay = []types.WeekDay{{Week: int64((first.Day()-1)/7 + 1), Day: weekDayPatterns[first.Weekday()]}}
+		default:
+			rr.ByMonthDay = []int64{int64(first.Day())}
+		}
+		if !current {
+			last := o.run.transitions[len(o.run.transitions)-1]
+			rr.EndDate = types.DateTime(time.Unix(last.at, 0).UTC())
+		}
+		rule = &rr
+	}
+	var rdates []types.RecurrenceDateTimeValue
+	for _, t := range o.onsets[1:] {
+		rdates = append(rdates, types.DateTime(t))
+	}
+	from := utcOffset(o.from)
+	to := utcOffset(o.offset)
+	params := parameter.Container{}
+
+	if o.isDST {
+		d := NewDaylight()
+		if err := d.SetStart(params, types.DateTime(o.onsets[0])); err != nil {
+			return err
+		}
+		if err := d.SetTimezoneOffsetFrom(params, from); err != nil {
+			return err
+		}
+		if err := d.SetTimezoneOffsetTo(params, to); err != nil {
+			return err
+		}
+		if rule != nil {
+			if err := d.SetRecurrenceRule(params, *rule); err != nil {
+				return err
+			}
+		}
+		if len(rdates) > 0 {
+			if err := d.SetRecurrenceDateTimes(params, rdates); err != nil {
+				return err
+			}
+		}
+		if err := d.SetTimezoneName(params, types.NewText(o.name)); err != nil {
+			return err
+		}
+		tz.Daylights = append(tz.Daylights, d)
+		return nil
+	}
+	s := NewStandard()
+	if err := s.SetStart(params, types.DateTime(o.onsets[0])); err != nil {
+		return err
+	}
+	if err := s.SetTimezoneOffsetFrom(params, from); err != nil {
+		return err
+	}
+	if err := s.SetTimezoneOffsetTo(params, to); err != nil {
+		return err
+	}
+	if rule != nil {
+		if err := s.SetRecurrenceRule(params, *rule); err != nil {
+			return err
+		}
+	}
+	if len(rdates) > 0 {
+		if err := s.SetRecurrenceDateTimes(params, rdates); err != nil {
+			return err
+		}
+	}
+	if err := s.SetTimezoneName(params, types.NewText(o.name)); err != nil {
+		return err
+	}
+	tz.Standards = append(tz.Standards, s)
+	return nil
+}
+
+func utcOffset(offset int) types.UTCOffset {
+	o := types.UTCOffset{Direction: offset >= 0}
+	if offset < 0 {
+		offset = -offset
+	}
+	o.Hour = uint64(offset / 3600)
+	o.Minute = uint64(offset / 60 % 60)
+	o.Second = uint64(offset % 60)
+	return o
+}
@@ -0,0 +1,131 @@
+package ical
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/knsh14/ical/errcode"
+	"github.com/knsh14/ical/parameter"
+	"github.com/knsh14/ical/types"
+	"github.com/morikuni/failure"
+)
+
+func TestNewTimezoneFromLocation(t *testing.T) {
+	t.Parallel()
+	from := time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	testcases := map[string]struct {
+		name      string
+		standards int
+		daylights int
+	}{
+		"no daylight saving time": {
+			name:      "Asia/Tokyo",
+			standards: 1,
+		},
+		"yearly rules": {
+			name:      "America/New_York",
+			standards: 1,
+			daylights: 1,
+		},
+		"daylight saving time is abolished": {
+			name:      "America/Sao_Paulo",
+			standards: 2,
+			daylights: 2,
+		},
+	}
+
+	for title, tt := range testcases {
+		tt := tt
+		t.Run(title, func(t *testing.T) {
+			t.Parallel()
+			loc, err := time.LoadLocation(tt.name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tz, err := NewTimezoneFromLocation(loc, from, to)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff([]int{tt.standards, tt.daylights}, []int{len(tz.Standards), len(tz.Daylights)}); diff != "" {
+				t.Errorf("number of STANDARD and DAYLIGHT (-want, +got)\n%s", diff)
+			}
+			got, err := tz.Location()
+			if err != nil {
+				t.Fatal(err)
+			}
+			for v := from; v.Before(to); v = v.Add(time.Hour) {
+				wantName, wantOffset := v.In(loc).Zone()
+				gotName, gotOffset := v.In(got).Zone()
+				if wantName != gotName || wantOffset != gotOffset {
+					t.Fatalf("zone at %v: want %s %d, got %s %d", v, wantName, wantOffset, gotName, gotOffset)
+				}
+			}
+		})
+	}
+}
+
+func TestTimezone_Location_Error(t *testing.T) {
+	t.Parallel()
+	testcases := map[string]struct {
+		rule string
+	}{
+		"not yearly": {
+			rule: "FREQ=MINUTELY",
+		},
+		"too many onsets": {
+			rule: "FREQ=YEARLY;BYMONTH=1;BYMONTHDAY=1;BYHOUR=0,3,6,9,12,15,18,21;BYMINUTE=0,15,30,45",
+		},
+	}
+
+	for title, tt := range testcases {
+		tt := tt
+		t.Run(title, func(t *testing.T) {
+			t.Parallel()
+			rule, err := types.NewRecurrenceRule(tt.rule)
+			mustNoError(t, err)
+			offset, err := types.NewUTCOffset("+0900")
+			mustNoError(t, err)
+			s := NewStandard()
+			mustNoError(t, s.SetStart(parameter.Container{}, types.DateTime(time.Date(1601, 1, 1, 0, 0, 0, 0, time.UTC))))
+			mustNoError(t, s.SetTimezoneOffsetFrom(parameter.Container{}, offset))
+			mustNoError(t, s.SetTimezoneOffsetTo(parameter.Container{}, offset))
+			mustNoError(t, s.SetRecurrenceRule(parameter.Container{}, rule))
+			tz := NewTimezone()
+			mustNoError(t, tz.SetTimezoneID(parameter.Container{}, "Runaway"))
+			tz.Standards = append(tz.Standards, s)
+			if _, err := tz.Location(); !failure.Is(err, errcode.Invalid) {
+				t.Errorf("expected %s, but %v", errcode.Invalid, err)
+			}
+		})
+	}
+}
+
+func TestCalendar_AddMissingTimezones(t *testing.T) {
+	t.Parallel()
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	mustNoError(t, err)
+	c := newTestCalendar(t)
+	defined := NewTimezone()
+	mustNoError(t, defined.SetTimezoneID(parameter.Container{}, types.NewText("Europe/London")))
+	e := newTestEvent(t)
+	mustNoError(t, e.SetDateTimeStart(parameter.Container{
+		parameter.TypeNameReferenceTimezone: []parameter.Base{&parameter.ReferenceTimezone{Value: "Asia/Tokyo"}},
+	}, types.DateTime(time.Date(2020, 1, 1, 9, 0, 0, 0, tokyo))))
+	mustNoError(t, e.SetDateTimeEnd(parameter.Container{
+		parameter.TypeNameReferenceTimezone: []parameter.Base{&parameter.ReferenceTimezone{Value: "Europe/London"}},
+	}, types.DateTime(time.Date(2020, 1, 1, 1, 0, 0, 0, time.UTC))))
+	c.Components = append(c.Components, defined, e)
+
+	mustNoError(t, c.AddMissingTimezones(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)))
+	var got []string
+	for _, cc := range c.Components {
+		if tz, ok := cc.(*Timezone); ok {
+			got = append(got, string(tz.TimezoneIdentifier.Value))
+		}
+	}
+	if diff := cmp.Diff([]string{"Asia/Tokyo", "Europe/London"}, got); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
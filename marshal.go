@@ -0,0 +1,683 @@
+package ical
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/knsh14/ical/component"
+	"github.com/knsh14/ical/errcode"
+	"github.com/knsh14/ical/parameter"
+	"github.com/knsh14/ical/property"
+	"github.com/knsh14/ical/token"
+	"github.com/knsh14/ical/types"
+)
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// Marshal returns VEVENT of struct v or pointer to it.
+// exported fields are mapped to properties by tag such as `ical:"DTSTART"` or `ical:"X-BOOKING-ID"`,
+// fields without tag, nil pointers and zero values are skipped.
+// option "date" makes DATE instead of DATE-TIME, such as `ical:"DTSTART,date"`.
+// DTSTAMP is the current time if v does not have it, as EventBuilder.
+//
+// fields of properties are
+//
+//	string: UID, CLASS, DESCRIPTION, LOCATION, STATUS, SUMMARY, TRANSP, URL, RRULE and ORGANIZER
+//	string or []string: ATTENDEE, CATEGORIES, COMMENT, CONTACT, RELATED-TO and RESOURCES
+//	time.Time: DTSTART, DTEND, RECURRENCE-ID, DTSTAMP, CREATED and LAST-MODIFIED
+//	time.Time or []time.Time: EXDATE
+//	time.Duration: DURATION
+//	int: PRIORITY and SEQUENCE
+//	string, []string, bool, int, float or time.Time: X-name properties
+func Marshal(v interface{}) (*Event, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, errcode.Errorf(errcode.Invalid, "marshal %T: struct is required", v)
+	}
+	fields, err := taggedFieldsOf(rv)
+	if err != nil {
+		return nil, err
+	}
+	e := NewEvent()
+	for _, f := range fields {
+		if f.value.Kind() == reflect.Ptr {
+			if f.value.IsNil() {
+				continue
+			}
+			f.value = f.value.Elem()
+		}
+		if f.value.IsZero() {
+			continue
+		}
+		if err := f.setEventProperty(e); err != nil {
+			return nil, buildError(f.name, err)
+		}
+	}
+	if e.DateTimeStamp == nil {
+		if err := e.SetDateTimeStamp(parameter.Container{}, types.DateTime(time.Now().UTC().Truncate(time.Second))); err != nil {
+			return nil, buildError(property.NameDateTimeStamp, err)
+		}
+	}
+	return e, nil
+}
+
+// Unmarshal stores properties of e in struct which v points to.
+// fields are mapped by tag as Marshal, and fields of properties which e does not have are left unchanged.
+// a field which is not slice gets the first value of property which has multiple values.
+func Unmarshal(e *Event, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errcode.Errorf(errcode.Invalid, "unmarshal into %T: non-nil pointer to struct is required", v)
+	}
+	if e == nil {
+		return errcode.Errorf(errcode.Invalid, "unmarshal nil %s", component.TypeEvent)
+	}
+	fields, err := taggedFieldsOf(rv.Elem())
+	if err != nil {
+		return err
+	}
+	for _, f := range fields {
+		value, err := f.eventProperty(e)
+		if err != nil {
+			return fmt.Errorf("get %s: %w", f.name, err)
+		}
+		if value == nil {
+			continue
+		}
+		if err := f.assign(value); err != nil {
+			return fmt.Errorf("get %s: %w", f.name, err)
+		}
+	}
+	return nil
+}
+
+// taggedField is a struct field which has ical tag
+type taggedField struct {
+	name  property.Name
+	date  bool
+	field reflect.StructField
+	value reflect.Value
+}
+
+// taggedFieldsOf returns fields of rv which have ical tag, fields of embedded structs are included
+func taggedFieldsOf(rv reflect.Value) ([]taggedField, error) {
+	var fields []taggedField
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		tag, ok := sf.Tag.Lookup("ical")
+		if !ok {
+			if sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+				fs, err := taggedFieldsOf(rv.Field(i))
+				if err != nil {
+					return nil, err
+				}
+				fields = append(fields, fs...)
+			}
+			continue
+		}
+		if tag == "-" || sf.PkgPath != "" {
+			continue
+		}
+		opts := strings.Split(tag, ",")
+		f := taggedField{name: property.Name(opts[0]), field: sf, value: rv.Field(i)}
+		for _, o := range opts[1:] {
+			switch o {
+			case "date":
+				f.date = true
+			default:
+				return nil, errcode.Errorf(errcode.Invalid, "field %s has unknown option %s", sf.Name, o)
+			}
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+func (f taggedField) typeError() error {
+	return errcode.Errorf(errcode.ValueFormat, "field %s of %s can not be %s", f.field.Name, f.field.Type, f.name)
+}
+
+func (f taggedField) text() (string, error) {
+	if f.value.Kind() != reflect.String {
+		return "", f.typeError()
+	}
+	return f.value.String(), nil
+}
+
+func (f taggedField) texts() ([]types.Text, error) {
+	switch {
+	case f.value.Kind() == reflect.String:
+		return []types.Text{types.NewText(f.value.String())}, nil
+	case f.value.Kind() == reflect.Slice && f.value.Type().Elem().Kind() == reflect.String:
+		res := make([]types.Text, f.value.Len())
+		for i := range res {
+			res[i] = types.NewText(f.value.Index(i).String())
+		}
+		return res, nil
+	}
+	return nil, f.typeError()
+}
+
+func (f taggedField) integer() (types.Integer, error) {
+	if !isInt(f.value.Kind()) || f.value.Type() == durationType {
+		return 0, f.typeError()
+	}
+	return types.Integer(f.value.Int()), nil
+}
+
+func (f taggedField) duration() (types.Duration, error) {
+	if f.value.Type() != durationType {
+		return types.Duration{}, f.typeError()
+	}
+	return durationOf(time.Duration(f.value.Int())), nil
+}
+
+// timeValue returns DATE or DATE-TIME of t by date option
+func (f taggedField) timeValue(t time.Time) (parameter.Container, types.TimeValue, error) {
+	if f.date {
+		params, d := dateOf(t)
+		return params, d, nil
+	}
+	return dateTimeOf(t)
+}
+
+func (f taggedField) timeField() (parameter.Container, types.TimeValue, error) {
+	if f.value.Type() != timeType {
+		return nil, nil, f.typeError()
+	}
+	return f.timeValue(f.value.Interface().(time.Time))
+}
+
+func (f taggedField) utcTimeField() (types.DateTime, error) {
+	if f.value.Type() != timeType {
+		return types.DateTime{}, f.typeError()
+	}
+	return types.DateTime(f.value.Interface().(time.Time).UTC().Truncate(time.Second)), nil
+}
+
+// timesField returns DATE or DATE-TIME of time.Time or []time.Time, they have TZID of the first one
+func (f taggedField) timesField() (parameter.Container, []types.TimeValue, error) {
+	var ts []time.Time
+	switch {
+	case f.value.Type() == timeType:
+		ts = []time.Time{f.value.Interface().(time.Time)}
+	case f.value.Kind() == reflect.Slice && f.value.Type().Elem() == timeType:
+		ts = f.value.Interface().([]time.Time)
+	default:
+		return nil, nil, f.typeError()
+	}
+	var params parameter.Container
+	res := make([]types.TimeValue, len(ts))
+	for i, t := range ts {
+		if i > 0 && !f.date {
+			t = t.In(ts[0].Location())
+		}
+		p, v, err := f.timeValue(t)
+		if err != nil {
+			return nil, nil, err
+		}
+		if i == 0 {
+			params = p
+		}
+		res[i] = v
+	}
+	return params, res, nil
+}
+
+// xValue returns value of X-name property
+func (f taggedField) xValue() (parameter.Container, interface{}, error) {
+	switch {
+	case f.value.Type() == timeType:
+		return f.timeField()
+	case f.value.Type() == durationType:
+		d, err := f.duration()
+		return parameter.Container{}, d, err
+	}
+	switch f.value.Kind() {
+	case reflect.String, reflect.Slice:
+		ts, err := f.texts()
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(ts) == 1 && f.value.Kind() == reflect.String {
+			return parameter.Container{}, ts[0], nil
+		}
+		return parameter.Container{}, ts, nil
+	case reflect.Bool:
+		b, err := types.NewBoolean(strconv.FormatBool(f.value.Bool()))
+		return parameter.Container{}, b, err
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := types.NewInteger(strconv.FormatInt(f.value.Int(), 10))
+		return parameter.Container{}, i, err
+	case reflect.Float32, reflect.Float64:
+		fl, err := types.NewFloat(strconv.FormatFloat(f.value.Float(), 'f', -1, 64))
+		return parameter.Container{}, fl, err
+	}
+	return nil, nil, f.typeError()
+}
+
+// setEventProperty sets property of f to e
+func (f taggedField) setEventProperty(e *Event) error {
+	params := parameter.Container{}
+	switch f.name {
+	case property.NameUID, property.NameClass, property.NameDescription, property.NameLocation, property.NameStatus, property.NameSummary:
+		s, err := f.text()
+		if err != nil {
+			return err
+		}
+		setters := map[property.Name]func(parameter.Container, types.Text) error{
+			property.NameUID:         e.SetUID,
+			property.NameClass:       e.SetClass,
+			property.NameDescription: e.SetDescription,
+			property.NameLocation:    e.SetLocation,
+			property.NameStatus:      e.SetStatus,
+			property.NameSummary:     e.SetSummary,
+		}
+		return setters[f.name](params, types.NewText(s))
+	case property.NameTimeTransparency:
+		s, err := f.text()
+		if err != nil {
+			return err
+		}
+		return e.SetTimeTransparency(params, property.TransparencyValueType(s))
+	case property.NameURL:
+		s, err := f.text()
+		if err != nil {
+			return err
+		}
+		u, err := types.NewURI(s)
+		if err != nil {
+			return err
+		}
+		return e.SetURL(params, u)
+	case property.NameRecurrenceRule:
+		s, err := f.text()
+		if err != nil {
+			return err
+		}
+		rr, err := types.NewRecurrenceRule(s)
+		if err != nil {
+			return err
+		}
+		return e.SetRecurrenceRule(params, rr)
+	case property.NameOrganizer:
+		s, err := f.text()
+		if err != nil {
+			return err
+		}
+		cua, err := types.NewCalenderUserAddress(s)
+		if err != nil {
+			return err
+		}
+		return e.SetOrganizer(params, cua)
+	case property.NameAttendee:
+		ts, err := f.texts()
+		if err != nil {
+			return err
+		}
+		for _, t := range ts {
+			cua, err := types.NewCalenderUserAddress(string(t))
+			if err != nil {
+				return err
+			}
+			if err := e.AddAttendee(parameter.Container{}, cua); err != nil {
+				return err
+			}
+		}
+		return nil
+	case property.NameCategories, property.NameResources:
+		ts, err := f.texts()
+		if err != nil {
+			return err
+		}
+		if f.name == property.NameCategories {
+			return e.AddCategories(params, ts)
+		}
+		return e.AddResources(params, ts)
+	case property.NameComment, property.NameContact, property.NameRelatedTo:
+		ts, err := f.texts()
+		if err != nil {
+			return err
+		}
+		adders := map[property.Name]func(parameter.Container, types.Text) error{
+			property.NameComment:   e.AddComment,
+			property.NameContact:   e.AddContact,
+			property.NameRelatedTo: e.AddRelatedTo,
+		}
+		for _, t := range ts {
+			if err := adders[f.name](parameter.Container{}, t); err != nil {
+				return err
+			}
+		}
+		return nil
+	case property.NameDateTimeStart, property.NameDateTimeEnd, property.NameRecurrenceID:
+		params, t, err := f.timeField()
+		if err != nil {
+			return err
+		}
+		setters := map[property.Name]func(parameter.Container, types.TimeValue) error{
+			property.NameDateTimeStart: e.SetDateTimeStart,
+			property.NameDateTimeEnd:   e.SetDateTimeEnd,
+			property.NameRecurrenceID:  e.SetRecurrenceID,
+		}
+		return setters[f.name](params, t)
+	case property.NameDateTimeStamp, property.NameDateTimeCreated, property.NameLastModified:
+		t, err := f.utcTimeField()
+		if err != nil {
+			return err
+		}
+		setters := map[property.Name]func(parameter.Container, types.DateTime) error{
+			property.NameDateTimeStamp:   e.SetDateTimeStamp,
+			property.NameDateTimeCreated: e.SetDateTimeCreated,
+			property.NameLastModified:    e.SetLastModified,
+		}
+		return setters[f.name](params, t)
+	case property.NameExceptionDateTimes:
+		params, ts, err := f.timesField()
+		if err != nil {
+			return err
+		}
+		return e.AddExceptionDateTimes(params, ts)
+	case property.NameDuration:
+		d, err := f.duration()
+		if err != nil {
+			return err
+		}
+		return e.SetDuration(params, d)
+	case property.NamePriority, property.NameSequenceNumber:
+		i, err := f.integer()
+		if err != nil {
+			return err
+		}
+		if f.name == property.NamePriority {
+			return e.SetPriority(params, i)
+		}
+		return e.SetSequenceNumber(params, i)
+	}
+	if !token.IsXName(string(f.name)) {
+		return errcode.Errorf(errcode.UnknownProperty, "field %s: %s is not supported", f.field.Name, f.name)
+	}
+	params, v, err := f.xValue()
+	if err != nil {
+		return err
+	}
+	ns, err := property.NewNonStandard(string(f.name), params, v)
+	if err != nil {
+		return err
+	}
+	e.XProperties = append(e.XProperties, ns)
+	return nil
+}
+
+// eventProperty returns value of property of f in e as string, []string, time.Time, []time.Time, time.Duration or int64.
+// it returns nil if e does not have the property.
+func (f taggedField) eventProperty(e *Event) (interface{}, error) {
+	switch f.name {
+	case property.NameUID:
+		if e.UID != nil {
+			return string(e.UID.Value), nil
+		}
+	case property.NameClass:
+		if e.Class != nil {
+			return string(e.Class.Value), nil
+		}
+	case property.NameDescription:
+		if e.Description != nil {
+			return string(e.Description.Value), nil
+		}
+	case property.NameLocation:
+		if e.Location != nil {
+			return string(e.Location.Value), nil
+		}
+	case property.NameStatus:
+		if e.Status != nil {
+			return string(e.Status.Value), nil
+		}
+	case property.NameSummary:
+		if e.Summary != nil {
+			return string(e.Summary.Value), nil
+		}
+	case property.NameTimeTransparency:
+		if e.TimeTransparency != nil {
+			return string(e.TimeTransparency.Value), nil
+		}
+	case property.NameURL:
+		if e.URL != nil {
+			return e.URL.Value.String(), nil
+		}
+	case property.NameRecurrenceRule:
+		if e.RecurrenceRule != nil {
+			return e.RecurrenceRule.Value.String(), nil
+		}
+	case property.NameOrganizer:
+		if e.Organizer != nil {
+			return e.Organizer.Value.String(), nil
+		}
+	case property.NameAttendee:
+		var res []string
+		for _, a := range e.Attendees {
+			res = append(res, a.Value.String())
+		}
+		return stringsOrNil(res), nil
+	case property.NameCategories:
+		return stringsOrNil(categoriesOf(e.Categories)), nil
+	case property.NameResources:
+		var res []string
+		for _, r := range e.Resources {
+			res = append(res, stringsOfTexts(r.Values)...)
+		}
+		return stringsOrNil(res), nil
+	case property.NameComment:
+		var res []string
+		for _, c := range e.Comments {
+			res = append(res, string(c.Value))
+		}
+		return stringsOrNil(res), nil
+	case property.NameContact:
+		var res []string
+		for _, c := range e.Contacts {
+			res = append(res, string(c.Value))
+		}
+		return stringsOrNil(res), nil
+	case property.NameRelatedTo:
+		var res []string
+		for _, r := range e.RelatedTos {
+			res = append(res, string(r.Value))
+		}
+		return stringsOrNil(res), nil
+	case property.NameDateTimeStart:
+		if e.DateTimeStart != nil {
+			return timeOf(e.DateTimeStart.Value), nil
+		}
+	case property.NameDateTimeEnd:
+		if e.DateTimeEnd != nil {
+			return timeOf(e.DateTimeEnd.Value), nil
+		}
+	case property.NameRecurrenceID:
+		if e.RecurrenceID != nil {
+			return timeOf(e.RecurrenceID.Value), nil
+		}
+	case property.NameDateTimeStamp:
+		if e.DateTimeStamp != nil {
+			return time.Time(e.DateTimeStamp.Value), nil
+		}
+	case property.NameDateTimeCreated:
+		if e.DateTimeCreated != nil {
+			return time.Time(e.DateTimeCreated.Value), nil
+		}
+	case property.NameLastModified:
+		if e.LastModified != nil {
+			return time.Time(e.LastModified.Value), nil
+		}
+	case property.NameExceptionDateTimes:
+		var res []time.Time
+		for _, ex := range e.ExceptionDateTimes {
+			for _, v := range ex.Values {
+				res = append(res, timeOf(v))
+			}
+		}
+		if len(res) > 0 {
+			return res, nil
+		}
+	case property.NameDuration:
+		if e.Duration != nil {
+			return durationValue(e.Duration.Value), nil
+		}
+	case property.NamePriority:
+		if e.Priority != nil {
+			return int64(e.Priority.Value), nil
+		}
+	case property.NameSequenceNumber:
+		if e.SequenceNumber != nil {
+			return int64(e.SequenceNumber.Value), nil
+		}
+	default:
+		if !token.IsXName(string(f.name)) {
+			return nil, errcode.Errorf(errcode.UnknownProperty, "field %s: %s is not supported", f.field.Name, f.name)
+		}
+		for _, ns := range e.XProperties {
+			if property.Name(ns.Name) == f.name {
+				return f.xField(ns)
+			}
+		}
+	}
+	return nil, nil
+}
+
+// xField converts value of X-name property into type of f with types.New* functions
+func (f taggedField) xField(ns *property.NonStandard) (interface{}, error) {
+	vs := xStrings(ns.Value)
+	if len(vs) == 0 {
+		return nil, nil
+	}
+	t := f.field.Type
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch {
+	case t == timeType:
+		v, err := NewTimeTypeInLocation(ns.Parameter, vs[0], nil)
+		if err != nil {
+			return nil, err
+		}
+		return timeOf(v), nil
+	case t == durationType:
+		d, err := types.NewDuration(vs[0])
+		if err != nil {
+			return nil, err
+		}
+		return durationValue(d), nil
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return strings.Join(vs, ","), nil
+	case reflect.Slice:
+		return vs, nil
+	case reflect.Bool:
+		b, err := types.NewBoolean(vs[0])
+		return bool(b), err
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := types.NewInteger(vs[0])
+		return int64(i), err
+	case reflect.Float32, reflect.Float64:
+		fl, err := types.NewFloat(vs[0])
+		return float64(fl), err
+	}
+	return nil, f.typeError()
+}
+
+// xStrings returns unescaped values of X-name property.
+// values from parser are raw []string, and values from Marshal are types.
+func xStrings(value interface{}) []string {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []string:
+		res := make([]string, len(v))
+		for i, s := range v {
+			res[i] = string(types.UnescapeText(s))
+		}
+		return res
+	case string:
+		return []string{v}
+	case types.Text:
+		return []string{string(v)}
+	case []types.Text:
+		return stringsOfTexts(v)
+	case fmt.Stringer:
+		return []string{v.String()}
+	default:
+		return []string{fmt.Sprint(v)}
+	}
+}
+
+// assign sets v to field of f.
+// v is converted to type of the field, the first value of v is set if v is slice and the field is not.
+func (f taggedField) assign(v interface{}) error {
+	dst := f.value
+	if dst.Kind() == reflect.Ptr {
+		if dst.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+		dst = dst.Elem()
+	}
+	src := reflect.ValueOf(v)
+	if src.Kind() == reflect.Slice && dst.Kind() != reflect.Slice {
+		src = src.Index(0)
+	}
+	switch {
+	case src.Type().AssignableTo(dst.Type()):
+		dst.Set(src)
+	case src.Kind() == dst.Kind() && src.Type().ConvertibleTo(dst.Type()):
+		dst.Set(src.Convert(dst.Type()))
+	case isInt(src.Kind()) && isInt(dst.Kind()) && dst.Type() != durationType,
+		src.Kind() == reflect.Float64 && (dst.Kind() == reflect.Float32 || dst.Kind() == reflect.Float64):
+		dst.Set(src.Convert(dst.Type()))
+	default:
+		return f.typeError()
+	}
+	return nil
+}
+
+// durationValue returns d as time.Duration, a day is 24 hours
+func durationValue(d types.Duration) time.Duration {
+	res := time.Duration(d.Week*7+d.Day)*24*time.Hour + d.HourDuration
+	if d.Direction == "-" {
+		return -res
+	}
+	return res
+}
+
+func isInt(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	}
+	return false
+}
+
+func stringsOfTexts(ts []types.Text) []string {
+	res := make([]string, len(ts))
+	for i, t := range ts {
+		res[i] = string(t)
+	}
+	return res
+}
+
+// stringsOrNil returns nil interface for empty ss, so that the field is left unchanged
+func stringsOrNil(ss []string) interface{} {
+	if len(ss) == 0 {
+		return nil
+	}
+	return ss
+}
@@ -0,0 +1,302 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/knsh14/ical/errcode"
+	"github.com/knsh14/ical/parameter"
+	"github.com/knsh14/ical/property"
+	"github.com/knsh14/ical/types"
+	"github.com/morikuni/failure"
+)
+
+type testBookingBase struct {
+	ID string `ical:"UID"`
+}
+
+type testBooking struct {
+	testBookingBase
+	Stamp     time.Time     `ical:"DTSTAMP"`
+	Start     time.Time     `ical:"DTSTART"`
+	Length    time.Duration `ical:"DURATION"`
+	Title     string        `ical:"SUMMARY"`
+	Note      *string       `ical:"DESCRIPTION"`
+	Guests    []string      `ical:"ATTENDEE"`
+	Tags      []string      `ical:"CATEGORIES"`
+	Revision  int           `ical:"SEQUENCE"`
+	BookingID string        `ical:"X-BOOKING-ID"`
+	Seats     int           `ical:"X-SEATS"`
+	Paid      bool          `ical:"X-PAID"`
+	Memo      string
+	Secret    string `ical:"-"`
+}
+
+type testDeadline struct {
+	ID   string      `ical:"UID"`
+	Day  time.Time   `ical:"DTSTART,date"`
+	Skip []time.Time `ical:"EXDATE,date"`
+	Rule string      `ical:"RRULE"`
+}
+
+func TestMarshal(t *testing.T) {
+	t.Parallel()
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	mustNoError(t, err)
+	note := "bring laptop"
+
+	testcases := map[string]struct {
+		input    interface{}
+		expected []string
+		// stamped is true if DTSTAMP is the current time
+		stamped bool
+	}{
+		"booking": {
+			input: &testBooking{
+				testBookingBase: testBookingBase{ID: "booking-1@example.com"},
+				Stamp:           time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+				Start:           time.Date(2020, 1, 10, 10, 0, 0, 0, tokyo),
+				Length:          90 * time.Minute,
+				Title:           "Room A",
+				Note:            &note,
+				Guests:          []string{"mailto:bob@example.com", "mailto:carol@example.com"},
+				Tags:            []string{"ROOM", "BOOKING"},
+				BookingID:       "B-123",
+				Seats:           4,
+				Paid:            true,
+				Memo:            "not exported",
+				Secret:          "not exported",
+			},
+			expected: []string{
+				"BEGIN:VEVENT",
+				"UID:booking-1@example.com",
+				"DTSTAMP:20200102T030405Z",
+				"DTSTART;TZID=Asia/Tokyo:20200110T100000",
+				"DESCRIPTION:bring laptop",
+				"SUMMARY:Room A",
+				"DURATION:PT1H30M",
+				"ATTENDEE:mailto:bob@example.com",
+				"ATTENDEE:mailto:carol@example.com",
+				"CATEGORIES:ROOM,BOOKING",
+				"X-BOOKING-ID:B-123",
+				"X-SEATS:4",
+				"X-PAID:TRUE",
+				"END:VEVENT",
+			},
+		},
+		"deadline": {
+			input: testDeadline{
+				ID:   "deadline-1@example.com",
+				Day:  time.Date(2020, 1, 31, 0, 0, 0, 0, tokyo),
+				Skip: []time.Time{time.Date(2020, 2, 29, 0, 0, 0, 0, tokyo)},
+				Rule: "FREQ=MONTHLY;BYMONTHDAY=-1",
+			},
+			expected: []string{
+				"BEGIN:VEVENT",
+				"UID:deadline-1@example.com",
+				"DTSTAMP:now",
+				"DTSTART;VALUE=DATE:20200131",
+				"RRULE:FREQ=MONTHLY;BYMONTHDAY=-1",
+				"EXDATE;VALUE=DATE:20200229",
+				"END:VEVENT",
+			},
+			stamped: true,
+		},
+	}
+
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			before := time.Now().Truncate(time.Second)
+			e, err := Marshal(tc.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.stamped {
+				if stamp := time.Time(e.DateTimeStamp.Value); stamp.Before(before) || stamp.After(time.Now()) {
+					t.Errorf("expected DTSTAMP of current time, but %v", stamp)
+				}
+			}
+			b := &bytes.Buffer{}
+			mustNoError(t, e.Decode(b))
+			got := strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n")
+			if tc.stamped {
+				for i := range got {
+					if strings.HasPrefix(got[i], "DTSTAMP:") {
+						got[i] = "DTSTAMP:now"
+					}
+				}
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("diff: (-expected +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMarshal_Encode(t *testing.T) {
+	t.Parallel()
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	mustNoError(t, err)
+	deadline := testDeadline{
+		ID:   "deadline-1@example.com",
+		Day:  time.Date(2020, 1, 31, 0, 0, 0, 0, tokyo),
+		Skip: []time.Time{time.Date(2020, 2, 29, 0, 0, 0, 0, tokyo)},
+		Rule: "FREQ=MONTHLY;BYMONTHDAY=-1",
+	}
+	e, err := Marshal(deadline)
+	mustNoError(t, err)
+	c := newTestCalendar(t)
+	c.Components = append(c.Components, e)
+	if err := NewEncoder(&bytes.Buffer{}).Encode(c); err != nil {
+		t.Fatalf("expected no error, but %v", err)
+	}
+
+	var got testDeadline
+	if err := Unmarshal(e, &got); err != nil {
+		t.Fatal(err)
+	}
+	// DATE is read as midnight in UTC
+	expected := deadline
+	expected.Day = time.Date(2020, 1, 31, 0, 0, 0, 0, time.UTC)
+	expected.Skip = []time.Time{time.Date(2020, 2, 29, 0, 0, 0, 0, time.UTC)}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("diff: (-expected +got)\n%s", diff)
+	}
+}
+
+func TestMarshal_Error(t *testing.T) {
+	t.Parallel()
+
+	testcases := map[string]struct {
+		input interface{}
+		code  failure.Code
+	}{
+		"not struct": {
+			input: "booking",
+			code:  errcode.Invalid,
+		},
+		"unknown option": {
+			input: struct {
+				Start time.Time `ical:"DTSTART,utc"`
+			}{Start: time.Now()},
+			code: errcode.Invalid,
+		},
+		"unsupported property": {
+			input: struct {
+				Version string `ical:"VERSION"`
+			}{Version: "2.0"},
+			code: errcode.UnknownProperty,
+		},
+		"wrong type": {
+			input: struct {
+				Start string `ical:"DTSTART"`
+			}{Start: "20200110T100000Z"},
+			code: errcode.ValueFormat,
+		},
+		"invalid value": {
+			input: struct {
+				Rule string `ical:"RRULE"`
+			}{Rule: "FREQ=SOMETIMES"},
+			code: errcode.ValueFormat,
+		},
+	}
+
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			_, err := Marshal(tc.input)
+			if !failure.Is(err, tc.code) {
+				t.Errorf("expected %s, but %v", tc.code, err)
+			}
+		})
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	t.Parallel()
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	mustNoError(t, err)
+	note := "bring laptop"
+	booking := testBooking{
+		testBookingBase: testBookingBase{ID: "booking-1@example.com"},
+		Stamp:           time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+		Start:           time.Date(2020, 1, 10, 10, 0, 0, 0, tokyo),
+		Length:          90 * time.Minute,
+		Title:           "Room A",
+		Note:            &note,
+		Guests:          []string{"mailto:bob@example.com"},
+		Tags:            []string{"ROOM", "BOOKING"},
+		Revision:        2,
+		BookingID:       "B-123",
+		Seats:           4,
+		Paid:            true,
+	}
+	e, err := Marshal(booking)
+	mustNoError(t, err)
+
+	t.Run("round trip", func(t *testing.T) {
+		t.Parallel()
+		var got testBooking
+		got.Memo = "kept"
+		if err := Unmarshal(e, &got); err != nil {
+			t.Fatal(err)
+		}
+		expected := booking
+		expected.Memo = "kept"
+		if diff := cmp.Diff(expected, got, cmp.AllowUnexported(testBooking{})); diff != "" {
+			t.Errorf("diff: (-expected +got)\n%s", diff)
+		}
+	})
+
+	t.Run("parsed values", func(t *testing.T) {
+		t.Parallel()
+		// parser keeps values of X-name properties as raw []string
+		parsed := NewEvent()
+		mustNoError(t, parsed.SetDateTimeStart(parameter.Container{}, types.DateTime(booking.Start.UTC())))
+		for name, v := range map[string][]string{
+			"X-BOOKING-ID": {`B\,123`},
+			"X-SEATS":      {"4"},
+			"X-PAID":       {"FALSE"},
+		} {
+			ns, err := property.NewNonStandard(name, parameter.Container{}, v)
+			mustNoError(t, err)
+			parsed.XProperties = append(parsed.XProperties, ns)
+		}
+		var got struct {
+			Start     *time.Time `ical:"DTSTART"`
+			BookingID string     `ical:"X-BOOKING-ID"`
+			Seats     int64      `ical:"X-SEATS"`
+			Paid      bool       `ical:"X-PAID"`
+			Missing   string     `ical:"X-MISSING"`
+		}
+		got.Paid = true
+		if err := Unmarshal(parsed, &got); err != nil {
+			t.Fatal(err)
+		}
+		if got.Start == nil || !got.Start.Equal(booking.Start) {
+			t.Errorf("expected %v, but %v", booking.Start, got.Start)
+		}
+		if got.BookingID != "B,123" || got.Seats != 4 || got.Paid || got.Missing != "" {
+			t.Errorf("unexpected %+v", got)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		t.Parallel()
+		var wrong struct {
+			Start int `ical:"DTSTART"`
+		}
+		if err := Unmarshal(e, &wrong); !failure.Is(err, errcode.ValueFormat) {
+			t.Errorf("expected %s, but %v", errcode.ValueFormat, err)
+		}
+		if err := Unmarshal(e, booking); !failure.Is(err, errcode.Invalid) {
+			t.Errorf("expected %s, but %v", errcode.Invalid, err)
+		}
+	})
+}
@@ -0,0 +1,381 @@
+package ical
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/knsh14/ical/component"
+	"github.com/knsh14/ical/parameter"
+	"github.com/knsh14/ical/property"
+	"github.com/knsh14/ical/types"
+)
+
+// Occurrence is an instance of VEVENT or VTODO
+type Occurrence struct {
+	Start time.Time
+	End   time.Time
+	// RecurrenceID is start time of the instance in the recurrence set
+	RecurrenceID time.Time
+	// Component is VEVENT or VTODO which defines the instance.
+	// it is an override which has RECURRENCE-ID if the instance is overridden.
+	Component CalenderComponent
+}
+
+// Occurrences returns instances of e which overlap with start and end.
+// overrides are VEVENTs which share UID of e and have RECURRENCE-ID.
+// https://tools.ietf.org/html/rfc5545#section-3.8.5
+func (e *Event) Occurrences(start, end time.Time, overrides ...*Event) ([]Occurrence, error) {
+	var rs []recurrence
+	for _, o := range overrides {
+		rs = append(rs, o.recurrence())
+	}
+	return occurrences(e.recurrence(), rs, start, end)
+}
+
+// Occurrences returns instances of todo which overlap with start and end.
+// overrides are VTODOs which share UID of todo and have RECURRENCE-ID.
+// https://tools.ietf.org/html/rfc5545#section-3.8.5
+func (todo *ToDo) Occurrences(start, end time.Time, overrides ...*ToDo) ([]Occurrence, error) {
+	var rs []recurrence
+	for _, o := range overrides {
+		rs = append(rs, o.recurrence())
+	}
+	return occurrences(todo.recurrence(), rs, start, end)
+}
+
+// Occurrences returns instances of VEVENTs and VTODOs in c which overlap with start and end.
+// components which have RECURRENCE-ID override instances of the component which has the same UID.
+// components which have no DTSTART, RECURRENCE-ID or DUE of VTODO are not scheduled, so they are skipped.
+func (c *Calendar) Occurrences(start, end time.Time) ([]Occurrence, error) {
+	type group struct {
+		master    *recurrence
+		overrides []recurrence
+	}
+	groups := map[string]*group{}
+	var keys []string
+	for _, cc := range c.Components {
+		var r recurrence
+		switch v := cc.(type) {
+		case *Event:
+			r = v.recurrence()
+		case *ToDo:
+			r = v.recurrence()
+		default:
+			continue
+		}
+		if !r.scheduled() {
+			continue
+		}
+		key := fmt.Sprintf("%T/%s", cc, r.uid)
+		g, ok := groups[key]
+		if !ok {
+			g = &group{}
+			groups[key] = g
+			keys = append(keys, key)
+		}
+		if r.recurrenceID == nil {
+			r := r
+			g.master = &r
+			continue
+		}
+		g.overrides = append(g.overrides, r)
+	}
+
+	var res []Occurrence
+	for _, key := range keys {
+		g := groups[key]
+		if g.master == nil {
+			// only overridden instances are in the calendar
+			for _, o := range g.overrides {
+				os, err := occurrences(o, nil, start, end)
+				if err != nil {
+					return nil, err
+				}
+				res = append(res, os...)
+			}
+			continue
+		}
+		os, err := occurrences(*g.master, g.overrides, start, end)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, os...)
+	}
+	sort.SliceStable(res, func(i, j int) bool { return res[i].Start.Before(res[j].Start) })
+	return res, nil
+}
+
+// recurrence is properties of VEVENT and VTODO to calculate occurrences
+type recurrence struct {
+	component    CalenderComponent
+	kind         component.Type
+	uid          string
+	start        *property.DateTimeStart
+	end          types.TimeValue // DTEND or DUE
+	duration     *property.Duration
+	rule         *property.RecurrenceRule
+	dates        []*property.RecurrenceDateTimes
+	exceptions   []*property.ExceptionDateTimes
+	recurrenceID *property.RecurrenceID
+}
+
+func (e *Event) recurrence() recurrence {
+	r := recurrence{
+		component:    e,
+		kind:         component.TypeEvent,
+		start:        e.DateTimeStart,
+		duration:     e.Duration,
+		rule:         e.RecurrenceRule,
+		dates:        e.RecurrenceDateTimes,
+		exceptions:   e.ExceptionDateTimes,
+		recurrenceID: e.RecurrenceID,
+	}
+	if e.UID != nil {
+		r.uid = string(e.UID.Value)
+	}
+	if e.DateTimeEnd != nil {
+		r.end = e.DateTimeEnd.Value
+	}
+	return r
+}
+
+func (todo *ToDo) recurrence() recurrence {
+	r := recurrence{
+		component:    todo,
+		kind:         component.TypeTODO,
+		start:        todo.DateTimeStart,
+		duration:     todo.Duration,
+		rule:         todo.RecurrenceRule,
+		dates:        todo.RecurrenceDateTimes,
+		exceptions:   todo.ExceptionDateTimes,
+		recurrenceID: todo.RecurrenceID,
+	}
+	if todo.UID != nil {
+		r.uid = string(todo.UID.Value)
+	}
+	if todo.DateTimeDue != nil {
+		r.end = todo.DateTimeDue.Value
+	}
+	return r
+}
+
+// scheduled returns true if r has time to start
+func (r recurrence) scheduled() bool {
+	_, err := r.startTime()
+	return err == nil
+}
+
+// startTime returns DTSTART, or RECURRENCE-ID if DTSTART is not set.
+// VTODO which has neither of them starts at DUE.
+func (r recurrence) startTime() (time.Time, error) {
+	switch {
+	case r.start != nil:
+		return timeOf(r.start.Value), nil
+	case r.recurrenceID != nil:
+		return timeOf(r.recurrenceID.Value), nil
+	case r.kind == component.TypeTODO && r.end != nil:
+		return timeOf(r.end), nil
+	default:
+		return time.Time{}, NewValidationError(r.kind, property.NameDateTimeStart, "must not to be nil")
+	}
+}
+
+// endTime returns end of the instance which starts at start
+func (r recurrence) endTime(start time.Time) time.Time {
+	switch {
+	case r.duration != nil:
+		return r.duration.Value.Add(start)
+	case r.end != nil && r.start != nil:
+		return start.Add(timeOf(r.end).Sub(timeOf(r.start.Value)))
+	case r.start != nil && isDate(r.start.Value):
+		// https://tools.ietf.org/html/rfc5545#section-3.6.1
+		return start.AddDate(0, 0, 1)
+	default:
+		return start
+	}
+}
+
+func (r recurrence) isThisAndFuture() bool {
+	if r.recurrenceID == nil {
+		return false
+	}
+	_, ok := r.recurrenceID.Parameter[parameter.TypeNameRecurrenceIDRange]
+	return ok
+}
+
+func occurrences(master recurrence, overrides []recurrence, start, end time.Time) ([]Occurrence, error) {
+	dtstart, err := master.startTime()
+	if err != nil {
+		return nil, err
+	}
+
+	// instances starting by limit are enough, even if THISANDFUTURE moves them later
+	limit := end
+	var ranges []recurrence
+	exact := map[int64]recurrence{}
+	for _, o := range overrides {
+		if o.recurrenceID == nil {
+			continue
+		}
+		rid := timeOf(o.recurrenceID.Value)
+		if !o.isThisAndFuture() {
+			exact[rid.Unix()] = o
+			continue
+		}
+		ranges = append(ranges, o)
+		s, err := o.startTime()
+		if err != nil {
+			return nil, err
+		}
+		if shift := rid.Sub(s); shift > 0 {
+			limit = maxTime(limit, end.Add(shift))
+		}
+	}
+	sort.Slice(ranges, func(i, j int) bool {
+		return timeOf(ranges[i].recurrenceID.Value).Before(timeOf(ranges[j].recurrenceID.Value))
+	})
+
+	// recurrence set, key is unix time of RECURRENCE-ID
+	type instance struct {
+		start, end time.Time
+		period     bool
+	}
+	instances := map[int64]instance{}
+	add := func(t time.Time) {
+		if _, ok := instances[t.Unix()]; !ok {
+			instances[t.Unix()] = instance{start: t}
+		}
+	}
+	add(dtstart)
+	if master.rule != nil {
+		it := master.rule.Value.Iterator(dtstart)
+		for t, ok := it.Next(); ok && t.Before(limit); t, ok = it.Next() {
+			add(t)
+		}
+	}
+	for _, rdt := range master.dates {
+		for _, v := range rdt.Values {
+			switch rd := v.(type) {
+			case types.Period:
+				s := time.Time(rd.Start)
+				e := time.Time(rd.End)
+				if rd.Type == types.PeriodTypeStart {
+					e = rd.Range.Add(s)
+				}
+				instances[s.Unix()] = instance{start: s, end: e, period: true}
+			case types.Date:
+				add(time.Time(rd))
+			case types.DateTime:
+				add(time.Time(rd))
+			}
+		}
+	}
+	for _, exdt := range master.exceptions {
+		for _, v := range exdt.Values {
+			ex := timeOf(v)
+			for k, inst := range instances {
+				if inst.start.Equal(ex) || (isDate(v) && sameDate(inst.start, ex)) {
+					delete(instances, k)
+				}
+			}
+		}
+	}
+	// overridden instance which is not in the set is added
+	for k, o := range exact {
+		if _, ok := instances[k]; !ok {
+			rid := timeOf(o.recurrenceID.Value)
+			instances[k] = instance{start: rid}
+		}
+	}
+
+	var res []Occurrence
+	for k, inst := range instances {
+		occ := Occurrence{
+			Start:        inst.start,
+			RecurrenceID: inst.start,
+			Component:    master.component,
+		}
+		if inst.period {
+			occ.End = inst.end
+		} else {
+			occ.End = master.endTime(inst.start)
+		}
+		if o, ok := exact[k]; ok {
+			s, err := o.startTime()
+			if err != nil {
+				return nil, err
+			}
+			occ.Start = s
+			occ.End = o.endOf(master, s)
+			occ.Component = o.component
+		} else if o, ok := latestRange(ranges, inst.start); ok {
+			s, err := o.startTime()
+			if err != nil {
+				return nil, err
+			}
+			occ.Start = inst.start.Add(s.Sub(timeOf(o.recurrenceID.Value)))
+			occ.End = o.endOf(master, occ.Start)
+			occ.Component = o.component
+		}
+		if overlaps(occ, start, end) {
+			res = append(res, occ)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Start.Before(res[j].Start) })
+	return res, nil
+}
+
+// endOf returns end of overridden instance which starts at start.
+// duration of master is used if r does not have it.
+func (r recurrence) endOf(master recurrence, start time.Time) time.Time {
+	if r.duration == nil && r.end == nil {
+		return master.endTime(start)
+	}
+	return r.endTime(start)
+}
+
+// latestRange returns THISANDFUTURE override which applies to instance at t
+func latestRange(ranges []recurrence, t time.Time) (recurrence, bool) {
+	for i := len(ranges) - 1; i >= 0; i-- {
+		if !timeOf(ranges[i].recurrenceID.Value).After(t) {
+			return ranges[i], true
+		}
+	}
+	return recurrence{}, false
+}
+
+func overlaps(occ Occurrence, start, end time.Time) bool {
+	if occ.End.After(occ.Start) {
+		return occ.Start.Before(end) && occ.End.After(start)
+	}
+	return !occ.Start.Before(start) && occ.Start.Before(end)
+}
+
+func timeOf(v types.TimeValue) time.Time {
+	switch t := v.(type) {
+	case types.Date:
+		return time.Time(t)
+	case types.DateTime:
+		return time.Time(t)
+	}
+	return time.Time{}
+}
+
+func isDate(v types.TimeValue) bool {
+	_, ok := v.(types.Date)
+	return ok
+}
+
+func sameDate(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
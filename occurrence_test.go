@@ -0,0 +1,112 @@
+package ical
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/knsh14/ical/parameter"
+	"github.com/knsh14/ical/types"
+)
+
+func TestCalendar_Occurrences(t *testing.T) {
+	t.Parallel()
+	utc := func(m time.Month, d, h, min int) time.Time {
+		return time.Date(2020, m, d, h, min, 0, 0, time.UTC)
+	}
+	newEvent := func(t *testing.T, start time.Time) *Event {
+		t.Helper()
+		e := NewEvent()
+		mustNoError(t, e.SetUID(parameter.Container{}, types.NewText("weekly@example.com")))
+		mustNoError(t, e.SetDateTimeStamp(parameter.Container{}, types.DateTime(utc(1, 1, 0, 0))))
+		mustNoError(t, e.SetDateTimeStart(parameter.Container{}, types.DateTime(start)))
+		return e
+	}
+
+	master := newEvent(t, utc(1, 6, 10, 0))
+	mustNoError(t, master.SetDateTimeEnd(parameter.Container{}, types.DateTime(utc(1, 6, 11, 0))))
+	rule, err := types.NewRecurrenceRule("FREQ=WEEKLY;COUNT=6")
+	mustNoError(t, err)
+	mustNoError(t, master.SetRecurrenceRule(parameter.Container{}, rule))
+	mustNoError(t, master.AddExceptionDateTimes(parameter.Container{}, []types.TimeValue{types.DateTime(utc(1, 13, 10, 0))}))
+	period, err := types.NewPeriod("20200115T090000Z/PT2H")
+	mustNoError(t, err)
+	mustNoError(t, master.AddRecurrenceDateTimes(parameter.Container{
+		parameter.TypeNameValueType: []parameter.Base{parameter.NewValueType("PERIOD")},
+	}, []types.RecurrenceDateTimeValue{period}))
+
+	// moved to next day, duration is same as master
+	moved := newEvent(t, utc(1, 21, 12, 0))
+	mustNoError(t, moved.SetRecurrenceID(parameter.Container{}, types.DateTime(utc(1, 20, 10, 0))))
+
+	// later instances start at 14:00 and are shorter
+	future := newEvent(t, utc(2, 3, 14, 0))
+	mustNoError(t, future.SetDuration(parameter.Container{}, types.Duration{HourDuration: 30 * time.Minute}))
+	r, err := parameter.NewRecurrenceIDRange("THISANDFUTURE")
+	mustNoError(t, err)
+	mustNoError(t, future.SetRecurrenceID(parameter.Container{
+		parameter.TypeNameRecurrenceIDRange: []parameter.Base{r},
+	}, types.DateTime(utc(2, 3, 10, 0))))
+
+	todo := NewToDo()
+	mustNoError(t, todo.SetUID(parameter.Container{}, types.NewText("todo@example.com")))
+	mustNoError(t, todo.SetDateTimeStart(parameter.Container{}, types.Date(utc(1, 30, 0, 0))))
+	todoRule, err := types.NewRecurrenceRule("FREQ=DAILY;COUNT=2")
+	mustNoError(t, err)
+	mustNoError(t, todo.SetRecurrenceRule(parameter.Container{}, todoRule))
+
+	// to-dos which are not scheduled by DTSTART
+	undated := NewToDo()
+	mustNoError(t, undated.SetUID(parameter.Container{}, types.NewText("undated@example.com")))
+	due := NewToDo()
+	mustNoError(t, due.SetUID(parameter.Container{}, types.NewText("due@example.com")))
+	mustNoError(t, due.SetDateTimeDue(parameter.Container{}, types.DateTime(utc(2, 20, 17, 0))))
+
+	c := newTestCalendar(t)
+	c.Components = append(c.Components, master, undated, moved, future, todo, due)
+
+	testcases := map[string]struct {
+		start, end time.Time
+		expected   []Occurrence
+	}{
+		"all": {
+			start: utc(1, 1, 0, 0),
+			end:   utc(3, 1, 0, 0),
+			expected: []Occurrence{
+				{Start: utc(1, 6, 10, 0), End: utc(1, 6, 11, 0), RecurrenceID: utc(1, 6, 10, 0), Component: master},
+				{Start: utc(1, 15, 9, 0), End: utc(1, 15, 11, 0), RecurrenceID: utc(1, 15, 9, 0), Component: master},
+				{Start: utc(1, 21, 12, 0), End: utc(1, 21, 13, 0), RecurrenceID: utc(1, 20, 10, 0), Component: moved},
+				{Start: utc(1, 27, 10, 0), End: utc(1, 27, 11, 0), RecurrenceID: utc(1, 27, 10, 0), Component: master},
+				{Start: utc(1, 30, 0, 0), End: utc(1, 31, 0, 0), RecurrenceID: utc(1, 30, 0, 0), Component: todo},
+				{Start: utc(1, 31, 0, 0), End: utc(2, 1, 0, 0), RecurrenceID: utc(1, 31, 0, 0), Component: todo},
+				{Start: utc(2, 3, 14, 0), End: utc(2, 3, 14, 30), RecurrenceID: utc(2, 3, 10, 0), Component: future},
+				{Start: utc(2, 10, 14, 0), End: utc(2, 10, 14, 30), RecurrenceID: utc(2, 10, 10, 0), Component: future},
+				{Start: utc(2, 20, 17, 0), End: utc(2, 20, 17, 0), RecurrenceID: utc(2, 20, 17, 0), Component: due},
+			},
+		},
+		"overlap with window": {
+			start: utc(1, 15, 10, 0),
+			end:   utc(1, 21, 12, 30),
+			expected: []Occurrence{
+				{Start: utc(1, 15, 9, 0), End: utc(1, 15, 11, 0), RecurrenceID: utc(1, 15, 9, 0), Component: master},
+				{Start: utc(1, 21, 12, 0), End: utc(1, 21, 13, 0), RecurrenceID: utc(1, 20, 10, 0), Component: moved},
+			},
+		},
+	}
+
+	for title, tt := range testcases {
+		tt := tt
+		t.Run(title, func(t *testing.T) {
+			t.Parallel()
+			got, err := c.Occurrences(tt.start, tt.end)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// components are compared by identity
+			opt := cmp.Comparer(func(a, b CalenderComponent) bool { return a == b })
+			if diff := cmp.Diff(tt.expected, got, opt); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
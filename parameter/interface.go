@@ -1,16 +1,65 @@
 package parameter
 
-import "strings"
+import (
+	"sort"
+	"strings"
+
+	"github.com/knsh14/ical/errcode"
+)
 
 type Container map[TypeName][]Base
 
+// orderName is key of order of parameter names, it is not a valid parameter name
+const orderName TypeName = ""
+
+// order is order of parameter names in parsed stream
+type order []TypeName
+
+func (o order) implementParameter() {}
+func (o order) String() string      { return "" }
+
+// SetOrder records order of parameter names in parsed stream.
+// String writes parameters in this order.
+func (c Container) SetOrder(names []TypeName) {
+	c[orderName] = []Base{order(names)}
+}
+
+// Order returns order of parameter names which SetOrder records
+func (c Container) Order() []TypeName {
+	l := c[orderName]
+	if len(l) != 1 {
+		return nil
+	}
+	o, _ := l[0].(order)
+	return o
+}
+
+// String returns parameters joined by semicolon with leading semicolon.
+// parameters are written in the order which SetOrder records,
+// and others are sorted by name to make output stable.
 func (c Container) String() string {
+	written := map[TypeName]bool{orderName: true}
 	v := []string{""}
-	for _, bases := range c {
-		for _, b := range bases {
+	write := func(name TypeName) {
+		if written[name] {
+			return
+		}
+		written[name] = true
+		for _, b := range c[name] {
 			v = append(v, b.String())
 		}
 	}
+	for _, name := range c.Order() {
+		write(name)
+	}
+	names := make([]string, 0, len(c))
+	for name := range c {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		write(TypeName(name))
+	}
 	return strings.Join(v, ";")
 }
 
@@ -33,3 +82,58 @@ func (c Container) GetTimezone() string {
 	}
 	return v.Value
 }
+
+// GetParticipationStatus returns PARTSTAT, nil if it is not set
+func (c Container) GetParticipationStatus() *ParticipationStatus {
+	l, ok := c[TypeNameParticipationStatus]
+	if !ok {
+		return nil
+	}
+	if len(l) != 1 {
+		return nil
+	}
+	v, ok := l[0].(*ParticipationStatus)
+	if !ok {
+		return nil
+	}
+	return v
+}
+
+// GetRSVP returns RSVP, nil if it is not set
+func (c Container) GetRSVP() *RSVP {
+	l, ok := c[TypeNameRSVP]
+	if !ok {
+		return nil
+	}
+	if len(l) != 1 {
+		return nil
+	}
+	v, ok := l[0].(*RSVP)
+	if !ok {
+		return nil
+	}
+	return v
+}
+
+// quote returns value surrounded with DQUOTE
+func quote(value string) string {
+	return `"` + value + `"`
+}
+
+// QuoteIfNeeded returns value surrounded with DQUOTE if value contains COLON, SEMICOLON or COMMA.
+// parameter value can not contain DQUOTE, so it returns error if value contains it.
+// https://tools.ietf.org/html/rfc5545#section-3.1
+func QuoteIfNeeded(value string) (string, error) {
+	if strings.Contains(value, `"`) {
+		return "", errcode.Errorf(errcode.ValueFormat, "parameter value must not contain DQUOTE, got %s", value)
+	}
+	return quoteIfNeeded(value), nil
+}
+
+// quoteIfNeeded returns value surrounded with DQUOTE if value contains COLON, SEMICOLON or COMMA
+func quoteIfNeeded(value string) string {
+	if strings.ContainsAny(value, ":;,") {
+		return quote(value)
+	}
+	return value
+}
@@ -4,7 +4,7 @@ type ParticipationStatusType string
 
 const (
 	ParticipationStatusTypeNeedsAction ParticipationStatusType = "NEEDS-ACTION"
-	ParticipationStatusTypeAccepted    ParticipationStatusType = "ACCEPTED "
+	ParticipationStatusTypeAccepted    ParticipationStatusType = "ACCEPTED"
 	ParticipationStatusTypeDeclined    ParticipationStatusType = "DECLINED"
 	ParticipationStatusTypeTentative   ParticipationStatusType = "TENTATIVE"
 	ParticipationStatusTypeDelegated   ParticipationStatusType = "DELEGATED"
@@ -7,6 +7,7 @@ import (
 	"unicode"
 
 	"github.com/knsh14/ical/component"
+	"github.com/knsh14/ical/errcode"
 	"github.com/knsh14/ical/mime"
 	"github.com/knsh14/ical/token"
 	"github.com/knsh14/ical/types"
@@ -30,7 +31,7 @@ type AlternateTextRepresentation struct {
 
 func (a *AlternateTextRepresentation) implementParameter() {}
 func (a *AlternateTextRepresentation) String() string {
-	return fmt.Sprintf("%s=%s", TypeNameAlarmTriggerRelationship, a.URI.String())
+	return fmt.Sprintf("%s=%s", TypeNameAlternateTextRepresentation, quote(a.URI.String()))
 }
 
 func NewCommonName(value string) *CommonName {
@@ -46,7 +47,7 @@ type CommonName struct {
 
 func (cn *CommonName) implementParameter() {}
 func (cn *CommonName) String() string {
-	return fmt.Sprintf("%s=%s", TypeNameCommonName, cn.String())
+	return fmt.Sprintf("%s=%s", TypeNameCommonName, quoteIfNeeded(string(cn.Value)))
 }
 
 func NewCalenderUserType(value string) (*CalenderUserType, error) {
@@ -61,7 +62,7 @@ func NewCalenderUserType(value string) (*CalenderUserType, error) {
 		if token.IsXName(value) {
 			return &CalenderUserType{Type: CalendarUserTypeKindXToken, Value: value}, nil
 		}
-		return nil, fmt.Errorf("undefined CalenderUserType %s", value)
+		return nil, errcode.Errorf(errcode.ValueFormat, "undefined CalenderUserType %s", value)
 	}
 }
 
@@ -99,7 +100,7 @@ func (d *Delegator) implementParameter() {}
 func (d *Delegator) String() string {
 	var v []string
 	for _, a := range d.Addresses {
-		v = append(v, a.String())
+		v = append(v, quote(a.String()))
 	}
 	return fmt.Sprintf("%s=%s", TypeNameDelegator, strings.Join(v, ","))
 }
@@ -124,7 +125,7 @@ func (d *Delegatee) implementParameter() {}
 func (d *Delegatee) String() string {
 	var v []string
 	for _, a := range d.Addresses {
-		v = append(v, a.String())
+		v = append(v, quote(a.String()))
 	}
 	return fmt.Sprintf("%s=%s", TypeNameDelegatee, strings.Join(v, ","))
 }
@@ -147,7 +148,7 @@ type DirectoryEntry struct {
 
 func (de *DirectoryEntry) implementParameter() {}
 func (de *DirectoryEntry) String() string {
-	return fmt.Sprintf("%s=%s", TypeNameDirectoryEntry, de.URI.String())
+	return fmt.Sprintf("%s=%s", TypeNameDirectoryEntry, quote(de.URI.String()))
 }
 
 func NewInlineEncoding(value string) (*InlineEncoding, error) {
@@ -158,7 +159,7 @@ func NewInlineEncoding(value string) (*InlineEncoding, error) {
 		if token.IsXName(value) {
 			return &InlineEncoding{Type: InlineEncodingTypeXName, Value: value}, nil
 		}
-		return nil, fmt.Errorf("undefined InlineEncodingType %s", value)
+		return nil, errcode.Errorf(errcode.ValueFormat, "undefined InlineEncodingType %s", value)
 	}
 }
 
@@ -179,7 +180,7 @@ func NewFormatType(value string) (*FormatType, error) {
 	if mime.IsMIMEType(value) {
 		return &FormatType{Value: types.NewText(value)}, nil
 	}
-	return nil, fmt.Errorf("invalid format type %s", value)
+	return nil, errcode.Errorf(errcode.ValueFormat, "invalid format type %s", value)
 }
 
 type FormatType struct {
@@ -188,7 +189,7 @@ type FormatType struct {
 
 func (ft *FormatType) implementParameter() {}
 func (ft *FormatType) String() string {
-	return fmt.Sprintf("%s=%s", TypeNameFormatType, ft.Value)
+	return fmt.Sprintf("%s=%s", TypeNameFormatType, quoteIfNeeded(string(ft.Value)))
 }
 
 func NewFreeBusyTimeType(value string) (*FreeBusyTimeType, error) {
@@ -202,7 +203,7 @@ func NewFreeBusyTimeType(value string) (*FreeBusyTimeType, error) {
 		if token.IsXName(value) {
 			return &FreeBusyTimeType{Type: FreeBusyTimeTypeKindXName, Value: value}, nil
 		}
-		return nil, fmt.Errorf("invalid FreeBusyTimeType %s", v)
+		return nil, errcode.Errorf(errcode.ValueFormat, "invalid FreeBusyTimeType %s", v)
 	}
 }
 
@@ -223,7 +224,7 @@ func (fbtt *FreeBusyTimeType) String() string {
 func NewLanguage(value string) (*Language, error) {
 	tag, err := language.Parse(value)
 	if err != nil {
-		return nil, fmt.Errorf("parse language tag: %w", err)
+		return nil, errcode.Errorf(errcode.ValueFormat, "parse language tag: %w", err)
 	}
 	return &Language{Tag: tag}, nil
 }
@@ -257,7 +258,7 @@ func (m *Membership) implementParameter() {}
 func (m *Membership) String() string {
 	var v []string
 	for _, u := range m.URIs {
-		v = append(v, u.String())
+		v = append(v, quote(u.String()))
 	}
 	return fmt.Sprintf("%s=%s", TypeNameMembership, strings.Join(v, ","))
 }
@@ -270,6 +271,7 @@ func NewParticipationStatus(value string, kind component.Type) (*ParticipationSt
 			ParticipationStatusTypeNeedsAction: {},
 			ParticipationStatusTypeAccepted:    {},
 			ParticipationStatusTypeDeclined:    {},
+			ParticipationStatusTypeTentative:   {},
 			ParticipationStatusTypeDelegated:   {},
 			ParticipationStatusTypeXToken:      {},
 		}
@@ -292,7 +294,7 @@ func NewParticipationStatus(value string, kind component.Type) (*ParticipationSt
 			ParticipationStatusTypeXToken:      {},
 		}
 	default:
-		return nil, fmt.Errorf("invalid kind type %s, must be VEVENT, VTODO or VJOURNAL", kind)
+		return nil, errcode.Errorf(errcode.UnsupportedComponent, "invalid kind type %s, must be VEVENT, VTODO or VJOURNAL", kind)
 	}
 	t := ParticipationStatusType(value)
 	if token.IsXName(value) {
@@ -305,7 +307,7 @@ func NewParticipationStatus(value string, kind component.Type) (*ParticipationSt
 			Value: value,
 		}, nil
 	}
-	return nil, fmt.Errorf("%s is not for %s", value, kind)
+	return nil, errcode.Errorf(errcode.ValueFormat, "%s is not for %s", value, kind)
 }
 
 type ParticipationStatus struct {
@@ -324,7 +326,7 @@ func (ps *ParticipationStatus) String() string {
 
 func NewRecurrenceIDRange(value string) (*RecurrenceIDRange, error) {
 	if strings.ToUpper(value) != "THISANDFUTURE" {
-		return nil, fmt.Errorf("value must be THISANDFUTURE, but %s", value)
+		return nil, errcode.Errorf(errcode.ValueFormat, "value must be THISANDFUTURE, but %s", value)
 	}
 	return &RecurrenceIDRange{}, nil
 }
@@ -344,7 +346,7 @@ func NewAlarmTriggerRelationship(value string) (*AlarmTriggerRelationship, error
 	if value == "END" {
 		return &AlarmTriggerRelationship{IsStart: false}, nil
 	}
-	return nil, fmt.Errorf("value must be START or END, but %s", value)
+	return nil, errcode.Errorf(errcode.ValueFormat, "value must be START or END, but %s", value)
 }
 
 type AlarmTriggerRelationship struct {
@@ -369,7 +371,7 @@ func NewRelationshipType(value string) (*RelationshipType, error) {
 		if token.IsXName(value) {
 			return &RelationshipType{Type: RelationshipTypeKindXName, Value: value}, nil
 		}
-		return nil, fmt.Errorf("invalid RelationshipType %s", value)
+		return nil, errcode.Errorf(errcode.ValueFormat, "invalid RelationshipType %s", value)
 	}
 }
 
@@ -397,7 +399,7 @@ func NewParticipationRole(value string) (*ParticipationRole, error) {
 		if token.IsXName(value) {
 			return &ParticipationRole{Type: ParticipationRoleTypeXName, Value: value}, nil
 		}
-		return nil, fmt.Errorf("invalid ParticipationRoleType %s", value)
+		return nil, errcode.Errorf(errcode.ValueFormat, "invalid ParticipationRoleType %s", value)
 	}
 }
 
@@ -432,9 +434,13 @@ func (rsvp *RSVP) String() string {
 }
 
 func NewSentBy(value string) (*SentBy, error) {
-	v, err := strconv.Unquote(value)
-	if err != nil {
-		return nil, fmt.Errorf("unquote input: %w", err)
+	v := value
+	if strings.HasPrefix(value, `"`) {
+		uq, err := strconv.Unquote(value)
+		if err != nil {
+			return nil, errcode.Errorf(errcode.ValueFormat, "unquote input: %w", err)
+		}
+		v = uq
 	}
 	uri, err := types.NewCalenderUserAddress(v)
 	if err != nil {
@@ -449,18 +455,18 @@ type SentBy struct {
 
 func (sb *SentBy) implementParameter() {}
 func (sb *SentBy) String() string {
-	return fmt.Sprintf("%s=%s", TypeNameSentBy, sb.Address.String())
+	return fmt.Sprintf("%s=%s", TypeNameSentBy, quote(sb.Address.String()))
 }
 
 func NewReferenceTimezone(value string) (*ReferenceTimezone, error) {
 	for _, v := range value {
 		r := rune(v)
 		if unicode.IsControl(r) {
-			return nil, fmt.Errorf("control character in %s", value)
+			return nil, errcode.Errorf(errcode.ValueFormat, "control character in %s", value)
 		}
 	}
 	if strings.ContainsAny(value, "\",;:") {
-		return nil, fmt.Errorf("not param safe character in %s", value)
+		return nil, errcode.Errorf(errcode.ValueFormat, "not param safe character in %s", value)
 	}
 	return &ReferenceTimezone{Value: value}, nil
 }
@@ -471,7 +477,7 @@ type ReferenceTimezone struct {
 
 func (rtz *ReferenceTimezone) implementParameter() {}
 func (rtz *ReferenceTimezone) String() string {
-	return fmt.Sprintf("%s=%s", TypeNameReferenceTimezone, rtz.Value)
+	return fmt.Sprintf("%s=%s", TypeNameReferenceTimezone, quoteIfNeeded(rtz.Value))
 }
 
 func NewValueType(value string) *ValueType {
@@ -501,5 +507,9 @@ type XParam struct {
 
 func (xp *XParam) implementParameter() {}
 func (xp *XParam) String() string {
-	return fmt.Sprintf("%s=%s", xp.Parameter, xp.Value)
+	v := make([]string, 0, len(xp.Value))
+	for _, s := range xp.Value {
+		v = append(v, quoteIfNeeded(s))
+	}
+	return fmt.Sprintf("%s=%s", xp.Parameter, strings.Join(v, ","))
 }
@@ -0,0 +1,97 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/knsh14/ical"
+	"github.com/knsh14/ical/component"
+	"github.com/knsh14/ical/property"
+)
+
+func TestParse_Findings(t *testing.T) {
+	t.Parallel()
+	input := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//example//EN",
+		"PRODID:-//example//EN",
+		"BEGIN:VEVENT",
+		"UID:duplicated@example.com",
+		"DTSTAMP:20200101T000000Z",
+		"DTSTART:20200106T100000Z",
+		"SUMMARY:first",
+		"SUMMARY:second",
+		"RRULE:FREQ=DAILY;COUNT=2",
+		"RRULE:FREQ=WEEKLY;COUNT=2",
+		"BEGIN:VALARM",
+		"ACTION:AUDIO",
+		"TRIGGER:-PT15M",
+		"TRIGGER:-PT5M",
+		"END:VALARM",
+		"END:VEVENT",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n")
+	event := []component.Type{component.TypeCalendar, component.TypeEvent}
+	type finding struct {
+		Severity ical.Severity
+		Rule     ical.Rule
+		Path     []component.Type
+		Property property.Name
+	}
+	// findings of a component are found at its END
+	expected := []finding{
+		{Severity: ical.SeverityError, Rule: ical.RuleOnce, Path: append(event, component.TypeAlarm), Property: property.NameTrigger},
+		{Severity: ical.SeverityError, Rule: ical.RuleOnce, Path: event, Property: property.NameSummary},
+		{Severity: ical.SeverityWarning, Rule: ical.RuleOnce, Path: event, Property: property.NameRecurrenceRule},
+		{Severity: ical.SeverityError, Rule: ical.RuleOnce, Path: []component.Type{component.TypeCalendar}, Property: property.NameProdID},
+	}
+	convert := func(fs ical.Findings) []finding {
+		var res []finding
+		for _, f := range fs {
+			res = append(res, finding{Severity: f.Severity, Rule: f.Rule, Path: f.Path, Property: f.Property})
+		}
+		return res
+	}
+
+	testcases := map[string]struct {
+		opts      []Option
+		roundTrip bool
+	}{
+		"default": {},
+		"round trip": {
+			opts:      []Option{WithRoundTrip()},
+			roundTrip: true,
+		},
+		"tolerance": {
+			opts: []Option{WithTolerance()},
+		},
+	}
+
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			var fs ical.Findings
+			c, err := Parse(strings.NewReader(input), append(tc.opts, WithFindings(&fs))...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(expected, convert(fs)); diff != "" {
+				t.Errorf("diff: (-expected +got)\n%s", diff)
+			}
+			// components keep only one of each property, so Check finds them only by PropertyOrder
+			var expectedCheck []finding
+			if tc.roundTrip {
+				expectedCheck = expected
+			}
+			byProperty := cmpopts.SortSlices(func(a, b finding) bool { return a.Property < b.Property })
+			if diff := cmp.Diff(expectedCheck, convert(c.Check()), byProperty); diff != "" {
+				t.Errorf("Check diff: (-expected +got)\n%s", diff)
+			}
+		})
+	}
+}
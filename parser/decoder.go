@@ -0,0 +1,420 @@
+package parser
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/knsh14/ical"
+	"github.com/knsh14/ical/component"
+	"github.com/knsh14/ical/contentline"
+	"github.com/knsh14/ical/errcode"
+	"github.com/knsh14/ical/lexer"
+	"github.com/knsh14/ical/parameter"
+	"github.com/knsh14/ical/property"
+)
+
+// Decoder reads components of iCalendar stream one by one.
+// only lines of a component being decoded are kept in memory.
+// TZID is resolved by VTIMEZONEs decoded before, or IANA time zone database.
+type Decoder struct {
+	scanner *bufio.Scanner
+	opts    []Option
+	parser  *Parser
+	// deferUnknownTimezones makes next keep components which refer TZIDs of VTIMEZONEs not decoded yet.
+	// next returns nil component for them, and they are parsed by parseDeferred.
+	deferUnknownTimezones bool
+	deferred              []deferredComponent
+
+	// lineNumber is number of physical lines read from scanner
+	lineNumber int
+	// pending is a line read ahead to find folded lines
+	pending    string
+	hasPending bool
+	// pendingLineNumber is physical line number of pending
+	pendingLineNumber int
+	// currentLineNumber is physical line number where the last unfolded line begins
+	currentLineNumber int
+	// segments is physical lines of the last unfolded line
+	segments []segment
+	// path is types of components which have the last content line
+	path []component.Type
+
+	calendar *ical.Calendar
+	done     bool
+	err      error
+}
+
+// NewDecoder returns Decoder which reads from r
+func NewDecoder(r io.Reader, opts ...Option) *Decoder {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), contentline.MaxLineLength)
+	return &Decoder{
+		scanner: scanner,
+		opts:    opts,
+		parser:  NewParser(nil, opts...),
+	}
+}
+
+// Next returns next component in VCALENDAR.
+// it returns io.EOF after END:VCALENDAR.
+func (d *Decoder) Next() (ical.CalenderComponent, error) {
+	if d.err != nil {
+		return nil, d.err
+	}
+	c, err := d.next()
+	if err != nil {
+		if !errors.Is(err, io.EOF) {
+			err = fmt.Errorf("parse %s: %w", component.TypeCalendar, err)
+		}
+		d.err = err
+		return nil, err
+	}
+	return c, nil
+}
+
+// Errors returns errors found so far in tolerant mode
+func (d *Decoder) Errors() LineErrors {
+	return d.parser.errors
+}
+
+// Calendar returns VCALENDAR which has properties read so far.
+// Components of it is empty, they are returned by Next.
+func (d *Decoder) Calendar() *ical.Calendar {
+	return d.calendar
+}
+
+func (d *Decoder) next() (ical.CalenderComponent, error) {
+	if d.done {
+		return nil, io.EOF
+	}
+	if d.calendar == nil {
+		if err := d.begin(); err != nil {
+			return nil, err
+		}
+	}
+	for {
+		l, err := d.readContentLine()
+		if errors.Is(err, io.EOF) {
+			return nil, d.finish(NoEndError(component.TypeCalendar))
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch property.Name(l.Name) {
+		case property.NameBegin:
+			if len(l.Values) != 1 {
+				if err := d.tolerate(d.currentLineNumber, 1, l, NewInvalidValueLengthError(1, len(l.Values))); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			lines, numbers, err := d.readComponent(l)
+			var noEnd NoEndError
+			if errors.As(err, &noEnd) {
+				return nil, d.finish(err)
+			}
+			if err != nil {
+				return nil, err
+			}
+			if d.deferUnknownTimezones && d.refersUnknownTimezone(lines) {
+				d.deferred = append(d.deferred, deferredComponent{lines: lines, numbers: numbers})
+				return nil, nil
+			}
+			c, err := d.parseComponent(lines, numbers)
+			if err != nil {
+				return nil, err
+			}
+			if c == nil {
+				continue
+			}
+			return c, nil
+		case property.NameEnd:
+			if len(l.Values) != 1 || component.Type(l.Values[0]) != component.TypeCalendar {
+				if err := d.tolerate(d.currentLineNumber, 1, l, errcode.Errorf(errcode.Invalid, "Invalid END")); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			d.done = true
+			d.finishCalendar()
+			return nil, io.EOF
+		default:
+			if err := d.parser.setCalendarProperty(d.calendar, l); err != nil {
+				if err := d.tolerate(d.currentLineNumber, 1, l, err); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+}
+
+// parseComponent parses a component in lines.
+// it returns nil component if the component is skipped in tolerant mode.
+func (d *Decoder) parseComponent(lines []*contentline.ContentLine, numbers []int) (ical.CalenderComponent, error) {
+	p := NewParser(lines, d.opts...)
+	p.timezones = d.parser.timezones
+	p.lineNumbers = numbers
+	if p.tolerant {
+		c, errs := p.parseComponentTolerantly()
+		d.parser.errors = append(d.parser.errors, errs...)
+		if c != nil {
+			p.flushFindings()
+		}
+		return c, nil
+	}
+	c, err := p.parseComponent()
+	if err != nil {
+		return nil, p.lineError(p.CurrentIndex, err)
+	}
+	p.flushFindings()
+	return c, nil
+}
+
+// deferredComponent is lines of a component which is parsed after VTIMEZONEs in the stream are decoded
+type deferredComponent struct {
+	lines   []*contentline.ContentLine
+	numbers []int
+}
+
+// refersUnknownTimezone reports whether lines have TZID which is not defined by VTIMEZONEs decoded so far
+func (d *Decoder) refersUnknownTimezone(lines []*contentline.ContentLine) bool {
+	for _, l := range lines {
+		for _, param := range l.Parameters {
+			if !strings.EqualFold(param.Name, string(parameter.TypeNameReferenceTimezone)) {
+				continue
+			}
+			for _, v := range param.Values {
+				if _, ok := d.parser.timezones[v]; !ok {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// parseDeferred parses components deferred by next, in order of them.
+// nil is returned for a component skipped in tolerant mode.
+func (d *Decoder) parseDeferred() ([]ical.CalenderComponent, error) {
+	res := make([]ical.CalenderComponent, len(d.deferred))
+	for i, dc := range d.deferred {
+		c, err := d.parseComponent(dc.lines, dc.numbers)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", component.TypeCalendar, err)
+		}
+		res[i] = c
+	}
+	d.deferred = nil
+	return res, nil
+}
+
+// tolerate records err of content line l at line and column in tolerant mode.
+// it returns err as LineError if tolerant mode is not enabled.
+func (d *Decoder) tolerate(line, column int, l *contentline.ContentLine, err error) error {
+	le := &LineError{
+		Line:   line,
+		Column: column,
+		Path:   append([]component.Type(nil), d.path...),
+		Err:    err,
+	}
+	if l != nil {
+		le.Property = property.Name(l.Name)
+	}
+	if !d.parser.tolerant {
+		return le
+	}
+	d.parser.errors = append(d.parser.errors, le)
+	return nil
+}
+
+// finish records err at the end of the stream and stops decoding in tolerant mode.
+func (d *Decoder) finish(err error) error {
+	if err := d.tolerate(d.lineNumber, 1, nil, err); err != nil {
+		return err
+	}
+	d.done = true
+	d.finishCalendar()
+	return io.EOF
+}
+
+// finishCalendar records findings of properties of VCALENDAR
+func (d *Decoder) finishCalendar() {
+	d.parser.checkOccurrences(d.calendar, d.parser.calendarCounts, component.TypeCalendar)
+	d.parser.flushFindings()
+}
+
+func (d *Decoder) begin() error {
+	l, err := d.readContentLine()
+	if errors.Is(err, io.EOF) {
+		return errcode.Errorf(errcode.Invalid, "not %s:%s, got empty input", property.NameBegin, component.TypeCalendar)
+	}
+	if err != nil {
+		return err
+	}
+	if property.Name(l.Name) != property.NameBegin || len(l.Values) != 1 || component.Type(l.Values[0]) != component.TypeCalendar {
+		return &LineError{
+			Line:     d.currentLineNumber,
+			Column:   1,
+			Property: property.Name(l.Name),
+			Err:      errcode.Errorf(errcode.Invalid, "not %s:%s, got %v", property.NameBegin, component.TypeCalendar, l),
+		}
+	}
+	d.calendar = ical.NewCalendar()
+	d.parser.currentComponentType = component.TypeCalendar
+	return nil
+}
+
+// readComponent reads lines until END of component begun by begin
+// numbers are physical line numbers of lines.
+func (d *Decoder) readComponent(begin *contentline.ContentLine) ([]*contentline.ContentLine, []int, error) {
+	ct := begin.Values[0]
+	lines := []*contentline.ContentLine{begin}
+	numbers := []int{d.currentLineNumber}
+	depth := 1
+	for depth > 0 {
+		l, err := d.readContentLine()
+		if errors.Is(err, io.EOF) {
+			return nil, nil, NoEndError(component.Type(ct))
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(l.Values) == 1 && l.Values[0] == ct {
+			switch property.Name(l.Name) {
+			case property.NameBegin:
+				depth++
+			case property.NameEnd:
+				depth--
+			}
+		}
+		lines = append(lines, l)
+		numbers = append(numbers, d.currentLineNumber)
+	}
+	return lines, numbers, nil
+}
+
+// readContentLine returns next content line.
+// in tolerant mode, lines which can not be converted are recorded and skipped.
+func (d *Decoder) readContentLine() (*contentline.ContentLine, error) {
+	for {
+		line, err := d.readLine()
+		if err != nil {
+			return nil, err
+		}
+		cl, err := contentline.ConvertContentLine(lexer.New(line))
+		if err != nil {
+			lineNumber, column := d.currentLineNumber, 1
+			var se *contentline.SyntaxError
+			if errors.As(err, &se) {
+				lineNumber, column = d.position(se.Offset)
+			}
+			if err := d.tolerate(lineNumber, column, nil, errcode.Errorf(errcode.Syntax, "convert content line: %w", err)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		d.updatePath(cl)
+		return cl, nil
+	}
+}
+
+// updatePath updates path of components by BEGIN and END in l
+func (d *Decoder) updatePath(l *contentline.ContentLine) {
+	if len(l.Values) != 1 {
+		return
+	}
+	switch property.Name(l.Name) {
+	case property.NameBegin:
+		d.path = append(d.path, component.Type(l.Values[0]))
+	case property.NameEnd:
+		if n := len(d.path); n > 0 && d.path[n-1] == component.Type(l.Values[0]) {
+			d.path = d.path[:n-1]
+		}
+	}
+}
+
+// readLine returns a unfolded line
+// https://tools.ietf.org/html/rfc5545#section-3.1
+func (d *Decoder) readLine() (string, error) {
+	if !d.hasPending {
+		l, err := d.scan()
+		if err != nil {
+			return "", err
+		}
+		if isFolded(l) {
+			return "", &LineError{Line: d.lineNumber, Column: 1, Err: errcode.Errorf(errcode.Syntax, "first line must not be folded line")}
+		}
+		d.pending = l
+		d.pendingLineNumber = d.lineNumber
+	}
+	line := d.pending
+	d.currentLineNumber = d.pendingLineNumber
+	d.segments = append(d.segments[:0], segment{line: d.pendingLineNumber})
+	d.hasPending = false
+	for {
+		l, err := d.scan()
+		if errors.Is(err, io.EOF) {
+			return line, nil
+		}
+		if err != nil {
+			return "", err
+		}
+		if !isFolded(l) {
+			d.pending = l
+			d.pendingLineNumber = d.lineNumber
+			d.hasPending = true
+			return line, nil
+		}
+		d.segments = append(d.segments, segment{offset: utf8.RuneCountInString(line), line: d.lineNumber, folded: true})
+		line += l[1:]
+	}
+}
+
+// segment is a physical line in unfolded line
+type segment struct {
+	// offset is offset of the first rune of the physical line in unfolded line
+	offset int
+	line   int
+	// folded is true if the physical line begins with white space for folding
+	folded bool
+}
+
+// position returns physical line number and column of rune at offset in the last unfolded line
+func (d *Decoder) position(offset int) (int, int) {
+	for i := len(d.segments) - 1; i >= 0; i-- {
+		s := d.segments[i]
+		if s.offset > offset {
+			continue
+		}
+		column := offset - s.offset + 1
+		if s.folded {
+			column++
+		}
+		return s.line, column
+	}
+	return d.currentLineNumber, 1
+}
+
+// scan returns next non empty physical line
+func (d *Decoder) scan() (string, error) {
+	for d.scanner.Scan() {
+		d.lineNumber++
+		l := strings.TrimSuffix(d.scanner.Text(), "\r")
+		if l == "" {
+			continue
+		}
+		return l, nil
+	}
+	if err := d.scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", io.EOF
+}
+
+func isFolded(l string) bool {
+	return strings.HasPrefix(l, " ") || strings.HasPrefix(l, "\t")
+}
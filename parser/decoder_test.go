@@ -0,0 +1,206 @@
+package parser
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/knsh14/ical"
+	"github.com/knsh14/ical/component"
+	"github.com/knsh14/ical/property"
+)
+
+func TestDecoder_Next(t *testing.T) {
+	t.Parallel()
+
+	testcases := map[string]struct {
+		input         []string
+		expected      []component.Type
+		expectedError error
+	}{
+		"components": {
+			input: []string{
+				"BEGIN:VCALENDAR",
+				"VERSION:2.0",
+				"PRODID:-//ABC Corporation//NONSGML My Product//EN",
+				"BEGIN:VEVENT",
+				"UID:19970901T130000Z-123401@example.com",
+				"DTSTAMP:19970901T130000Z",
+				"BEGIN:VALARM",
+				"ACTION:DISPLAY",
+				"TRIGGER:-PT30M",
+				"DESCRIPTION:Breakfast meeting",
+				"END:VALARM",
+				"END:VEVENT",
+				"BEGIN:VJOURNAL",
+				"UID:19970901T130000Z-123405@example.com",
+				"END:VJOURNAL",
+				"BEGIN:VTODO",
+				"UID:20070313T123432Z-456553@example.com",
+				"DTSTAMP:20070313T123432Z",
+				"END:VTODO",
+				"END:VCALENDAR",
+			},
+			expected: []component.Type{component.TypeEvent, component.TypeJournal, component.TypeTODO},
+		},
+		"folded line": {
+			input: []string{
+				"BEGIN:VCALENDAR",
+				"BEGIN:VEV",
+				" ENT",
+				"UID:19970901T130000Z-123401@example.com",
+				"DTSTAMP:19970901T130000Z",
+				"END:VEVENT",
+				"END:VCALENDAR",
+			},
+			expected: []component.Type{component.TypeEvent},
+		},
+		"line over 64 KiB": {
+			input: []string{
+				"BEGIN:VCALENDAR",
+				"BEGIN:VEVENT",
+				"UID:19970901T130000Z-123401@example.com",
+				"DTSTAMP:19970901T130000Z",
+				"ATTACH;ENCODING=BASE64;VALUE=BINARY:" + strings.Repeat("AAAA", 32*1024),
+				"END:VEVENT",
+				"END:VCALENDAR",
+			},
+			expected: []component.Type{component.TypeEvent},
+		},
+		"no end of calendar": {
+			input: []string{
+				"BEGIN:VCALENDAR",
+				"BEGIN:VEVENT",
+				"UID:19970901T130000Z-123401@example.com",
+				"DTSTAMP:19970901T130000Z",
+				"END:VEVENT",
+			},
+			expected:      []component.Type{component.TypeEvent},
+			expectedError: NoEndError(component.TypeCalendar),
+		},
+		"no end of event": {
+			input: []string{
+				"BEGIN:VCALENDAR",
+				"BEGIN:VEVENT",
+				"UID:19970901T130000Z-123401@example.com",
+				"END:VCALENDAR",
+			},
+			expectedError: NoEndError(component.TypeEvent),
+		},
+	}
+
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			d := NewDecoder(strings.NewReader(strings.Join(tc.input, "\r\n")))
+			var got []component.Type
+			var err error
+			for {
+				var c ical.CalenderComponent
+				c, err = d.Next()
+				if err != nil {
+					break
+				}
+				switch c.(type) {
+				case *ical.Event:
+					got = append(got, component.TypeEvent)
+				case *ical.ToDo:
+					got = append(got, component.TypeTODO)
+				case *ical.Journal:
+					got = append(got, component.TypeJournal)
+				case *ical.Timezone:
+					got = append(got, component.TypeTimezone)
+				}
+			}
+			if tc.expectedError == nil {
+				if !errors.Is(err, io.EOF) {
+					t.Fatalf("expected io.EOF, but %v", err)
+				}
+			} else if !errors.Is(err, tc.expectedError) {
+				t.Fatalf("expected error %v, but %v", tc.expectedError, err)
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("diff: (-expected +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDecoder_Next_Position(t *testing.T) {
+	t.Parallel()
+
+	testcases := map[string]struct {
+		input    []string
+		expected *LineError
+	}{
+		"syntax error in folded line": {
+			input: []string{
+				"BEGIN:VCALENDAR",
+				"VERSION:2.0",
+				"SUMMARY;LANG",
+				" UAGE@=en:hello",
+				"END:VCALENDAR",
+			},
+			expected: &LineError{Line: 4, Column: 6, Path: []component.Type{component.TypeCalendar}},
+		},
+		"syntax error after empty line": {
+			input: []string{
+				"BEGIN:VCALENDAR",
+				"",
+				"EX@MPLE:value",
+				"END:VCALENDAR",
+			},
+			expected: &LineError{Line: 3, Column: 3, Path: []component.Type{component.TypeCalendar}},
+		},
+		"invalid property value": {
+			input: []string{
+				"BEGIN:VCALENDAR",
+				"BEGIN:VEVENT",
+				"UID:19970901T130000Z-123401@example.com",
+				"DESCRIPTION:folded",
+				"  line",
+				"DTSTAMP:1997",
+				"END:VEVENT",
+				"END:VCALENDAR",
+			},
+			expected: &LineError{Line: 6, Column: 1, Path: []component.Type{component.TypeCalendar, component.TypeEvent}, Property: property.NameDateTimeStamp},
+		},
+		"folded first line": {
+			input: []string{
+				" BEGIN:VCALENDAR",
+				"END:VCALENDAR",
+			},
+			expected: &LineError{Line: 1, Column: 1},
+		},
+		"not calendar": {
+			input: []string{
+				"BEGIN:VEVENT",
+				"END:VEVENT",
+			},
+			expected: &LineError{Line: 1, Column: 1, Property: property.NameBegin},
+		},
+	}
+
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			d := NewDecoder(strings.NewReader(strings.Join(tc.input, "\r\n")))
+			var err error
+			for err == nil {
+				_, err = d.Next()
+			}
+			var got *LineError
+			if !errors.As(err, &got) {
+				t.Fatalf("expected LineError, but %v", err)
+			}
+			if diff := cmp.Diff(tc.expected, got, cmpopts.IgnoreFields(LineError{}, "Err")); diff != "" {
+				t.Errorf("diff: (-expected +got)\n%s", diff)
+			}
+		})
+	}
+}
@@ -2,15 +2,16 @@ package parser
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/knsh14/ical/component"
+	"github.com/knsh14/ical/errcode"
 	"github.com/knsh14/ical/property"
 	"github.com/morikuni/failure"
 )
 
-const (
-	Invalid failure.StringCode = "Invalid"
-)
+// Invalid is same as errcode.Invalid, codes of errors are defined in package errcode
+const Invalid = errcode.Invalid
 
 type NoEndError component.Type
 
@@ -18,6 +19,10 @@ func (e NoEndError) Error() string {
 	return fmt.Sprintf("finished without END:%s", string(e))
 }
 
+func (e NoEndError) As(x interface{}) bool {
+	return asCode(x, errcode.NoEnd)
+}
+
 func NewParseError(cname component.Type, pname property.Name, e error) ParseError {
 	return ParseError{
 		componentName: cname,
@@ -36,6 +41,10 @@ func (e ParseError) Error() string {
 	return fmt.Sprintf("parse %s.%s: %s", e.componentName, e.propertyName, e.err)
 }
 
+func (e ParseError) Unwrap() error {
+	return e.err
+}
+
 type InvalidPropertyError property.Name
 
 type UnknownComponentTypeError component.Type
@@ -44,6 +53,10 @@ func (e UnknownComponentTypeError) Error() string {
 	return fmt.Sprintf("unknown type %s", string(e))
 }
 
+func (e UnknownComponentTypeError) As(x interface{}) bool {
+	return asCode(x, errcode.UnsupportedComponent)
+}
+
 func NewInvalidValueLengthError(r, a int) InvalidValueLengthError {
 	return InvalidValueLengthError{
 		require: r,
@@ -58,3 +71,62 @@ type InvalidValueLengthError struct {
 func (ivle InvalidValueLengthError) Error() string {
 	return fmt.Sprintf("value length must be %d, but %d", ivle.require, ivle.actual)
 }
+
+func (ivle InvalidValueLengthError) As(x interface{}) bool {
+	return asCode(x, errcode.Cardinality)
+}
+
+// asCode sets code to x for failure.CodeOf
+func asCode(x interface{}, code failure.Code) bool {
+	if c, ok := x.(*failure.Code); ok {
+		*c = code
+		return true
+	}
+	return false
+}
+
+// LineError is error of a content line with its position in the stream.
+// errors returned by Decoder and Parse are LineError or LineErrors in tolerant mode.
+type LineError struct {
+	// Line is physical line number where the error is found, it starts with 1.
+	// folded lines are counted as they are in the stream.
+	Line int
+	// Column is column of the rune which causes the error in Line, it starts with 1.
+	// it is 1 if the whole content line is invalid.
+	Column int
+	// Path is types of components which have the content line, outermost first
+	Path []component.Type
+	// Property is name of the content line
+	Property property.Name
+	Err      error
+}
+
+func (e *LineError) Error() string {
+	s := fmt.Sprintf("line %d, column %d", e.Line, e.Column)
+	if len(e.Path) > 0 {
+		path := make([]string, len(e.Path))
+		for i, ct := range e.Path {
+			path[i] = string(ct)
+		}
+		s += ": " + strings.Join(path, "/")
+	}
+	if e.Property != "" {
+		s += ": " + string(e.Property)
+	}
+	return fmt.Sprintf("%s: %s", s, e.Err)
+}
+
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+// LineErrors is all errors found in tolerant mode
+type LineErrors []*LineError
+
+func (e LineErrors) Error() string {
+	s := make([]string, len(e))
+	for i, err := range e {
+		s[i] = err.Error()
+	}
+	return strings.Join(s, "\n")
+}
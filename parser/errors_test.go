@@ -0,0 +1,72 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/knsh14/ical/errcode"
+	"github.com/morikuni/failure"
+)
+
+func TestParse_ErrorCode(t *testing.T) {
+	t.Parallel()
+
+	testcases := map[string]struct {
+		lines    []string
+		expected failure.Code
+	}{
+		"syntax": {
+			lines:    []string{"EX@MPLE:value"},
+			expected: errcode.Syntax,
+		},
+		"unknown property": {
+			lines:    []string{"FOO:bar"},
+			expected: errcode.UnknownProperty,
+		},
+		"unsupported component": {
+			lines:    []string{"BEGIN:VUNKNOWN", "END:VUNKNOWN"},
+			expected: errcode.UnsupportedComponent,
+		},
+		"value format": {
+			lines:    []string{"BEGIN:VEVENT", "UID:1", "DTSTAMP:2020", "END:VEVENT"},
+			expected: errcode.ValueFormat,
+		},
+		"cardinality": {
+			lines:    []string{"BEGIN:VEVENT", "UID:1,2", "END:VEVENT"},
+			expected: errcode.Cardinality,
+		},
+		"missing property": {
+			lines:    []string{"BEGIN:VEVENT", "UID:1", "BEGIN:VALARM", "TRIGGER:-PT5M", "END:VALARM", "END:VEVENT"},
+			expected: errcode.MissingProperty,
+		},
+		"time zone not found": {
+			lines:    []string{"BEGIN:VEVENT", "UID:1", "DTSTART;TZID=Nowhere/Unknown:20200101T000000", "END:VEVENT"},
+			expected: errcode.TimezoneNotFound,
+		},
+		"invalid end": {
+			lines:    []string{"BEGIN:VEVENT", "UID:1", "END:VTODO", "END:VEVENT"},
+			expected: errcode.Invalid,
+		},
+		"no end": {
+			lines:    []string{"BEGIN:VEVENT", "UID:1"},
+			expected: errcode.NoEnd,
+		},
+	}
+
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			lines := append([]string{"BEGIN:VCALENDAR"}, tc.lines...)
+			lines = append(lines, "END:VCALENDAR")
+			_, err := Parse(strings.NewReader(strings.Join(lines, "\r\n")))
+			if err == nil {
+				t.Fatal("expected error but got nil")
+			}
+			if !failure.Is(err, tc.expected) {
+				code, _ := failure.CodeOf(err)
+				t.Errorf("expected code %v, but %v: %v", tc.expected, code, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParse_Occurrences(t *testing.T) {
+	t.Parallel()
+	utc := func(m time.Month, d, h int) time.Time {
+		return time.Date(2020, m, d, h, 0, 0, 0, time.UTC)
+	}
+
+	testcases := map[string]struct {
+		component []string
+		expected  []time.Time
+	}{
+		"event with BYDAY": {
+			component: []string{
+				"BEGIN:VEVENT",
+				"UID:weekly@example.com",
+				"DTSTAMP:20200101T000000Z",
+				"DTSTART:20200106T100000Z",
+				"DTEND:20200106T110000Z",
+				"RRULE:FREQ=WEEKLY;BYDAY=MO,WE;COUNT=4",
+				"END:VEVENT",
+			},
+			expected: []time.Time{utc(1, 6, 10), utc(1, 8, 10), utc(1, 13, 10), utc(1, 15, 10)},
+		},
+		"todo with BYHOUR": {
+			component: []string{
+				"BEGIN:VTODO",
+				"UID:daily@example.com",
+				"DTSTAMP:20200101T000000Z",
+				"DTSTART:20200106T090000Z",
+				"RRULE:FREQ=DAILY;BYHOUR=9,17;COUNT=3",
+				"END:VTODO",
+			},
+			expected: []time.Time{utc(1, 6, 9), utc(1, 6, 17), utc(1, 7, 9)},
+		},
+	}
+
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			lines := append([]string{"BEGIN:VCALENDAR", "VERSION:2.0", "PRODID:-//example//EN"}, tc.component...)
+			lines = append(lines, "END:VCALENDAR", "")
+			c, err := Parse(strings.NewReader(strings.Join(lines, "\r\n")))
+			if err != nil {
+				t.Fatal(err)
+			}
+			occs, err := c.Occurrences(utc(1, 1, 0), utc(2, 1, 0))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []time.Time
+			for _, o := range occs {
+				got = append(got, o.Start)
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("diff: (-expected +got)\n%s", diff)
+			}
+		})
+	}
+}
@@ -6,12 +6,14 @@ import (
 	"github.com/knsh14/ical"
 	"github.com/knsh14/ical/component"
 	"github.com/knsh14/ical/contentline"
+	"github.com/knsh14/ical/errcode"
 	"github.com/knsh14/ical/property"
 	"github.com/knsh14/ical/token"
 	"github.com/knsh14/ical/types"
 )
 
 func (p *Parser) parseAlarm() (ical.Alarm, error) {
+	begin := p.CurrentIndex
 	p.nextLine()
 	var lines []*contentline.ContentLine
 
@@ -20,12 +22,18 @@ func (p *Parser) parseAlarm() (ical.Alarm, error) {
 		switch pname := property.Name(l.Name); pname {
 		case property.NameEnd:
 			if !p.isEndComponent(component.TypeAlarm) {
-				return nil, fmt.Errorf("Invalid END")
+				return nil, errcode.Errorf(errcode.Invalid, "Invalid END")
 			}
 			if parseFunc == nil {
-				return nil, NewParseError(component.TypeAlarm, pname, fmt.Errorf("required ACTION but not found"))
+				return nil, NewParseError(component.TypeAlarm, pname, errcode.Errorf(errcode.MissingProperty, "required ACTION but not found"))
 			}
-			return parseFunc(lines)
+			a, err := parseFunc(lines)
+			if err != nil {
+				// lines are between BEGIN and END, point the line which causes error
+				p.CurrentIndex = begin + 1 + p.alarmLine
+				return nil, err
+			}
+			return a, nil
 		case property.NameAction:
 			if len(l.Values) != 1 {
 				return nil, NewInvalidValueLengthError(1, len(l.Values))
@@ -48,9 +56,10 @@ func (p *Parser) parseAlarm() (ical.Alarm, error) {
 }
 
 func (p *Parser) parseAlarmAudio(lines []*contentline.ContentLine) (ical.Alarm, error) {
-
 	aa := &ical.AlarmAudio{}
-	for _, l := range lines {
+	counts := map[property.Name]int{}
+	for i, l := range lines {
+		p.alarmLine = i
 		params, err := p.parseParameter(l)
 		if err != nil {
 			return nil, fmt.Errorf("parse parameter: %w", err)
@@ -115,15 +124,22 @@ func (p *Parser) parseAlarmAudio(lines []*contentline.ContentLine) (ical.Alarm,
 					return nil, fmt.Errorf("value : %w", err)
 				}
 				aa.XProperties = append(aa.XProperties, ns)
+				break
+			}
+			if p.roundTrip {
+				aa.IANAProperties = append(aa.IANAProperties, property.NewIANA(l.Name, params, l.Values))
 			}
 		}
+		p.recordProperty(&aa.PropertyOrder, counts, l)
 	}
+	p.checkOccurrences(aa, counts, component.TypeCalendar, p.currentComponentType, component.TypeAlarm)
 	return aa, nil
 }
 func (p *Parser) parseAlarmDisplay(lines []*contentline.ContentLine) (ical.Alarm, error) {
-
 	ad := &ical.AlarmDisplay{}
-	for _, l := range lines {
+	counts := map[property.Name]int{}
+	for i, l := range lines {
+		p.alarmLine = i
 		params, err := p.parseParameter(l)
 		if err != nil {
 			return nil, fmt.Errorf("parse parameter: %w", err)
@@ -185,15 +201,22 @@ func (p *Parser) parseAlarmDisplay(lines []*contentline.ContentLine) (ical.Alarm
 					return nil, fmt.Errorf("value : %w", err)
 				}
 				ad.XProperties = append(ad.XProperties, ns)
+				break
+			}
+			if p.roundTrip {
+				ad.IANAProperties = append(ad.IANAProperties, property.NewIANA(l.Name, params, l.Values))
 			}
 		}
+		p.recordProperty(&ad.PropertyOrder, counts, l)
 	}
+	p.checkOccurrences(ad, counts, component.TypeCalendar, p.currentComponentType, component.TypeAlarm)
 	return ad, nil
 }
 func (p *Parser) parseAlarmEmail(lines []*contentline.ContentLine) (ical.Alarm, error) {
-
 	ae := &ical.AlarmEmail{}
-	for _, l := range lines {
+	counts := map[property.Name]int{}
+	for i, l := range lines {
+		p.alarmLine = i
 		params, err := p.parseParameter(l)
 		if err != nil {
 			return nil, fmt.Errorf("parse parameter: %w", err)
@@ -285,8 +308,14 @@ func (p *Parser) parseAlarmEmail(lines []*contentline.ContentLine) (ical.Alarm,
 					return nil, fmt.Errorf("value : %w", err)
 				}
 				ae.XProperties = append(ae.XProperties, ns)
+				break
+			}
+			if p.roundTrip {
+				ae.IANAProperties = append(ae.IANAProperties, property.NewIANA(l.Name, params, l.Values))
 			}
 		}
+		p.recordProperty(&ae.PropertyOrder, counts, l)
 	}
+	p.checkOccurrences(ae, counts, component.TypeCalendar, p.currentComponentType, component.TypeAlarm)
 	return ae, nil
 }
@@ -5,6 +5,8 @@ import (
 
 	"github.com/knsh14/ical"
 	"github.com/knsh14/ical/component"
+	"github.com/knsh14/ical/contentline"
+	"github.com/knsh14/ical/errcode"
 	"github.com/knsh14/ical/property"
 	"github.com/knsh14/ical/token"
 	"github.com/knsh14/ical/types"
@@ -16,101 +18,135 @@ func (p *Parser) parseCalender() (*ical.Calendar, error) {
 	c := ical.NewCalendar()
 
 	for l := p.getCurrentLine(); l != nil; l = p.getCurrentLine() {
-		params, err := p.parseParameter(l)
-		if err != nil {
-			return nil, fmt.Errorf("parse parameter: %w", err)
-		}
 		switch pname := property.Name(l.Name); pname {
-		case property.NameCalScale:
-			if len(l.Values) > 1 {
-				return nil, NewInvalidValueLengthError(1, len(l.Values))
-			}
-			t := types.NewText(l.Values[0])
-			err = c.SetCalScale(params, t)
-			if err != nil {
-				return nil, NewParseError(component.TypeCalendar, pname, err)
-			}
-		case property.NameMethod:
-			if len(l.Values) > 1 {
-				return nil, NewInvalidValueLengthError(1, len(l.Values))
-			}
-			t := types.NewText(l.Values[0])
-			err = c.SetMethod(params, t)
-			if err != nil {
-				return nil, NewParseError(component.TypeCalendar, pname, err)
-			}
-		case property.NameProdID:
-			if len(l.Values) > 1 {
-				return nil, NewInvalidValueLengthError(1, len(l.Values))
-			}
-			t := types.NewText(l.Values[0])
-			err = c.SetProdID(params, t)
-			if err != nil {
-				return nil, NewParseError(component.TypeCalendar, pname, err)
-			}
-		case property.NameVersion:
-			if len(l.Values) > 1 {
-				return nil, NewInvalidValueLengthError(1, len(l.Values))
-			}
-			t := types.NewText(l.Values[0])
-			err = c.SetVersion(params, t)
-			if err != nil {
-				return nil, NewParseError(component.TypeCalendar, pname, err)
-			}
 		case property.NameBegin:
-			if len(l.Values) != 1 {
-				return nil, NewInvalidValueLengthError(1, len(l.Values))
-			}
-			switch ct := component.Type(l.Values[0]); ct {
-			case component.TypeEvent:
-				e, err := p.parseEvent()
-				if err != nil {
-					return nil, fmt.Errorf("parse %s: %w", ct, err)
-				}
-				c.Components = append(c.Components, e)
-			case component.TypeTODO:
-				todo, err := p.parseTodo()
-				if err != nil {
-					return nil, fmt.Errorf("parse %s: %w", ct, err)
-				}
-				c.Components = append(c.Components, todo)
-			case component.TypeJournal:
-				for !p.isEndComponent(ct) {
-					p.nextLine()
-				}
-			case component.TypeFreeBusy:
-				for !p.isEndComponent(ct) {
-					p.nextLine()
-				}
-			case component.TypeTimezone:
-				tz, err := p.parseTimezone()
-				if err != nil {
-					return nil, fmt.Errorf("parse %s: %w", ct, err)
-				}
-				c.Components = append(c.Components, tz)
-			default:
-				return nil, fmt.Errorf("unknown component type %s", ct)
+			cc, err := p.parseComponent()
+			if err != nil {
+				return nil, err
 			}
-			p.currentComponentType = component.TypeCalendar
+			c.Components = append(c.Components, cc)
 		case property.NameEnd:
 			if !p.isEndComponent(component.TypeCalendar) {
-				return nil, fmt.Errorf("Invalid END")
+				return nil, errcode.Errorf(errcode.Invalid, "Invalid END")
 			}
+			p.checkOccurrences(c, p.calendarCounts, component.TypeCalendar)
 			return c, nil
 		default:
-			if token.IsXName(l.Name) {
-				ns, err := property.NewNonStandard(l.Name, params, l.Values)
-				if err != nil {
-					return nil, fmt.Errorf("value : %w", err)
-				}
-				c.XProperties = append(c.XProperties, ns)
-				break
+			if err := p.setCalendarProperty(c, l); err != nil {
+				return nil, err
 			}
-			// if isIANAProp {
-			// }
-			return nil, fmt.Errorf("no property matched,LINE:%d %v", p.CurrentIndex+1, l)
 		}
 		p.nextLine()
 	}
 	return nil, NoEndError(component.TypeCalendar)
 }
+
+// setCalendarProperty sets calendar property in l to c
+func (p *Parser) setCalendarProperty(c *ical.Calendar, l *contentline.ContentLine) error {
+	params, err := p.parseParameter(l)
+	if err != nil {
+		return fmt.Errorf("parse parameter: %w", err)
+	}
+	switch pname := property.Name(l.Name); pname {
+	case property.NameCalScale:
+		if len(l.Values) > 1 {
+			return NewInvalidValueLengthError(1, len(l.Values))
+		}
+		t := types.NewText(l.Values[0])
+		err = c.SetCalScale(params, t)
+		if err != nil {
+			return NewParseError(component.TypeCalendar, pname, err)
+		}
+	case property.NameMethod:
+		if len(l.Values) > 1 {
+			return NewInvalidValueLengthError(1, len(l.Values))
+		}
+		t := types.NewText(l.Values[0])
+		err = c.SetMethod(params, t)
+		if err != nil {
+			return NewParseError(component.TypeCalendar, pname, err)
+		}
+	case property.NameProdID:
+		if len(l.Values) > 1 {
+			return NewInvalidValueLengthError(1, len(l.Values))
+		}
+		t := types.UnescapeText(l.Values[0])
+		err = c.SetProdID(params, t)
+		if err != nil {
+			return NewParseError(component.TypeCalendar, pname, err)
+		}
+	case property.NameVersion:
+		if len(l.Values) > 1 {
+			return NewInvalidValueLengthError(1, len(l.Values))
+		}
+		t := types.NewText(l.Values[0])
+		err = c.SetVersion(params, t)
+		if err != nil {
+			return NewParseError(component.TypeCalendar, pname, err)
+		}
+	default:
+		switch {
+		case token.IsXName(l.Name):
+			ns, err := property.NewNonStandard(l.Name, params, l.Values)
+			if err != nil {
+				return fmt.Errorf("value : %w", err)
+			}
+			c.XProperties = append(c.XProperties, ns)
+		case p.roundTrip:
+			c.IANAProperties = append(c.IANAProperties, property.NewIANA(l.Name, params, l.Values))
+		default:
+			return errcode.Errorf(errcode.UnknownProperty, "no property matched %v", l)
+		}
+	}
+	if p.calendarCounts == nil {
+		p.calendarCounts = map[property.Name]int{}
+	}
+	p.recordProperty(&c.PropertyOrder, p.calendarCounts, l)
+	return nil
+}
+
+// parseComponent parses component which begins at current line.
+func (p *Parser) parseComponent() (ical.CalenderComponent, error) {
+	l := p.getCurrentLine()
+	if len(l.Values) != 1 {
+		return nil, NewInvalidValueLengthError(1, len(l.Values))
+	}
+	defer func() {
+		p.currentComponentType = component.TypeCalendar
+	}()
+	switch ct := component.Type(l.Values[0]); ct {
+	case component.TypeEvent:
+		e, err := p.parseEvent()
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", ct, err)
+		}
+		return e, nil
+	case component.TypeTODO:
+		todo, err := p.parseTodo()
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", ct, err)
+		}
+		return todo, nil
+	case component.TypeJournal:
+		j, err := p.parseJournal()
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", ct, err)
+		}
+		return j, nil
+	case component.TypeFreeBusy:
+		fb, err := p.parseFreeBusy()
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", ct, err)
+		}
+		return fb, nil
+	case component.TypeTimezone:
+		tz, err := p.parseTimezone()
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", ct, err)
+		}
+		p.addTimezone(tz)
+		return tz, nil
+	default:
+		return nil, errcode.Errorf(errcode.UnsupportedComponent, "unknown component type %s", ct)
+	}
+}
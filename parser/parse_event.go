@@ -5,6 +5,7 @@ import (
 
 	"github.com/knsh14/ical"
 	"github.com/knsh14/ical/component"
+	"github.com/knsh14/ical/errcode"
 	"github.com/knsh14/ical/property"
 	"github.com/knsh14/ical/token"
 	"github.com/knsh14/ical/types"
@@ -14,6 +15,7 @@ func (p *Parser) parseEvent() (*ical.Event, error) {
 	p.nextLine() // skip BEGIN:VEVENT line
 	p.currentComponentType = component.TypeEvent
 	event := ical.NewEvent()
+	counts := map[property.Name]int{}
 
 	for l := p.getCurrentLine(); l != nil; l = p.getCurrentLine() {
 		params, err := p.parseParameter(l)
@@ -23,12 +25,13 @@ func (p *Parser) parseEvent() (*ical.Event, error) {
 		switch pname := property.Name(l.Name); pname {
 		case property.NameEnd:
 			if !p.isEndComponent(component.TypeEvent) {
-				return nil, fmt.Errorf("Invalid END")
+				return nil, errcode.Errorf(errcode.Invalid, "Invalid END")
 			}
+			p.checkOccurrences(event, counts, component.TypeCalendar, component.TypeEvent)
 			return event, nil
 		case property.NameBegin:
 			if !p.isBeginComponent(component.TypeAlarm) {
-				return nil, fmt.Errorf("allow only BEGIN:VALARM, but %v", l)
+				return nil, errcode.Errorf(errcode.UnsupportedComponent, "allow only BEGIN:VALARM, but %v", l)
 			}
 			a, err := p.parseAlarm()
 			if err != nil {
@@ -40,7 +43,7 @@ func (p *Parser) parseEvent() (*ical.Event, error) {
 			if len(l.Values) != 1 {
 				return nil, NewInvalidValueLengthError(1, len(l.Values))
 			}
-			t := types.NewText(l.Values[0])
+			t := types.UnescapeText(l.Values[0])
 			if err := event.SetUID(params, t); err != nil {
 				return nil, NewParseError(component.TypeEvent, pname, err)
 			}
@@ -60,7 +63,7 @@ func (p *Parser) parseEvent() (*ical.Event, error) {
 			if len(l.Values) != 1 {
 				return nil, NewInvalidValueLengthError(1, len(l.Values))
 			}
-			t, err := ical.NewTimeType(params, l.Values[0])
+			t, err := ical.NewTimeTypeInLocation(params, l.Values[0], p.location(params))
 			if err != nil {
 				return nil, fmt.Errorf("convert date time for %s: %w", pname, err)
 			}
@@ -91,7 +94,7 @@ func (p *Parser) parseEvent() (*ical.Event, error) {
 			if len(l.Values) != 1 {
 				return nil, NewInvalidValueLengthError(1, len(l.Values))
 			}
-			t := types.NewText(l.Values[0])
+			t := types.UnescapeText(l.Values[0])
 			if err := event.SetDescription(params, t); err != nil {
 				return nil, NewParseError(component.TypeEvent, pname, err)
 			}
@@ -119,7 +122,7 @@ func (p *Parser) parseEvent() (*ical.Event, error) {
 			if len(l.Values) > 1 {
 				return nil, NewInvalidValueLengthError(1, len(l.Values))
 			}
-			t := types.NewText(l.Values[0])
+			t := types.UnescapeText(l.Values[0])
 			if err := event.SetLocation(params, t); err != nil {
 				return nil, NewParseError(component.TypeEvent, pname, err)
 			}
@@ -169,7 +172,7 @@ func (p *Parser) parseEvent() (*ical.Event, error) {
 			if len(l.Values) > 1 {
 				return nil, NewInvalidValueLengthError(1, len(l.Values))
 			}
-			t := types.NewText(l.Values[0])
+			t := types.UnescapeText(l.Values[0])
 			if err := event.SetSummary(params, t); err != nil {
 				return nil, NewParseError(component.TypeEvent, pname, err)
 			}
@@ -196,7 +199,7 @@ func (p *Parser) parseEvent() (*ical.Event, error) {
 			if len(l.Values) > 1 {
 				return nil, NewInvalidValueLengthError(1, len(l.Values))
 			}
-			t, err := ical.NewTimeType(params, l.Values[0])
+			t, err := ical.NewTimeTypeInLocation(params, l.Values[0], p.location(params))
 			if err != nil {
 				return nil, fmt.Errorf("convert %s into TimeType: %w", l.Values[0], err)
 			}
@@ -204,12 +207,9 @@ func (p *Parser) parseEvent() (*ical.Event, error) {
 				return nil, NewParseError(component.TypeEvent, pname, err)
 			}
 		case property.NameRecurrenceRule:
-			if len(l.Values) > 1 {
-				return nil, NewInvalidValueLengthError(1, len(l.Values))
-			}
-			rr, err := types.NewRecurrenceRule(l.Values[0])
+			rr, err := recurrenceRule(l)
 			if err != nil {
-				return nil, fmt.Errorf("convert %s into RecurrenceRule: %w", l.Values[0], err)
+				return nil, err
 			}
 			if err := event.SetRecurrenceRule(params, rr); err != nil {
 				return nil, NewParseError(component.TypeEvent, pname, err)
@@ -218,7 +218,7 @@ func (p *Parser) parseEvent() (*ical.Event, error) {
 			if len(l.Values) > 1 {
 				return nil, NewInvalidValueLengthError(1, len(l.Values))
 			}
-			t, err := ical.NewTimeType(params, l.Values[0])
+			t, err := ical.NewTimeTypeInLocation(params, l.Values[0], p.location(params))
 			if err != nil {
 				return nil, fmt.Errorf("convert %s into TimeType: %w", l.Values[0], err)
 			}
@@ -263,23 +263,31 @@ func (p *Parser) parseEvent() (*ical.Event, error) {
 		case property.NameCategories:
 			var ts []types.Text
 			for _, v := range l.Values {
-				ts = append(ts, types.NewText(v))
+				ts = append(ts, types.UnescapeText(v))
 			}
 			if err := event.AddCategories(params, ts); err != nil {
 				return nil, NewParseError(component.TypeEvent, pname, err)
 			}
+		case property.NameComment:
+			if len(l.Values) > 1 {
+				return nil, NewInvalidValueLengthError(1, len(l.Values))
+			}
+			t := types.UnescapeText(l.Values[0])
+			if err := event.AddComment(params, t); err != nil {
+				return nil, NewParseError(component.TypeEvent, pname, err)
+			}
 		case property.NameContact:
 			if len(l.Values) > 1 {
 				return nil, NewInvalidValueLengthError(1, len(l.Values))
 			}
-			t := types.NewText(l.Values[0])
+			t := types.UnescapeText(l.Values[0])
 			if err := event.AddContact(params, t); err != nil {
 				return nil, NewParseError(component.TypeEvent, pname, err)
 			}
 		case property.NameExceptionDateTimes:
 			var ts []types.TimeValue
 			for _, v := range l.Values {
-				t, err := ical.NewTimeType(params, v)
+				t, err := ical.NewTimeTypeInLocation(params, v, p.location(params))
 				if err != nil {
 					return nil, fmt.Errorf("convert %s into TimeType in %s: %w", v, pname, err)
 				}
@@ -300,14 +308,14 @@ func (p *Parser) parseEvent() (*ical.Event, error) {
 			if len(l.Values) > 1 {
 				return nil, NewInvalidValueLengthError(1, len(l.Values))
 			}
-			t := types.NewText(l.Values[0])
+			t := types.UnescapeText(l.Values[0])
 			if err := event.AddRelatedTo(params, t); err != nil {
 				return nil, NewParseError(component.TypeEvent, pname, err)
 			}
 		case property.NameResources:
 			var ts []types.Text
 			for _, v := range l.Values {
-				ts = append(ts, types.NewText(v))
+				ts = append(ts, types.UnescapeText(v))
 			}
 			if err := event.AddResources(params, ts); err != nil {
 				return nil, NewParseError(component.TypeEvent, pname, err)
@@ -315,7 +323,7 @@ func (p *Parser) parseEvent() (*ical.Event, error) {
 		case property.NameRecurrenceDateTimes:
 			var rdts []types.RecurrenceDateTimeValue
 			for _, v := range l.Values {
-				rdt, err := property.NewRecurrenceDateTime(params, v)
+				rdt, err := property.NewRecurrenceDateTimeInLocation(params, v, p.location(params))
 				if err != nil {
 					return nil, fmt.Errorf("convert %s to RecurrenceDateTime: %w", v, err)
 				}
@@ -331,8 +339,13 @@ func (p *Parser) parseEvent() (*ical.Event, error) {
 					return nil, fmt.Errorf("value : %w", err)
 				}
 				event.XProperties = append(event.XProperties, ns)
+				break
+			}
+			if p.roundTrip {
+				event.IANAProperties = append(event.IANAProperties, property.NewIANA(l.Name, params, l.Values))
 			}
 		}
+		p.recordProperty(&event.PropertyOrder, counts, l)
 		p.nextLine()
 	}
 	return nil, NoEndError(component.TypeEvent)
@@ -0,0 +1,161 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/knsh14/ical"
+	"github.com/knsh14/ical/component"
+	"github.com/knsh14/ical/errcode"
+	"github.com/knsh14/ical/property"
+	"github.com/knsh14/ical/token"
+	"github.com/knsh14/ical/types"
+)
+
+func (p *Parser) parseFreeBusy() (*ical.FreeBusy, error) {
+	p.nextLine() // skip BEGIN:VFREEBUSY line
+	p.currentComponentType = component.TypeFreeBusy
+	fb := ical.NewFreeBusy()
+	counts := map[property.Name]int{}
+
+	for l := p.getCurrentLine(); l != nil; l = p.getCurrentLine() {
+		params, err := p.parseParameter(l)
+		if err != nil {
+			return nil, fmt.Errorf("parse parameter: %w", err)
+		}
+
+		switch pname := property.Name(l.Name); pname {
+		case property.NameEnd:
+			if !p.isEndComponent(component.TypeFreeBusy) {
+				return nil, errcode.Errorf(errcode.Invalid, "Invalid END")
+			}
+			p.checkOccurrences(fb, counts, component.TypeCalendar, component.TypeFreeBusy)
+			return fb, nil
+		case property.NameUID:
+			if len(l.Values) != 1 {
+				return nil, NewInvalidValueLengthError(1, len(l.Values))
+			}
+			t := types.UnescapeText(l.Values[0])
+			if err := fb.SetUID(params, t); err != nil {
+				return nil, NewParseError(component.TypeFreeBusy, pname, err)
+			}
+		case property.NameDateTimeStamp:
+			if len(l.Values) != 1 {
+				return nil, NewInvalidValueLengthError(1, len(l.Values))
+			}
+			tz := params.GetTimezone()
+			t, err := types.NewDateTime(l.Values[0], tz)
+			if err != nil {
+				return nil, fmt.Errorf("convert date time: %w", err)
+			}
+			if err := fb.SetDateTimeStamp(params, t); err != nil {
+				return nil, NewParseError(component.TypeFreeBusy, pname, err)
+			}
+		case property.NameContact:
+			if len(l.Values) != 1 {
+				return nil, NewInvalidValueLengthError(1, len(l.Values))
+			}
+			t := types.UnescapeText(l.Values[0])
+			if err := fb.SetContact(params, t); err != nil {
+				return nil, NewParseError(component.TypeFreeBusy, pname, err)
+			}
+		case property.NameDateTimeStart:
+			if len(l.Values) != 1 {
+				return nil, NewInvalidValueLengthError(1, len(l.Values))
+			}
+			t, err := types.NewDateTime(l.Values[0], "")
+			if err != nil {
+				return nil, fmt.Errorf("convert date time for %s: %w", pname, err)
+			}
+			if err := fb.SetDateTimeStart(params, t); err != nil {
+				return nil, NewParseError(component.TypeFreeBusy, pname, err)
+			}
+		case property.NameDateTimeEnd:
+			if len(l.Values) != 1 {
+				return nil, NewInvalidValueLengthError(1, len(l.Values))
+			}
+			t, err := types.NewDateTime(l.Values[0], "")
+			if err != nil {
+				return nil, fmt.Errorf("convert date time for %s: %w", pname, err)
+			}
+			if err := fb.SetDateTimeEnd(params, t); err != nil {
+				return nil, NewParseError(component.TypeFreeBusy, pname, err)
+			}
+		case property.NameOrganizer:
+			if len(l.Values) != 1 {
+				return nil, NewInvalidValueLengthError(1, len(l.Values))
+			}
+			t, err := types.NewCalenderUserAddress(l.Values[0])
+			if err != nil {
+				return nil, fmt.Errorf("convert %s into CalenderUserAddress: %w", l.Values[0], err)
+			}
+			if err := fb.SetOrganizer(params, t); err != nil {
+				return nil, NewParseError(component.TypeFreeBusy, pname, err)
+			}
+		case property.NameURL:
+			if len(l.Values) != 1 {
+				return nil, NewInvalidValueLengthError(1, len(l.Values))
+			}
+			t, err := types.NewURI(l.Values[0])
+			if err != nil {
+				return nil, fmt.Errorf("convert %s into URI: %w", l.Values[0], err)
+			}
+			if err := fb.SetURL(params, t); err != nil {
+				return nil, NewParseError(component.TypeFreeBusy, pname, err)
+			}
+		case property.NameAttendee:
+			if len(l.Values) != 1 {
+				return nil, NewInvalidValueLengthError(1, len(l.Values))
+			}
+			a, err := types.NewCalenderUserAddress(l.Values[0])
+			if err != nil {
+				return nil, fmt.Errorf("convert %s into CalenderUserAddress: %w", l.Values[0], err)
+			}
+			if err := fb.AddAttendee(params, a); err != nil {
+				return nil, NewParseError(component.TypeFreeBusy, pname, err)
+			}
+		case property.NameComment:
+			if len(l.Values) != 1 {
+				return nil, NewInvalidValueLengthError(1, len(l.Values))
+			}
+			t := types.UnescapeText(l.Values[0])
+			if err := fb.AddComment(params, t); err != nil {
+				return nil, NewParseError(component.TypeFreeBusy, pname, err)
+			}
+		case property.NameFreeBusyTime:
+			var ps []types.Period
+			for _, v := range l.Values {
+				period, err := types.NewPeriod(v)
+				if err != nil {
+					return nil, fmt.Errorf("convert %s into Period: %w", v, err)
+				}
+				ps = append(ps, period)
+			}
+			if err := fb.AddFreeBusyTime(params, ps); err != nil {
+				return nil, NewParseError(component.TypeFreeBusy, pname, err)
+			}
+		case property.NameRequestStatus:
+			if len(l.Values) != 1 {
+				return nil, NewInvalidValueLengthError(1, len(l.Values))
+			}
+			t := types.NewText(l.Values[0])
+			if err := fb.AddRequestStatus(params, t); err != nil {
+				return nil, NewParseError(component.TypeFreeBusy, pname, err)
+			}
+		default:
+			if token.IsXName(l.Name) {
+				ns, err := property.NewNonStandard(l.Name, params, l.Values)
+				if err != nil {
+					return nil, fmt.Errorf("value : %w", err)
+				}
+				fb.XProperties = append(fb.XProperties, ns)
+				break
+			}
+			if p.roundTrip {
+				fb.IANAProperties = append(fb.IANAProperties, property.NewIANA(l.Name, params, l.Values))
+			}
+		}
+		p.recordProperty(&fb.PropertyOrder, counts, l)
+		p.nextLine()
+	}
+	return nil, NoEndError(component.TypeFreeBusy)
+}
@@ -0,0 +1,61 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/knsh14/ical/contentline"
+	"github.com/knsh14/ical/parameter"
+)
+
+func TestParseFreeBusy(t *testing.T) {
+	t.Parallel()
+	testcases := map[string]struct {
+		input    []*contentline.ContentLine
+		expected []parameter.FreeBusyTimeTypeKind
+	}{
+		"default type is busy": {
+			input: []*contentline.ContentLine{
+				{Name: "BEGIN", Values: []string{"VFREEBUSY"}},
+				{Name: "FREEBUSY", Values: []string{"19980314T233000Z/19980315T003000Z"}},
+				{Name: "END", Values: []string{"VFREEBUSY"}},
+			},
+			expected: []parameter.FreeBusyTimeTypeKind{parameter.FreeBusyTimeTypeKindBusy},
+		},
+		"free busy types": {
+			input: []*contentline.ContentLine{
+				{Name: "BEGIN", Values: []string{"VFREEBUSY"}},
+				{
+					Name:       "FREEBUSY",
+					Parameters: []contentline.Parameter{{Name: "FBTYPE", Values: []string{"FREE"}}},
+					Values:     []string{"19970308T160000Z/PT8H30M"},
+				},
+				{
+					Name:       "FREEBUSY",
+					Parameters: []contentline.Parameter{{Name: "FBTYPE", Values: []string{"BUSY-TENTATIVE"}}},
+					Values:     []string{"19980320T150000Z/PT2H", "19980321T150000Z/PT1H"},
+				},
+				{Name: "END", Values: []string{"VFREEBUSY"}},
+			},
+			expected: []parameter.FreeBusyTimeTypeKind{parameter.FreeBusyTimeTypeKindFree, parameter.FreeBusyTimeTypeKindBusyTentative},
+		},
+	}
+	for title, tc := range testcases {
+		tc := tc
+		t.Run(title, func(t *testing.T) {
+			t.Parallel()
+			p := NewParser(tc.input)
+			fb, err := p.parseFreeBusy()
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []parameter.FreeBusyTimeTypeKind
+			for _, fbt := range fb.FreeBusyTimes {
+				got = append(got, fbt.FreeBusyType().Type)
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,275 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/knsh14/ical"
+	"github.com/knsh14/ical/component"
+	"github.com/knsh14/ical/errcode"
+	"github.com/knsh14/ical/property"
+	"github.com/knsh14/ical/token"
+	"github.com/knsh14/ical/types"
+)
+
+func (p *Parser) parseJournal() (*ical.Journal, error) {
+	p.nextLine() // skip BEGIN:VJOURNAL line
+	p.currentComponentType = component.TypeJournal
+	journal := ical.NewJournal()
+	counts := map[property.Name]int{}
+
+	for l := p.getCurrentLine(); l != nil; l = p.getCurrentLine() {
+		params, err := p.parseParameter(l)
+		if err != nil {
+			return nil, fmt.Errorf("parse parameter: %w", err)
+		}
+
+		switch pname := property.Name(l.Name); pname {
+		case property.NameEnd:
+			if !p.isEndComponent(component.TypeJournal) {
+				return nil, errcode.Errorf(errcode.Invalid, "Invalid END")
+			}
+			p.checkOccurrences(journal, counts, component.TypeCalendar, component.TypeJournal)
+			return journal, nil
+		case property.NameUID:
+			if len(l.Values) != 1 {
+				return nil, NewInvalidValueLengthError(1, len(l.Values))
+			}
+			t := types.UnescapeText(l.Values[0])
+			if err := journal.SetUID(params, t); err != nil {
+				return nil, NewParseError(component.TypeJournal, pname, err)
+			}
+		case property.NameDateTimeStamp:
+			if len(l.Values) != 1 {
+				return nil, NewInvalidValueLengthError(1, len(l.Values))
+			}
+			tz := params.GetTimezone()
+			t, err := types.NewDateTime(l.Values[0], tz)
+			if err != nil {
+				return nil, fmt.Errorf("convert date time: %w", err)
+			}
+			if err := journal.SetDateTimeStamp(params, t); err != nil {
+				return nil, NewParseError(component.TypeJournal, pname, err)
+			}
+		case property.NameClass:
+			if len(l.Values) != 1 {
+				return nil, NewInvalidValueLengthError(1, len(l.Values))
+			}
+			t := types.NewText(l.Values[0])
+			if err := journal.SetClass(params, t); err != nil {
+				return nil, NewParseError(component.TypeJournal, pname, err)
+			}
+		case property.NameDateTimeCreated:
+			if len(l.Values) != 1 {
+				return nil, NewInvalidValueLengthError(1, len(l.Values))
+			}
+			tz := params.GetTimezone()
+			t, err := types.NewDateTime(l.Values[0], tz)
+			if err != nil {
+				return nil, fmt.Errorf("convert date time: %w", err)
+			}
+			if err := journal.SetDateTimeCreated(params, t); err != nil {
+				return nil, NewParseError(component.TypeJournal, pname, err)
+			}
+		case property.NameDescription:
+			if len(l.Values) != 1 {
+				return nil, NewInvalidValueLengthError(1, len(l.Values))
+			}
+			t := types.UnescapeText(l.Values[0])
+			if err := journal.AddDescription(params, t); err != nil {
+				return nil, NewParseError(component.TypeJournal, pname, err)
+			}
+		case property.NameDateTimeStart:
+			if len(l.Values) != 1 {
+				return nil, NewInvalidValueLengthError(1, len(l.Values))
+			}
+			t, err := ical.NewTimeTypeInLocation(params, l.Values[0], p.location(params))
+			if err != nil {
+				return nil, fmt.Errorf("convert date time for %s: %w", pname, err)
+			}
+			if err := journal.SetDateTimeStart(params, t); err != nil {
+				return nil, NewParseError(component.TypeJournal, pname, err)
+			}
+		case property.NameLastModified:
+			if len(l.Values) > 1 {
+				return nil, NewInvalidValueLengthError(1, len(l.Values))
+			}
+			tz := params.GetTimezone()
+			v, err := types.NewDateTime(l.Values[0], tz)
+			if err != nil {
+				return nil, fmt.Errorf("conbert value to DateTime: %w", err)
+			}
+			if err := journal.SetLastModified(params, v); err != nil {
+				return nil, NewParseError(component.TypeJournal, pname, err)
+			}
+		case property.NameOrganizer:
+			if len(l.Values) > 1 {
+				return nil, NewInvalidValueLengthError(1, len(l.Values))
+			}
+			t, err := types.NewCalenderUserAddress(l.Values[0])
+			if err != nil {
+				return nil, fmt.Errorf("convert %s into CalenderUserAddress: %w", l.Values[0], err)
+			}
+			if err := journal.SetOrganizer(params, t); err != nil {
+				return nil, NewParseError(component.TypeJournal, pname, err)
+			}
+		case property.NameRecurrenceID:
+			if len(l.Values) > 1 {
+				return nil, NewInvalidValueLengthError(1, len(l.Values))
+			}
+			t, err := ical.NewTimeTypeInLocation(params, l.Values[0], p.location(params))
+			if err != nil {
+				return nil, fmt.Errorf("convert %s into TimeType: %w", l.Values[0], err)
+			}
+			if err := journal.SetRecurrenceID(params, t); err != nil {
+				return nil, NewParseError(component.TypeJournal, pname, err)
+			}
+		case property.NameSequenceNumber:
+			if len(l.Values) > 1 {
+				return nil, NewInvalidValueLengthError(1, len(l.Values))
+			}
+			i, err := types.NewInteger(l.Values[0])
+			if err != nil {
+				return nil, fmt.Errorf("convert %s into Integer: %w", l.Values[0], err)
+			}
+			if err := journal.SetSequenceNumber(params, i); err != nil {
+				return nil, NewParseError(component.TypeJournal, pname, err)
+			}
+		case property.NameStatus:
+			if len(l.Values) > 1 {
+				return nil, NewInvalidValueLengthError(1, len(l.Values))
+			}
+			t := types.NewText(l.Values[0])
+			if err := journal.SetStatus(params, t); err != nil {
+				return nil, NewParseError(component.TypeJournal, pname, err)
+			}
+		case property.NameSummary:
+			if len(l.Values) > 1 {
+				return nil, NewInvalidValueLengthError(1, len(l.Values))
+			}
+			t := types.UnescapeText(l.Values[0])
+			if err := journal.SetSummary(params, t); err != nil {
+				return nil, NewParseError(component.TypeJournal, pname, err)
+			}
+		case property.NameURL:
+			if len(l.Values) > 1 {
+				return nil, NewInvalidValueLengthError(1, len(l.Values))
+			}
+			t, err := types.NewURI(l.Values[0])
+			if err != nil {
+				return nil, fmt.Errorf("convert %s into URI: %w", l.Values[0], err)
+			}
+			if err := journal.SetURL(params, t); err != nil {
+				return nil, NewParseError(component.TypeJournal, pname, err)
+			}
+		case property.NameRecurrenceRule:
+			rr, err := recurrenceRule(l)
+			if err != nil {
+				return nil, err
+			}
+			if err := journal.SetRecurrenceRule(params, rr); err != nil {
+				return nil, NewParseError(component.TypeJournal, pname, err)
+			}
+		case property.NameAttachment:
+			if len(l.Values) > 1 {
+				return nil, NewInvalidValueLengthError(1, len(l.Values))
+			}
+			a, err := property.NewAttachmentValue(params, l.Values[0])
+			if err != nil {
+				return nil, fmt.Errorf("convert %s into Attachment value: %w", l.Values[0], err)
+			}
+			if err := journal.AddAttachment(params, a); err != nil {
+				return nil, NewParseError(component.TypeJournal, pname, err)
+			}
+		case property.NameAttendee:
+			if len(l.Values) > 1 {
+				return nil, NewInvalidValueLengthError(1, len(l.Values))
+			}
+			a, err := types.NewCalenderUserAddress(l.Values[0])
+			if err != nil {
+				return nil, fmt.Errorf("convert %s into Duration: %w", l.Values[0], err)
+			}
+			if err := journal.AddAttendee(params, a); err != nil {
+				return nil, NewParseError(component.TypeJournal, pname, err)
+			}
+		case property.NameCategories:
+			var ts []types.Text
+			for _, v := range l.Values {
+				ts = append(ts, types.UnescapeText(v))
+			}
+			if err := journal.AddCategories(params, ts); err != nil {
+				return nil, NewParseError(component.TypeJournal, pname, err)
+			}
+		case property.NameComment:
+			if len(l.Values) > 1 {
+				return nil, NewInvalidValueLengthError(1, len(l.Values))
+			}
+			t := types.UnescapeText(l.Values[0])
+			if err := journal.AddComment(params, t); err != nil {
+				return nil, NewParseError(component.TypeJournal, pname, err)
+			}
+		case property.NameContact:
+			if len(l.Values) > 1 {
+				return nil, NewInvalidValueLengthError(1, len(l.Values))
+			}
+			t := types.UnescapeText(l.Values[0])
+			if err := journal.AddContact(params, t); err != nil {
+				return nil, NewParseError(component.TypeJournal, pname, err)
+			}
+		case property.NameExceptionDateTimes:
+			var ts []types.TimeValue
+			for _, v := range l.Values {
+				t, err := ical.NewTimeTypeInLocation(params, v, p.location(params))
+				if err != nil {
+					return nil, fmt.Errorf("convert %s into TimeType in %s: %w", v, pname, err)
+				}
+				ts = append(ts, t)
+			}
+			if err := journal.AddExceptionDateTimes(params, ts); err != nil {
+				return nil, NewParseError(component.TypeJournal, pname, err)
+			}
+		case property.NameRequestStatus:
+			if len(l.Values) > 1 {
+				return nil, NewInvalidValueLengthError(1, len(l.Values))
+			}
+			t := types.NewText(l.Values[0])
+			if err := journal.AddRequestStatus(params, t); err != nil {
+				return nil, NewParseError(component.TypeJournal, pname, err)
+			}
+		case property.NameRelatedTo:
+			if len(l.Values) > 1 {
+				return nil, NewInvalidValueLengthError(1, len(l.Values))
+			}
+			t := types.UnescapeText(l.Values[0])
+			if err := journal.AddRelatedTo(params, t); err != nil {
+				return nil, NewParseError(component.TypeJournal, pname, err)
+			}
+		case property.NameRecurrenceDateTimes:
+			var rdts []types.RecurrenceDateTimeValue
+			for _, v := range l.Values {
+				rdt, err := property.NewRecurrenceDateTimeInLocation(params, v, p.location(params))
+				if err != nil {
+					return nil, fmt.Errorf("convert %s to RecurrenceDateTime: %w", v, err)
+				}
+				rdts = append(rdts, rdt)
+			}
+			if err := journal.AddRecurrenceDateTimes(params, rdts); err != nil {
+				return nil, NewParseError(component.TypeJournal, pname, err)
+			}
+		default:
+			if token.IsXName(l.Name) {
+				ns, err := property.NewNonStandard(l.Name, params, l.Values)
+				if err != nil {
+					return nil, fmt.Errorf("value : %w", err)
+				}
+				journal.XProperties = append(journal.XProperties, ns)
+				break
+			}
+			if p.roundTrip {
+				journal.IANAProperties = append(journal.IANAProperties, property.NewIANA(l.Name, params, l.Values))
+			}
+		}
+		p.recordProperty(&journal.PropertyOrder, counts, l)
+		p.nextLine()
+	}
+	return nil, NoEndError(component.TypeJournal)
+}
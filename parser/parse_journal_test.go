@@ -0,0 +1,98 @@
+package parser
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/knsh14/ical"
+	"github.com/knsh14/ical/component"
+	"github.com/knsh14/ical/contentline"
+	"github.com/knsh14/ical/parameter"
+	"github.com/knsh14/ical/types"
+)
+
+func TestParseJournal(t *testing.T) {
+	t.Parallel()
+	testcases := map[string]struct {
+		input       []*contentline.ContentLine
+		expected    func(*testing.T) *ical.Journal
+		assertError func(*testing.T, error)
+	}{
+		"minutes": {
+			input: []*contentline.ContentLine{
+				{Name: "BEGIN", Values: []string{"VJOURNAL"}},
+				{Name: "UID", Values: []string{"19970901T130000Z-123405@example.com"}},
+				{Name: "DTSTAMP", Values: []string{"19970901T130000Z"}},
+				{Name: "SUMMARY", Values: []string{"Staff meeting minutes"}},
+				{Name: "DESCRIPTION", Values: []string{"1. Staff meeting: Participants include Joe\\, Lisa\\, and Bob."}},
+				{Name: "DESCRIPTION", Values: []string{"2. Telephone Conference: ABC Corp. sales representative called."}},
+				{Name: "STATUS", Values: []string{"FINAL"}},
+				{Name: "END", Values: []string{"VJOURNAL"}},
+			},
+			expected: func(t *testing.T) *ical.Journal {
+				t.Helper()
+				j := ical.NewJournal()
+				params := parameter.Container{}
+				mustNoError(t, j.SetUID(params, types.NewText("19970901T130000Z-123405@example.com")))
+				mustNoError(t, j.SetDateTimeStamp(params, types.DateTime(time.Date(1997, 9, 1, 13, 0, 0, 0, time.UTC))))
+				mustNoError(t, j.SetSummary(params, types.NewText("Staff meeting minutes")))
+				mustNoError(t, j.AddDescription(params, types.NewText("1. Staff meeting: Participants include Joe, Lisa, and Bob.")))
+				mustNoError(t, j.AddDescription(params, types.NewText("2. Telephone Conference: ABC Corp. sales representative called.")))
+				mustNoError(t, j.SetStatus(params, types.NewText("FINAL")))
+				return j
+			},
+			assertError: func(t *testing.T, err error) {
+				if err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+		"status of event": {
+			input: []*contentline.ContentLine{
+				{Name: "BEGIN", Values: []string{"VJOURNAL"}},
+				{Name: "STATUS", Values: []string{"CONFIRMED"}},
+				{Name: "END", Values: []string{"VJOURNAL"}},
+			},
+			expected: func(t *testing.T) *ical.Journal { return nil },
+			assertError: func(t *testing.T, err error) {
+				var pe ParseError
+				if !errors.As(err, &pe) {
+					t.Fatalf("expected ParseError, but %v", err)
+				}
+			},
+		},
+		"no end": {
+			input: []*contentline.ContentLine{
+				{Name: "BEGIN", Values: []string{"VJOURNAL"}},
+				{Name: "UID", Values: []string{"19970901T130000Z-123405@example.com"}},
+			},
+			expected: func(t *testing.T) *ical.Journal { return nil },
+			assertError: func(t *testing.T, err error) {
+				if !errors.Is(err, NoEndError(component.TypeJournal)) {
+					t.Fatalf("expected NoEndError, but %v", err)
+				}
+			},
+		},
+	}
+	for title, tc := range testcases {
+		tc := tc
+		t.Run(title, func(t *testing.T) {
+			t.Parallel()
+			p := NewParser(tc.input)
+			actual, err := p.parseJournal()
+			tc.assertError(t, err)
+			if diff := cmp.Diff(tc.expected(t), actual, cmp.AllowUnexported(types.DateTime{}, types.Date{})); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func mustNoError(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/knsh14/ical/contentline"
+	"github.com/knsh14/ical/errcode"
 	"github.com/knsh14/ical/parameter"
 )
 
@@ -13,7 +14,7 @@ func (p *Parser) parseParameter(cl *contentline.ContentLine) (parameter.Containe
 		switch t := parameter.TypeName(v.Name); t {
 		case parameter.TypeNameAlternateTextRepresentation:
 			if len(v.Values) != 1 {
-				return nil, fmt.Errorf("value for %s must be 1, but %d", t, len(v.Values))
+				return nil, errcode.Errorf(errcode.Cardinality, "value for %s must be 1, but %d", t, len(v.Values))
 			}
 			p, err := parameter.NewAlternateTextRepresentation(v.Values[0])
 			if err != nil {
@@ -22,13 +23,13 @@ func (p *Parser) parseParameter(cl *contentline.ContentLine) (parameter.Containe
 			params[t] = append(params[t], p)
 		case parameter.TypeNameCommonName:
 			if len(v.Values) != 1 {
-				return nil, fmt.Errorf("value for %s must be 1, but %d", t, len(v.Values))
+				return nil, errcode.Errorf(errcode.Cardinality, "value for %s must be 1, but %d", t, len(v.Values))
 			}
 			p := parameter.NewCommonName(v.Values[0])
 			params[t] = append(params[t], p)
 		case parameter.TypeNameCalenderUserType:
 			if len(v.Values) != 1 {
-				return nil, fmt.Errorf("value for %s must be 1, but %d", t, len(v.Values))
+				return nil, errcode.Errorf(errcode.Cardinality, "value for %s must be 1, but %d", t, len(v.Values))
 			}
 			p, err := parameter.NewCalenderUserType(v.Values[0])
 			if err != nil {
@@ -49,7 +50,7 @@ func (p *Parser) parseParameter(cl *contentline.ContentLine) (parameter.Containe
 			params[t] = append(params[t], p)
 		case parameter.TypeNameDirectoryEntry:
 			if len(v.Values) != 1 {
-				return nil, fmt.Errorf("value for %s must be 1, but %d", t, len(v.Values))
+				return nil, errcode.Errorf(errcode.Cardinality, "value for %s must be 1, but %d", t, len(v.Values))
 			}
 			p, err := parameter.NewDirectoryEntry(v.Values[0])
 			if err != nil {
@@ -58,7 +59,7 @@ func (p *Parser) parseParameter(cl *contentline.ContentLine) (parameter.Containe
 			params[t] = append(params[t], p)
 		case parameter.TypeNameInlineEncoding:
 			if len(v.Values) != 1 {
-				return nil, fmt.Errorf("value for %s must be 1, but %d", t, len(v.Values))
+				return nil, errcode.Errorf(errcode.Cardinality, "value for %s must be 1, but %d", t, len(v.Values))
 			}
 			p, err := parameter.NewInlineEncoding(v.Values[0])
 			if err != nil {
@@ -67,7 +68,7 @@ func (p *Parser) parseParameter(cl *contentline.ContentLine) (parameter.Containe
 			params[t] = append(params[t], p)
 		case parameter.TypeNameFormatType:
 			if len(v.Values) != 1 {
-				return nil, fmt.Errorf("value for %s must be 1, but %d", t, len(v.Values))
+				return nil, errcode.Errorf(errcode.Cardinality, "value for %s must be 1, but %d", t, len(v.Values))
 			}
 			p, err := parameter.NewFormatType(v.Values[0])
 			if err != nil {
@@ -76,7 +77,7 @@ func (p *Parser) parseParameter(cl *contentline.ContentLine) (parameter.Containe
 			params[t] = append(params[t], p)
 		case parameter.TypeNameFreeBusyTimeType:
 			if len(v.Values) != 1 {
-				return nil, fmt.Errorf("value for %s must be 1, but %d", t, len(v.Values))
+				return nil, errcode.Errorf(errcode.Cardinality, "value for %s must be 1, but %d", t, len(v.Values))
 			}
 			p, err := parameter.NewFreeBusyTimeType(v.Values[0])
 			if err != nil {
@@ -85,7 +86,7 @@ func (p *Parser) parseParameter(cl *contentline.ContentLine) (parameter.Containe
 			params[t] = append(params[t], p)
 		case parameter.TypeNameLanguage:
 			if len(v.Values) != 1 {
-				return nil, fmt.Errorf("value for %s must be 1, but %d", t, len(v.Values))
+				return nil, errcode.Errorf(errcode.Cardinality, "value for %s must be 1, but %d", t, len(v.Values))
 			}
 			p, err := parameter.NewLanguage(v.Values[0])
 			if err != nil {
@@ -100,7 +101,7 @@ func (p *Parser) parseParameter(cl *contentline.ContentLine) (parameter.Containe
 			params[t] = append(params[t], p)
 		case parameter.TypeNameParticipationStatus:
 			if len(v.Values) != 1 {
-				return nil, fmt.Errorf("value for %s must be 1, but %d", t, len(v.Values))
+				return nil, errcode.Errorf(errcode.Cardinality, "value for %s must be 1, but %d", t, len(v.Values))
 			}
 			p, err := parameter.NewParticipationStatus(v.Values[0], p.currentComponentType)
 			if err != nil {
@@ -109,7 +110,7 @@ func (p *Parser) parseParameter(cl *contentline.ContentLine) (parameter.Containe
 			params[t] = append(params[t], p)
 		case parameter.TypeNameRecurrenceIDRange:
 			if len(v.Values) != 1 {
-				return nil, fmt.Errorf("value for %s must be 1, but %d", t, len(v.Values))
+				return nil, errcode.Errorf(errcode.Cardinality, "value for %s must be 1, but %d", t, len(v.Values))
 			}
 			p, err := parameter.NewRecurrenceIDRange(v.Values[0])
 			if err != nil {
@@ -118,7 +119,7 @@ func (p *Parser) parseParameter(cl *contentline.ContentLine) (parameter.Containe
 			params[t] = append(params[t], p)
 		case parameter.TypeNameAlarmTriggerRelationship:
 			if len(v.Values) != 1 {
-				return nil, fmt.Errorf("value for %s must be 1, but %d", t, len(v.Values))
+				return nil, errcode.Errorf(errcode.Cardinality, "value for %s must be 1, but %d", t, len(v.Values))
 			}
 			p, err := parameter.NewAlarmTriggerRelationship(v.Values[0])
 			if err != nil {
@@ -127,7 +128,7 @@ func (p *Parser) parseParameter(cl *contentline.ContentLine) (parameter.Containe
 			params[t] = append(params[t], p)
 		case parameter.TypeNameRelationshipType:
 			if len(v.Values) != 1 {
-				return nil, fmt.Errorf("value for %s must be 1, but %d", t, len(v.Values))
+				return nil, errcode.Errorf(errcode.Cardinality, "value for %s must be 1, but %d", t, len(v.Values))
 			}
 			p, err := parameter.NewRelationshipType(v.Values[0])
 			if err != nil {
@@ -136,7 +137,7 @@ func (p *Parser) parseParameter(cl *contentline.ContentLine) (parameter.Containe
 			params[t] = append(params[t], p)
 		case parameter.TypeNameParticipationRole:
 			if len(v.Values) != 1 {
-				return nil, fmt.Errorf("value for %s must be 1, but %d", t, len(v.Values))
+				return nil, errcode.Errorf(errcode.Cardinality, "value for %s must be 1, but %d", t, len(v.Values))
 			}
 			p, err := parameter.NewParticipationRole(v.Values[0])
 			if err != nil {
@@ -145,7 +146,7 @@ func (p *Parser) parseParameter(cl *contentline.ContentLine) (parameter.Containe
 			params[t] = append(params[t], p)
 		case parameter.TypeNameRSVP:
 			if len(v.Values) != 1 {
-				return nil, fmt.Errorf("value for %s must be 1, but %d", t, len(v.Values))
+				return nil, errcode.Errorf(errcode.Cardinality, "value for %s must be 1, but %d", t, len(v.Values))
 			}
 			p, err := parameter.NewRSVP(v.Values[0])
 			if err != nil {
@@ -154,7 +155,7 @@ func (p *Parser) parseParameter(cl *contentline.ContentLine) (parameter.Containe
 			params[t] = append(params[t], p)
 		case parameter.TypeNameSentBy:
 			if len(v.Values) != 1 {
-				return nil, fmt.Errorf("value for %s must be 1, but %d", t, len(v.Values))
+				return nil, errcode.Errorf(errcode.Cardinality, "value for %s must be 1, but %d", t, len(v.Values))
 			}
 			p, err := parameter.NewSentBy(v.Values[0])
 			if err != nil {
@@ -163,7 +164,7 @@ func (p *Parser) parseParameter(cl *contentline.ContentLine) (parameter.Containe
 			params[t] = append(params[t], p)
 		case parameter.TypeNameReferenceTimezone:
 			if len(v.Values) != 1 {
-				return nil, fmt.Errorf("value for %s must be 1, but %d", t, len(v.Values))
+				return nil, errcode.Errorf(errcode.Cardinality, "value for %s must be 1, but %d", t, len(v.Values))
 			}
 			p, err := parameter.NewReferenceTimezone(v.Values[0])
 			if err != nil {
@@ -172,13 +173,22 @@ func (p *Parser) parseParameter(cl *contentline.ContentLine) (parameter.Containe
 			params[t] = append(params[t], p)
 		case parameter.TypeNameValueType:
 			if len(v.Values) != 1 {
-				return nil, fmt.Errorf("value for %s must be 1, but %d", t, len(v.Values))
+				return nil, errcode.Errorf(errcode.Cardinality, "value for %s must be 1, but %d", t, len(v.Values))
 			}
 			p := parameter.NewValueType(v.Values[0])
 			params[t] = append(params[t], p)
 		default:
-			// if x-token
+			if p.roundTrip {
+				params[t] = append(params[t], parameter.NewXParam(v.Name, v.Values))
+			}
+		}
+	}
+	if p.roundTrip && len(cl.Parameters) > 0 {
+		names := make([]parameter.TypeName, 0, len(cl.Parameters))
+		for _, v := range cl.Parameters {
+			names = append(names, parameter.TypeName(v.Name))
 		}
+		params.SetOrder(names)
 	}
 	return params, nil
 }
@@ -2,9 +2,11 @@ package parser
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/knsh14/ical"
 	"github.com/knsh14/ical/component"
+	"github.com/knsh14/ical/errcode"
 	"github.com/knsh14/ical/parameter"
 	"github.com/knsh14/ical/property"
 	"github.com/knsh14/ical/token"
@@ -15,6 +17,7 @@ func (p *Parser) parseTimezone() (*ical.Timezone, error) {
 	p.nextLine() // skip BEGIN:VTIMEZONE line
 	p.currentComponentType = component.TypeTimezone
 	timezone := ical.NewTimezone()
+	counts := map[property.Name]int{}
 
 	for l := p.getCurrentLine(); l != nil; l = p.getCurrentLine() {
 		params, err := p.parseParameter(l)
@@ -24,12 +27,13 @@ func (p *Parser) parseTimezone() (*ical.Timezone, error) {
 		switch pname := property.Name(l.Name); pname {
 		case property.NameEnd:
 			if !p.isEndComponent(component.TypeTimezone) {
-				return nil, fmt.Errorf("Invalid END")
+				return nil, errcode.Errorf(errcode.Invalid, "Invalid END")
 			}
+			p.checkOccurrences(timezone, counts, component.TypeCalendar, component.TypeTimezone)
 			return timezone, nil
 		case property.NameTimezoneIdentifier:
 			if len(l.Values) > 1 {
-				return nil, fmt.Errorf("")
+				return nil, NewInvalidValueLengthError(1, len(l.Values))
 			}
 			t := types.NewText(l.Values[0])
 			if err := timezone.SetTimezoneID(params, t); err != nil {
@@ -50,7 +54,7 @@ func (p *Parser) parseTimezone() (*ical.Timezone, error) {
 			}
 		case property.NameTimezoneURL:
 			if len(l.Values) > 1 {
-				return nil, fmt.Errorf("")
+				return nil, NewInvalidValueLengthError(1, len(l.Values))
 			}
 			t, err := types.NewURI(l.Values[0])
 			if err != nil {
@@ -61,7 +65,7 @@ func (p *Parser) parseTimezone() (*ical.Timezone, error) {
 			}
 		case property.NameBegin:
 			if len(l.Values) != 1 {
-				return nil, fmt.Errorf("")
+				return nil, NewInvalidValueLengthError(1, len(l.Values))
 			}
 			switch cname := component.Type(l.Values[0]); cname {
 			case component.TypeStandard:
@@ -87,8 +91,13 @@ func (p *Parser) parseTimezone() (*ical.Timezone, error) {
 					return nil, fmt.Errorf("value : %w", err)
 				}
 				timezone.XProperties = append(timezone.XProperties, ns)
+				break
+			}
+			if p.roundTrip {
+				timezone.IANAProperties = append(timezone.IANAProperties, property.NewIANA(l.Name, params, l.Values))
 			}
 		}
+		p.recordProperty(&timezone.PropertyOrder, counts, l)
 		p.nextLine()
 	}
 	return nil, NoEndError(component.TypeTimezone)
@@ -98,6 +107,7 @@ func (p *Parser) parseStandard() (*ical.Standard, error) {
 	p.nextLine() // skip BEGIN line
 	p.currentComponentType = component.TypeStandard
 	standard := ical.NewStandard()
+	counts := map[property.Name]int{}
 
 	for l := p.getCurrentLine(); l != nil; l = p.getCurrentLine() {
 		params, err := p.parseParameter(l)
@@ -107,8 +117,9 @@ func (p *Parser) parseStandard() (*ical.Standard, error) {
 		switch pname := property.Name(l.Name); pname {
 		case property.NameEnd:
 			if !p.isEndComponent(component.TypeStandard) {
-				return nil, fmt.Errorf("Invalid END")
+				return nil, errcode.Errorf(errcode.Invalid, "Invalid END")
 			}
+			p.checkOccurrences(standard, counts, component.TypeCalendar, component.TypeTimezone, component.TypeStandard)
 			return standard, nil
 		case property.NameDateTimeStart:
 			if len(l.Values) != 1 {
@@ -145,12 +156,9 @@ func (p *Parser) parseStandard() (*ical.Standard, error) {
 				return nil, NewParseError(component.TypeStandard, pname, err)
 			}
 		case property.NameRecurrenceRule:
-			if len(l.Values) != 1 {
-				return nil, NewInvalidValueLengthError(1, len(l.Values))
-			}
-			rr, err := types.NewRecurrenceRule(l.Values[0])
+			rr, err := recurrenceRule(l)
 			if err != nil {
-				return nil, fmt.Errorf("parse recurrence rule: %w", err)
+				return nil, err
 			}
 			if err := standard.SetRecurrenceRule(params, rr); err != nil {
 				return nil, NewParseError(component.TypeStandard, pname, err)
@@ -159,14 +167,14 @@ func (p *Parser) parseStandard() (*ical.Standard, error) {
 			if len(l.Values) != 1 {
 				return nil, NewInvalidValueLengthError(1, len(l.Values))
 			}
-			t := types.NewText(l.Values[0])
+			t := types.UnescapeText(l.Values[0])
 			if err := standard.SetComment(params, t); err != nil {
 				return nil, NewParseError(component.TypeStandard, pname, err)
 			}
 		case property.NameRecurrenceDateTimes:
 			var ts []types.RecurrenceDateTimeValue
 			for _, v := range l.Values {
-				t, err := property.NewRecurrenceDateTime(params, l.Values[0])
+				t, err := property.NewRecurrenceDateTime(params, v)
 				if err != nil {
 					return nil, fmt.Errorf("parse %s to DATE-TIME: %w", v, err)
 				}
@@ -179,7 +187,7 @@ func (p *Parser) parseStandard() (*ical.Standard, error) {
 			if len(l.Values) != 1 {
 				return nil, NewInvalidValueLengthError(1, len(l.Values))
 			}
-			t := types.NewText(l.Values[0])
+			t := types.UnescapeText(l.Values[0])
 			if err := standard.SetTimezoneName(params, t); err != nil {
 				return nil, NewParseError(component.TypeStandard, pname, err)
 			}
@@ -190,8 +198,13 @@ func (p *Parser) parseStandard() (*ical.Standard, error) {
 					return nil, fmt.Errorf("value : %w", err)
 				}
 				standard.XProperties = append(standard.XProperties, ns)
+				break
+			}
+			if p.roundTrip {
+				standard.IANAProperties = append(standard.IANAProperties, property.NewIANA(l.Name, params, l.Values))
 			}
 		}
+		p.recordProperty(&standard.PropertyOrder, counts, l)
 		p.nextLine()
 	}
 	return nil, NoEndError(component.TypeStandard)
@@ -200,6 +213,7 @@ func (p *Parser) parseDaylight() (*ical.Daylight, error) {
 	p.nextLine() // skip BEGIN line
 	p.currentComponentType = component.TypeDaylight
 	daylight := ical.NewDaylight()
+	counts := map[property.Name]int{}
 
 	for l := p.getCurrentLine(); l != nil; l = p.getCurrentLine() {
 		params, err := p.parseParameter(l)
@@ -209,8 +223,9 @@ func (p *Parser) parseDaylight() (*ical.Daylight, error) {
 		switch pname := property.Name(l.Name); pname {
 		case property.NameEnd:
 			if !p.isEndComponent(component.TypeDaylight) {
-				return nil, fmt.Errorf("finished without END:%s", component.TypeDaylight)
+				return nil, errcode.Errorf(errcode.Invalid, "finished without END:%s", component.TypeDaylight)
 			}
+			p.checkOccurrences(daylight, counts, component.TypeCalendar, component.TypeTimezone, component.TypeDaylight)
 			return daylight, nil
 		case property.NameDateTimeStart:
 			if len(l.Values) != 1 {
@@ -247,12 +262,9 @@ func (p *Parser) parseDaylight() (*ical.Daylight, error) {
 				return nil, NewParseError(component.TypeStandard, pname, err)
 			}
 		case property.NameRecurrenceRule:
-			if len(l.Values) != 1 {
-				return nil, NewInvalidValueLengthError(1, len(l.Values))
-			}
-			rr, err := types.NewRecurrenceRule(l.Values[0])
+			rr, err := recurrenceRule(l)
 			if err != nil {
-				return nil, fmt.Errorf("parse recurrence rule: %w", err)
+				return nil, err
 			}
 			if err := daylight.SetRecurrenceRule(params, rr); err != nil {
 				return nil, NewParseError(component.TypeStandard, pname, err)
@@ -261,14 +273,14 @@ func (p *Parser) parseDaylight() (*ical.Daylight, error) {
 			if len(l.Values) != 1 {
 				return nil, NewInvalidValueLengthError(1, len(l.Values))
 			}
-			t := types.NewText(l.Values[0])
+			t := types.UnescapeText(l.Values[0])
 			if err := daylight.SetComment(params, t); err != nil {
 				return nil, NewParseError(component.TypeStandard, pname, err)
 			}
 		case property.NameRecurrenceDateTimes:
 			var ts []types.RecurrenceDateTimeValue
 			for _, v := range l.Values {
-				t, err := property.NewRecurrenceDateTime(params, l.Values[0])
+				t, err := property.NewRecurrenceDateTime(params, v)
 				if err != nil {
 					return nil, fmt.Errorf("parse %s to DATE-TIME: %w", v, err)
 				}
@@ -281,7 +293,7 @@ func (p *Parser) parseDaylight() (*ical.Daylight, error) {
 			if len(l.Values) != 1 {
 				return nil, NewInvalidValueLengthError(1, len(l.Values))
 			}
-			t := types.NewText(l.Values[0])
+			t := types.UnescapeText(l.Values[0])
 			if err := daylight.SetTimezoneName(params, t); err != nil {
 				return nil, NewParseError(component.TypeStandard, pname, err)
 			}
@@ -292,9 +304,56 @@ func (p *Parser) parseDaylight() (*ical.Daylight, error) {
 					return nil, fmt.Errorf("value : %w", err)
 				}
 				daylight.XProperties = append(daylight.XProperties, ns)
+				break
+			}
+			if p.roundTrip {
+				daylight.IANAProperties = append(daylight.IANAProperties, property.NewIANA(l.Name, params, l.Values))
 			}
 		}
+		p.recordProperty(&daylight.PropertyOrder, counts, l)
 		p.nextLine()
 	}
 	return nil, NoEndError(component.TypeDaylight)
 }
+
+// addTimezone registers location of tz to resolve TZID.
+// tz which can not be converted to location is ignored, and its TZID is resolved by IANA time zone database.
+func (p *Parser) addTimezone(tz *ical.Timezone) {
+	loc, err := tz.Location()
+	if err != nil {
+		return
+	}
+	p.timezones[string(tz.TimezoneIdentifier.Value)] = loc
+}
+
+// location returns location of TZID in params which is defined by VTIMEZONE.
+// it returns nil if TZID is not defined.
+func (p *Parser) location(params parameter.Container) *time.Location {
+	tz := params.GetTimezone()
+	if tz == "" {
+		return nil
+	}
+	return p.timezones[tz]
+}
+
+// scanTimezones registers VTIMEZONEs in Lines before parsing components which refer them
+func (p *Parser) scanTimezones() {
+	for i := 0; i < len(p.Lines); i++ {
+		l := p.Lines[i]
+		if property.Name(l.Name) != property.NameBegin || len(l.Values) != 1 || component.Type(l.Values[0]) != component.TypeTimezone {
+			continue
+		}
+		for j := i + 1; j < len(p.Lines); j++ {
+			e := p.Lines[j]
+			if property.Name(e.Name) != property.NameEnd || len(e.Values) != 1 || component.Type(e.Values[0]) != component.TypeTimezone {
+				continue
+			}
+			sub := &Parser{Lines: p.Lines[i : j+1], roundTrip: p.roundTrip, timezones: p.timezones}
+			if tz, err := sub.parseTimezone(); err == nil {
+				p.addTimezone(tz)
+			}
+			i = j
+			break
+		}
+	}
+}
@@ -1,7 +1,9 @@
 package parser
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/knsh14/ical"
@@ -91,3 +93,138 @@ func TestParseStandard(t *testing.T) {
 		})
 	}
 }
+
+func TestParse_TimezoneReference(t *testing.T) {
+	t.Parallel()
+	calendar := func(components ...[]string) string {
+		lines := []string{"BEGIN:VCALENDAR", "VERSION:2.0", "PRODID:-//ABC Corporation//NONSGML My Product//EN"}
+		for _, c := range components {
+			lines = append(lines, c...)
+		}
+		lines = append(lines, "END:VCALENDAR")
+		return strings.Join(lines, "\r\n")
+	}
+	event := func(uid, dtstart string) []string {
+		return []string{
+			"BEGIN:VEVENT",
+			"UID:" + uid,
+			"DTSTAMP:20200101T000000Z",
+			"DTSTART;" + dtstart,
+			"END:VEVENT",
+		}
+	}
+	tokyo := []string{
+		"BEGIN:VTIMEZONE",
+		"TZID:Tokyo Standard Time",
+		"BEGIN:STANDARD",
+		"DTSTART:16010101T000000",
+		"TZOFFSETFROM:+0900",
+		"TZOFFSETTO:+0900",
+		"END:STANDARD",
+		"END:VTIMEZONE",
+	}
+	eastern := []string{
+		"BEGIN:VTIMEZONE",
+		"TZID:Custom Eastern",
+		"BEGIN:STANDARD",
+		"DTSTART:20071104T020000",
+		"RRULE:FREQ=YEARLY;BYMONTH=11;BYDAY=1SU",
+		"TZOFFSETFROM:-0400",
+		"TZOFFSETTO:-0500",
+		"TZNAME:EST",
+		"END:STANDARD",
+		"BEGIN:DAYLIGHT",
+		"DTSTART:20070311T020000",
+		"RRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=2SU",
+		"TZOFFSETFROM:-0500",
+		"TZOFFSETTO:-0400",
+		"TZNAME:EDT",
+		"END:DAYLIGHT",
+		"END:VTIMEZONE",
+	}
+	// minutely has observance which repeats every minute, it must not be expanded until the end
+	minutely := []string{
+		"BEGIN:VTIMEZONE",
+		"TZID:Minutely",
+		"BEGIN:STANDARD",
+		"DTSTART:16010101T000000",
+		"RRULE:FREQ=MINUTELY",
+		"TZOFFSETFROM:+0900",
+		"TZOFFSETTO:+0900",
+		"END:STANDARD",
+		"END:VTIMEZONE",
+	}
+	type start struct {
+		UTC  time.Time
+		Zone string
+	}
+
+	testcases := map[string]struct {
+		input    string
+		expected []start
+	}{
+		"fixed offset": {
+			input: calendar(tokyo, event("a@example.com", "TZID=Tokyo Standard Time:20200601T090000")),
+			expected: []start{
+				{UTC: time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC), Zone: "+0900"},
+			},
+		},
+		"daylight saving time defined after event": {
+			input: calendar(
+				event("a@example.com", "TZID=Custom Eastern:20200115T090000"),
+				event("b@example.com", "TZID=Custom Eastern:20200715T090000"),
+				event("c@example.com", "TZID=Custom Eastern:20201101T013000"),
+				eastern,
+			),
+			expected: []start{
+				{UTC: time.Date(2020, 1, 15, 14, 0, 0, 0, time.UTC), Zone: "EST"},
+				{UTC: time.Date(2020, 7, 15, 13, 0, 0, 0, time.UTC), Zone: "EDT"},
+				{UTC: time.Date(2020, 11, 1, 5, 30, 0, 0, time.UTC), Zone: "EDT"},
+			},
+		},
+		"events before and after timezone": {
+			input: calendar(
+				event("a@example.com", "TZID=Custom Eastern:20200115T090000"),
+				eastern,
+				event("b@example.com", "TZID=Custom Eastern:20200715T090000"),
+				event("c@example.com", "TZID=Tokyo Standard Time:20200601T090000"),
+				tokyo,
+			),
+			expected: []start{
+				{UTC: time.Date(2020, 1, 15, 14, 0, 0, 0, time.UTC), Zone: "EST"},
+				{UTC: time.Date(2020, 7, 15, 13, 0, 0, 0, time.UTC), Zone: "EDT"},
+				{UTC: time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC), Zone: "+0900"},
+			},
+		},
+		"observance repeats every minute": {
+			input: calendar(minutely, event("a@example.com", "VALUE=DATE-TIME:20200601T000000Z")),
+			expected: []start{
+				{UTC: time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC), Zone: "UTC"},
+			},
+		},
+	}
+
+	for title, tc := range testcases {
+		tc := tc
+		t.Run(title, func(t *testing.T) {
+			t.Parallel()
+			cal, err := Parse(strings.NewReader(tc.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []start
+			for _, c := range cal.Components {
+				e, ok := c.(*ical.Event)
+				if !ok {
+					continue
+				}
+				dt := time.Time(e.DateTimeStart.Value.(types.DateTime))
+				zone, _ := dt.Zone()
+				got = append(got, start{UTC: dt.UTC(), Zone: zone})
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
@@ -5,15 +5,17 @@ import (
 
 	"github.com/knsh14/ical"
 	"github.com/knsh14/ical/component"
+	"github.com/knsh14/ical/errcode"
 	"github.com/knsh14/ical/property"
 	"github.com/knsh14/ical/token"
 	"github.com/knsh14/ical/types"
 )
 
 func (p *Parser) parseTodo() (*ical.ToDo, error) {
-	p.nextLine() // skip BEGIN:VEVENT line
+	p.nextLine() // skip BEGIN:VTODO line
 	p.currentComponentType = component.TypeTODO
 	todo := ical.NewToDo()
+	counts := map[property.Name]int{}
 
 	for l := p.getCurrentLine(); l != nil; l = p.getCurrentLine() {
 		params, err := p.parseParameter(l)
@@ -23,15 +25,16 @@ func (p *Parser) parseTodo() (*ical.ToDo, error) {
 
 		switch pname := property.Name(l.Name); pname {
 		case property.NameEnd:
-			if !p.isEndComponent(component.TypeEvent) {
-				return nil, fmt.Errorf("Invalid END")
+			if !p.isEndComponent(component.TypeTODO) {
+				return nil, errcode.Errorf(errcode.Invalid, "Invalid END")
 			}
+			p.checkOccurrences(todo, counts, component.TypeCalendar, component.TypeTODO)
 			return todo, nil
 		case property.NameUID:
 			if len(l.Values) != 1 {
 				return nil, NewInvalidValueLengthError(1, len(l.Values))
 			}
-			t := types.NewText(l.Values[0])
+			t := types.UnescapeText(l.Values[0])
 			if err := todo.SetUID(params, t); err != nil {
 				return nil, NewParseError(component.TypeTODO, pname, err)
 			}
@@ -83,7 +86,7 @@ func (p *Parser) parseTodo() (*ical.ToDo, error) {
 			if len(l.Values) != 1 {
 				return nil, NewInvalidValueLengthError(1, len(l.Values))
 			}
-			t := types.NewText(l.Values[0])
+			t := types.UnescapeText(l.Values[0])
 			if err := todo.SetDescription(params, t); err != nil {
 				return nil, NewParseError(component.TypeTODO, pname, err)
 			}
@@ -91,7 +94,7 @@ func (p *Parser) parseTodo() (*ical.ToDo, error) {
 			if len(l.Values) != 1 {
 				return nil, NewInvalidValueLengthError(1, len(l.Values))
 			}
-			t, err := ical.NewTimeType(params, l.Values[0])
+			t, err := ical.NewTimeTypeInLocation(params, l.Values[0], p.location(params))
 			if err != nil {
 				return nil, fmt.Errorf("convert date time for %s: %w", pname, err)
 			}
@@ -122,7 +125,7 @@ func (p *Parser) parseTodo() (*ical.ToDo, error) {
 			if len(l.Values) > 1 {
 				return nil, NewInvalidValueLengthError(1, len(l.Values))
 			}
-			t := types.NewText(l.Values[0])
+			t := types.UnescapeText(l.Values[0])
 			if err := todo.SetLocation(params, t); err != nil {
 				return nil, NewParseError(component.TypeTODO, pname, err)
 			}
@@ -163,7 +166,7 @@ func (p *Parser) parseTodo() (*ical.ToDo, error) {
 			if len(l.Values) > 1 {
 				return nil, NewInvalidValueLengthError(1, len(l.Values))
 			}
-			t, err := ical.NewTimeType(params, l.Values[0])
+			t, err := ical.NewTimeTypeInLocation(params, l.Values[0], p.location(params))
 			if err != nil {
 				return nil, fmt.Errorf("convert %s into TimeType: %w", l.Values[0], err)
 			}
@@ -193,7 +196,7 @@ func (p *Parser) parseTodo() (*ical.ToDo, error) {
 			if len(l.Values) > 1 {
 				return nil, NewInvalidValueLengthError(1, len(l.Values))
 			}
-			t := types.NewText(l.Values[0])
+			t := types.UnescapeText(l.Values[0])
 			if err := todo.SetSummary(params, t); err != nil {
 				return nil, NewParseError(component.TypeTODO, pname, err)
 			}
@@ -208,11 +211,19 @@ func (p *Parser) parseTodo() (*ical.ToDo, error) {
 			if err := todo.SetURL(params, t); err != nil {
 				return nil, NewParseError(component.TypeTODO, pname, err)
 			}
+		case property.NameRecurrenceRule:
+			rr, err := recurrenceRule(l)
+			if err != nil {
+				return nil, err
+			}
+			if err := todo.SetRecurrenceRule(params, rr); err != nil {
+				return nil, NewParseError(component.TypeTODO, pname, err)
+			}
 		case property.NameDateTimeDue:
 			if len(l.Values) != 1 {
 				return nil, NewInvalidValueLengthError(1, len(l.Values))
 			}
-			t, err := ical.NewTimeType(params, l.Values[0])
+			t, err := ical.NewTimeTypeInLocation(params, l.Values[0], p.location(params))
 			if err != nil {
 				return nil, fmt.Errorf("convert date time for %s: %w", pname, err)
 			}
@@ -255,7 +266,7 @@ func (p *Parser) parseTodo() (*ical.ToDo, error) {
 		case property.NameCategories:
 			var ts []types.Text
 			for _, v := range l.Values {
-				ts = append(ts, types.NewText(v))
+				ts = append(ts, types.UnescapeText(v))
 			}
 			if err := todo.AddCategories(params, ts); err != nil {
 				return nil, NewParseError(component.TypeTODO, pname, err)
@@ -264,7 +275,7 @@ func (p *Parser) parseTodo() (*ical.ToDo, error) {
 			if len(l.Values) > 1 {
 				return nil, NewInvalidValueLengthError(1, len(l.Values))
 			}
-			t := types.NewText(l.Values[0])
+			t := types.UnescapeText(l.Values[0])
 			if err := todo.AddComment(params, t); err != nil {
 				return nil, NewParseError(component.TypeTODO, pname, err)
 			}
@@ -272,14 +283,14 @@ func (p *Parser) parseTodo() (*ical.ToDo, error) {
 			if len(l.Values) > 1 {
 				return nil, NewInvalidValueLengthError(1, len(l.Values))
 			}
-			t := types.NewText(l.Values[0])
+			t := types.UnescapeText(l.Values[0])
 			if err := todo.AddContact(params, t); err != nil {
 				return nil, NewParseError(component.TypeTODO, pname, err)
 			}
 		case property.NameExceptionDateTimes:
 			var ts []types.TimeValue
 			for _, v := range l.Values {
-				t, err := ical.NewTimeType(params, v)
+				t, err := ical.NewTimeTypeInLocation(params, v, p.location(params))
 				if err != nil {
 					return nil, fmt.Errorf("convert %s into TimeType in %s: %w", v, pname, err)
 				}
@@ -296,10 +307,18 @@ func (p *Parser) parseTodo() (*ical.ToDo, error) {
 			if err := todo.AddRequestStatus(params, t); err != nil {
 				return nil, NewParseError(component.TypeTODO, pname, err)
 			}
+		case property.NameRelatedTo:
+			if len(l.Values) > 1 {
+				return nil, NewInvalidValueLengthError(1, len(l.Values))
+			}
+			t := types.UnescapeText(l.Values[0])
+			if err := todo.AddRelatedTo(params, t); err != nil {
+				return nil, NewParseError(component.TypeTODO, pname, err)
+			}
 		case property.NameResources:
 			var ts []types.Text
 			for _, v := range l.Values {
-				ts = append(ts, types.NewText(v))
+				ts = append(ts, types.UnescapeText(v))
 			}
 			if err := todo.AddResources(params, ts); err != nil {
 				return nil, NewParseError(component.TypeTODO, pname, err)
@@ -307,7 +326,7 @@ func (p *Parser) parseTodo() (*ical.ToDo, error) {
 		case property.NameRecurrenceDateTimes:
 			var rdts []types.RecurrenceDateTimeValue
 			for _, v := range l.Values {
-				rdt, err := property.NewRecurrenceDateTime(params, v)
+				rdt, err := property.NewRecurrenceDateTimeInLocation(params, v, p.location(params))
 				if err != nil {
 					return nil, fmt.Errorf("convert %s to RecurrenceDateTime: %w", v, err)
 				}
@@ -318,11 +337,11 @@ func (p *Parser) parseTodo() (*ical.ToDo, error) {
 			}
 		case property.NameBegin:
 			if !p.isBeginComponent(component.TypeAlarm) {
-				return nil, fmt.Errorf("allow only BEGIN:VALARM, but %v", l)
+				return nil, errcode.Errorf(errcode.UnsupportedComponent, "allow only BEGIN:VALARM, but %v", l)
 			}
 			a, err := p.parseAlarm()
 			if err != nil {
-				return nil, NewParseError(component.TypeEvent, pname, err)
+				return nil, NewParseError(component.TypeTODO, pname, err)
 			}
 			todo.AddAlarm(a)
 			p.currentComponentType = component.TypeTODO
@@ -333,9 +352,14 @@ func (p *Parser) parseTodo() (*ical.ToDo, error) {
 					return nil, fmt.Errorf("value : %w", err)
 				}
 				todo.XProperties = append(todo.XProperties, ns)
+				break
+			}
+			if p.roundTrip {
+				todo.IANAProperties = append(todo.IANAProperties, property.NewIANA(l.Name, params, l.Values))
 			}
 		}
+		p.recordProperty(&todo.PropertyOrder, counts, l)
 		p.nextLine()
 	}
-	return nil, NoEndError(component.TypeEvent)
+	return nil, NoEndError(component.TypeTODO)
 }
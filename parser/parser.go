@@ -1,90 +1,140 @@
 package parser
 
 import (
-	"bufio"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/knsh14/ical"
 	"github.com/knsh14/ical/component"
 	"github.com/knsh14/ical/contentline"
-	"github.com/knsh14/ical/lexer"
+	"github.com/knsh14/ical/errcode"
 	"github.com/knsh14/ical/property"
-	"golang.org/x/sync/errgroup"
+	"github.com/knsh14/ical/types"
 )
 
-func Parse(r io.Reader) (*ical.Calendar, error) {
-	return parseFromScanner(bufio.NewScanner(r))
+// Option configures Parser
+type Option func(*Parser)
+
+// WithRoundTrip makes Parser keep data to write the stream back as it was.
+// order of properties, unknown parameters and unknown properties are kept.
+func WithRoundTrip() Option {
+	return func(p *Parser) {
+		p.roundTrip = true
+	}
 }
 
-func ParseFile(path string) (*ical.Calendar, error) {
-	f, err := os.Open(path)
-	if err != nil {
-		return nil, err
+// WithTolerance makes Parser continue parsing after errors.
+// a content line which causes error is skipped with its nested component,
+// and all errors are returned as LineErrors with the rest of the calendar.
+func WithTolerance() Option {
+	return func(p *Parser) {
+		p.tolerant = true
 	}
-	scanner := bufio.NewScanner(f)
-	return parseFromScanner(scanner)
 }
 
-func parseFromScanner(scanner *bufio.Scanner) (*ical.Calendar, error) {
-	lines, err := scanLines(scanner)
-	if err != nil {
-		return nil, err
+// WithFindings makes Parser append findings of the stream to f, which are properties occurring more than once
+// though RFC 5545 allows at most one. the parsed components keep only one of them, so Check can not find them
+// unless PropertyOrder is kept by WithRoundTrip.
+func WithFindings(f *ical.Findings) Option {
+	return func(p *Parser) {
+		p.findings = f
 	}
-	var eg errgroup.Group
-	contentlines := make([]*contentline.ContentLine, len(lines))
-	for i := range lines {
-		i := i
-		eg.Go(func() error {
-			l := lexer.New(lines[i])
-			cl, err := contentline.ConvertContentLine(l)
-			if err != nil {
-				return fmt.Errorf("convert content line in line %d: %w", i, err)
+}
+
+// Parse reads whole iCalendar stream from r.
+// TZID is resolved by VTIMEZONEs in the stream wherever they are.
+// components which refer VTIMEZONEs defined after them are kept until the end of the stream to resolve TZID.
+// with WithTolerance, it returns the calendar and LineErrors if there are errors.
+func Parse(r io.Reader, opts ...Option) (*ical.Calendar, error) {
+	d := NewDecoder(r, opts...)
+	d.deferUnknownTimezones = true
+	var components []ical.CalenderComponent
+	// deferred is indexes of components deferred by d
+	var deferred []int
+	for {
+		c, err := d.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if c == nil {
+			deferred = append(deferred, len(components))
+		}
+		components = append(components, c)
+	}
+	if len(deferred) > 0 {
+		cs, err := d.parseDeferred()
+		if err != nil {
+			return nil, err
+		}
+		for i, c := range cs {
+			components[deferred[i]] = c
+		}
+		res := components[:0]
+		for _, c := range components {
+			if c != nil {
+				res = append(res, c)
 			}
-			contentlines[i] = cl
-			return nil
-		})
+		}
+		components = res
+		sort.SliceStable(d.parser.errors, func(i, j int) bool { return d.parser.errors[i].Line < d.parser.errors[j].Line })
 	}
-	if err := eg.Wait(); err != nil {
-		return nil, err
+	cal := d.Calendar()
+	cal.Components = components
+	if errs := d.Errors(); len(errs) > 0 {
+		return cal, errs
 	}
-	p := NewParser(contentlines)
-	return p.parse()
+	return cal, nil
 }
 
-func scanLines(scanner *bufio.Scanner) ([]string, error) {
-	var res []string
-	for scanner.Scan() {
-		l := scanner.Text()
-		switch {
-		case strings.HasPrefix(l, " "):
-			res[len(res)-1] += "\n" + strings.TrimPrefix(l, " ")
-		case strings.HasPrefix(l, "\t"):
-			res[len(res)-1] += "\n" + strings.TrimPrefix(l, "\t")
-			continue
-		default:
-			res = append(res, l)
-		}
-	}
-	if err := scanner.Err(); err != nil {
+// ParseFile reads iCalendar file at path
+func ParseFile(path string, opts ...Option) (*ical.Calendar, error) {
+	f, err := os.Open(path)
+	if err != nil {
 		return nil, err
 	}
-	return res, nil
+	defer f.Close()
+	return Parse(f, opts...)
 }
 
-func NewParser(cls []*contentline.ContentLine) *Parser {
-	return &Parser{
-		Lines: cls,
+func NewParser(cls []*contentline.ContentLine, opts ...Option) *Parser {
+	p := &Parser{
+		Lines:     cls,
+		timezones: map[string]*time.Location{},
+	}
+	for _, opt := range opts {
+		opt(p)
 	}
+	return p
 }
 
 type Parser struct {
 	Lines                []*contentline.ContentLine
 	CurrentIndex         int
 	currentComponentType component.Type
-	// errors               []error
+	roundTrip            bool
+	tolerant             bool
+	// timezones is locations of VTIMEZONEs, key is TZID
+	timezones map[string]*time.Location
+	// lineNumbers is physical line numbers of Lines
+	lineNumbers []int
+	// alarmLine is index of line in VALARM being parsed
+	alarmLine int
+	// errors is errors found in tolerant mode
+	errors LineErrors
+	// findings is destination of findings given by WithFindings
+	findings *ical.Findings
+	// found is findings of components parsed so far, they are appended to findings when the component is parsed
+	found ical.Findings
+	// calendarCounts is number of occurrences of calendar properties
+	calendarCounts map[property.Name]int
 }
 
 func (p *Parser) getCurrentLine() *contentline.ContentLine {
@@ -103,6 +153,7 @@ func (p *Parser) Parse() (*ical.Calendar, error) {
 	if err != nil {
 		return nil, err
 	}
+	p.flushFindings()
 	return c, nil
 }
 
@@ -115,19 +166,49 @@ func (p *Parser) parse() (*ical.Calendar, error) {
 		}
 		switch ct := component.Type(l.Values[0]); ct {
 		case component.TypeCalendar:
+			p.scanTimezones()
 			c, err := p.parseCalender()
 			if err != nil {
 				return nil, fmt.Errorf("parse %s: %w", ct, err)
 			}
 			return c, nil
 		default:
-			return nil, fmt.Errorf("not %s:%s, got %v", property.NameBegin, component.TypeCalendar, l)
+			return nil, errcode.Errorf(errcode.Invalid, "not %s:%s, got %v", property.NameBegin, component.TypeCalendar, l)
 		}
 	default:
-		return nil, fmt.Errorf("not %s:%s, got %v", property.NameBegin, component.TypeCalendar, l)
+		return nil, errcode.Errorf(errcode.Invalid, "not %s:%s, got %v", property.NameBegin, component.TypeCalendar, l)
 	}
 }
 
+// recordProperty appends name of l to order in round trip mode, and counts it in counts of the component
+func (p *Parser) recordProperty(order *[]property.Name, counts map[property.Name]int, l *contentline.ContentLine) {
+	pname := property.Name(strings.ToUpper(l.Name))
+	switch pname {
+	case property.NameBegin, property.NameEnd:
+		return
+	}
+	if p.roundTrip {
+		*order = append(*order, pname)
+	}
+	counts[pname]++
+}
+
+// checkOccurrences records findings of properties which occur more than once in c though it allows at most one.
+// path is types of components from VCALENDAR to c.
+func (p *Parser) checkOccurrences(c interface{}, counts map[property.Name]int, path ...component.Type) {
+	if p.findings != nil {
+		p.found = append(p.found, ical.CheckOccurrences(path, c, counts)...)
+	}
+}
+
+// flushFindings appends findings recorded so far to findings given by WithFindings
+func (p *Parser) flushFindings() {
+	if p.findings != nil {
+		*p.findings = append(*p.findings, p.found...)
+	}
+	p.found = nil
+}
+
 func (p *Parser) isBeginComponent(c component.Type) bool {
 	if property.Name(p.getCurrentLine().Name) != property.NameBegin {
 		return false
@@ -147,3 +228,14 @@ func (p *Parser) isEndComponent(c component.Type) bool {
 	}
 	return component.Type(p.getCurrentLine().Values[0]) == c
 }
+
+// recurrenceRule returns RRULE of l.
+// values of rule parts such as BYDAY are separated by COMMA, so they are split into values of l.
+func recurrenceRule(l *contentline.ContentLine) (types.RecurrenceRule, error) {
+	v := strings.Join(l.Values, ",")
+	rr, err := types.NewRecurrenceRule(v)
+	if err != nil {
+		return types.RecurrenceRule{}, fmt.Errorf("convert %s into RecurrenceRule: %w", v, err)
+	}
+	return rr, nil
+}
@@ -0,0 +1,58 @@
+package parser
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParse_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	files, err := filepath.Glob(filepath.Join("testdata", "roundtrip", "*.ics"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("no test data")
+	}
+	for _, file := range files {
+		file := file
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			t.Parallel()
+			expected, err := ioutil.ReadFile(strings.TrimSuffix(file, ".ics") + ".golden")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			c, err := ParseFile(file, WithRoundTrip())
+			if err != nil {
+				t.Fatal(err)
+			}
+			var buf bytes.Buffer
+			if err := c.Decode(&buf); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(string(expected), buf.String()); diff != "" {
+				t.Errorf("diff: (-expected +got)\n%s", diff)
+			}
+
+			// output must be stable after parsing it again
+			c, err = Parse(bytes.NewReader(buf.Bytes()), WithRoundTrip())
+			if err != nil {
+				t.Fatal(err)
+			}
+			var again bytes.Buffer
+			if err := c.Decode(&again); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(buf.String(), again.String()); diff != "" {
+				t.Errorf("diff: (-first +second)\n%s", diff)
+			}
+		})
+	}
+}
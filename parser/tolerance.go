@@ -0,0 +1,127 @@
+package parser
+
+import (
+	"github.com/knsh14/ical"
+	"github.com/knsh14/ical/component"
+	"github.com/knsh14/ical/contentline"
+	"github.com/knsh14/ical/property"
+)
+
+// parseComponentTolerantly parses component in Lines.
+// a line which causes error is removed with its nested component, then the component is parsed again.
+// it returns nil component if BEGIN or END of the component itself causes error.
+func (p *Parser) parseComponentTolerantly() (ical.CalenderComponent, LineErrors) {
+	var errs LineErrors
+	for {
+		p.CurrentIndex = 0
+		p.currentComponentType = component.TypeCalendar
+		// findings of the previous attempt are found again
+		p.found = nil
+		c, err := p.parseComponent()
+		if err == nil {
+			return c, errs
+		}
+		i := p.CurrentIndex
+		errs = append(errs, p.lineError(i, err))
+		begin, end, ok := p.brokenRange(i)
+		if !ok {
+			return nil, errs
+		}
+		p.removeLines(begin, end)
+	}
+}
+
+// lineError returns LineError of err which is caused by Lines[i]
+func (p *Parser) lineError(i int, err error) *LineError {
+	le := &LineError{
+		Column: 1,
+		Path:   []component.Type{component.TypeCalendar},
+		Err:    err,
+	}
+	switch n := len(p.lineNumbers); {
+	case i < n:
+		le.Line = p.lineNumbers[i]
+	case n > 0:
+		le.Line = p.lineNumbers[n-1]
+	}
+	if i < len(p.Lines) {
+		le.Property = property.Name(p.Lines[i].Name)
+	}
+	for j := 0; j < i && j < len(p.Lines); j++ {
+		l := p.Lines[j]
+		if len(l.Values) != 1 {
+			continue
+		}
+		switch property.Name(l.Name) {
+		case property.NameBegin:
+			le.Path = append(le.Path, component.Type(l.Values[0]))
+		case property.NameEnd:
+			if n := len(le.Path); n > 1 {
+				le.Path = le.Path[:n-1]
+			}
+		}
+	}
+	return le
+}
+
+// brokenRange returns range of lines to remove for error caused by Lines[i].
+// nested component is removed entirely if its BEGIN or END causes error.
+// ok is false if the whole component is broken.
+func (p *Parser) brokenRange(i int) (begin, end int, ok bool) {
+	last := len(p.Lines) - 1
+	if i <= 0 || i >= last {
+		return 0, 0, false
+	}
+	l := p.Lines[i]
+	if len(l.Values) != 1 {
+		return i, i + 1, true
+	}
+	ct := l.Values[0]
+	switch property.Name(l.Name) {
+	case property.NameBegin:
+		depth := 0
+		for j := i; j < last; j++ {
+			depth += nesting(p.Lines[j], ct)
+			if depth == 0 {
+				return i, j + 1, true
+			}
+		}
+		return 0, 0, false
+	case property.NameEnd:
+		depth := 0
+		for j := i; j > 0; j-- {
+			depth += nesting(p.Lines[j], ct)
+			if depth == 0 {
+				return j, i + 1, true
+			}
+		}
+	}
+	return i, i + 1, true
+}
+
+// nesting returns 1 for BEGIN of ct, -1 for END of ct, otherwise 0
+func nesting(l *contentline.ContentLine, ct string) int {
+	if len(l.Values) != 1 || l.Values[0] != ct {
+		return 0
+	}
+	switch property.Name(l.Name) {
+	case property.NameBegin:
+		return 1
+	case property.NameEnd:
+		return -1
+	}
+	return 0
+}
+
+// removeLines removes Lines[begin:end] and their line numbers
+func (p *Parser) removeLines(begin, end int) {
+	lines := make([]*contentline.ContentLine, 0, len(p.Lines)-(end-begin))
+	lines = append(lines, p.Lines[:begin]...)
+	p.Lines = append(lines, p.Lines[end:]...)
+	if len(p.lineNumbers) < end {
+		return
+	}
+	numbers := make([]int, 0, len(p.lineNumbers)-(end-begin))
+	numbers = append(numbers, p.lineNumbers[:begin]...)
+	p.lineNumbers = append(numbers, p.lineNumbers[end:]...)
+}
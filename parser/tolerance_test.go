@@ -0,0 +1,182 @@
+package parser
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/knsh14/ical"
+	"github.com/knsh14/ical/component"
+	"github.com/knsh14/ical/property"
+)
+
+func TestParse_WithTolerance(t *testing.T) {
+	t.Parallel()
+
+	testcases := map[string]struct {
+		input          []string
+		expected       []component.Type
+		expectedErrors LineErrors
+	}{
+		"no error": {
+			input: []string{
+				"BEGIN:VCALENDAR",
+				"BEGIN:VEVENT",
+				"UID:1",
+				"DTSTAMP:20200101T000000Z",
+				"END:VEVENT",
+				"END:VCALENDAR",
+			},
+			expected: []component.Type{component.TypeEvent},
+		},
+		"invalid properties": {
+			input: []string{
+				"BEGIN:VCALENDAR",
+				"VERSION:2.0",
+				"FOO:bar",
+				"BEGIN:VEVENT",
+				"UID:1",
+				"DTSTAMP:2020",
+				"ATTENDEE;CUTYPE=X:mailto:a@example.com",
+				"DESCRIPTION:folded",
+				"  line",
+				"DTSTART:abc",
+				"END:VEVENT",
+				"END:VCALENDAR",
+			},
+			expected: []component.Type{component.TypeEvent},
+			expectedErrors: LineErrors{
+				{Line: 3, Column: 1, Path: []component.Type{component.TypeCalendar}, Property: "FOO"},
+				{Line: 6, Column: 1, Path: []component.Type{component.TypeCalendar, component.TypeEvent}, Property: property.NameDateTimeStamp},
+				{Line: 7, Column: 1, Path: []component.Type{component.TypeCalendar, component.TypeEvent}, Property: property.NameAttendee},
+				{Line: 10, Column: 1, Path: []component.Type{component.TypeCalendar, component.TypeEvent}, Property: property.NameDateTimeStart},
+			},
+		},
+		"invalid alarm property": {
+			input: []string{
+				"BEGIN:VCALENDAR",
+				"BEGIN:VEVENT",
+				"UID:1",
+				"DTSTAMP:20200101T000000Z",
+				"BEGIN:VALARM",
+				"ACTION:DISPLAY",
+				"TRIGGER:abc",
+				"DESCRIPTION:reminder",
+				"END:VALARM",
+				"END:VEVENT",
+				"END:VCALENDAR",
+			},
+			expected: []component.Type{component.TypeEvent},
+			expectedErrors: LineErrors{
+				{Line: 7, Column: 1, Path: []component.Type{component.TypeCalendar, component.TypeEvent, component.TypeAlarm}, Property: property.NameTrigger},
+			},
+		},
+		"invalid nested component": {
+			input: []string{
+				"BEGIN:VCALENDAR",
+				"BEGIN:VEVENT",
+				"UID:1",
+				"DTSTAMP:20200101T000000Z",
+				"BEGIN:VTODO",
+				"UID:2",
+				"END:VTODO",
+				"END:VEVENT",
+				"END:VCALENDAR",
+			},
+			expected: []component.Type{component.TypeEvent},
+			expectedErrors: LineErrors{
+				{Line: 5, Column: 1, Path: []component.Type{component.TypeCalendar, component.TypeEvent}, Property: property.NameBegin},
+			},
+		},
+		"unknown component": {
+			input: []string{
+				"BEGIN:VCALENDAR",
+				"BEGIN:VUNKNOWN",
+				"UID:1",
+				"END:VUNKNOWN",
+				"BEGIN:VTODO",
+				"UID:2",
+				"DTSTAMP:20200101T000000Z",
+				"END:VTODO",
+				"END:VCALENDAR",
+			},
+			expected: []component.Type{component.TypeTODO},
+			expectedErrors: LineErrors{
+				{Line: 2, Column: 1, Path: []component.Type{component.TypeCalendar}, Property: property.NameBegin},
+			},
+		},
+		"no end of calendar": {
+			input: []string{
+				"BEGIN:VCALENDAR",
+				"BEGIN:VEVENT",
+				"UID:1",
+				"DTSTAMP:20200101T000000Z",
+				"END:VEVENT",
+			},
+			expected: []component.Type{component.TypeEvent},
+			expectedErrors: LineErrors{
+				{Line: 5, Column: 1, Path: []component.Type{component.TypeCalendar}},
+			},
+		},
+	}
+
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			c, err := Parse(strings.NewReader(strings.Join(tc.input, "\r\n")), WithTolerance())
+			var errs LineErrors
+			if err != nil && !errors.As(err, &errs) {
+				t.Fatalf("expected LineErrors, but %v", err)
+			}
+			if diff := cmp.Diff(tc.expectedErrors, errs, cmpopts.IgnoreFields(LineError{}, "Err")); diff != "" {
+				t.Errorf("diff: (-expected +got)\n%s", diff)
+			}
+			for _, e := range errs {
+				if e.Err == nil {
+					t.Errorf("line %d: error is not recorded", e.Line)
+				}
+			}
+			if c == nil {
+				t.Fatal("calendar is nil")
+			}
+			var got []component.Type
+			for _, cc := range c.Components {
+				switch cc.(type) {
+				case *ical.Event:
+					got = append(got, component.TypeEvent)
+				case *ical.ToDo:
+					got = append(got, component.TypeTODO)
+				}
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("diff: (-expected +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParse_WithoutTolerance(t *testing.T) {
+	t.Parallel()
+	input := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"BEGIN:VEVENT",
+		"UID:1",
+		"DTSTAMP:2020",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+	c, err := Parse(strings.NewReader(input))
+	if err == nil {
+		t.Fatal("expected error but got nil")
+	}
+	var errs LineErrors
+	if errors.As(err, &errs) {
+		t.Errorf("expected first error only, but %v", errs)
+	}
+	if c != nil {
+		t.Errorf("expected nil calendar, but %v", c)
+	}
+}
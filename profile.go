@@ -0,0 +1,617 @@
+package ical
+
+import (
+	"sort"
+
+	"github.com/knsh14/ical/component"
+	"github.com/knsh14/ical/property"
+)
+
+// Profile is restrictions of an iTIP method on calendar components.
+// Check and Validate verify them in addition to rules of RFC 5545.
+// https://tools.ietf.org/html/rfc5546#section-3
+type Profile property.MethodType
+
+const (
+	ProfilePublish        = Profile(property.MethodTypePublish)
+	ProfileRequest        = Profile(property.MethodTypeRequest)
+	ProfileReply          = Profile(property.MethodTypeReply)
+	ProfileAdd            = Profile(property.MethodTypeAdd)
+	ProfileCancel         = Profile(property.MethodTypeCancel)
+	ProfileRefresh        = Profile(property.MethodTypeRefresh)
+	ProfileCounter        = Profile(property.MethodTypeCounter)
+	ProfileDeclineCounter = Profile(property.MethodTypeDeclinecounter)
+
+	// ProfilePublishLenient is ProfilePublish which reports ATTENDEE as warning instead of error,
+	// since published calendars often have it. METHOD of the calendar must be PUBLISH.
+	ProfilePublishLenient Profile = "PUBLISH-LENIENT"
+)
+
+// method returns profile of METHOD which p restricts
+func (p Profile) method() Profile {
+	if p == ProfilePublishLenient {
+		return ProfilePublish
+	}
+	return p
+}
+
+const (
+	// RuleProfileMethod is METHOD which is different from the profile
+	// https://tools.ietf.org/html/rfc5546#section-3
+	RuleProfileMethod Rule = "profile-method"
+	// RuleProfileComponent is component which the profile does not allow,
+	// or calendar which has none or several types of VEVENT, VTODO, VJOURNAL and VFREEBUSY
+	// https://tools.ietf.org/html/rfc5546#section-3
+	RuleProfileComponent Rule = "profile-component"
+	// RuleProfileRequired is property which must occur in the profile
+	RuleProfileRequired Rule = "profile-required"
+	// RuleProfileCardinality is property which occurs more than the profile allows
+	RuleProfileCardinality Rule = "profile-cardinality"
+	// RuleProfileNotAllowed is property which must not occur in the profile.
+	// ATTENDEE is reported as warning in ProfilePublishLenient.
+	RuleProfileNotAllowed Rule = "profile-not-allowed"
+	// RuleProfileUID is components of a message whose UIDs are different
+	// https://tools.ietf.org/html/rfc5546#section-2.1.5
+	RuleProfileUID Rule = "profile-uid"
+	// RuleProfileRSVP is ATTENDEE of PUBLISH which requests reply with RSVP=TRUE
+	// https://tools.ietf.org/html/rfc5546#section-3.2.1
+	RuleProfileRSVP Rule = "profile-rsvp"
+)
+
+// presence is how many times a property may occur in a profile
+type presence int
+
+const (
+	// presenceNever is property which must not occur, properties which a profile does not have are so
+	presenceNever presence = iota
+	// presenceDiscouraged is property which must not occur, but it is reported as warning in lenient profiles
+	presenceDiscouraged
+	// presenceOptional is "0 or 1"
+	presenceOptional
+	// presenceOne is "1"
+	presenceOne
+	// presenceOneOrMore is "1+"
+	presenceOneOrMore
+	// presenceAny is "0+"
+	presenceAny
+)
+
+// componentProfile is restriction table of a component in a profile.
+// X-PROPERTY and IANA-PROPERTY are allowed in every profile.
+type componentProfile struct {
+	properties map[property.Name]presence
+	// alarms is true if VALARM is allowed
+	alarms bool
+}
+
+// newComponentProfile returns componentProfile which has properties of base replaced by overrides
+func newComponentProfile(alarms bool, base, overrides map[property.Name]presence) componentProfile {
+	cp := componentProfile{properties: map[property.Name]presence{}, alarms: alarms}
+	for n, p := range base {
+		cp.properties[n] = p
+	}
+	for n, p := range overrides {
+		if p == presenceNever {
+			delete(cp.properties, n)
+			continue
+		}
+		cp.properties[n] = p
+	}
+	return cp
+}
+
+var (
+	// eventProperties is properties of VEVENT which most of methods allow
+	eventProperties = map[property.Name]presence{
+		property.NameUID:                 presenceOne,
+		property.NameDateTimeStamp:       presenceOne,
+		property.NameOrganizer:           presenceOne,
+		property.NameAttachment:          presenceAny,
+		property.NameCategories:          presenceAny,
+		property.NameClass:               presenceOptional,
+		property.NameComment:             presenceAny,
+		property.NameContact:             presenceAny,
+		property.NameDateTimeCreated:     presenceOptional,
+		property.NameDescription:         presenceOptional,
+		property.NameDateTimeStart:       presenceOptional,
+		property.NameDateTimeEnd:         presenceOptional,
+		property.NameDuration:            presenceOptional,
+		property.NameExceptionDateTimes:  presenceAny,
+		property.NameGeo:                 presenceOptional,
+		property.NameLastModified:        presenceOptional,
+		property.NameLocation:            presenceOptional,
+		property.NamePriority:            presenceOptional,
+		property.NameRecurrenceDateTimes: presenceAny,
+		property.NameRecurrenceID:        presenceOptional,
+		property.NameRelatedTo:           presenceAny,
+		property.NameResources:           presenceAny,
+		property.NameRecurrenceRule:      presenceOptional,
+		property.NameSequenceNumber:      presenceOptional,
+		property.NameStatus:              presenceOptional,
+		property.NameSummary:             presenceOptional,
+		property.NameTimeTransparency:    presenceOptional,
+		property.NameURL:                 presenceOptional,
+	}
+
+	// todoProperties is properties of VTODO which most of methods allow
+	todoProperties = newComponentProfile(false, eventProperties, map[property.Name]presence{
+		property.NameDateTimeEnd:       presenceNever,
+		property.NameTimeTransparency:  presenceNever,
+		property.NameDateTimeDue:       presenceOptional,
+		property.NameDateTimeCompleted: presenceOptional,
+		property.NamePercentComplete:   presenceOptional,
+	}).properties
+
+	// journalProperties is properties of VJOURNAL which most of methods allow
+	journalProperties = map[property.Name]presence{
+		property.NameUID:                 presenceOne,
+		property.NameDateTimeStamp:       presenceOne,
+		property.NameOrganizer:           presenceOne,
+		property.NameAttachment:          presenceAny,
+		property.NameCategories:          presenceAny,
+		property.NameClass:               presenceOptional,
+		property.NameComment:             presenceAny,
+		property.NameContact:             presenceAny,
+		property.NameDateTimeCreated:     presenceOptional,
+		property.NameDescription:         presenceAny,
+		property.NameDateTimeStart:       presenceOptional,
+		property.NameExceptionDateTimes:  presenceAny,
+		property.NameLastModified:        presenceOptional,
+		property.NameRecurrenceDateTimes: presenceAny,
+		property.NameRecurrenceID:        presenceOptional,
+		property.NameRelatedTo:           presenceAny,
+		property.NameRecurrenceRule:      presenceOptional,
+		property.NameSequenceNumber:      presenceOptional,
+		property.NameStatus:              presenceOptional,
+		property.NameSummary:             presenceOptional,
+		property.NameURL:                 presenceOptional,
+	}
+
+	// refreshProperties is properties which REFRESH allows
+	// https://tools.ietf.org/html/rfc5546#section-3.2.6
+	// https://tools.ietf.org/html/rfc5546#section-3.4.5
+	refreshProperties = map[property.Name]presence{
+		property.NameUID:           presenceOne,
+		property.NameDateTimeStamp: presenceOne,
+		property.NameOrganizer:     presenceOne,
+		property.NameAttendee:      presenceOne,
+		property.NameComment:       presenceOptional,
+		property.NameRecurrenceID:  presenceOptional,
+	}
+
+	// declineCounterProperties is properties which DECLINECOUNTER allows
+	// https://tools.ietf.org/html/rfc5546#section-3.2.8
+	// https://tools.ietf.org/html/rfc5546#section-3.4.7
+	declineCounterProperties = map[property.Name]presence{
+		property.NameUID:            presenceOne,
+		property.NameDateTimeStamp:  presenceOne,
+		property.NameOrganizer:      presenceOne,
+		property.NameAttendee:       presenceAny,
+		property.NameComment:        presenceAny,
+		property.NameRecurrenceID:   presenceOptional,
+		property.NameRequestStatus:  presenceAny,
+		property.NameSequenceNumber: presenceOptional,
+	}
+
+	// profiles is restriction tables of RFC 5546 section 3 for each method
+	profiles = map[Profile]map[component.Type]componentProfile{
+		ProfilePublish:        publishProfile(presenceNever),
+		ProfilePublishLenient: publishProfile(presenceDiscouraged),
+		// https://tools.ietf.org/html/rfc5546#section-3.2.2
+		// https://tools.ietf.org/html/rfc5546#section-3.3.2
+		// https://tools.ietf.org/html/rfc5546#section-3.4.2
+		ProfileRequest: {
+			component.TypeEvent: newComponentProfile(true, eventProperties, map[property.Name]presence{
+				property.NameDateTimeStart: presenceOne,
+				property.NameSummary:       presenceOne,
+				property.NameAttendee:      presenceOneOrMore,
+				property.NameRequestStatus: presenceAny,
+			}),
+			component.TypeTODO: newComponentProfile(true, todoProperties, map[property.Name]presence{
+				property.NameDateTimeStart: presenceOne,
+				property.NamePriority:      presenceOne,
+				property.NameSummary:       presenceOne,
+				property.NameAttendee:      presenceOneOrMore,
+				property.NameRequestStatus: presenceAny,
+			}),
+			component.TypeFreeBusy: newComponentProfile(false, map[property.Name]presence{
+				property.NameUID:           presenceOne,
+				property.NameDateTimeStamp: presenceOne,
+				property.NameDateTimeStart: presenceOne,
+				property.NameDateTimeEnd:   presenceOne,
+				property.NameOrganizer:     presenceOne,
+				property.NameAttendee:      presenceOneOrMore,
+			}, nil),
+		},
+		// https://tools.ietf.org/html/rfc5546#section-3.2.3
+		// https://tools.ietf.org/html/rfc5546#section-3.3.3
+		// https://tools.ietf.org/html/rfc5546#section-3.4.3
+		ProfileReply: {
+			component.TypeEvent: newComponentProfile(false, eventProperties, map[property.Name]presence{
+				property.NameAttendee:      presenceOne,
+				property.NameRequestStatus: presenceAny,
+				property.NameContact:       presenceNever,
+			}),
+			component.TypeTODO: newComponentProfile(false, todoProperties, map[property.Name]presence{
+				property.NameAttendee:      presenceOne,
+				property.NameRequestStatus: presenceAny,
+				property.NameContact:       presenceNever,
+			}),
+			component.TypeFreeBusy: newComponentProfile(false, map[property.Name]presence{
+				property.NameUID:           presenceOne,
+				property.NameDateTimeStamp: presenceOne,
+				property.NameDateTimeStart: presenceOne,
+				property.NameDateTimeEnd:   presenceOne,
+				property.NameOrganizer:     presenceOne,
+				property.NameAttendee:      presenceOne,
+				property.NameComment:       presenceAny,
+				property.NameContact:       presenceOptional,
+				property.NameFreeBusyTime:  presenceAny,
+				property.NameRequestStatus: presenceAny,
+				property.NameURL:           presenceOptional,
+			}, nil),
+		},
+		// https://tools.ietf.org/html/rfc5546#section-3.2.4
+		// https://tools.ietf.org/html/rfc5546#section-3.4.4
+		// https://tools.ietf.org/html/rfc5546#section-3.5.3
+		ProfileAdd: {
+			component.TypeEvent: newComponentProfile(true, eventProperties, map[property.Name]presence{
+				property.NameDateTimeStart:      presenceOne,
+				property.NameSequenceNumber:     presenceOne,
+				property.NameSummary:            presenceOne,
+				property.NameAttendee:           presenceAny,
+				property.NameExceptionDateTimes: presenceNever,
+				property.NameRecurrenceID:       presenceNever,
+				property.NameRecurrenceRule:     presenceNever,
+			}),
+			component.TypeTODO: newComponentProfile(true, todoProperties, map[property.Name]presence{
+				property.NameDateTimeStart:      presenceOne,
+				property.NamePriority:           presenceOne,
+				property.NameSequenceNumber:     presenceOne,
+				property.NameSummary:            presenceOne,
+				property.NameAttendee:           presenceAny,
+				property.NameExceptionDateTimes: presenceNever,
+				property.NameRecurrenceID:       presenceNever,
+				property.NameRecurrenceRule:     presenceNever,
+			}),
+			component.TypeJournal: newComponentProfile(false, journalProperties, map[property.Name]presence{
+				property.NameDescription:        presenceOne,
+				property.NameDateTimeStart:      presenceOne,
+				property.NameSequenceNumber:     presenceOne,
+				property.NameAttendee:           presenceAny,
+				property.NameExceptionDateTimes: presenceNever,
+				property.NameRecurrenceID:       presenceNever,
+				property.NameRecurrenceRule:     presenceNever,
+			}),
+		},
+		// https://tools.ietf.org/html/rfc5546#section-3.2.5
+		// https://tools.ietf.org/html/rfc5546#section-3.4.5
+		// https://tools.ietf.org/html/rfc5546#section-3.5.2
+		ProfileCancel: {
+			component.TypeEvent: newComponentProfile(false, eventProperties, map[property.Name]presence{
+				property.NameSequenceNumber: presenceOne,
+				property.NameAttendee:       presenceAny,
+			}),
+			component.TypeTODO: newComponentProfile(false, todoProperties, map[property.Name]presence{
+				property.NameSequenceNumber: presenceOne,
+				property.NameAttendee:       presenceAny,
+			}),
+			component.TypeJournal: newComponentProfile(false, journalProperties, map[property.Name]presence{
+				property.NameSequenceNumber: presenceOne,
+				property.NameAttendee:       presenceAny,
+			}),
+		},
+		// https://tools.ietf.org/html/rfc5546#section-3.2.6
+		// https://tools.ietf.org/html/rfc5546#section-3.4.6
+		ProfileRefresh: {
+			component.TypeEvent: newComponentProfile(false, refreshProperties, nil),
+			component.TypeTODO:  newComponentProfile(false, refreshProperties, nil),
+		},
+		// https://tools.ietf.org/html/rfc5546#section-3.2.7
+		// https://tools.ietf.org/html/rfc5546#section-3.4.7
+		ProfileCounter: {
+			component.TypeEvent: newComponentProfile(true, eventProperties, map[property.Name]presence{
+				property.NameDateTimeStart: presenceOne,
+				property.NameSummary:       presenceOne,
+				property.NameAttendee:      presenceAny,
+				property.NameRequestStatus: presenceAny,
+			}),
+			component.TypeTODO: newComponentProfile(true, todoProperties, map[property.Name]presence{
+				property.NameSummary:       presenceOne,
+				property.NameAttendee:      presenceAny,
+				property.NameRequestStatus: presenceAny,
+			}),
+		},
+		// https://tools.ietf.org/html/rfc5546#section-3.2.8
+		// https://tools.ietf.org/html/rfc5546#section-3.4.8
+		ProfileDeclineCounter: {
+			component.TypeEvent: newComponentProfile(false, declineCounterProperties, nil),
+			component.TypeTODO:  newComponentProfile(false, declineCounterProperties, nil),
+		},
+	}
+)
+
+// publishProfile returns restriction tables of PUBLISH in which ATTENDEE has presence attendee
+// https://tools.ietf.org/html/rfc5546#section-3.2.1
+// https://tools.ietf.org/html/rfc5546#section-3.3.1
+// https://tools.ietf.org/html/rfc5546#section-3.4.1
+// https://tools.ietf.org/html/rfc5546#section-3.5.1
+func publishProfile(attendee presence) map[component.Type]componentProfile {
+	return map[component.Type]componentProfile{
+		component.TypeEvent: newComponentProfile(true, eventProperties, map[property.Name]presence{
+			property.NameDateTimeStart: presenceOne,
+			property.NameSummary:       presenceOne,
+			property.NameAttendee:      attendee,
+		}),
+		component.TypeTODO: newComponentProfile(true, todoProperties, map[property.Name]presence{
+			property.NameDateTimeStart: presenceOne,
+			property.NamePriority:      presenceOne,
+			property.NameSummary:       presenceOne,
+			property.NameAttendee:      attendee,
+		}),
+		component.TypeJournal: newComponentProfile(false, journalProperties, map[property.Name]presence{
+			property.NameDateTimeStart: presenceOne,
+			property.NameAttendee:      attendee,
+		}),
+		component.TypeFreeBusy: newComponentProfile(false, map[property.Name]presence{
+			property.NameUID:           presenceOne,
+			property.NameDateTimeStamp: presenceOne,
+			property.NameDateTimeStart: presenceOne,
+			property.NameDateTimeEnd:   presenceOne,
+			property.NameFreeBusyTime:  presenceOneOrMore,
+			property.NameOrganizer:     presenceOne,
+			property.NameComment:       presenceAny,
+			property.NameContact:       presenceOptional,
+			property.NameURL:           presenceOptional,
+		}, nil),
+	}
+}
+
+// Allows reports whether p allows property n in component of kind.
+// X-PROPERTY and IANA-PROPERTY are allowed in every profile, so they are not looked up.
+// property which p discourages such as ATTENDEE of ProfilePublishLenient is not allowed.
+func (p Profile) Allows(kind component.Type, n property.Name) bool {
+	switch profiles[p][kind].properties[n] {
+	case presenceNever, presenceDiscouraged:
+		return false
+	}
+	return true
+}
+
+// AllowsAlarms reports whether p allows VALARM in component of kind
+func (p Profile) AllowsAlarms(kind component.Type) bool {
+	return profiles[p][kind].alarms
+}
+
+// profileTarget is a component which profiles restrict
+type profileTarget struct {
+	kind      component.Type
+	uid       string
+	counts    map[property.Name]int
+	attendees []*property.Attendee
+	alarms    int
+}
+
+// profileTargetOf returns profileTarget of c, ok is false if profiles do not restrict c
+func profileTargetOf(c CalenderComponent) (profileTarget, bool) {
+	t := profileTarget{counts: map[property.Name]int{}}
+	one := func(n property.Name, ok bool) {
+		if ok {
+			t.counts[n]++
+		}
+	}
+	switch v := c.(type) {
+	case *Event:
+		t.kind, t.attendees, t.alarms = component.TypeEvent, v.Attendees, len(v.Alarms)
+		if v.UID != nil {
+			t.uid = string(v.UID.Value)
+		}
+		one(property.NameUID, v.UID != nil)
+		one(property.NameDateTimeStamp, v.DateTimeStamp != nil)
+		one(property.NameDateTimeStart, v.DateTimeStart != nil)
+		one(property.NameClass, v.Class != nil)
+		one(property.NameDateTimeCreated, v.DateTimeCreated != nil)
+		one(property.NameDescription, v.Description != nil)
+		one(property.NameGeo, v.Geo != nil)
+		one(property.NameLastModified, v.LastModified != nil)
+		one(property.NameLocation, v.Location != nil)
+		one(property.NameOrganizer, v.Organizer != nil)
+		one(property.NamePriority, v.Priority != nil)
+		one(property.NameSequenceNumber, v.SequenceNumber != nil)
+		one(property.NameStatus, v.Status != nil)
+		one(property.NameSummary, v.Summary != nil)
+		one(property.NameTimeTransparency, v.TimeTransparency != nil)
+		one(property.NameURL, v.URL != nil)
+		one(property.NameRecurrenceID, v.RecurrenceID != nil)
+		one(property.NameRecurrenceRule, v.RecurrenceRule != nil)
+		one(property.NameDateTimeEnd, v.DateTimeEnd != nil)
+		one(property.NameDuration, v.Duration != nil)
+		t.counts[property.NameAttachment] = len(v.Attachments)
+		t.counts[property.NameAttendee] = len(v.Attendees)
+		t.counts[property.NameCategories] = len(v.Categories)
+		t.counts[property.NameComment] = len(v.Comments)
+		t.counts[property.NameContact] = len(v.Contacts)
+		t.counts[property.NameExceptionDateTimes] = len(v.ExceptionDateTimes)
+		t.counts[property.NameRequestStatus] = len(v.RequestStatus)
+		t.counts[property.NameRelatedTo] = len(v.RelatedTos)
+		t.counts[property.NameResources] = len(v.Resources)
+		t.counts[property.NameRecurrenceDateTimes] = len(v.RecurrenceDateTimes)
+	case *ToDo:
+		t.kind, t.attendees, t.alarms = component.TypeTODO, v.Attendees, len(v.Alarms)
+		if v.UID != nil {
+			t.uid = string(v.UID.Value)
+		}
+		one(property.NameUID, v.UID != nil)
+		one(property.NameDateTimeStamp, v.DateTimeStamp != nil)
+		one(property.NameClass, v.Class != nil)
+		one(property.NameDateTimeCompleted, v.DateTimeCompleted != nil)
+		one(property.NameDateTimeCreated, v.DateTimeCreated != nil)
+		one(property.NameDescription, v.Description != nil)
+		one(property.NameDateTimeStart, v.DateTimeStart != nil)
+		one(property.NameGeo, v.Geo != nil)
+		one(property.NameLastModified, v.LastModified != nil)
+		one(property.NameLocation, v.Location != nil)
+		one(property.NameOrganizer, v.Organizer != nil)
+		one(property.NamePercentComplete, v.PercentComplete != nil)
+		one(property.NamePriority, v.Priority != nil)
+		one(property.NameRecurrenceID, v.RecurrenceID != nil)
+		one(property.NameSequenceNumber, v.SequenceNumber != nil)
+		one(property.NameStatus, v.Status != nil)
+		one(property.NameSummary, v.Summary != nil)
+		one(property.NameURL, v.URL != nil)
+		one(property.NameRecurrenceRule, v.RecurrenceRule != nil)
+		one(property.NameDateTimeDue, v.DateTimeDue != nil)
+		one(property.NameDuration, v.Duration != nil)
+		t.counts[property.NameAttachment] = len(v.Attachments)
+		t.counts[property.NameAttendee] = len(v.Attendees)
+		t.counts[property.NameCategories] = len(v.Categories)
+		t.counts[property.NameComment] = len(v.Comments)
+		t.counts[property.NameContact] = len(v.Contacts)
+		t.counts[property.NameExceptionDateTimes] = len(v.ExceptionDateTimes)
+		t.counts[property.NameRequestStatus] = len(v.RequestStatus)
+		t.counts[property.NameRelatedTo] = len(v.RelatedTos)
+		t.counts[property.NameResources] = len(v.Resources)
+		t.counts[property.NameRecurrenceDateTimes] = len(v.RecurrenceDateTimes)
+	case *Journal:
+		t.kind, t.attendees = component.TypeJournal, v.Attendees
+		if v.UID != nil {
+			t.uid = string(v.UID.Value)
+		}
+		one(property.NameUID, v.UID != nil)
+		one(property.NameDateTimeStamp, v.DateTimeStamp != nil)
+		one(property.NameClass, v.Class != nil)
+		one(property.NameDateTimeCreated, v.DateTimeCreated != nil)
+		one(property.NameDateTimeStart, v.DateTimeStart != nil)
+		one(property.NameLastModified, v.LastModified != nil)
+		one(property.NameOrganizer, v.Organizer != nil)
+		one(property.NameRecurrenceID, v.RecurrenceID != nil)
+		one(property.NameSequenceNumber, v.SequenceNumber != nil)
+		one(property.NameStatus, v.Status != nil)
+		one(property.NameSummary, v.Summary != nil)
+		one(property.NameURL, v.URL != nil)
+		one(property.NameRecurrenceRule, v.RecurrenceRule != nil)
+		t.counts[property.NameAttachment] = len(v.Attachments)
+		t.counts[property.NameAttendee] = len(v.Attendees)
+		t.counts[property.NameCategories] = len(v.Categories)
+		t.counts[property.NameComment] = len(v.Comments)
+		t.counts[property.NameContact] = len(v.Contacts)
+		t.counts[property.NameDescription] = len(v.Descriptions)
+		t.counts[property.NameExceptionDateTimes] = len(v.ExceptionDateTimes)
+		t.counts[property.NameRelatedTo] = len(v.RelatedTos)
+		t.counts[property.NameRecurrenceDateTimes] = len(v.RecurrenceDateTimes)
+		t.counts[property.NameRequestStatus] = len(v.RequestStatus)
+	case *FreeBusy:
+		t.kind, t.attendees = component.TypeFreeBusy, v.Attendees
+		if v.UID != nil {
+			t.uid = string(v.UID.Value)
+		}
+		one(property.NameUID, v.UID != nil)
+		one(property.NameDateTimeStamp, v.DateTimeStamp != nil)
+		one(property.NameContact, v.Contact != nil)
+		one(property.NameDateTimeStart, v.DateTimeStart != nil)
+		one(property.NameDateTimeEnd, v.DateTimeEnd != nil)
+		one(property.NameOrganizer, v.Organizer != nil)
+		one(property.NameURL, v.URL != nil)
+		t.counts[property.NameAttendee] = len(v.Attendees)
+		t.counts[property.NameComment] = len(v.Comments)
+		t.counts[property.NameFreeBusyTime] = len(v.FreeBusyTimes)
+		t.counts[property.NameRequestStatus] = len(v.RequestStatus)
+	default:
+		return profileTarget{}, false
+	}
+	return t, true
+}
+
+// checkProfile checks c against restriction tables of p
+func (ch *checker) checkProfile(c *Calendar, p Profile) {
+	path := []component.Type{component.TypeCalendar}
+	tables, ok := profiles[p]
+	if !ok {
+		ch.add(SeverityError, RuleProfileMethod, path, property.NameMethod, "unknown profile %s", p)
+		return
+	}
+	if c.Method == nil || Profile(c.Method.Value) != p.method() {
+		ch.add(SeverityError, RuleProfileMethod, path, property.NameMethod, "must be %s", p.method())
+	}
+	var (
+		kind component.Type
+		uid  string
+	)
+	for _, cc := range c.Components {
+		t, ok := profileTargetOf(cc)
+		if !ok {
+			continue
+		}
+		sub := childPath(path, t.kind)
+		cp, ok := tables[t.kind]
+		if !ok {
+			ch.add(SeverityError, RuleProfileComponent, sub, "", "is not allowed in %s", p)
+			continue
+		}
+		switch {
+		case kind == "":
+			kind, uid = t.kind, t.uid
+		case kind != t.kind:
+			ch.add(SeverityError, RuleProfileComponent, sub, "", "must not be mixed with %s", kind)
+		case uid != t.uid:
+			ch.add(SeverityError, RuleProfileUID, sub, property.NameUID, "must be %s as other components in the message", uid)
+		}
+		ch.checkPresence(sub, p, cp, t.counts)
+		if t.alarms > 0 && !cp.alarms {
+			ch.add(SeverityError, RuleProfileComponent, childPath(sub, component.TypeAlarm), "", "is not allowed in %s", p)
+		}
+		if p.method() == ProfilePublish {
+			for _, a := range t.attendees {
+				if rsvp := a.Parameter.GetRSVP(); rsvp != nil && bool(rsvp.Value) {
+					ch.add(SeverityError, RuleProfileRSVP, sub, property.NameAttendee, "%s must not request reply with RSVP=TRUE", a.Value)
+				}
+			}
+		}
+	}
+	if kind == "" {
+		ch.add(SeverityError, RuleProfileComponent, path, "", "must have %s, %s, %s or %s", component.TypeEvent, component.TypeTODO, component.TypeJournal, component.TypeFreeBusy)
+	}
+}
+
+// checkPresence reports properties whose counts do not match presence in cp
+func (ch *checker) checkPresence(path []component.Type, p Profile, cp componentProfile, counts map[property.Name]int) {
+	names := make([]string, 0, len(counts)+len(cp.properties))
+	for n := range cp.properties {
+		names = append(names, string(n))
+	}
+	for n := range counts {
+		if _, ok := cp.properties[n]; !ok {
+			names = append(names, string(n))
+		}
+	}
+	sort.Strings(names)
+	for _, s := range names {
+		n := property.Name(s)
+		count := counts[n]
+		switch cp.properties[n] {
+		case presenceNever:
+			if count > 0 {
+				ch.add(SeverityError, RuleProfileNotAllowed, path, n, "must not occur in %s", p)
+			}
+		case presenceDiscouraged:
+			if count > 0 {
+				ch.add(SeverityWarning, RuleProfileNotAllowed, path, n, "should not occur in %s", p)
+			}
+		case presenceOptional:
+			if count > 1 {
+				ch.add(SeverityError, RuleProfileCardinality, path, n, "must not occur more than once in %s, but %d", p, count)
+			}
+		case presenceOne:
+			if count == 0 {
+				ch.add(SeverityError, RuleProfileRequired, path, n, "is required in %s", p)
+			}
+			if count > 1 {
+				ch.add(SeverityError, RuleProfileCardinality, path, n, "must occur exactly once in %s, but %d", p, count)
+			}
+		case presenceOneOrMore:
+			if count == 0 {
+				ch.add(SeverityError, RuleProfileRequired, path, n, "is required in %s", p)
+			}
+		}
+	}
+}
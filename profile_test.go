@@ -0,0 +1,238 @@
+package ical
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/knsh14/ical/component"
+	"github.com/knsh14/ical/parameter"
+	"github.com/knsh14/ical/property"
+	"github.com/knsh14/ical/types"
+)
+
+func TestCalendar_Check_Profile(t *testing.T) {
+	t.Parallel()
+
+	calendar := []component.Type{component.TypeCalendar}
+	event := []component.Type{component.TypeCalendar, component.TypeEvent}
+	newMessage := func(t *testing.T, method property.MethodType, components ...CalenderComponent) *Calendar {
+		t.Helper()
+		c := newTestCalendar(t)
+		mustNoError(t, c.SetMethod(parameter.Container{}, types.NewText(string(method))))
+		c.Components = components
+		return c
+	}
+	newEvent := func(t *testing.T, attendees ...string) *Event {
+		t.Helper()
+		e := newTestEvent(t)
+		cua, err := types.NewCalenderUserAddress("mailto:alice@example.com")
+		mustNoError(t, err)
+		mustNoError(t, e.SetOrganizer(parameter.Container{}, cua))
+		mustNoError(t, e.SetSummary(parameter.Container{}, types.NewText("Planning")))
+		for _, a := range attendees {
+			cua, err := types.NewCalenderUserAddress(a)
+			mustNoError(t, err)
+			rsvp, err := parameter.NewRSVP("TRUE")
+			mustNoError(t, err)
+			mustNoError(t, e.AddAttendee(parameter.Container{
+				parameter.TypeNameRSVP: []parameter.Base{rsvp},
+			}, cua))
+		}
+		return e
+	}
+
+	testcases := map[string]struct {
+		input    func(*testing.T) *Calendar
+		profile  Profile
+		expected Findings
+	}{
+		"publish": {
+			input: func(t *testing.T) *Calendar {
+				return newMessage(t, property.MethodTypePublish, newEvent(t))
+			},
+			profile: ProfilePublish,
+		},
+		"publish with attendee": {
+			input: func(t *testing.T) *Calendar {
+				return newMessage(t, property.MethodTypePublish, newEvent(t, "mailto:bob@example.com"))
+			},
+			profile: ProfilePublish,
+			expected: Findings{
+				{Severity: SeverityError, Rule: RuleProfileNotAllowed, Path: event, Property: property.NameAttendee},
+				{Severity: SeverityError, Rule: RuleProfileRSVP, Path: event, Property: property.NameAttendee},
+			},
+		},
+		"lenient publish with attendee": {
+			input: func(t *testing.T) *Calendar {
+				return newMessage(t, property.MethodTypePublish, newEvent(t, "mailto:bob@example.com"))
+			},
+			profile: ProfilePublishLenient,
+			expected: Findings{
+				{Severity: SeverityWarning, Rule: RuleProfileNotAllowed, Path: event, Property: property.NameAttendee},
+				{Severity: SeverityError, Rule: RuleProfileRSVP, Path: event, Property: property.NameAttendee},
+			},
+		},
+		"lenient publish with other method": {
+			input: func(t *testing.T) *Calendar {
+				return newMessage(t, property.MethodTypeRequest, newEvent(t))
+			},
+			profile: ProfilePublishLenient,
+			expected: Findings{
+				{Severity: SeverityError, Rule: RuleProfileMethod, Path: []component.Type{component.TypeCalendar}, Property: property.NameMethod},
+			},
+		},
+		"reply with alarm and attendees": {
+			input: func(t *testing.T) *Calendar {
+				e := newEvent(t, "mailto:bob@example.com", "mailto:carol@example.com")
+				a := NewAlarmAudio()
+				mustNoError(t, a.SetTrigger(parameter.Container{}, types.Duration{Direction: "-", HourDuration: 15 * time.Minute}))
+				e.AddAlarm(a)
+				return newMessage(t, property.MethodTypeReply, e)
+			},
+			profile: ProfileReply,
+			expected: Findings{
+				{Severity: SeverityError, Rule: RuleProfileCardinality, Path: event, Property: property.NameAttendee},
+				{Severity: SeverityError, Rule: RuleProfileComponent, Path: append(event, component.TypeAlarm)},
+			},
+		},
+		"request without required properties": {
+			input: func(t *testing.T) *Calendar {
+				return newMessage(t, property.MethodTypePublish, newTestEvent(t))
+			},
+			profile: ProfileRequest,
+			expected: Findings{
+				{Severity: SeverityError, Rule: RuleProfileMethod, Path: calendar, Property: property.NameMethod},
+				{Severity: SeverityError, Rule: RuleProfileRequired, Path: event, Property: property.NameAttendee},
+				{Severity: SeverityError, Rule: RuleProfileRequired, Path: event, Property: property.NameOrganizer},
+				{Severity: SeverityError, Rule: RuleProfileRequired, Path: event, Property: property.NameSummary},
+			},
+		},
+		"mixed components": {
+			input: func(t *testing.T) *Calendar {
+				other := newEvent(t, "mailto:bob@example.com")
+				mustNoError(t, other.SetUID(parameter.Container{}, types.NewText("other")))
+				todo := NewToDo()
+				mustNoError(t, todo.SetUID(parameter.Container{}, types.NewText("todo")))
+				mustNoError(t, todo.SetDateTimeStamp(parameter.Container{}, types.DateTime(time.Date(1997, 6, 10, 17, 23, 45, 0, time.UTC))))
+				return newMessage(t, property.MethodTypeCancel, newEvent(t, "mailto:bob@example.com"), other, todo)
+			},
+			profile: ProfileCancel,
+			expected: Findings{
+				{Severity: SeverityError, Rule: RuleProfileRequired, Path: event, Property: property.NameSequenceNumber},
+				{Severity: SeverityError, Rule: RuleProfileUID, Path: event, Property: property.NameUID},
+				{Severity: SeverityError, Rule: RuleProfileRequired, Path: event, Property: property.NameSequenceNumber},
+				{Severity: SeverityError, Rule: RuleProfileComponent, Path: []component.Type{component.TypeCalendar, component.TypeTODO}},
+				{Severity: SeverityError, Rule: RuleProfileRequired, Path: []component.Type{component.TypeCalendar, component.TypeTODO}, Property: property.NameOrganizer},
+				{Severity: SeverityError, Rule: RuleProfileRequired, Path: []component.Type{component.TypeCalendar, component.TypeTODO}, Property: property.NameSequenceNumber},
+			},
+		},
+		"journal in refresh": {
+			input: func(t *testing.T) *Calendar {
+				j := NewJournal()
+				mustNoError(t, j.SetUID(parameter.Container{}, types.NewText("journal")))
+				mustNoError(t, j.SetDateTimeStamp(parameter.Container{}, types.DateTime(time.Date(1997, 6, 10, 17, 23, 45, 0, time.UTC))))
+				return newMessage(t, property.MethodTypeRefresh, j)
+			},
+			profile: ProfileRefresh,
+			expected: Findings{
+				{Severity: SeverityError, Rule: RuleProfileComponent, Path: []component.Type{component.TypeCalendar, component.TypeJournal}},
+				{Severity: SeverityError, Rule: RuleProfileComponent, Path: calendar},
+			},
+		},
+	}
+
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got := tc.input(t).Check(tc.profile)
+			if diff := cmp.Diff(tc.expected, got, cmpopts.IgnoreFields(Finding{}, "Err")); diff != "" {
+				t.Errorf("diff: (-expected +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCalendar_Validate_Profile(t *testing.T) {
+	t.Parallel()
+	c := newTestCalendar(t)
+	mustNoError(t, c.SetMethod(parameter.Container{}, types.NewText(string(property.MethodTypePublish))))
+	e := newTestEvent(t)
+	cua, err := types.NewCalenderUserAddress("mailto:bob@example.com")
+	mustNoError(t, err)
+	mustNoError(t, e.AddAttendee(parameter.Container{}, cua))
+	c.Components = append(c.Components, e)
+
+	if err := c.Validate(); err != nil {
+		t.Fatalf("expected no error without profile, but %v", err)
+	}
+	err = c.Validate(ProfilePublish)
+	var findings Findings
+	if !errors.As(err, &findings) {
+		t.Fatalf("expected Findings, but %v", err)
+	}
+	expected := Findings{
+		{Severity: SeverityError, Rule: RuleProfileNotAllowed, Path: []component.Type{component.TypeCalendar, component.TypeEvent}, Property: property.NameAttendee},
+		{Severity: SeverityError, Rule: RuleProfileRequired, Path: []component.Type{component.TypeCalendar, component.TypeEvent}, Property: property.NameOrganizer},
+		{Severity: SeverityError, Rule: RuleProfileRequired, Path: []component.Type{component.TypeCalendar, component.TypeEvent}, Property: property.NameSummary},
+	}
+	if diff := cmp.Diff(expected, findings, cmpopts.IgnoreFields(Finding{}, "Err")); diff != "" {
+		t.Errorf("diff: (-expected +got)\n%s", diff)
+	}
+}
+
+func TestProfile_Allows(t *testing.T) {
+	t.Parallel()
+	testcases := map[string]struct {
+		profile  Profile
+		kind     component.Type
+		name     property.Name
+		expected bool
+	}{
+		"attendee of request":       {profile: ProfileRequest, kind: component.TypeEvent, name: property.NameAttendee, expected: true},
+		"attendee of publish":       {profile: ProfilePublish, kind: component.TypeEvent, name: property.NameAttendee, expected: false},
+		"attendee of lenient":       {profile: ProfilePublishLenient, kind: component.TypeEvent, name: property.NameAttendee, expected: false},
+		"contact of reply":          {profile: ProfileReply, kind: component.TypeTODO, name: property.NameContact, expected: false},
+		"contact of freebusy reply": {profile: ProfileReply, kind: component.TypeFreeBusy, name: property.NameContact, expected: true},
+		"rrule of add":              {profile: ProfileAdd, kind: component.TypeEvent, name: property.NameRecurrenceRule, expected: false},
+		"summary of refresh":        {profile: ProfileRefresh, kind: component.TypeEvent, name: property.NameSummary, expected: false},
+		"journal of counter":        {profile: ProfileCounter, kind: component.TypeJournal, name: property.NameUID, expected: false},
+		"unknown profile":           {profile: Profile("UNKNOWN"), kind: component.TypeEvent, name: property.NameUID, expected: false},
+		"request status of decline": {profile: ProfileDeclineCounter, kind: component.TypeTODO, name: property.NameRequestStatus, expected: true},
+	}
+	for title, tt := range testcases {
+		tt := tt
+		t.Run(title, func(t *testing.T) {
+			t.Parallel()
+			if actual := tt.profile.Allows(tt.kind, tt.name); actual != tt.expected {
+				t.Errorf("expect:%v\nactual:%v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestProfile_AllowsAlarms(t *testing.T) {
+	t.Parallel()
+	testcases := map[string]struct {
+		profile  Profile
+		kind     component.Type
+		expected bool
+	}{
+		"event of request": {profile: ProfileRequest, kind: component.TypeEvent, expected: true},
+		"todo of reply":    {profile: ProfileReply, kind: component.TypeTODO, expected: false},
+		"event of cancel":  {profile: ProfileCancel, kind: component.TypeEvent, expected: false},
+		"journal of add":   {profile: ProfileAdd, kind: component.TypeJournal, expected: false},
+	}
+	for title, tt := range testcases {
+		tt := tt
+		t.Run(title, func(t *testing.T) {
+			t.Parallel()
+			if actual := tt.profile.AllowsAlarms(tt.kind); actual != tt.expected {
+				t.Errorf("expect:%v\nactual:%v", tt.expected, actual)
+			}
+		})
+	}
+}
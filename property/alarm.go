@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/knsh14/ical/contentline"
+	"github.com/knsh14/ical/errcode"
 	"github.com/knsh14/ical/parameter"
 	"github.com/knsh14/ical/token"
 	"github.com/knsh14/ical/types"
@@ -31,12 +33,14 @@ func (a *Action) SetAction(params parameter.Container, value types.Text) error {
 			a.Value = value
 			return nil
 		}
-		return fmt.Errorf("%s is invalid value for ACTION", value)
+		return errcode.Errorf(errcode.ValueFormat, "%s is invalid value for ACTION", value)
 	}
 }
 
 func (a *Action) Decode(w io.Writer) error {
-	fmt.Fprintf(w, "%s%s:%s", NameAction, a.Parameter.String(), a.Value)
+	if err := contentline.Encode(w, fmt.Sprintf("%s%s:%s", NameAction, a.Parameter.String(), a.Value)); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -45,7 +49,7 @@ func (a *Action) Validate() error {
 	case ActionTypeAudio, ActionTypeDisplay, ActionTypeEMail:
 		return nil
 	}
-	return fmt.Errorf("Invalid Action %s", a.Value)
+	return errcode.Errorf(errcode.ValueFormat, "Invalid Action %s", a.Value)
 }
 
 // RepeatCount is REPEAT
@@ -57,7 +61,7 @@ type RepeatCount struct {
 
 func (rc *RepeatCount) SetRepeatCount(params parameter.Container, value types.Integer) error {
 	if value < 0 {
-		return fmt.Errorf("value must be > 0")
+		return errcode.Errorf(errcode.ValueFormat, "value must be > 0")
 	}
 	rc.Parameter = params
 	rc.Value = value
@@ -65,20 +69,22 @@ func (rc *RepeatCount) SetRepeatCount(params parameter.Container, value types.In
 }
 
 func (rc *RepeatCount) Decode(w io.Writer) error {
-	fmt.Fprintf(w, "%s%s:%d", NameRepeatCount, rc.Parameter.String(), rc.Value)
+	if err := contentline.Encode(w, fmt.Sprintf("%s%s:%d", NameRepeatCount, rc.Parameter.String(), rc.Value)); err != nil {
+		return err
+	}
 	return nil
 }
 
 func (rc *RepeatCount) Validate() error {
 	if rc.Value < 0 {
-		return fmt.Errorf("value must be > 0")
+		return errcode.Errorf(errcode.ValueFormat, "value must be > 0")
 	}
 	return nil
 }
 
 func NewTriggerValue(params parameter.Container, value string) (types.TriggerValue, error) {
 	if len(params[parameter.TypeNameValueType]) > 1 {
-		return nil, fmt.Errorf("invalid %s parameter count", parameter.TypeNameValueType)
+		return nil, errcode.Errorf(errcode.Cardinality, "invalid %s parameter count", parameter.TypeNameValueType)
 	}
 	if len(params[parameter.TypeNameValueType]) == 0 {
 		d, err := types.NewDuration(value)
@@ -90,7 +96,7 @@ func NewTriggerValue(params parameter.Container, value string) (types.TriggerVal
 
 	valueType, ok := params[parameter.TypeNameValueType][0].(*parameter.ValueType)
 	if !ok {
-		return nil, fmt.Errorf("invalid type %T in %s ", params[parameter.TypeNameValueType][0], parameter.TypeNameValueType)
+		return nil, errcode.Errorf(errcode.ValueFormat, "invalid type %T in %s ", params[parameter.TypeNameValueType][0], parameter.TypeNameValueType)
 	}
 
 	switch valueType.Value {
@@ -108,7 +114,7 @@ func NewTriggerValue(params parameter.Container, value string) (types.TriggerVal
 		}
 		return dt, nil
 	default:
-		return nil, fmt.Errorf("invalid value type %s, must be DURATION or DATE-TIME", valueType.Value)
+		return nil, errcode.Errorf(errcode.ValueFormat, "invalid value type %s, must be DURATION or DATE-TIME", valueType.Value)
 	}
 }
 
@@ -121,11 +127,11 @@ type Trigger struct {
 
 func (t *Trigger) SetTrigger(params parameter.Container, value types.TriggerValue) error {
 	if len(params[parameter.TypeNameValueType]) > 1 {
-		return fmt.Errorf("invalid %s parameter count", parameter.TypeNameValueType)
+		return errcode.Errorf(errcode.Cardinality, "invalid %s parameter count", parameter.TypeNameValueType)
 	}
 	if len(params[parameter.TypeNameValueType]) == 0 {
 		if _, ok := value.(types.Duration); !ok {
-			return fmt.Errorf("parameter value type is DURATION, but acutual type is %T", value)
+			return errcode.Errorf(errcode.ValueFormat, "parameter value type is DURATION, but acutual type is %T", value)
 		}
 		t.Parameter = params
 		t.Value = value
@@ -134,31 +140,33 @@ func (t *Trigger) SetTrigger(params parameter.Container, value types.TriggerValu
 
 	valueType, ok := params[parameter.TypeNameValueType][0].(*parameter.ValueType)
 	if !ok {
-		return fmt.Errorf("invalid type %T in %s ", params[parameter.TypeNameValueType][0], parameter.TypeNameValueType)
+		return errcode.Errorf(errcode.ValueFormat, "invalid type %T in %s ", params[parameter.TypeNameValueType][0], parameter.TypeNameValueType)
 	}
 
 	switch valueType.Value {
 	case "DURATION":
 		if _, ok := value.(types.Duration); !ok {
-			return fmt.Errorf("parameter value type is DURATION, but acutual type is %T", value)
+			return errcode.Errorf(errcode.ValueFormat, "parameter value type is DURATION, but acutual type is %T", value)
 		}
 		t.Parameter = params
 		t.Value = value
 		return nil
 	case "DATE-TIME":
 		if _, ok := value.(types.DateTime); !ok {
-			return fmt.Errorf("parameter value type is DATE-TIME, but acutual type is %T", value)
+			return errcode.Errorf(errcode.ValueFormat, "parameter value type is DATE-TIME, but acutual type is %T", value)
 		}
 		t.Parameter = params
 		t.Value = value
 		return nil
 	default:
-		return fmt.Errorf("invalid value type %s, must be DURATION or DATE-TIME", valueType.Value)
+		return errcode.Errorf(errcode.ValueFormat, "invalid value type %s, must be DURATION or DATE-TIME", valueType.Value)
 	}
 }
 
 func (t *Trigger) Decode(w io.Writer) error {
-	fmt.Fprintf(w, "%s%s:%d", NameTrigger, t.Parameter.String(), t.Value)
+	if err := contentline.Encode(w, fmt.Sprintf("%s%s:%s", NameTrigger, t.Parameter.String(), t.Value)); err != nil {
+		return err
+	}
 	return nil
 }
 
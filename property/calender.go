@@ -7,6 +7,8 @@ import (
 	"strings"
 
 	"github.com/Masterminds/semver/v3"
+	"github.com/knsh14/ical/contentline"
+	"github.com/knsh14/ical/errcode"
 	"github.com/knsh14/ical/parameter"
 	"github.com/knsh14/ical/types"
 )
@@ -23,10 +25,10 @@ type CalScale struct {
 
 func (cs *CalScale) SetCalScale(params parameter.Container, value types.Text) error {
 	if value == "" {
-		return ErrInputIsEmpty
+		return errcode.Wrap(errcode.ValueFormat, ErrInputIsEmpty)
 	}
 	if value != types.Text("GREGORIAN") {
-		return fmt.Errorf("Invalid CALSCALE Value %s, allow only GREGORIAN", value)
+		return errcode.Errorf(errcode.ValueFormat, "Invalid CALSCALE Value %s, allow only GREGORIAN", value)
 	}
 	cs.Parameter = params
 	cs.Value = value
@@ -37,13 +39,15 @@ func (cs *CalScale) Decode(w io.Writer) error {
 	if err := cs.Validate(); err != nil {
 		return err
 	}
-	fmt.Fprintf(w, "%s%s:%s", NameCalScale, cs.Parameter.String(), cs.Value)
+	if err := contentline.Encode(w, fmt.Sprintf("%s%s:%s", NameCalScale, cs.Parameter.String(), cs.Value)); err != nil {
+		return err
+	}
 	return nil
 }
 
 func (cs *CalScale) Validate() error {
 	if cs.Value != types.Text("GREGORIAN") {
-		return fmt.Errorf("allow only \"GREGORIAN\", but %s", cs.Value)
+		return errcode.Errorf(errcode.ValueFormat, "allow only \"GREGORIAN\", but %s", cs.Value)
 	}
 	return nil
 }
@@ -57,26 +61,28 @@ type Method struct {
 
 func (m *Method) SetMethod(params parameter.Container, value types.Text) error {
 	if value == "" {
-		return ErrInputIsEmpty
+		return errcode.Wrap(errcode.ValueFormat, ErrInputIsEmpty)
 	}
 	if isMethod(string(value)) {
 		m.Parameter = params
 		m.Value = value
 		return nil
 	}
-	return fmt.Errorf("Invalid Method Value %s, allow only registerd IANA tokens", value)
+	return errcode.Errorf(errcode.ValueFormat, "Invalid Method Value %s, allow only registerd IANA tokens", value)
 }
 
 func (m *Method) Decode(w io.Writer) error {
-	fmt.Fprintf(w, "%s%s:%s", NameMethod, m.Parameter.String(), m.Value)
+	if err := contentline.Encode(w, fmt.Sprintf("%s%s:%s", NameMethod, m.Parameter.String(), m.Value)); err != nil {
+		return err
+	}
 	return nil
 }
 func (m *Method) Validate() error {
 	if m.Value == "" {
-		return ErrInputIsEmpty
+		return errcode.Wrap(errcode.ValueFormat, ErrInputIsEmpty)
 	}
 	if !isMethod(string(m.Value)) {
-		return fmt.Errorf("Invalid Method Value %s, allow only registerd IANA tokens", m.Value)
+		return errcode.Errorf(errcode.ValueFormat, "Invalid Method Value %s, allow only registerd IANA tokens", m.Value)
 	}
 	return nil
 }
@@ -90,7 +96,7 @@ type ProdID struct {
 
 func (p *ProdID) SetProdID(params parameter.Container, value types.Text) error {
 	if value == "" {
-		return ErrInputIsEmpty
+		return errcode.Wrap(errcode.ValueFormat, ErrInputIsEmpty)
 	}
 	p.Parameter = params
 	p.Value = value
@@ -98,13 +104,15 @@ func (p *ProdID) SetProdID(params parameter.Container, value types.Text) error {
 }
 
 func (p *ProdID) Decode(w io.Writer) error {
-	fmt.Fprintf(w, "%s%s:%s", NameProdID, p.Parameter.String(), p.Value)
+	if err := contentline.Encode(w, fmt.Sprintf("%s%s:%s", NameProdID, p.Parameter.String(), p.Value.Escape())); err != nil {
+		return err
+	}
 	return nil
 }
 
 func (p *ProdID) Validate() error {
 	if p.Value == "" {
-		return ErrInputIsEmpty
+		return errcode.Wrap(errcode.ValueFormat, ErrInputIsEmpty)
 	}
 	return nil
 }
@@ -124,10 +132,12 @@ type Version struct {
 
 func (v *Version) Decode(w io.Writer) error {
 	s := v.Max
-	if v.Min == "" {
+	if v.Min != "" {
 		s = v.Min + ";" + s
 	}
-	fmt.Fprintf(w, "%s%s:%s", NameVersion, v.Parameter.String(), s)
+	if err := contentline.Encode(w, fmt.Sprintf("%s%s:%s", NameVersion, v.Parameter.String(), s)); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -138,7 +148,7 @@ func (v *Version) Validate() error {
 
 func (v *Version) SetVersion(params parameter.Container, value types.Text) error {
 	if value == "" {
-		return ErrInputIsEmpty
+		return errcode.Wrap(errcode.ValueFormat, ErrInputIsEmpty)
 	}
 	isMatch, err := regexp.MatchString(`^\d+.\d+$`, string(value))
 	if err != nil {
@@ -154,11 +164,11 @@ func (v *Version) SetVersion(params parameter.Container, value types.Text) error
 		return err
 	}
 	if !isMatch {
-		return fmt.Errorf("not required format, allow X.Y or W.X;Y.Z")
+		return errcode.Errorf(errcode.ValueFormat, "not required format, allow X.Y or W.X;Y.Z")
 	}
 	versions := strings.SplitN(string(value), ";", 2)
 	if len(versions) != 2 {
-		return fmt.Errorf("versions must be 2, but %d", len(versions))
+		return errcode.Errorf(errcode.Cardinality, "versions must be 2, but %d", len(versions))
 	}
 	return v.UpdateVersion(params, types.NewText(versions[0]), types.NewText(versions[1]))
 }
@@ -166,14 +176,14 @@ func (v *Version) SetVersion(params parameter.Container, value types.Text) error
 func (v *Version) UpdateVersion(params parameter.Container, min, max types.Text) error {
 	a, err := semver.NewVersion(string(min))
 	if err != nil {
-		return fmt.Errorf("convert %s to semvar: %w", min, err)
+		return errcode.Errorf(errcode.ValueFormat, "convert %s to semvar: %w", min, err)
 	}
 	b, err := semver.NewVersion(string(min))
 	if err != nil {
-		return fmt.Errorf("convert %s to semvar: %w", max, err)
+		return errcode.Errorf(errcode.ValueFormat, "convert %s to semvar: %w", max, err)
 	}
 	if a.GreaterThan(b) {
-		return fmt.Errorf("min version %s is greater than max version %s", min, max)
+		return errcode.Errorf(errcode.ValueFormat, "min version %s is greater than max version %s", min, max)
 	}
 	v.Parameter = params
 	v.Min = min
@@ -5,6 +5,8 @@ import (
 	"io"
 	"time"
 
+	"github.com/knsh14/ical/contentline"
+	"github.com/knsh14/ical/errcode"
 	"github.com/knsh14/ical/parameter"
 	"github.com/knsh14/ical/types"
 )
@@ -20,7 +22,9 @@ type DateTimeCreated struct {
 }
 
 func (dc *DateTimeCreated) Decode(w io.Writer) error {
-	fmt.Fprintf(w, "%s%s:%s", NameDateTimeCreated, dc.Parameter.String(), dc.Value)
+	if err := contentline.Encode(w, fmt.Sprintf("%s%s:%s", NameDateTimeCreated, dc.Parameter.String(), dc.Value)); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -31,7 +35,7 @@ func (dc *DateTimeCreated) Validate() error {
 
 func (dc *DateTimeCreated) SetDateTimeCreated(params parameter.Container, value types.DateTime) error {
 	if value == types.DateTime(time.Time{}) {
-		return fmt.Errorf("input is nil")
+		return errcode.Errorf(errcode.ValueFormat, "input is nil")
 	}
 	dc.Parameter = params
 	dc.Value = value
@@ -46,7 +50,9 @@ type DateTimeStamp struct {
 }
 
 func (ds *DateTimeStamp) Decode(w io.Writer) error {
-	fmt.Fprintf(w, "%s%s:%s", NameDateTimeStamp, ds.Parameter.String(), ds.Value)
+	if err := contentline.Encode(w, fmt.Sprintf("%s%s:%s", NameDateTimeStamp, ds.Parameter.String(), ds.Value)); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -57,7 +63,7 @@ func (ds *DateTimeStamp) Validate() error {
 
 func (ds *DateTimeStamp) SetDateTimeStamp(params parameter.Container, value types.DateTime) error {
 	if value == types.DateTime(time.Time{}) {
-		return fmt.Errorf("input is nil")
+		return errcode.Errorf(errcode.ValueFormat, "input is nil")
 	}
 	ds.Parameter = params
 	ds.Value = value
@@ -72,7 +78,9 @@ type LastModified struct {
 }
 
 func (lm *LastModified) Decode(w io.Writer) error {
-	fmt.Fprintf(w, "%s%s:%s", NameLastModified, lm.Parameter.String(), lm.Value)
+	if err := contentline.Encode(w, fmt.Sprintf("%s%s:%s", NameLastModified, lm.Parameter.String(), lm.Value)); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -82,7 +90,7 @@ func (lm *LastModified) Validate() error {
 
 func (lm *LastModified) SetLastModified(params parameter.Container, value types.DateTime) error {
 	if value == types.DateTime(time.Time{}) {
-		return fmt.Errorf("input is nil")
+		return errcode.Errorf(errcode.ValueFormat, "input is nil")
 	}
 	lm.Parameter = params
 	lm.Value = value
@@ -97,7 +105,9 @@ type SequenceNumber struct {
 }
 
 func (sn *SequenceNumber) Decode(w io.Writer) error {
-	fmt.Fprintf(w, "%s%s:%d", NameLastModified, sn.Parameter.String(), sn.Value)
+	if err := contentline.Encode(w, fmt.Sprintf("%s%s:%d", NameSequenceNumber, sn.Parameter.String(), sn.Value)); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -107,7 +117,7 @@ func (sn *SequenceNumber) Validate() error {
 
 func (sn *SequenceNumber) SetSequenceNumber(params parameter.Container, value types.Integer) error {
 	if value < 0 {
-		return fmt.Errorf("value must be > 0")
+		return errcode.Errorf(errcode.ValueFormat, "value must be > 0")
 	}
 	sn.Parameter = params
 	sn.Value = value
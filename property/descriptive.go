@@ -3,9 +3,12 @@ package property
 import (
 	"fmt"
 	"io"
+	"strconv"
 	"strings"
 
 	"github.com/knsh14/ical/component"
+	"github.com/knsh14/ical/contentline"
+	"github.com/knsh14/ical/errcode"
 	"github.com/knsh14/ical/parameter"
 	"github.com/knsh14/ical/token"
 	"github.com/knsh14/ical/types"
@@ -22,7 +25,9 @@ type Attachment struct {
 }
 
 func (a *Attachment) Decode(w io.Writer) error {
-	fmt.Fprintf(w, "%s%s:%s", NameAttachment, a.Parameter.String(), a.Value)
+	if err := contentline.Encode(w, fmt.Sprintf("%s%s:%s", NameAttachment, a.Parameter.String(), a.Value)); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -73,37 +78,37 @@ func (a *Attachment) SetAttachment(params parameter.Container, value types.Attac
 	val, valOK := params[parameter.TypeNameValueType]
 	if encOK && valOK {
 		if len(enc) == 0 {
-			return fmt.Errorf("")
+			return errcode.Errorf(errcode.Cardinality, "no value for parameter %s", parameter.TypeNameInlineEncoding)
 		}
 		if len(val) == 0 {
-			return fmt.Errorf("")
+			return errcode.Errorf(errcode.Cardinality, "no value for parameter %s", parameter.TypeNameValueType)
 		}
 		encoding, ok := enc[0].(*parameter.InlineEncoding)
 		if !ok || encoding.Type != parameter.InlineEncodingTypeBASE64 {
-			return fmt.Errorf("")
+			return errcode.Errorf(errcode.ValueFormat, "%s must be BASE64", parameter.TypeNameInlineEncoding)
 		}
 		valueType, ok := val[0].(*parameter.ValueType)
 		if !ok || valueType.Value != "BINARY" {
-			return fmt.Errorf("")
+			return errcode.Errorf(errcode.ValueFormat, "%s must be BINARY", parameter.TypeNameValueType)
 		}
 		v, ok := value.(types.Binary)
 		if !ok {
-			return fmt.Errorf("invalid type %T", value)
+			return errcode.Errorf(errcode.ValueFormat, "invalid type %T", value)
 		}
 		a.Parameter = params
 		a.Value = v
 		return nil
 	} else if encOK {
-		return fmt.Errorf("%s and %s are must be true", parameter.TypeNameInlineEncoding, parameter.TypeNameValueType)
+		return errcode.Errorf(errcode.MissingProperty, "%s and %s are must be true", parameter.TypeNameInlineEncoding, parameter.TypeNameValueType)
 	} else if valOK {
-		return fmt.Errorf("%s and %s are must be true", parameter.TypeNameInlineEncoding, parameter.TypeNameValueType)
+		return errcode.Errorf(errcode.MissingProperty, "%s and %s are must be true", parameter.TypeNameInlineEncoding, parameter.TypeNameValueType)
 	}
 	if len(params[parameter.TypeNameFormatType]) > 1 {
-		return fmt.Errorf("%s must be set only 1", parameter.TypeNameFormatType)
+		return errcode.Errorf(errcode.Cardinality, "%s must be set only 1", parameter.TypeNameFormatType)
 	}
 	v, ok := value.(types.URI)
 	if !ok {
-		return fmt.Errorf("invalid type %T", value)
+		return errcode.Errorf(errcode.ValueFormat, "invalid type %T", value)
 	}
 	a.Parameter = params
 	a.Value = v
@@ -120,9 +125,9 @@ type Categories struct {
 func (c *Categories) Decode(w io.Writer) error {
 	var s []string
 	for _, v := range c.Values {
-		s = append(s, string(v))
+		s = append(s, v.Escape())
 	}
-	if _, err := fmt.Fprintf(w, "%s%s:%s", NameCategories, c.Parameter.String(), strings.Join(s, ",")); err != nil {
+	if err := contentline.Encode(w, fmt.Sprintf("%s%s:%s", NameCategories, c.Parameter.String(), strings.Join(s, ","))); err != nil {
 		return err
 	}
 	return nil
@@ -137,10 +142,10 @@ func (c *Categories) Validate() error {
 // specification https://tools.ietf.org/html/rfc5545#section-3.8.1.2
 func (c *Categories) SetCategories(params parameter.Container, values []types.Text) error {
 	if len(values) == 0 {
-		return ErrInputIsEmpty
+		return errcode.Wrap(errcode.ValueFormat, ErrInputIsEmpty)
 	}
 	if len(params[parameter.TypeNameLanguage]) > 1 {
-		return fmt.Errorf("%s must be set only 1", parameter.TypeNameLanguage)
+		return errcode.Errorf(errcode.Cardinality, "%s must be set only 1", parameter.TypeNameLanguage)
 	}
 	c.Parameter = params
 	c.Values = values
@@ -155,7 +160,7 @@ type Class struct {
 }
 
 func (c *Class) Decode(w io.Writer) error {
-	if _, err := fmt.Fprintf(w, "%s%s:%s", NameClass, c.Parameter.String(), c.Value); err != nil {
+	if err := contentline.Encode(w, fmt.Sprintf("%s%s:%s", NameClass, c.Parameter.String(), c.Value)); err != nil {
 		return err
 	}
 	return nil
@@ -178,7 +183,7 @@ func (c *Class) SetClass(params parameter.Container, value types.Text) error {
 			c.Value = value
 			return nil
 		}
-		return fmt.Errorf("invalid value: %s", value)
+		return errcode.Errorf(errcode.ValueFormat, "invalid value: %s", value)
 	}
 }
 
@@ -190,7 +195,7 @@ type Comment struct {
 }
 
 func (c *Comment) Decode(w io.Writer) error {
-	if _, err := fmt.Fprintf(w, "%s%s:%s", NameComment, c.Parameter.String(), c.Value); err != nil {
+	if err := contentline.Encode(w, fmt.Sprintf("%s%s:%s", NameComment, c.Parameter.String(), c.Value.Escape())); err != nil {
 		return err
 	}
 	return nil
@@ -204,13 +209,13 @@ func (c *Comment) Validate() error {
 // SetComment updates property value
 func (c *Comment) SetComment(params parameter.Container, value types.Text) error {
 	if len(value) == 0 {
-		return fmt.Errorf("")
+		return errcode.Wrap(errcode.ValueFormat, ErrInputIsEmpty)
 	}
 	if len(params[parameter.TypeNameAlternateTextRepresentation]) > 1 {
-		return fmt.Errorf("")
+		return errcode.Errorf(errcode.Cardinality, "too much values for parameter %s", parameter.TypeNameAlternateTextRepresentation)
 	}
 	if len(params[parameter.TypeNameLanguage]) > 1 {
-		return fmt.Errorf("")
+		return errcode.Errorf(errcode.Cardinality, "too much values for parameter %s", parameter.TypeNameLanguage)
 	}
 	c.Parameter = params
 	c.Value = value
@@ -225,7 +230,7 @@ type Description struct {
 }
 
 func (d *Description) Decode(w io.Writer) error {
-	if _, err := fmt.Fprintf(w, "%s%s:%s", NameDescription, d.Parameter.String(), d.Value); err != nil {
+	if err := contentline.Encode(w, fmt.Sprintf("%s%s:%s", NameDescription, d.Parameter.String(), d.Value.Escape())); err != nil {
 		return err
 	}
 	return nil
@@ -238,10 +243,10 @@ func (d *Description) Validate() error {
 
 func (d *Description) SetDescription(params parameter.Container, value types.Text) error {
 	if len(params[parameter.TypeNameAlternateTextRepresentation]) > 1 {
-		return fmt.Errorf("")
+		return errcode.Errorf(errcode.Cardinality, "too much values for parameter %s", parameter.TypeNameAlternateTextRepresentation)
 	}
 	if len(params[parameter.TypeNameLanguage]) > 1 {
-		return fmt.Errorf("")
+		return errcode.Errorf(errcode.Cardinality, "too much values for parameter %s", parameter.TypeNameLanguage)
 	}
 	d.Parameter = params
 	d.Value = value
@@ -257,7 +262,9 @@ type Geo struct {
 }
 
 func (g *Geo) Decode(w io.Writer) error {
-	if _, err := fmt.Fprintf(w, "%s%s:%f;%f", NameGeo, g.Parameter.String(), g.Latitude, g.Longitude); err != nil {
+	lat := strconv.FormatFloat(float64(g.Latitude), 'f', -1, 64)
+	lon := strconv.FormatFloat(float64(g.Longitude), 'f', -1, 64)
+	if err := contentline.Encode(w, fmt.Sprintf("%s%s:%s;%s", NameGeo, g.Parameter.String(), lat, lon)); err != nil {
 		return err
 	}
 	return nil
@@ -270,10 +277,10 @@ func (g *Geo) Validate() error {
 
 func (g *Geo) SetGeo(params parameter.Container, latitude, longitude types.Float) error {
 	if latitude > 180 || latitude < -180 {
-		return fmt.Errorf("")
+		return errcode.Errorf(errcode.ValueFormat, "latitude %v is out of range", latitude)
 	}
 	if longitude > 180 || longitude < -180 {
-		return fmt.Errorf("")
+		return errcode.Errorf(errcode.ValueFormat, "longitude %v is out of range", longitude)
 	}
 	g.Parameter = params
 	g.Latitude = latitude
@@ -286,7 +293,7 @@ func (g *Geo) SetGeoWithText(params parameter.Container, value types.Text) error
 	var err error
 	v := strings.SplitN(string(value), ";", 2)
 	if len(v) != 2 {
-		return fmt.Errorf("input %s cannot split with ;", value)
+		return errcode.Errorf(errcode.ValueFormat, "input %s cannot split with ;", value)
 	}
 	lat, err = types.NewFloat(v[0])
 	if err != nil {
@@ -308,7 +315,7 @@ type Location struct {
 }
 
 func (l *Location) Decode(w io.Writer) error {
-	if _, err := fmt.Fprintf(w, "%s%s:%s", NameLocation, l.Parameter.String(), l.Value); err != nil {
+	if err := contentline.Encode(w, fmt.Sprintf("%s%s:%s", NameLocation, l.Parameter.String(), l.Value.Escape())); err != nil {
 		return err
 	}
 	return nil
@@ -321,10 +328,10 @@ func (l *Location) Validate() error {
 
 func (l *Location) SetLocation(params parameter.Container, value types.Text) error {
 	if len(params[parameter.TypeNameAlternateTextRepresentation]) > 1 {
-		return fmt.Errorf("")
+		return errcode.Errorf(errcode.Cardinality, "too much values for parameter %s", parameter.TypeNameAlternateTextRepresentation)
 	}
 	if len(params[parameter.TypeNameLanguage]) > 1 {
-		return fmt.Errorf("")
+		return errcode.Errorf(errcode.Cardinality, "too much values for parameter %s", parameter.TypeNameLanguage)
 	}
 	l.Parameter = params
 	l.Value = value
@@ -340,7 +347,7 @@ type PercentComplete struct {
 }
 
 func (pc *PercentComplete) Decode(w io.Writer) error {
-	if _, err := fmt.Fprintf(w, "%s%s:%d", NamePercentComplete, pc.Parameter.String(), pc.Value); err != nil {
+	if err := contentline.Encode(w, fmt.Sprintf("%s%s:%d", NamePercentComplete, pc.Parameter.String(), pc.Value)); err != nil {
 		return err
 	}
 	return nil
@@ -353,7 +360,7 @@ func (pc *PercentComplete) Validate() error {
 
 func (pc *PercentComplete) SetPercentComplete(params parameter.Container, value types.Integer) error {
 	if value > 100 || value < 0 {
-		return fmt.Errorf("")
+		return errcode.Errorf(errcode.ValueFormat, "value %d is out of range", value)
 	}
 	pc.Parameter = params
 	pc.Value = value
@@ -369,7 +376,7 @@ type Priority struct {
 }
 
 func (p *Priority) Decode(w io.Writer) error {
-	if _, err := fmt.Fprintf(w, "%s%s:%d", NamePriority, p.Parameter.String(), p.Value); err != nil {
+	if err := contentline.Encode(w, fmt.Sprintf("%s%s:%d", NamePriority, p.Parameter.String(), p.Value)); err != nil {
 		return err
 	}
 	return nil
@@ -382,7 +389,7 @@ func (p *Priority) Validate() error {
 
 func (p *Priority) SetPriority(params parameter.Container, value types.Integer) error {
 	if value > 9 || value < 0 {
-		return fmt.Errorf("")
+		return errcode.Errorf(errcode.ValueFormat, "value %d is out of range", value)
 	}
 	p.Parameter = params
 	p.Value = value
@@ -399,9 +406,9 @@ type Resources struct {
 func (r *Resources) Decode(w io.Writer) error {
 	var s []string
 	for _, v := range r.Values {
-		s = append(s, string(v))
+		s = append(s, v.Escape())
 	}
-	if _, err := fmt.Fprintf(w, "%s%s:%s", NameResources, r.Parameter.String(), strings.Join(s, ",")); err != nil {
+	if err := contentline.Encode(w, fmt.Sprintf("%s%s:%s", NameResources, r.Parameter.String(), strings.Join(s, ","))); err != nil {
 		return err
 	}
 	return nil
@@ -414,13 +421,13 @@ func (r *Resources) Validate() error {
 
 func (r *Resources) SetResources(params parameter.Container, values []types.Text) error {
 	if len(values) == 0 {
-		return fmt.Errorf("input is nil")
+		return errcode.Errorf(errcode.ValueFormat, "input is nil")
 	}
 	if len(params[parameter.TypeNameAlternateTextRepresentation]) > 1 {
-		return fmt.Errorf("")
+		return errcode.Errorf(errcode.Cardinality, "too much values for parameter %s", parameter.TypeNameAlternateTextRepresentation)
 	}
 	if len(params[parameter.TypeNameLanguage]) > 1 {
-		return fmt.Errorf("")
+		return errcode.Errorf(errcode.Cardinality, "too much values for parameter %s", parameter.TypeNameLanguage)
 	}
 	r.Parameter = params
 	r.Values = values
@@ -435,7 +442,7 @@ type Status struct {
 }
 
 func (s *Status) Decode(w io.Writer) error {
-	if _, err := fmt.Fprintf(w, "%s%s:%s", NameStatus, s.Parameter.String(), s.Value); err != nil {
+	if err := contentline.Encode(w, fmt.Sprintf("%s%s:%s", NameStatus, s.Parameter.String(), s.Value)); err != nil {
 		return err
 	}
 	return nil
@@ -456,7 +463,7 @@ func (s *Status) SetStatus(params parameter.Container, value types.Text, kind co
 			s.Value = v
 			return nil
 		default:
-			return fmt.Errorf("")
+			return errcode.Errorf(errcode.ValueFormat, "invalid status %s for %s", v, kind)
 		}
 	case component.TypeTODO:
 		switch v {
@@ -465,7 +472,7 @@ func (s *Status) SetStatus(params parameter.Container, value types.Text, kind co
 			s.Value = v
 			return nil
 		default:
-			return fmt.Errorf("")
+			return errcode.Errorf(errcode.ValueFormat, "invalid status %s for %s", v, kind)
 		}
 	case component.TypeJournal:
 		switch v {
@@ -474,10 +481,10 @@ func (s *Status) SetStatus(params parameter.Container, value types.Text, kind co
 			s.Value = v
 			return nil
 		default:
-			return fmt.Errorf("")
+			return errcode.Errorf(errcode.ValueFormat, "invalid status %s for %s", v, kind)
 		}
 	default:
-		return fmt.Errorf("")
+		return errcode.Errorf(errcode.ValueFormat, "invalid status %s for %s", v, kind)
 	}
 }
 
@@ -490,7 +497,7 @@ type Summary struct {
 }
 
 func (s *Summary) Decode(w io.Writer) error {
-	if _, err := fmt.Fprintf(w, "%s%s:%s", NameSummary, s.Parameter.String(), s.Value); err != nil {
+	if err := contentline.Encode(w, fmt.Sprintf("%s%s:%s", NameSummary, s.Parameter.String(), s.Value.Escape())); err != nil {
 		return err
 	}
 	return nil
@@ -503,13 +510,13 @@ func (s *Summary) Validate() error {
 
 func (s *Summary) SetSummary(params parameter.Container, value types.Text) error {
 	if len(value) == 0 {
-		return fmt.Errorf("input is nil")
+		return errcode.Errorf(errcode.ValueFormat, "input is nil")
 	}
 	if len(params[parameter.TypeNameAlternateTextRepresentation]) > 1 {
-		return fmt.Errorf("")
+		return errcode.Errorf(errcode.Cardinality, "too much values for parameter %s", parameter.TypeNameAlternateTextRepresentation)
 	}
 	if len(params[parameter.TypeNameLanguage]) > 1 {
-		return fmt.Errorf("")
+		return errcode.Errorf(errcode.Cardinality, "too much values for parameter %s", parameter.TypeNameLanguage)
 	}
 	s.Parameter = params
 	s.Value = value
@@ -1,9 +1,13 @@
 package property
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/knsh14/ical/errcode"
+)
 
 var (
-	ErrInputIsEmpty = fmt.Errorf("Input is empty")
+	ErrInputIsEmpty = errcode.Errorf(errcode.ValueFormat, "Input is empty")
 )
 
 func NewValidationError(msg string) error {
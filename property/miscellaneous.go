@@ -6,6 +6,8 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/knsh14/ical/contentline"
+	"github.com/knsh14/ical/errcode"
 	"github.com/knsh14/ical/parameter"
 	"github.com/knsh14/ical/token"
 	"github.com/knsh14/ical/types"
@@ -30,6 +32,13 @@ func NewIANA(name string, params parameter.Container, value interface{}) *IANA {
 	}
 }
 
+func (i *IANA) Decode(w io.Writer) error {
+	if err := contentline.Encode(w, fmt.Sprintf("%s%s:%s", i.Name, i.Parameter.String(), formatAnyValue(i.Value))); err != nil {
+		return err
+	}
+	return nil
+}
+
 // NonStandard is property name with a "X-" prefix
 // https://tools.ietf.org/html/rfc5545#section-3.8.8.2
 type NonStandard struct {
@@ -40,7 +49,7 @@ type NonStandard struct {
 
 func NewNonStandard(name string, params parameter.Container, value interface{}) (*NonStandard, error) {
 	if !token.IsXName(name) {
-		return nil, fmt.Errorf("%s", name)
+		return nil, errcode.Errorf(errcode.UnknownProperty, "%s is not X-name", name)
 	}
 	return &NonStandard{
 		Name:      name,
@@ -49,6 +58,38 @@ func NewNonStandard(name string, params parameter.Container, value interface{})
 	}, nil
 }
 
+func (ns *NonStandard) Decode(w io.Writer) error {
+	if err := contentline.Encode(w, fmt.Sprintf("%s%s:%s", ns.Name, ns.Parameter.String(), formatAnyValue(ns.Value))); err != nil {
+		return err
+	}
+	return nil
+}
+
+// formatAnyValue converts value of IANA or NonStandard property into string.
+// []string is raw values from parser, so it is written without escaping.
+func formatAnyValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []string:
+		return strings.Join(v, ",")
+	case types.Text:
+		return v.Escape()
+	case []types.Text:
+		s := make([]string, 0, len(v))
+		for _, t := range v {
+			s = append(s, t.Escape())
+		}
+		return strings.Join(s, ",")
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
 // RequestStatus is REQUEST-STATUS
 // https://tools.ietf.org/html/rfc5545#section-3.8.8.3
 type RequestStatus struct {
@@ -61,43 +102,61 @@ type RequestStatus struct {
 }
 
 func (rs *RequestStatus) Decode(w io.Writer) error {
-	v := []string{string(rs.StatusCode), string(rs.StatusDescription)}
+	v := []string{string(rs.StatusCode), rs.StatusDescription.Escape()}
 	if rs.ExtraData != "" {
-		v = append(v, string(rs.ExtraData))
+		v = append(v, rs.ExtraData.Escape())
 	}
-	if _, err := fmt.Fprintf(w, "%s%s:%s", NameRequestStatus, rs.Parameter.String(), strings.Join(v, ";")); err != nil {
+	if err := contentline.Encode(w, fmt.Sprintf("%s%s:%s", NameRequestStatus, rs.Parameter.String(), strings.Join(v, ";"))); err != nil {
 		return err
 	}
 	return nil
 }
 
+// SetRequestStatus sets value which is escaped text separated by SEMICOLON
 func (rs *RequestStatus) SetRequestStatus(params parameter.Container, value types.Text) error {
-	values := strings.SplitN(string(value), ";", 3)
+	values := SplitEscaped(string(value), ';', 3)
 	if len(values) < 2 {
-		return fmt.Errorf("")
+		return errcode.Errorf(errcode.ValueFormat, "request status must have code and description, got %s", value)
 	}
 	var exData types.Text
 	if len(values) == 3 {
-		exData = types.Text(values[2])
+		exData = types.UnescapeText(values[2])
+	}
+	return rs.Update(params, types.Text(values[0]), types.UnescapeText(values[1]), exData)
+}
+
+// SplitEscaped splits s by sep which is not escaped by BACKSLASH into at most n strings.
+// all substrings are returned if n is negative.
+func SplitEscaped(s string, sep byte, n int) []string {
+	var res []string
+	start := 0
+	for i := 0; i < len(s) && (n < 0 || len(res) < n-1); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case sep:
+			res = append(res, s[start:i])
+			start = i + 1
+		}
 	}
-	return rs.Update(params, types.Text(values[0]), types.Text(values[1]), exData)
+	return append(res, s[start:])
 }
 
 func (rs *RequestStatus) Update(params parameter.Container, code, desc, exdata types.Text) error {
 	if desc == "" {
-		return fmt.Errorf("description is empty")
+		return errcode.Errorf(errcode.ValueFormat, "description is empty")
 	}
 	if len(params[parameter.TypeNameLanguage]) > 1 {
-		return fmt.Errorf("too many language parameter, %d", len(params[parameter.TypeNameLanguage]))
+		return errcode.Errorf(errcode.Cardinality, "too many language parameter, %d", len(params[parameter.TypeNameLanguage]))
 	}
 
 	// consider to check by defined status
 	found, err := regexp.MatchString(`^\d\.\d{1,2}$`, string(code))
 	if err != nil {
-		return fmt.Errorf("find version strings: %w", err)
+		return errcode.Errorf(errcode.ValueFormat, "find version strings: %w", err)
 	}
 	if !found {
-		return fmt.Errorf("invalid pattern %s, must be X.YY", code)
+		return errcode.Errorf(errcode.ValueFormat, "invalid pattern %s, must be X.YY", code)
 	}
 
 	rs.Parameter = params
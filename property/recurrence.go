@@ -4,7 +4,10 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"time"
 
+	"github.com/knsh14/ical/contentline"
+	"github.com/knsh14/ical/errcode"
 	"github.com/knsh14/ical/parameter"
 	"github.com/knsh14/ical/types"
 )
@@ -24,7 +27,7 @@ func (edt *ExceptionDateTimes) Decode(w io.Writer) error {
 	for _, v := range edt.Values {
 		s = append(s, v.String())
 	}
-	if _, err := fmt.Fprintf(w, "%s%s:%s", NameExceptionDateTimes, edt.Parameter.String(), strings.Join(s, ",")); err != nil {
+	if err := contentline.Encode(w, fmt.Sprintf("%s%s:%s", NameExceptionDateTimes, edt.Parameter.String(), strings.Join(s, ","))); err != nil {
 		return err
 	}
 	return nil
@@ -53,7 +56,7 @@ func (edt *ExceptionDateTimes) SetExceptionDateTimes(params parameter.Container,
 	for _, v := range values {
 		_, ok := v.(types.Date)
 		if ok != isDate {
-			return fmt.Errorf("value type is different from parameter, %s", v)
+			return errcode.Errorf(errcode.ValueFormat, "value type is different from parameter, %s", v)
 		}
 	}
 	edt.Parameter = params
@@ -62,16 +65,23 @@ func (edt *ExceptionDateTimes) SetExceptionDateTimes(params parameter.Container,
 }
 
 func NewRecurrenceDateTime(params parameter.Container, s string) (types.RecurrenceDateTimeValue, error) {
-	value, ok := params[parameter.TypeNameValueType]
-	if !ok {
-		return nil, fmt.Errorf("no value type")
-	}
-	if len(value) == 1 {
-		return nil, fmt.Errorf("no value type")
-	}
-	vt, ok := value[0].(*parameter.ValueType)
-	if !ok {
-		return nil, fmt.Errorf("not VALUE, but %T", value[0])
+	return NewRecurrenceDateTimeInLocation(params, s, nil)
+}
+
+// NewRecurrenceDateTimeInLocation returns value of RDATE.
+// local time is interpreted in loc, or location of TZID if loc is nil.
+func NewRecurrenceDateTimeInLocation(params parameter.Container, s string, loc *time.Location) (types.RecurrenceDateTimeValue, error) {
+	// default value type is DATE-TIME
+	vt := parameter.NewValueType("DATE-TIME")
+	if value, ok := params[parameter.TypeNameValueType]; ok {
+		if len(value) != 1 {
+			return nil, errcode.Errorf(errcode.Cardinality, "value type must be 1, but %d", len(value))
+		}
+		v, ok := value[0].(*parameter.ValueType)
+		if !ok {
+			return nil, errcode.Errorf(errcode.ValueFormat, "not VALUE, but %T", value[0])
+		}
+		vt = v
 	}
 	var tz string
 	if tzid, ok := params[parameter.TypeNameReferenceTimezone]; ok {
@@ -83,7 +93,13 @@ func NewRecurrenceDateTime(params parameter.Container, s string) (types.Recurren
 
 	switch vt.Value {
 	case "DATE-TIME":
-		dt, err := types.NewDateTime(s, tz)
+		var dt types.DateTime
+		var err error
+		if loc != nil {
+			dt, err = types.NewDateTimeInLocation(s, loc)
+		} else {
+			dt, err = types.NewDateTime(s, tz)
+		}
 		if err != nil {
 			return nil, fmt.Errorf("convert %s to DATE-TIME: %w", s, err)
 		}
@@ -94,14 +110,14 @@ func NewRecurrenceDateTime(params parameter.Container, s string) (types.Recurren
 			return nil, fmt.Errorf("convert %s to DATE: %w", s, err)
 		}
 		return d, nil
-	case "RERIOD":
+	case "PERIOD":
 		p, err := types.NewPeriod(s)
 		if err != nil {
 			return nil, fmt.Errorf("convert %s to PERIOD: %w", s, err)
 		}
 		return p, nil
 	default:
-		return nil, fmt.Errorf("%s is invalid name for VALUE", vt.Value)
+		return nil, errcode.Errorf(errcode.ValueFormat, "%s is invalid name for VALUE", vt.Value)
 	}
 }
 
@@ -117,7 +133,7 @@ func (rdt *RecurrenceDateTimes) Decode(w io.Writer) error {
 	for _, v := range rdt.Values {
 		values = append(values, v.String())
 	}
-	if _, err := fmt.Fprintf(w, "%s%s:%s", NameRecurrenceDateTimes, rdt.Parameter.String(), strings.Join(values, ",")); err != nil {
+	if err := contentline.Encode(w, fmt.Sprintf("%s%s:%s", NameRecurrenceDateTimes, rdt.Parameter.String(), strings.Join(values, ","))); err != nil {
 		return err
 	}
 	return nil
@@ -142,7 +158,7 @@ type RecurrenceRule struct {
 }
 
 func (rr *RecurrenceRule) Decode(w io.Writer) error {
-	if _, err := fmt.Fprintf(w, "%s%s:%s", NameRecurrenceRule, rr.Parameter.String(), rr.Value); err != nil {
+	if err := contentline.Encode(w, fmt.Sprintf("%s%s:%s", NameRecurrenceRule, rr.Parameter.String(), rr.Value)); err != nil {
 		return err
 	}
 	return nil
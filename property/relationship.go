@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/knsh14/ical/contentline"
+	"github.com/knsh14/ical/errcode"
 	"github.com/knsh14/ical/parameter"
 	"github.com/knsh14/ical/types"
 )
@@ -18,7 +20,7 @@ type Attendee struct {
 }
 
 func (a *Attendee) Decode(w io.Writer) error {
-	if _, err := fmt.Fprintf(w, "%s%s:%s", NameAttendee, a.Parameter.String(), a.Value); err != nil {
+	if err := contentline.Encode(w, fmt.Sprintf("%s%s:%s", NameAttendee, a.Parameter.String(), a.Value)); err != nil {
 		return err
 	}
 	return nil
@@ -32,7 +34,7 @@ func (a *Attendee) Validate() error {
 func (a *Attendee) SetAttendee(params parameter.Container, value types.CalenderUserAddress) error {
 	for _, pname := range []parameter.TypeName{parameter.TypeNameCalenderUserType, parameter.TypeNameMembership, parameter.TypeNameParticipationRole, parameter.TypeNameParticipationStatus, parameter.TypeNameRSVP, parameter.TypeNameDelegatee, parameter.TypeNameDelegatee, parameter.TypeNameSentBy, parameter.TypeNameCommonName, parameter.TypeNameDirectoryEntry, parameter.TypeNameLanguage} {
 		if len(params[pname]) > 1 {
-			return fmt.Errorf("too much values for parameter %s", pname)
+			return errcode.Errorf(errcode.Cardinality, "too much values for parameter %s", pname)
 		}
 	}
 	a.Parameter = params
@@ -48,7 +50,7 @@ type Contact struct {
 }
 
 func (c *Contact) Decode(w io.Writer) error {
-	if _, err := fmt.Fprintf(w, "%s%s:%s", NameContact, c.Parameter.String(), c.Value); err != nil {
+	if err := contentline.Encode(w, fmt.Sprintf("%s%s:%s", NameContact, c.Parameter.String(), c.Value.Escape())); err != nil {
 		return err
 	}
 	return nil
@@ -61,10 +63,10 @@ func (c *Contact) Validate() error {
 
 func (c *Contact) SetContact(params parameter.Container, value types.Text) error {
 	if len(params[parameter.TypeNameAlternateTextRepresentation]) > 1 {
-		return fmt.Errorf("too much values for parameter %s", parameter.TypeNameAlternateTextRepresentation)
+		return errcode.Errorf(errcode.Cardinality, "too much values for parameter %s", parameter.TypeNameAlternateTextRepresentation)
 	}
 	if len(params[parameter.TypeNameLanguage]) > 1 {
-		return fmt.Errorf("too much values for parameter %s", parameter.TypeNameLanguage)
+		return errcode.Errorf(errcode.Cardinality, "too much values for parameter %s", parameter.TypeNameLanguage)
 	}
 	c.Parameter = params
 	c.Value = value
@@ -79,7 +81,7 @@ type Organizer struct {
 }
 
 func (o *Organizer) Decode(w io.Writer) error {
-	if _, err := fmt.Fprintf(w, "%s%s:%s", NameOrganizer, o.Parameter.String(), o.Value); err != nil {
+	if err := contentline.Encode(w, fmt.Sprintf("%s%s:%s", NameOrganizer, o.Parameter.String(), o.Value)); err != nil {
 		return err
 	}
 	return nil
@@ -93,16 +95,16 @@ func (o *Organizer) Validate() error {
 func (o *Organizer) SetOrganizer(params parameter.Container, value types.CalenderUserAddress) error {
 
 	if len(params[parameter.TypeNameCommonName]) > 1 {
-		return fmt.Errorf("too much values for parameter %s", parameter.TypeNameCommonName)
+		return errcode.Errorf(errcode.Cardinality, "too much values for parameter %s", parameter.TypeNameCommonName)
 	}
 	if len(params[parameter.TypeNameDirectoryEntry]) > 1 {
-		return fmt.Errorf("too much values for parameter %s", parameter.TypeNameDirectoryEntry)
+		return errcode.Errorf(errcode.Cardinality, "too much values for parameter %s", parameter.TypeNameDirectoryEntry)
 	}
 	if len(params[parameter.TypeNameSentBy]) > 1 {
-		return fmt.Errorf("too much values for parameter %s", parameter.TypeNameSentBy)
+		return errcode.Errorf(errcode.Cardinality, "too much values for parameter %s", parameter.TypeNameSentBy)
 	}
 	if len(params[parameter.TypeNameLanguage]) > 1 {
-		return fmt.Errorf("too much values for parameter %s", parameter.TypeNameLanguage)
+		return errcode.Errorf(errcode.Cardinality, "too much values for parameter %s", parameter.TypeNameLanguage)
 	}
 	o.Parameter = params
 	o.Value = value
@@ -117,7 +119,7 @@ type RecurrenceID struct {
 }
 
 func (rid *RecurrenceID) Decode(w io.Writer) error {
-	if _, err := fmt.Fprintf(w, "%s%s:%s", NameRecurrenceID, rid.Parameter.String(), rid.Value); err != nil {
+	if err := contentline.Encode(w, fmt.Sprintf("%s%s:%s", NameRecurrenceID, rid.Parameter.String(), rid.Value)); err != nil {
 		return err
 	}
 	return nil
@@ -128,12 +130,12 @@ func (rid *RecurrenceID) Validate() error {
 	return nil
 }
 
-func (rid RecurrenceID) SetRecurrenceID(params parameter.Container, value types.TimeValue) error {
+func (rid *RecurrenceID) SetRecurrenceID(params parameter.Container, value types.TimeValue) error {
 	if len(params[parameter.TypeNameReferenceTimezone]) > 1 {
-		return fmt.Errorf("too much values for parameter %s", parameter.TypeNameReferenceTimezone)
+		return errcode.Errorf(errcode.Cardinality, "too much values for parameter %s", parameter.TypeNameReferenceTimezone)
 	}
 	if len(params[parameter.TypeNameRecurrenceIDRange]) > 1 {
-		return fmt.Errorf("too much values for parameter %s", parameter.TypeNameRecurrenceIDRange)
+		return errcode.Errorf(errcode.Cardinality, "too much values for parameter %s", parameter.TypeNameRecurrenceIDRange)
 	}
 	rid.Parameter = params
 	rid.Value = value
@@ -148,7 +150,7 @@ type RelatedTo struct {
 }
 
 func (rt *RelatedTo) Decode(w io.Writer) error {
-	if _, err := fmt.Fprintf(w, "%s%s:%s", NameRelatedTo, rt.Parameter.String(), rt.Value); err != nil {
+	if err := contentline.Encode(w, fmt.Sprintf("%s%s:%s", NameRelatedTo, rt.Parameter.String(), rt.Value.Escape())); err != nil {
 		return err
 	}
 	return nil
@@ -161,7 +163,7 @@ func (rt *RelatedTo) Validate() error {
 
 func (rt *RelatedTo) SetRelatedTo(params parameter.Container, value types.Text) error {
 	if len(params[parameter.TypeNameRelationshipType]) > 1 {
-		return fmt.Errorf("too much values for parameter %s", parameter.TypeNameLanguage)
+		return errcode.Errorf(errcode.Cardinality, "too much values for parameter %s", parameter.TypeNameLanguage)
 	}
 	rt.Parameter = params
 	rt.Value = value
@@ -177,7 +179,7 @@ type URL struct {
 }
 
 func (u *URL) Decode(w io.Writer) error {
-	if _, err := fmt.Fprintf(w, "%s%s:%s", NameURL, u.Parameter.String(), u.Value); err != nil {
+	if err := contentline.Encode(w, fmt.Sprintf("%s%s:%s", NameURL, u.Parameter.String(), u.Value)); err != nil {
 		return err
 	}
 	return nil
@@ -201,7 +203,7 @@ type UID struct {
 }
 
 func (u *UID) Decode(w io.Writer) error {
-	if _, err := fmt.Fprintf(w, "%s%s:%s", NameUID, u.Parameter.String(), u.Value); err != nil {
+	if err := contentline.Encode(w, fmt.Sprintf("%s%s:%s", NameUID, u.Parameter.String(), u.Value.Escape())); err != nil {
 		return err
 	}
 	return nil
@@ -6,6 +6,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/knsh14/ical/contentline"
+	"github.com/knsh14/ical/errcode"
 	"github.com/knsh14/ical/parameter"
 	"github.com/knsh14/ical/types"
 )
@@ -20,7 +22,7 @@ type DateTimeCompleted struct {
 }
 
 func (dtc *DateTimeCompleted) Decode(w io.Writer) error {
-	if _, err := fmt.Fprintf(w, "%s%s:%s", NameDateTimeCompleted, dtc.Parameter.String(), dtc.Value); err != nil {
+	if err := contentline.Encode(w, fmt.Sprintf("%s%s:%s", NameDateTimeCompleted, dtc.Parameter.String(), dtc.Value)); err != nil {
 		return err
 	}
 	return nil
@@ -32,11 +34,11 @@ func (dtc *DateTimeCompleted) Validate() error {
 }
 
 func (dtc *DateTimeCompleted) SetCompleted(params parameter.Container, value types.DateTime) error {
-	if value != types.DateTime(time.Time{}) {
-		return ErrInputIsEmpty
+	if value == types.DateTime(time.Time{}) {
+		return errcode.Wrap(errcode.ValueFormat, ErrInputIsEmpty)
 	}
 	if loc := time.Time(value).Location(); loc != time.UTC {
-		return fmt.Errorf("Completed timezone must be UTC, but %s", loc)
+		return errcode.Errorf(errcode.ValueFormat, "Completed timezone must be UTC, but %s", loc)
 	}
 	dtc.Parameter = params
 	dtc.Value = value
@@ -51,7 +53,7 @@ type DateTimeEnd struct {
 }
 
 func (dte *DateTimeEnd) Decode(w io.Writer) error {
-	if _, err := fmt.Fprintf(w, "%s%s:%s", NameDateTimeEnd, dte.Parameter.String(), dte.Value); err != nil {
+	if err := contentline.Encode(w, fmt.Sprintf("%s%s:%s", NameDateTimeEnd, dte.Parameter.String(), dte.Value)); err != nil {
 		return err
 	}
 	return nil
@@ -76,7 +78,7 @@ type DateTimeDue struct {
 }
 
 func (dtd *DateTimeDue) Decode(w io.Writer) error {
-	if _, err := fmt.Fprintf(w, "%s%s:%s", NameDateTimeDue, dtd.Parameter.String(), dtd.Value); err != nil {
+	if err := contentline.Encode(w, fmt.Sprintf("%s%s:%s", NameDateTimeDue, dtd.Parameter.String(), dtd.Value)); err != nil {
 		return err
 	}
 	return nil
@@ -101,7 +103,7 @@ type DateTimeStart struct {
 }
 
 func (dts *DateTimeStart) Decode(w io.Writer) error {
-	if _, err := fmt.Fprintf(w, "%s%s:%s", NameDateTimeStart, dts.Parameter.String(), dts.Value); err != nil {
+	if err := contentline.Encode(w, fmt.Sprintf("%s%s:%s", NameDateTimeStart, dts.Parameter.String(), dts.Value)); err != nil {
 		return err
 	}
 	return nil
@@ -126,7 +128,7 @@ type Duration struct {
 }
 
 func (d *Duration) Decode(w io.Writer) error {
-	if _, err := fmt.Fprintf(w, "%s%s:%s", NameDuration, d.Parameter.String(), d.Value); err != nil {
+	if err := contentline.Encode(w, fmt.Sprintf("%s%s:%s", NameDuration, d.Parameter.String(), d.Value)); err != nil {
 		return err
 	}
 	return nil
@@ -155,7 +157,7 @@ func (fbt *FreeBusyTime) Decode(w io.Writer) error {
 	for _, v := range fbt.Values {
 		s = append(s, v.String())
 	}
-	if _, err := fmt.Fprintf(w, "%s%s:%s", NameFreeBusyTime, fbt.Parameter.String(), strings.Join(s, ",")); err != nil {
+	if err := contentline.Encode(w, fmt.Sprintf("%s%s:%s", NameFreeBusyTime, fbt.Parameter.String(), strings.Join(s, ","))); err != nil {
 		return err
 	}
 	return nil
@@ -168,16 +170,35 @@ func (fbt *FreeBusyTime) Validate() error {
 
 func (fbt *FreeBusyTime) SetFreeBusyTime(params parameter.Container, values []types.Period) error {
 	if len(values) == 0 {
-		return fmt.Errorf("")
+		return errcode.Errorf(errcode.Cardinality, "no period is specified")
 	}
 	if len(params[parameter.TypeNameFreeBusyTimeType]) > 1 {
-		return fmt.Errorf("")
+		return errcode.Errorf(errcode.Cardinality, "%s must be 1, but %d", parameter.TypeNameFreeBusyTimeType, len(params[parameter.TypeNameFreeBusyTimeType]))
+	}
+	for _, v := range values {
+		if time.Time(v.Start).Location() != time.UTC {
+			return errcode.Errorf(errcode.ValueFormat, "period must be UTC time, but %s", v)
+		}
+		if v.Type == types.PeriodTypeExplicit && time.Time(v.End).Location() != time.UTC {
+			return errcode.Errorf(errcode.ValueFormat, "period must be UTC time, but %s", v)
+		}
 	}
 	fbt.Parameter = params
 	fbt.Values = values
 	return nil
 }
 
+// FreeBusyType returns FBTYPE of fbt.
+// default value is BUSY.
+func (fbt *FreeBusyTime) FreeBusyType() *parameter.FreeBusyTimeType {
+	if l := fbt.Parameter[parameter.TypeNameFreeBusyTimeType]; len(l) == 1 {
+		if t, ok := l[0].(*parameter.FreeBusyTimeType); ok {
+			return t
+		}
+	}
+	return &parameter.FreeBusyTimeType{Type: parameter.FreeBusyTimeTypeKindBusy}
+}
+
 // TimeTransparency is TRANSP
 // https://tools.ietf.org/html/rfc5545#section-3.8.2.7
 type TimeTransparency struct {
@@ -186,7 +207,7 @@ type TimeTransparency struct {
 }
 
 func (tt *TimeTransparency) Decode(w io.Writer) error {
-	if _, err := fmt.Fprintf(w, "%s%s:%s", NameTimeTransparency, tt.Parameter.String(), tt.Value); err != nil {
+	if err := contentline.Encode(w, fmt.Sprintf("%s%s:%s", NameTimeTransparency, tt.Parameter.String(), tt.Value)); err != nil {
 		return err
 	}
 	return nil
@@ -204,6 +225,6 @@ func (tt *TimeTransparency) SetTransparency(params parameter.Container, value Tr
 		tt.Value = value
 		return nil
 	default:
-		return fmt.Errorf("unknown TransparencyValueType %s", value)
+		return errcode.Errorf(errcode.ValueFormat, "unknown TransparencyValueType %s", value)
 	}
 }
@@ -3,8 +3,9 @@ package property
 import (
 	"fmt"
 	"io"
-	"time"
 
+	"github.com/knsh14/ical/contentline"
+	"github.com/knsh14/ical/errcode"
 	"github.com/knsh14/ical/parameter"
 	"github.com/knsh14/ical/types"
 )
@@ -18,8 +19,8 @@ type TimezoneIdentifier struct {
 	Value     types.Text
 }
 
-func (ti *TimezoneIdentifier) Decoce(w io.Writer) error {
-	if _, err := fmt.Fprintf(w, "%s%s:%s", NameTimezoneIdentifier, ti.Parameter.String(), ti.Value); err != nil {
+func (ti *TimezoneIdentifier) Decode(w io.Writer) error {
+	if err := contentline.Encode(w, fmt.Sprintf("%s%s:%s", NameTimezoneIdentifier, ti.Parameter.String(), ti.Value.Escape())); err != nil {
 		return err
 	}
 	return nil
@@ -31,9 +32,9 @@ func (ti *TimezoneIdentifier) Validate() error {
 }
 
 func (tzid *TimezoneIdentifier) SetTimezoneID(params parameter.Container, value types.Text) error {
-	_, err := time.LoadLocation(string(value))
-	if err != nil {
-		return fmt.Errorf("value %s is not TimezoneID: %w", value, err)
+	// TZID is not always a name in IANA time zone database, e.g. "Tokyo Standard Time"
+	if value == "" {
+		return errcode.Errorf(errcode.ValueFormat, "TimezoneID must not be empty")
 	}
 	tzid.Parameter = params
 	tzid.Value = value
@@ -47,8 +48,8 @@ type TimezoneName struct {
 	Value     types.Text
 }
 
-func (tn *TimezoneName) Decoce(w io.Writer) error {
-	if _, err := fmt.Fprintf(w, "%s%s:%s", NameTimezoneName, tn.Parameter.String(), tn.Value); err != nil {
+func (tn *TimezoneName) Decode(w io.Writer) error {
+	if err := contentline.Encode(w, fmt.Sprintf("%s%s:%s", NameTimezoneName, tn.Parameter.String(), tn.Value.Escape())); err != nil {
 		return err
 	}
 	return nil
@@ -61,7 +62,7 @@ func (tn *TimezoneName) Validate() error {
 
 func (tzn *TimezoneName) SetTimezoneName(params parameter.Container, value types.Text) error {
 	if len(params[parameter.TypeNameLanguage]) > 1 {
-		return fmt.Errorf("")
+		return errcode.Errorf(errcode.Cardinality, "too much values for parameter %s", parameter.TypeNameLanguage)
 	}
 	tzn.Parameter = params
 	tzn.Value = value
@@ -75,8 +76,8 @@ type TimezoneOffsetFrom struct {
 	Value     types.UTCOffset
 }
 
-func (tzofrom *TimezoneOffsetFrom) Decoce(w io.Writer) error {
-	if _, err := fmt.Fprintf(w, "%s%s:%s", NameTimezoneOffsetFrom, tzofrom.Parameter.String(), tzofrom.Value); err != nil {
+func (tzofrom *TimezoneOffsetFrom) Decode(w io.Writer) error {
+	if err := contentline.Encode(w, fmt.Sprintf("%s%s:%s", NameTimezoneOffsetFrom, tzofrom.Parameter.String(), tzofrom.Value)); err != nil {
 		return err
 	}
 	return nil
@@ -100,8 +101,8 @@ type TimezoneOffsetTo struct {
 	Value     types.UTCOffset
 }
 
-func (tzoto *TimezoneOffsetTo) Decoce(w io.Writer) error {
-	if _, err := fmt.Fprintf(w, "%s%s:%s", NameTimezoneOffsetTo, tzoto.Parameter.String(), tzoto.Value); err != nil {
+func (tzoto *TimezoneOffsetTo) Decode(w io.Writer) error {
+	if err := contentline.Encode(w, fmt.Sprintf("%s%s:%s", NameTimezoneOffsetTo, tzoto.Parameter.String(), tzoto.Value)); err != nil {
 		return err
 	}
 	return nil
@@ -125,8 +126,8 @@ type TimezoneURL struct {
 	Value     types.URI
 }
 
-func (tzurl *TimezoneURL) Decoce(w io.Writer) error {
-	if _, err := fmt.Fprintf(w, "%s%s:%s", NameTimezoneURL, tzurl.Parameter.String(), tzurl.Value); err != nil {
+func (tzurl *TimezoneURL) Decode(w io.Writer) error {
+	if err := contentline.Encode(w, fmt.Sprintf("%s%s:%s", NameTimezoneURL, tzurl.Parameter.String(), tzurl.Value)); err != nil {
 		return err
 	}
 	return nil
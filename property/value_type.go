@@ -0,0 +1,63 @@
+package property
+
+import "github.com/knsh14/ical/types"
+
+// defaultValueTypes is value type of properties without VALUE parameter
+var defaultValueTypes = map[Name]types.ValueType{
+	NameCalScale:            types.ValueTypeText,
+	NameMethod:              types.ValueTypeText,
+	NameProdID:              types.ValueTypeText,
+	NameVersion:             types.ValueTypeText,
+	NameAttachment:          types.ValueTypeURI,
+	NameCategories:          types.ValueTypeText,
+	NameClass:               types.ValueTypeText,
+	NameComment:             types.ValueTypeText,
+	NameDescription:         types.ValueTypeText,
+	NameGeo:                 types.ValueTypeFloat,
+	NameLocation:            types.ValueTypeText,
+	NamePercentComplete:     types.ValueTypeInteger,
+	NamePriority:            types.ValueTypeInteger,
+	NameResources:           types.ValueTypeText,
+	NameStatus:              types.ValueTypeText,
+	NameSummary:             types.ValueTypeText,
+	NameDateTimeCompleted:   types.ValueTypeDateTime,
+	NameDateTimeEnd:         types.ValueTypeDateTime,
+	NameDateTimeDue:         types.ValueTypeDateTime,
+	NameDateTimeStart:       types.ValueTypeDateTime,
+	NameDuration:            types.ValueTypeDuration,
+	NameFreeBusyTime:        types.ValueTypePeriod,
+	NameTimeTransparency:    types.ValueTypeText,
+	NameTimezoneIdentifier:  types.ValueTypeText,
+	NameTimezoneName:        types.ValueTypeText,
+	NameTimezoneOffsetFrom:  types.ValueTypeUTCOffset,
+	NameTimezoneOffsetTo:    types.ValueTypeUTCOffset,
+	NameTimezoneURL:         types.ValueTypeURI,
+	NameAttendee:            types.ValueTypeCalendarUserAddress,
+	NameContact:             types.ValueTypeText,
+	NameOrganizer:           types.ValueTypeCalendarUserAddress,
+	NameRecurrenceID:        types.ValueTypeDateTime,
+	NameRelatedTo:           types.ValueTypeText,
+	NameURL:                 types.ValueTypeURI,
+	NameUID:                 types.ValueTypeText,
+	NameExceptionDateTimes:  types.ValueTypeDateTime,
+	NameRecurrenceDateTimes: types.ValueTypeDateTime,
+	NameRecurrenceRule:      types.ValueTypeRecurrenceRule,
+	NameAction:              types.ValueTypeText,
+	NameRepeatCount:         types.ValueTypeInteger,
+	NameTrigger:             types.ValueTypeDuration,
+	NameDateTimeCreated:     types.ValueTypeDateTime,
+	NameDateTimeStamp:       types.ValueTypeDateTime,
+	NameLastModified:        types.ValueTypeDateTime,
+	NameSequenceNumber:      types.ValueTypeInteger,
+	NameRequestStatus:       types.ValueTypeText,
+}
+
+// DefaultValueType returns value type of property n when VALUE parameter is not specified.
+// ValueTypeUnknown is returned for X-PROPERTY and unknown properties.
+// https://tools.ietf.org/html/rfc7265#section-3.4.2
+func DefaultValueType(n Name) types.ValueType {
+	if t, ok := defaultValueTypes[n]; ok {
+		return t
+	}
+	return types.ValueTypeUnknown
+}
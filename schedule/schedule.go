@@ -0,0 +1,149 @@
+package schedule
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/knsh14/ical"
+)
+
+// Constraints is condition of meeting slots
+type Constraints struct {
+	// Start and End is range to find slots
+	Start time.Time
+	End   time.Time
+	// Duration is length of the meeting
+	Duration time.Duration
+	// Location is time zone of working hours, UTC is used if it is nil
+	Location *time.Location
+	// WorkStart and WorkEnd is working hours as elapsed time from midnight in Location.
+	// whole day is working hours if both are zero.
+	WorkStart time.Duration
+	WorkEnd   time.Duration
+	// WorkDays is days of week to find slots, every day is used if it is empty
+	WorkDays []time.Weekday
+	// Step is interval of start time of slots from the beginning of working hours.
+	// Duration is used if it is zero, so slots do not overlap each other.
+	Step time.Duration
+	// Limit is max number of slots, all slots are returned if it is zero
+	Limit int
+}
+
+// Slot is free time for the meeting
+type Slot struct {
+	Start time.Time
+	End   time.Time
+}
+
+// FindSlots returns slots in working hours where attendees of calendars are all free.
+// slots are sorted by start time.
+// busy time is calculated by ical.NewFreeBusyFromCalendars, so transparent or cancelled events do not block slots.
+func FindSlots(calendars []*ical.Calendar, c Constraints) ([]Slot, error) {
+	if c.Duration <= 0 {
+		return nil, fmt.Errorf("duration must be positive, but %v", c.Duration)
+	}
+	if !c.Start.Before(c.End) {
+		return nil, fmt.Errorf("start %v must be before end %v", c.Start, c.End)
+	}
+	if c.WorkEnd < c.WorkStart {
+		return nil, fmt.Errorf("working hours end %v is before start %v", c.WorkEnd, c.WorkStart)
+	}
+	loc := c.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	step := c.Step
+	if step <= 0 {
+		step = c.Duration
+	}
+
+	fb, err := ical.NewFreeBusyFromCalendars(c.Start, c.End, calendars...)
+	if err != nil {
+		return nil, fmt.Errorf("calculate free busy time: %w", err)
+	}
+	var busy []Slot
+	for _, fbt := range fb.FreeBusyTimes {
+		for _, p := range fbt.Values {
+			busy = append(busy, Slot{Start: time.Time(p.Start), End: time.Time(p.End)})
+		}
+	}
+	sort.Slice(busy, func(i, j int) bool { return busy[i].Start.Before(busy[j].Start) })
+
+	var res []Slot
+	for _, w := range workingHours(c, loc) {
+		for _, free := range subtract(w, busy) {
+			// align start of slots with start of working hours
+			n := free.Start.Sub(w.Start) / step
+			t := w.Start.Add(n * step)
+			if t.Before(free.Start) {
+				t = t.Add(step)
+			}
+			for ; !t.Add(c.Duration).After(free.End); t = t.Add(step) {
+				res = append(res, Slot{Start: t, End: t.Add(c.Duration)})
+				if c.Limit > 0 && len(res) == c.Limit {
+					return res, nil
+				}
+			}
+		}
+	}
+	return res, nil
+}
+
+// workingHours returns working hours of each day between c.Start and c.End
+func workingHours(c Constraints, loc *time.Location) []Slot {
+	days := map[time.Weekday]bool{}
+	for _, d := range c.WorkDays {
+		days[d] = true
+	}
+	// wall clock is kept even on the day when daylight saving time starts or ends
+	at := func(day time.Time, d time.Duration) time.Time {
+		return time.Date(day.Year(), day.Month(), day.Day(), 0, 0, int(d/time.Second), int(d%time.Second), loc)
+	}
+	workEnd := c.WorkEnd
+	if c.WorkStart == 0 && c.WorkEnd == 0 {
+		workEnd = 24 * time.Hour
+	}
+
+	var res []Slot
+	first := c.Start.In(loc)
+	for day := time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, loc); day.Before(c.End); day = day.AddDate(0, 0, 1) {
+		if len(days) > 0 && !days[day.Weekday()] {
+			continue
+		}
+		w := Slot{Start: at(day, c.WorkStart), End: at(day, workEnd)}
+		if w.Start.Before(c.Start) {
+			w.Start = c.Start
+		}
+		if w.End.After(c.End) {
+			w.End = c.End
+		}
+		if w.Start.Before(w.End) {
+			res = append(res, w)
+		}
+	}
+	return res
+}
+
+// subtract returns parts of w which do not overlap with busy.
+// busy must be sorted by start time.
+func subtract(w Slot, busy []Slot) []Slot {
+	var res []Slot
+	start := w.Start
+	for _, b := range busy {
+		if !b.End.After(start) {
+			continue
+		}
+		if !b.Start.Before(w.End) {
+			break
+		}
+		if b.Start.After(start) {
+			res = append(res, Slot{Start: start, End: b.Start})
+		}
+		start = b.End
+	}
+	if start.Before(w.End) {
+		res = append(res, Slot{Start: start, End: w.End})
+	}
+	return res
+}
@@ -0,0 +1,137 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/knsh14/ical"
+	"github.com/knsh14/ical/parameter"
+	"github.com/knsh14/ical/property"
+	"github.com/knsh14/ical/types"
+)
+
+func TestFindSlots(t *testing.T) {
+	t.Parallel()
+	tokyo := time.FixedZone("JST", 9*60*60)
+	jst := func(d, h, min int) time.Time {
+		return time.Date(2020, 1, d, h, min, 0, 0, tokyo)
+	}
+	calendar := func(t *testing.T, events ...*ical.Event) *ical.Calendar {
+		t.Helper()
+		c := ical.NewCalendar()
+		for _, e := range events {
+			c.Components = append(c.Components, e)
+		}
+		return c
+	}
+	event := func(t *testing.T, uid string, start, end time.Time) *ical.Event {
+		t.Helper()
+		e := ical.NewEvent()
+		mustNoError(t, e.SetUID(parameter.Container{}, types.NewText(uid)))
+		mustNoError(t, e.SetDateTimeStamp(parameter.Container{}, types.DateTime(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))))
+		mustNoError(t, e.SetDateTimeStart(parameter.Container{}, types.DateTime(start)))
+		mustNoError(t, e.SetDateTimeEnd(parameter.Container{}, types.DateTime(end)))
+		return e
+	}
+
+	testcases := map[string]struct {
+		calendars   func(t *testing.T) []*ical.Calendar
+		constraints Constraints
+		expected    []Slot
+	}{
+		"busy time of all attendees": {
+			calendars: func(t *testing.T) []*ical.Calendar {
+				daily := event(t, "daily", jst(6, 9, 0), jst(6, 10, 30))
+				rule, err := types.NewRecurrenceRule("FREQ=DAILY")
+				mustNoError(t, err)
+				mustNoError(t, daily.SetRecurrenceRule(parameter.Container{}, rule))
+				lunch := event(t, "lunch", jst(6, 11, 30), jst(6, 13, 0))
+				return []*ical.Calendar{calendar(t, daily), calendar(t, lunch)}
+			},
+			constraints: Constraints{
+				Start:     jst(6, 0, 0),
+				End:       jst(7, 0, 0),
+				Duration:  time.Hour,
+				Location:  tokyo,
+				WorkStart: 9 * time.Hour,
+				WorkEnd:   17 * time.Hour,
+				Step:      30 * time.Minute,
+				Limit:     4,
+			},
+			expected: []Slot{
+				{Start: jst(6, 10, 30), End: jst(6, 11, 30)},
+				{Start: jst(6, 13, 0), End: jst(6, 14, 0)},
+				{Start: jst(6, 13, 30), End: jst(6, 14, 30)},
+				{Start: jst(6, 14, 0), End: jst(6, 15, 0)},
+			},
+		},
+		"transparent event and week end": {
+			calendars: func(t *testing.T) []*ical.Calendar {
+				busy := event(t, "busy", jst(10, 9, 0), jst(10, 16, 0))
+				transparent := event(t, "transparent", jst(13, 9, 0), jst(13, 17, 0))
+				mustNoError(t, transparent.SetTimeTransparency(parameter.Container{}, property.TransparencyValueTypeTransparent))
+				return []*ical.Calendar{calendar(t, busy, transparent)}
+			},
+			constraints: Constraints{
+				Start:     jst(10, 0, 0),
+				End:       jst(14, 0, 0),
+				Duration:  2 * time.Hour,
+				Location:  tokyo,
+				WorkStart: 9 * time.Hour,
+				WorkEnd:   17 * time.Hour,
+				WorkDays:  []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+			},
+			expected: []Slot{
+				{Start: jst(13, 9, 0), End: jst(13, 11, 0)},
+				{Start: jst(13, 11, 0), End: jst(13, 13, 0)},
+				{Start: jst(13, 13, 0), End: jst(13, 15, 0)},
+				{Start: jst(13, 15, 0), End: jst(13, 17, 0)},
+			},
+		},
+		"calendar with undated to-do": {
+			calendars: func(t *testing.T) []*ical.Calendar {
+				busy := event(t, "busy", jst(6, 9, 0), jst(6, 15, 0))
+				todo := ical.NewToDo()
+				mustNoError(t, todo.SetUID(parameter.Container{}, types.NewText("todo")))
+				mustNoError(t, todo.SetDateTimeStamp(parameter.Container{}, types.DateTime(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))))
+				c := calendar(t, busy)
+				c.Components = append(c.Components, todo)
+				return []*ical.Calendar{c}
+			},
+			constraints: Constraints{
+				Start:     jst(6, 0, 0),
+				End:       jst(7, 0, 0),
+				Duration:  time.Hour,
+				Location:  tokyo,
+				WorkStart: 9 * time.Hour,
+				WorkEnd:   17 * time.Hour,
+			},
+			expected: []Slot{
+				{Start: jst(6, 15, 0), End: jst(6, 16, 0)},
+				{Start: jst(6, 16, 0), End: jst(6, 17, 0)},
+			},
+		},
+	}
+
+	for title, tt := range testcases {
+		tt := tt
+		t.Run(title, func(t *testing.T) {
+			t.Parallel()
+			got, err := FindSlots(tt.calendars(t), tt.constraints)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.expected, got); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func mustNoError(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
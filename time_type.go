@@ -2,24 +2,42 @@ package ical
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/knsh14/ical/errcode"
 	"github.com/knsh14/ical/parameter"
 	"github.com/knsh14/ical/types"
+	"github.com/morikuni/failure"
 )
 
 func NewTimeType(params parameter.Container, s string) (types.TimeValue, error) {
+	return NewTimeTypeInLocation(params, s, nil)
+}
+
+// NewTimeTypeInLocation returns DATE or DATE-TIME.
+// local time is interpreted in loc, or location of TZID if loc is nil.
+func NewTimeTypeInLocation(params parameter.Container, s string, loc *time.Location) (types.TimeValue, error) {
 	var tz string
 	tzs := params[parameter.TypeNameReferenceTimezone]
 	if len(tzs) > 0 {
 		tz = tzs[0].(*parameter.ReferenceTimezone).Value
 	}
-	dt, err := types.NewDateTime(s, tz)
-	if err == nil {
+	var dt types.DateTime
+	var dtErr error
+	if loc != nil {
+		dt, dtErr = types.NewDateTimeInLocation(s, loc)
+	} else {
+		dt, dtErr = types.NewDateTime(s, tz)
+	}
+	if dtErr == nil {
 		return dt, nil
 	}
 	t, err := types.NewDate(s)
 	if err == nil {
 		return t, nil
 	}
-	return nil, fmt.Errorf("%s cant convert DATE or DATE-TIME", s)
+	if failure.Is(dtErr, errcode.TimezoneNotFound) {
+		return nil, fmt.Errorf("convert %s to DATE-TIME: %w", s, dtErr)
+	}
+	return nil, errcode.Errorf(errcode.ValueFormat, "%s cant convert DATE or DATE-TIME", s)
 }
@@ -28,13 +28,47 @@ type Timezone struct {
 
 	XProperties    []*property.NonStandard
 	IANAProperties []*property.IANA
+
+	// PropertyOrder is order of property names in parsed stream.
+	// Decode writes properties in this order if it is set.
+	PropertyOrder []property.Name
 }
 
 func (tz *Timezone) implementCalender() {}
 func (tz *Timezone) Decode(w io.Writer) error {
-	fmt.Fprintf(w, "%s:%s", property.NameBegin, component.TypeTimezone)
-	fmt.Fprintf(w, "%s:%s", property.NameEnd, component.TypeTimezone)
-	return nil
+	if err := encodeBegin(w, component.TypeTimezone); err != nil {
+		return err
+	}
+	pw := newPropertyWriter(w, tz.PropertyOrder)
+	if tz.TimezoneIdentifier != nil {
+		pw.add(property.NameTimezoneIdentifier, tz.TimezoneIdentifier)
+	}
+	if tz.LastModified != nil {
+		pw.add(property.NameLastModified, tz.LastModified)
+	}
+	if tz.TimezoneURL != nil {
+		pw.add(property.NameTimezoneURL, tz.TimezoneURL)
+	}
+	for _, v := range tz.XProperties {
+		pw.add(property.Name(v.Name), v)
+	}
+	for _, v := range tz.IANAProperties {
+		pw.add(property.Name(v.Name), v)
+	}
+	if err := pw.flush(); err != nil {
+		return err
+	}
+	for _, v := range tz.Standards {
+		if err := v.Decode(w); err != nil {
+			return err
+		}
+	}
+	for _, v := range tz.Daylights {
+		if err := v.Decode(w); err != nil {
+			return err
+		}
+	}
+	return encodeEnd(w, component.TypeTimezone)
 }
 
 func (tz *Timezone) Validate() error {
@@ -110,12 +144,48 @@ type Standard struct {
 
 	XProperties    []*property.NonStandard
 	IANAProperties []*property.IANA
+
+	// PropertyOrder is order of property names in parsed stream.
+	// Decode writes properties in this order if it is set.
+	PropertyOrder []property.Name
 }
 
 func (s *Standard) Decode(w io.Writer) error {
-	fmt.Fprintf(w, "%s:%s", property.NameBegin, component.TypeStandard)
-	fmt.Fprintf(w, "%s:%s", property.NameEnd, component.TypeStandard)
-	return nil
+	if err := encodeBegin(w, component.TypeStandard); err != nil {
+		return err
+	}
+	pw := newPropertyWriter(w, s.PropertyOrder)
+	if s.DateTimeStart != nil {
+		pw.add(property.NameDateTimeStart, s.DateTimeStart)
+	}
+	if s.TimezoneOffsetFrom != nil {
+		pw.add(property.NameTimezoneOffsetFrom, s.TimezoneOffsetFrom)
+	}
+	if s.TimezoneOffsetTo != nil {
+		pw.add(property.NameTimezoneOffsetTo, s.TimezoneOffsetTo)
+	}
+	if s.RecurrenceRule != nil {
+		pw.add(property.NameRecurrenceRule, s.RecurrenceRule)
+	}
+	if s.Comment != nil {
+		pw.add(property.NameComment, s.Comment)
+	}
+	if s.RecurrenceDateTimes != nil {
+		pw.add(property.NameRecurrenceDateTimes, s.RecurrenceDateTimes)
+	}
+	if s.TimezoneName != nil {
+		pw.add(property.NameTimezoneName, s.TimezoneName)
+	}
+	for _, v := range s.XProperties {
+		pw.add(property.Name(v.Name), v)
+	}
+	for _, v := range s.IANAProperties {
+		pw.add(property.Name(v.Name), v)
+	}
+	if err := pw.flush(); err != nil {
+		return err
+	}
+	return encodeEnd(w, component.TypeStandard)
 }
 
 func (s *Standard) Validate() error {
@@ -235,12 +305,48 @@ type Daylight struct {
 
 	XProperties    []*property.NonStandard
 	IANAProperties []*property.IANA
+
+	// PropertyOrder is order of property names in parsed stream.
+	// Decode writes properties in this order if it is set.
+	PropertyOrder []property.Name
 }
 
 func (d *Daylight) Decode(w io.Writer) error {
-	fmt.Fprintf(w, "%s:%s", property.NameBegin, component.TypeDaylight)
-	fmt.Fprintf(w, "%s:%s", property.NameEnd, component.TypeDaylight)
-	return nil
+	if err := encodeBegin(w, component.TypeDaylight); err != nil {
+		return err
+	}
+	pw := newPropertyWriter(w, d.PropertyOrder)
+	if d.DateTimeStart != nil {
+		pw.add(property.NameDateTimeStart, d.DateTimeStart)
+	}
+	if d.TimezoneOffsetFrom != nil {
+		pw.add(property.NameTimezoneOffsetFrom, d.TimezoneOffsetFrom)
+	}
+	if d.TimezoneOffsetTo != nil {
+		pw.add(property.NameTimezoneOffsetTo, d.TimezoneOffsetTo)
+	}
+	if d.RecurrenceRule != nil {
+		pw.add(property.NameRecurrenceRule, d.RecurrenceRule)
+	}
+	if d.Comment != nil {
+		pw.add(property.NameComment, d.Comment)
+	}
+	if d.RecurrenceDateTimes != nil {
+		pw.add(property.NameRecurrenceDateTimes, d.RecurrenceDateTimes)
+	}
+	if d.TimezoneName != nil {
+		pw.add(property.NameTimezoneName, d.TimezoneName)
+	}
+	for _, v := range d.XProperties {
+		pw.add(property.Name(v.Name), v)
+	}
+	for _, v := range d.IANAProperties {
+		pw.add(property.Name(v.Name), v)
+	}
+	if err := pw.flush(); err != nil {
+		return err
+	}
+	return encodeEnd(w, component.TypeDaylight)
 }
 
 func (d *Daylight) Validate() error {
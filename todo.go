@@ -5,6 +5,7 @@ import (
 	"io"
 
 	"github.com/knsh14/ical/component"
+	"github.com/knsh14/ical/errcode"
 	"github.com/knsh14/ical/parameter"
 	"github.com/knsh14/ical/property"
 	"github.com/knsh14/ical/types"
@@ -61,171 +62,127 @@ type ToDo struct {
 
 	XProperties    []*property.NonStandard
 	IANAProperties []*property.IANA
+
+	// PropertyOrder is order of property names in parsed stream.
+	// Decode writes properties in this order if it is set.
+	PropertyOrder []property.Name
 }
 
 func (todo *ToDo) implementCalender() {}
 
 func (todo *ToDo) Decode(w io.Writer) error {
-	fmt.Fprintf(w, "%s:%s", property.NameBegin, component.TypeTODO)
-	if err := todo.UID.Decode(w); err != nil {
+	if err := encodeBegin(w, component.TypeTODO); err != nil {
 		return err
 	}
-	if err := todo.DateTimeStamp.Decode(w); err != nil {
-		return err
+	pw := newPropertyWriter(w, todo.PropertyOrder)
+	if todo.UID != nil {
+		pw.add(property.NameUID, todo.UID)
+	}
+	if todo.DateTimeStamp != nil {
+		pw.add(property.NameDateTimeStamp, todo.DateTimeStamp)
 	}
-
 	if todo.Class != nil {
-		if err := todo.Class.Decode(w); err != nil {
-			return err
-		}
+		pw.add(property.NameClass, todo.Class)
 	}
 	if todo.DateTimeCompleted != nil {
-		if err := todo.DateTimeCompleted.Decode(w); err != nil {
-			return err
-		}
+		pw.add(property.NameDateTimeCompleted, todo.DateTimeCompleted)
 	}
 	if todo.DateTimeCreated != nil {
-		if err := todo.DateTimeCreated.Decode(w); err != nil {
-			return err
-		}
+		pw.add(property.NameDateTimeCreated, todo.DateTimeCreated)
 	}
 	if todo.Description != nil {
-		if err := todo.Description.Decode(w); err != nil {
-			return err
-		}
+		pw.add(property.NameDescription, todo.Description)
 	}
 	if todo.DateTimeStart != nil {
-		if err := todo.DateTimeStart.Decode(w); err != nil {
-			return err
-		}
+		pw.add(property.NameDateTimeStart, todo.DateTimeStart)
 	}
 	if todo.Geo != nil {
-		if err := todo.Geo.Decode(w); err != nil {
-			return err
-		}
+		pw.add(property.NameGeo, todo.Geo)
 	}
 	if todo.LastModified != nil {
-		if err := todo.LastModified.Decode(w); err != nil {
-			return err
-		}
+		pw.add(property.NameLastModified, todo.LastModified)
 	}
 	if todo.Location != nil {
-		if err := todo.Location.Decode(w); err != nil {
-			return err
-		}
+		pw.add(property.NameLocation, todo.Location)
 	}
 	if todo.Organizer != nil {
-		if err := todo.Organizer.Decode(w); err != nil {
-			return err
-		}
+		pw.add(property.NameOrganizer, todo.Organizer)
 	}
 	if todo.PercentComplete != nil {
-		if err := todo.PercentComplete.Decode(w); err != nil {
-			return err
-		}
+		pw.add(property.NamePercentComplete, todo.PercentComplete)
 	}
 	if todo.Priority != nil {
-		if err := todo.Priority.Decode(w); err != nil {
-			return err
-		}
+		pw.add(property.NamePriority, todo.Priority)
 	}
 	if todo.RecurrenceID != nil {
-		if err := todo.RecurrenceID.Decode(w); err != nil {
-			return err
-		}
+		pw.add(property.NameRecurrenceID, todo.RecurrenceID)
 	}
 	if todo.SequenceNumber != nil {
-		if err := todo.SequenceNumber.Decode(w); err != nil {
-			return err
-		}
+		pw.add(property.NameSequenceNumber, todo.SequenceNumber)
 	}
 	if todo.Status != nil {
-		if err := todo.Status.Decode(w); err != nil {
-			return err
-		}
+		pw.add(property.NameStatus, todo.Status)
 	}
 	if todo.Summary != nil {
-		if err := todo.Summary.Decode(w); err != nil {
-			return err
-		}
+		pw.add(property.NameSummary, todo.Summary)
 	}
 	if todo.URL != nil {
-		if err := todo.URL.Decode(w); err != nil {
-			return err
-		}
+		pw.add(property.NameURL, todo.URL)
 	}
 	if todo.RecurrenceRule != nil {
-		if err := todo.RecurrenceRule.Decode(w); err != nil {
-			return err
-		}
+		pw.add(property.NameRecurrenceRule, todo.RecurrenceRule)
 	}
 	if todo.DateTimeDue != nil {
-		if err := todo.DateTimeDue.Decode(w); err != nil {
-			return err
-		}
+		pw.add(property.NameDateTimeDue, todo.DateTimeDue)
 	}
 	if todo.Duration != nil {
-		if err := todo.Duration.Decode(w); err != nil {
-			return err
-		}
+		pw.add(property.NameDuration, todo.Duration)
 	}
 	for _, v := range todo.Attachments {
-		if err := v.Decode(w); err != nil {
-			return err
-		}
+		pw.add(property.NameAttachment, v)
 	}
 	for _, v := range todo.Attendees {
-		if err := v.Decode(w); err != nil {
-			return err
-		}
+		pw.add(property.NameAttendee, v)
 	}
 	for _, v := range todo.Categories {
-		if err := v.Decode(w); err != nil {
-			return err
-		}
+		pw.add(property.NameCategories, v)
 	}
 	for _, v := range todo.Comments {
-		if err := v.Decode(w); err != nil {
-			return err
-		}
+		pw.add(property.NameComment, v)
 	}
 	for _, v := range todo.Contacts {
-		if err := v.Decode(w); err != nil {
-			return err
-		}
+		pw.add(property.NameContact, v)
 	}
 	for _, v := range todo.ExceptionDateTimes {
-		if err := v.Decode(w); err != nil {
-			return err
-		}
+		pw.add(property.NameExceptionDateTimes, v)
 	}
 	for _, v := range todo.RequestStatus {
-		if err := v.Decode(w); err != nil {
-			return err
-		}
+		pw.add(property.NameRequestStatus, v)
 	}
 	for _, v := range todo.RelatedTos {
-		if err := v.Decode(w); err != nil {
-			return err
-		}
+		pw.add(property.NameRelatedTo, v)
 	}
 	for _, v := range todo.Resources {
-		if err := v.Decode(w); err != nil {
-			return err
-		}
+		pw.add(property.NameResources, v)
 	}
 	for _, v := range todo.RecurrenceDateTimes {
-		if err := v.Decode(w); err != nil {
-			return err
-		}
+		pw.add(property.NameRecurrenceDateTimes, v)
+	}
+	for _, v := range todo.XProperties {
+		pw.add(property.Name(v.Name), v)
+	}
+	for _, v := range todo.IANAProperties {
+		pw.add(property.Name(v.Name), v)
+	}
+	if err := pw.flush(); err != nil {
+		return err
 	}
 	for _, v := range todo.Alarms {
 		if err := v.Decode(w); err != nil {
 			return err
 		}
 	}
-	fmt.Fprintf(w, "%s:%s", property.NameEnd, component.TypeTODO)
-	return nil
+	return encodeEnd(w, component.TypeTODO)
 }
 
 func (todo *ToDo) Validate() error {
@@ -239,7 +196,7 @@ func (todo *ToDo) Validate() error {
 		return NewValidationError(component.TypeTODO, property.NameDateTimeStamp, "must not to be nil")
 	}
 	if todo.DateTimeDue != nil && todo.Duration != nil {
-		return fmt.Errorf("DateTimeEnd and Duraion are not nil")
+		return errcode.Errorf(errcode.Cardinality, "DateTimeEnd and Duraion are not nil")
 	}
 	for _, alarm := range todo.Alarms {
 		if err := alarm.Validate(); err != nil {
@@ -431,10 +388,10 @@ func (todo *ToDo) SetSequenceNumber(params parameter.Container, value types.Inte
 
 func (todo *ToDo) SetStatus(params parameter.Container, value types.Text) error {
 	if todo.Status != nil {
-		return todo.Status.SetStatus(params, value, component.TypeEvent)
+		return todo.Status.SetStatus(params, value, component.TypeTODO)
 	}
 	s := &property.Status{}
-	if err := s.SetStatus(params, value, component.TypeEvent); err != nil {
+	if err := s.SetStatus(params, value, component.TypeTODO); err != nil {
 		return err
 	}
 	todo.Status = s
@@ -0,0 +1,185 @@
+package ical
+
+import (
+	"time"
+
+	"github.com/knsh14/ical/component"
+	"github.com/knsh14/ical/parameter"
+	"github.com/knsh14/ical/property"
+	"github.com/knsh14/ical/types"
+)
+
+// ToDoBuilder builds VTODO from time.Time, time.Duration and strings.
+// the first error in building is returned by Build.
+type ToDoBuilder struct {
+	todo *ToDo
+	err  error
+}
+
+// NewToDoBuilder returns ToDoBuilder of empty VTODO
+func NewToDoBuilder() *ToDoBuilder {
+	return &ToDoBuilder{todo: NewToDo()}
+}
+
+func (b *ToDoBuilder) set(pname property.Name, err error) *ToDoBuilder {
+	if err != nil && b.err == nil {
+		b.err = buildError(pname, err)
+	}
+	return b
+}
+
+// UID sets UID
+func (b *ToDoBuilder) UID(uid string) *ToDoBuilder {
+	return b.set(property.NameUID, b.todo.SetUID(parameter.Container{}, types.NewText(uid)))
+}
+
+// Stamp sets DTSTAMP, Build sets current time if it is not set
+func (b *ToDoBuilder) Stamp(t time.Time) *ToDoBuilder {
+	return b.set(property.NameDateTimeStamp, b.todo.SetDateTimeStamp(parameter.Container{}, types.DateTime(t.UTC().Truncate(time.Second))))
+}
+
+// Start sets DTSTART of DATE-TIME, TZID is name of location of t
+func (b *ToDoBuilder) Start(t time.Time) *ToDoBuilder {
+	params, dt, err := dateTimeOf(t)
+	if err != nil {
+		return b.set(property.NameDateTimeStart, err)
+	}
+	return b.set(property.NameDateTimeStart, b.todo.SetDateTimeStart(params, dt))
+}
+
+// StartDate sets DTSTART of DATE
+func (b *ToDoBuilder) StartDate(t time.Time) *ToDoBuilder {
+	params, d := dateOf(t)
+	return b.set(property.NameDateTimeStart, b.todo.SetDateTimeStart(params, d))
+}
+
+// Due sets DUE of DATE-TIME, TZID is name of location of t
+func (b *ToDoBuilder) Due(t time.Time) *ToDoBuilder {
+	params, dt, err := dateTimeOf(t)
+	if err != nil {
+		return b.set(property.NameDateTimeDue, err)
+	}
+	return b.set(property.NameDateTimeDue, b.todo.SetDateTimeDue(params, dt))
+}
+
+// DueDate sets DUE of DATE
+func (b *ToDoBuilder) DueDate(t time.Time) *ToDoBuilder {
+	params, d := dateOf(t)
+	return b.set(property.NameDateTimeDue, b.todo.SetDateTimeDue(params, d))
+}
+
+// Duration sets DURATION
+func (b *ToDoBuilder) Duration(d time.Duration) *ToDoBuilder {
+	return b.set(property.NameDuration, b.todo.SetDuration(parameter.Container{}, durationOf(d)))
+}
+
+// Summary sets SUMMARY
+func (b *ToDoBuilder) Summary(s string, ps ...Param) *ToDoBuilder {
+	params, err := newParams(component.TypeTODO, ps)
+	if err != nil {
+		return b.set(property.NameSummary, err)
+	}
+	return b.set(property.NameSummary, b.todo.SetSummary(params, types.NewText(s)))
+}
+
+// Description sets DESCRIPTION
+func (b *ToDoBuilder) Description(s string, ps ...Param) *ToDoBuilder {
+	params, err := newParams(component.TypeTODO, ps)
+	if err != nil {
+		return b.set(property.NameDescription, err)
+	}
+	return b.set(property.NameDescription, b.todo.SetDescription(params, types.NewText(s)))
+}
+
+// Location sets LOCATION
+func (b *ToDoBuilder) Location(s string, ps ...Param) *ToDoBuilder {
+	params, err := newParams(component.TypeTODO, ps)
+	if err != nil {
+		return b.set(property.NameLocation, err)
+	}
+	return b.set(property.NameLocation, b.todo.SetLocation(params, types.NewText(s)))
+}
+
+// Organizer sets ORGANIZER of uri such as mailto:alice@example.com
+func (b *ToDoBuilder) Organizer(uri string, ps ...Param) *ToDoBuilder {
+	params, cua, err := addressOf(component.TypeTODO, uri, ps)
+	if err != nil {
+		return b.set(property.NameOrganizer, err)
+	}
+	return b.set(property.NameOrganizer, b.todo.SetOrganizer(params, cua))
+}
+
+// Attendee adds ATTENDEE of uri such as mailto:bob@example.com
+func (b *ToDoBuilder) Attendee(uri string, ps ...Param) *ToDoBuilder {
+	params, cua, err := addressOf(component.TypeTODO, uri, ps)
+	if err != nil {
+		return b.set(property.NameAttendee, err)
+	}
+	return b.set(property.NameAttendee, b.todo.AddAttendee(params, cua))
+}
+
+// Categories adds CATEGORIES
+func (b *ToDoBuilder) Categories(categories ...string) *ToDoBuilder {
+	return b.set(property.NameCategories, b.todo.AddCategories(parameter.Container{}, textsOf(categories)))
+}
+
+// Status sets STATUS such as NEEDS-ACTION
+func (b *ToDoBuilder) Status(s property.StatusType) *ToDoBuilder {
+	return b.set(property.NameStatus, b.todo.SetStatus(parameter.Container{}, types.NewText(string(s))))
+}
+
+// Sequence sets SEQUENCE
+func (b *ToDoBuilder) Sequence(n int) *ToDoBuilder {
+	return b.set(property.NameSequenceNumber, b.todo.SetSequenceNumber(parameter.Container{}, types.Integer(n)))
+}
+
+// Priority sets PRIORITY
+func (b *ToDoBuilder) Priority(n int) *ToDoBuilder {
+	return b.set(property.NamePriority, b.todo.SetPriority(parameter.Container{}, types.Integer(n)))
+}
+
+// PercentComplete sets PERCENT-COMPLETE
+func (b *ToDoBuilder) PercentComplete(n int) *ToDoBuilder {
+	return b.set(property.NamePercentComplete, b.todo.SetPercentComplete(parameter.Container{}, types.Integer(n)))
+}
+
+// URL sets URL
+func (b *ToDoBuilder) URL(s string) *ToDoBuilder {
+	u, err := types.NewURI(s)
+	if err != nil {
+		return b.set(property.NameURL, err)
+	}
+	return b.set(property.NameURL, b.todo.SetURL(parameter.Container{}, u))
+}
+
+// RecurrenceRule sets RRULE such as FREQ=WEEKLY;COUNT=10
+func (b *ToDoBuilder) RecurrenceRule(rule string) *ToDoBuilder {
+	rr, err := types.NewRecurrenceRule(rule)
+	if err != nil {
+		return b.set(property.NameRecurrenceRule, err)
+	}
+	return b.set(property.NameRecurrenceRule, b.todo.SetRecurrenceRule(parameter.Container{}, rr))
+}
+
+// Alarm adds VALARM
+func (b *ToDoBuilder) Alarm(a Alarm) *ToDoBuilder {
+	b.todo.AddAlarm(a)
+	return b
+}
+
+// Build returns the todo.
+// it returns error of building, or Findings of errors which Check finds in the todo.
+func (b *ToDoBuilder) Build() (*ToDo, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	if b.todo.DateTimeStamp == nil {
+		b.Stamp(time.Now())
+	}
+	ch := &checker{}
+	ch.checkToDo(nil, b.todo)
+	if errs := ch.findings.Errors(); len(errs) > 0 {
+		return nil, errs
+	}
+	return b.todo, nil
+}
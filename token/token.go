@@ -3,6 +3,8 @@ package token
 type Token struct {
 	Type  Type
 	Value string
+	// Pos is offset of the first rune of the token in input, it starts with 0
+	Pos int
 }
 
 type Type string
@@ -0,0 +1,485 @@
+package types
+
+import (
+	"sort"
+	"time"
+)
+
+// maxRecurrenceGapYears is limit of years without any occurrence.
+// gregorian calendar repeats every 400 years, so rule which has no occurrence in it never matches.
+const maxRecurrenceGapYears = 400
+
+var frequencyRank = map[FrequencyPattern]int{
+	FrequencyPatternSecondly: 0,
+	FrequencyPatternMinutely: 1,
+	FrequencyPatternHourly:   2,
+	FrequencyPatternDaily:    3,
+	FrequencyPatternWeekly:   4,
+	FrequencyPatternMonthly:  5,
+	FrequencyPatternYearly:   6,
+}
+
+var weekDays = map[WeekDayPattern]time.Weekday{
+	WeekDayPatternSunday:    time.Sunday,
+	WeekDayPatternMonday:    time.Monday,
+	WeekDayPatternTuesday:   time.Tuesday,
+	WeekDayPatternWednesday: time.Wednesday,
+	WeekDayPatternThursday:  time.Thursday,
+	WeekDayPatternFriday:    time.Friday,
+	WeekDayPatternSaturday:  time.Saturday,
+}
+
+// RecurrenceIterator generates occurrences of RecurrenceRule in order
+// https://tools.ietf.org/html/rfc5545#section-3.3.10
+type RecurrenceIterator struct {
+	rule  RecurrenceRule
+	start time.Time
+	// base is wall clock of start in UTC, every calculation is done in it
+	base     time.Time
+	until    time.Time
+	hasUntil bool
+	wkst     time.Weekday
+
+	period    int64
+	lastFound time.Time
+	// last is the last occurrence which Next returned
+	last    time.Time
+	buf     []time.Time
+	count   int64
+	started bool
+	done    bool
+}
+
+// Iterator returns RecurrenceIterator which starts at dtstart.
+// dtstart is always the first occurrence and is counted by COUNT.
+// occurrences are in the location of dtstart.
+func (rr RecurrenceRule) Iterator(dtstart time.Time) *RecurrenceIterator {
+	dtstart = dtstart.Truncate(time.Second)
+	it := &RecurrenceIterator{
+		rule:  rr,
+		start: dtstart,
+		base:  time.Date(dtstart.Year(), dtstart.Month(), dtstart.Day(), dtstart.Hour(), dtstart.Minute(), dtstart.Second(), 0, time.UTC),
+		wkst:  time.Monday,
+	}
+	if it.rule.Interval <= 0 {
+		it.rule.Interval = 1
+	}
+	if d, ok := weekDays[rr.WeekDay]; ok {
+		it.wkst = d
+	}
+	if rr.EndDate != nil {
+		it.until, it.hasUntil = untilTime(rr.EndDate, dtstart.Location()), true
+	}
+	it.setDefaults()
+	it.lastFound = it.base
+	return it
+}
+
+// Between returns occurrences t which satisfy start <= t < end
+func (rr RecurrenceRule) Between(dtstart, start, end time.Time) []time.Time {
+	var res []time.Time
+	it := rr.Iterator(dtstart)
+	for t, ok := it.Next(); ok && t.Before(end); t, ok = it.Next() {
+		if !t.Before(start) {
+			res = append(res, t)
+		}
+	}
+	return res
+}
+
+// Next returns next occurrence.
+// it returns false if there is no more occurrence.
+func (it *RecurrenceIterator) Next() (time.Time, bool) {
+	if !it.started {
+		it.started = true
+		if it.hasUntil && it.start.After(it.until) {
+			it.done = true
+			return time.Time{}, false
+		}
+		it.count++
+		it.last = it.start
+		return it.start, true
+	}
+	for !it.done {
+		if it.rule.Count > 0 && it.count >= it.rule.Count {
+			it.done = true
+			break
+		}
+		if len(it.buf) == 0 {
+			if !it.fill() {
+				it.done = true
+			}
+			continue
+		}
+		t := it.buf[0]
+		it.buf = it.buf[1:]
+		// wall clocks in a gap of DST may be the same instant as the next one
+		if !t.After(it.last) {
+			continue
+		}
+		if it.hasUntil && t.After(it.until) {
+			it.done = true
+			break
+		}
+		it.count++
+		it.last = t
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+func untilTime(v TimeValue, loc *time.Location) time.Time {
+	switch u := v.(type) {
+	case Date:
+		d := time.Time(u)
+		return wallTime(time.Date(d.Year(), d.Month(), d.Day(), 23, 59, 59, 0, time.UTC), loc)
+	case DateTime:
+		t := time.Time(u)
+		if t.Location() == time.UTC {
+			return t
+		}
+		return wallTime(t, loc)
+	}
+	return time.Time{}
+}
+
+// wallTime returns time in loc whose wall clock is the same as t.
+// wall clock in a gap of DST is interpreted with the offset before the gap, so it is shifted forward by the gap,
+// and wall clock which occurs twice is the first one.
+// https://tools.ietf.org/html/rfc5545#section-3.3.5
+func wallTime(t time.Time, loc *time.Location) time.Time {
+	res := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, loc)
+	wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC).Unix()
+	// offsets of a day before and after, transitions of DST are far more apart than it
+	_, before := time.Unix(wall-24*60*60, 0).In(loc).Zone()
+	_, after := time.Unix(wall+24*60*60, 0).In(loc).Zone()
+	if before == after {
+		return res
+	}
+	first := time.Unix(wall-int64(before), 0).In(loc)
+	if sameWall(first, t) {
+		return first
+	}
+	if second := time.Unix(wall-int64(after), 0).In(loc); sameWall(second, t) {
+		return second
+	}
+	return first
+}
+
+func sameWall(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	ah, amin, as := a.Clock()
+	bh, bmin, bs := b.Clock()
+	return ay == by && am == bm && ad == bd && ah == bh && amin == bmin && as == bs
+}
+
+// setDefaults fills BYxxx rule parts which are derived from DTSTART
+func (it *RecurrenceIterator) setDefaults() {
+	rr := &it.rule
+	if len(rr.ByWeekNo) == 0 && len(rr.ByYearDay) == 0 && len(rr.ByMonthDay) == 0 && len(rr.ByDay) == 0 {
+		switch rr.Frequency {
+		case FrequencyPatternYearly:
+			if len(rr.ByMonth) == 0 {
+				rr.ByMonth = []int64{int64(it.base.Month())}
+			}
+			rr.ByMonthDay = []int64{int64(it.base.Day())}
+		case FrequencyPatternMonthly:
+			rr.ByMonthDay = []int64{int64(it.base.Day())}
+		case FrequencyPatternWeekly:
+			for p, d := range weekDays {
+				if d == it.base.Weekday() {
+					rr.ByDay = []WeekDay{{Day: p}}
+				}
+			}
+		}
+	}
+	rank := frequencyRank[rr.Frequency]
+	if len(rr.ByHour) == 0 && rank > frequencyRank[FrequencyPatternHourly] {
+		rr.ByHour = []int64{int64(it.base.Hour())}
+	}
+	if len(rr.ByMinute) == 0 && rank > frequencyRank[FrequencyPatternMinutely] {
+		rr.ByMinute = []int64{int64(it.base.Minute())}
+	}
+	if len(rr.BySecond) == 0 && rank > frequencyRank[FrequencyPatternSecondly] {
+		rr.BySecond = []int64{int64(it.base.Second())}
+	}
+}
+
+// fill sets occurrences of next non empty period to buf
+func (it *RecurrenceIterator) fill() bool {
+	if _, ok := frequencyRank[it.rule.Frequency]; !ok {
+		return false
+	}
+	gap := maxRecurrenceGapYears
+	if frequencyRank[it.rule.Frequency] >= frequencyRank[FrequencyPatternDaily] {
+		gap *= int(it.rule.Interval)
+	}
+	for {
+		start, candidates, next := it.expand(it.period)
+		it.period = next
+		if start.After(it.lastFound.AddDate(gap, 0, 0)) {
+			return false
+		}
+		if it.hasUntil && it.toLocation(start).After(it.until) {
+			return false
+		}
+		if len(candidates) == 0 {
+			continue
+		}
+		it.lastFound = start
+		for _, c := range it.setPos(candidates) {
+			it.buf = append(it.buf, it.toLocation(c))
+		}
+		return true
+	}
+}
+
+func (it *RecurrenceIterator) toLocation(t time.Time) time.Time {
+	return wallTime(t, it.start.Location())
+}
+
+// expand returns start of k-th period, candidates in it and next period to expand
+func (it *RecurrenceIterator) expand(k int64) (time.Time, []time.Time, int64) {
+	n := int(k * it.rule.Interval)
+	b := it.base
+	var days []time.Time
+	switch it.rule.Frequency {
+	case FrequencyPatternYearly:
+		first := time.Date(b.Year()+n, time.January, 1, 0, 0, 0, 0, time.UTC)
+		days = daysBetween(first, first.AddDate(1, 0, 0))
+	case FrequencyPatternMonthly:
+		first := time.Date(b.Year(), b.Month()+time.Month(n), 1, 0, 0, 0, 0, time.UTC)
+		days = daysBetween(first, first.AddDate(0, 1, 0))
+	case FrequencyPatternWeekly:
+		offset := (int(b.Weekday()) - int(it.wkst) + 7) % 7
+		first := time.Date(b.Year(), b.Month(), b.Day()-offset+7*n, 0, 0, 0, 0, time.UTC)
+		days = daysBetween(first, first.AddDate(0, 0, 7))
+	case FrequencyPatternDaily:
+		days = []time.Time{time.Date(b.Year(), b.Month(), b.Day()+n, 0, 0, 0, 0, time.UTC)}
+	default:
+		return it.expandTime(k)
+	}
+
+	var candidates []time.Time
+	for _, d := range days {
+		if !it.matchDate(d) {
+			continue
+		}
+		for _, h := range sortedInts(it.rule.ByHour) {
+			for _, m := range sortedInts(it.rule.ByMinute) {
+				for _, s := range sortedInts(it.rule.BySecond) {
+					candidates = append(candidates, time.Date(d.Year(), d.Month(), d.Day(), int(h), int(m), int(s), 0, time.UTC))
+				}
+			}
+		}
+	}
+	return days[0], candidates, k + 1
+}
+
+// expandTime expands period of HOURLY, MINUTELY and SECONDLY.
+// it skips periods in a day, an hour or a minute which never match.
+func (it *RecurrenceIterator) expandTime(k int64) (time.Time, []time.Time, int64) {
+	var unit int64
+	switch it.rule.Frequency {
+	case FrequencyPatternHourly:
+		unit = 60 * 60
+	case FrequencyPatternMinutely:
+		unit = 60
+	default:
+		unit = 1
+	}
+	// calculate in seconds, time.Duration overflows in about 290 years
+	step := it.rule.Interval * unit
+	cur := time.Unix(it.base.Unix()+k*step, 0).UTC()
+	// skipTo returns the first period at or after t
+	skipTo := func(t time.Time) int64 {
+		n := (t.Unix() - it.base.Unix() + step - 1) / step
+		if n <= k {
+			return k + 1
+		}
+		return n
+	}
+
+	day := time.Date(cur.Year(), cur.Month(), cur.Day(), 0, 0, 0, 0, time.UTC)
+	if !it.matchDate(day) {
+		return cur, nil, skipTo(day.AddDate(0, 0, 1))
+	}
+	if len(it.rule.ByHour) > 0 && !containsInt(it.rule.ByHour, int64(cur.Hour())) {
+		return cur, nil, skipTo(cur.Truncate(time.Hour).Add(time.Hour))
+	}
+	minutes := []int64{int64(cur.Minute())}
+	if it.rule.Frequency == FrequencyPatternHourly {
+		minutes = sortedInts(it.rule.ByMinute)
+	} else if len(it.rule.ByMinute) > 0 && !containsInt(it.rule.ByMinute, int64(cur.Minute())) {
+		return cur, nil, skipTo(cur.Truncate(time.Minute).Add(time.Minute))
+	}
+	seconds := []int64{int64(cur.Second())}
+	if it.rule.Frequency != FrequencyPatternSecondly {
+		seconds = sortedInts(it.rule.BySecond)
+	} else if len(it.rule.BySecond) > 0 && !containsInt(it.rule.BySecond, int64(cur.Second())) {
+		return cur, nil, k + 1
+	}
+
+	var candidates []time.Time
+	for _, m := range minutes {
+		for _, s := range seconds {
+			candidates = append(candidates, time.Date(cur.Year(), cur.Month(), cur.Day(), cur.Hour(), int(m), int(s), 0, time.UTC))
+		}
+	}
+	return cur, candidates, k + 1
+}
+
+// matchDate reports whether d matches BYMONTH, BYWEEKNO, BYYEARDAY, BYMONTHDAY and BYDAY
+func (it *RecurrenceIterator) matchDate(d time.Time) bool {
+	rr := it.rule
+	if len(rr.ByMonth) > 0 && !containsInt(rr.ByMonth, int64(d.Month())) {
+		return false
+	}
+	if len(rr.ByWeekNo) > 0 {
+		week, weeks := weekNumber(d, it.wkst)
+		if !matchOrdinal(rr.ByWeekNo, week, weeks) {
+			return false
+		}
+	}
+	if len(rr.ByYearDay) > 0 && !matchOrdinal(rr.ByYearDay, d.YearDay(), daysInYear(d.Year())) {
+		return false
+	}
+	if len(rr.ByMonthDay) > 0 && !matchOrdinal(rr.ByMonthDay, d.Day(), daysInMonth(d.Year(), d.Month())) {
+		return false
+	}
+	if len(rr.ByDay) > 0 && !it.matchWeekDay(d) {
+		return false
+	}
+	return true
+}
+
+// matchWeekDay reports whether d matches BYDAY.
+// numeric value means nth week day in the month for MONTHLY or YEARLY with BYMONTH,
+// in the year for YEARLY, and it is ignored for other frequency.
+func (it *RecurrenceIterator) matchWeekDay(d time.Time) bool {
+	for _, wd := range it.rule.ByDay {
+		if weekDays[wd.Day] != d.Weekday() {
+			continue
+		}
+		if wd.Week == 0 {
+			return true
+		}
+		switch {
+		case it.rule.Frequency == FrequencyPatternMonthly || (it.rule.Frequency == FrequencyPatternYearly && len(it.rule.ByMonth) > 0):
+			if matchNth(wd.Week, d.Day(), daysInMonth(d.Year(), d.Month())) {
+				return true
+			}
+		case it.rule.Frequency == FrequencyPatternYearly:
+			if matchNth(wd.Week, d.YearDay(), daysInYear(d.Year())) {
+				return true
+			}
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+// setPos applies BYSETPOS to sorted candidates
+func (it *RecurrenceIterator) setPos(candidates []time.Time) []time.Time {
+	if len(it.rule.BySetPos) == 0 {
+		return candidates
+	}
+	var res []time.Time
+	for _, pos := range it.rule.BySetPos {
+		i := int(pos) - 1
+		if pos < 0 {
+			i = len(candidates) + int(pos)
+		}
+		if i < 0 || len(candidates) <= i {
+			continue
+		}
+		res = append(res, candidates[i])
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Before(res[j]) })
+	uniq := res[:0]
+	for i, t := range res {
+		if i == 0 || !t.Equal(res[i-1]) {
+			uniq = append(uniq, t)
+		}
+	}
+	return uniq
+}
+
+// weekNumber returns week number of d and number of weeks in the year.
+// week 1 is the first week which contains at least 4 days of the year.
+func weekNumber(d time.Time, wkst time.Weekday) (int, int) {
+	y := d.Year()
+	start := firstWeekStart(y, wkst)
+	if d.Before(start) {
+		y--
+		start = firstWeekStart(y, wkst)
+	} else if next := firstWeekStart(y+1, wkst); !d.Before(next) {
+		y++
+		start = next
+	}
+	weeks := int(firstWeekStart(y+1, wkst).Sub(start).Hours()) / 24 / 7
+	return int(d.Sub(start).Hours())/24/7 + 1, weeks
+}
+
+func firstWeekStart(y int, wkst time.Weekday) time.Time {
+	jan4 := time.Date(y, time.January, 4, 0, 0, 0, 0, time.UTC)
+	return jan4.AddDate(0, 0, -((int(jan4.Weekday()) - int(wkst) + 7) % 7))
+}
+
+func daysBetween(from, to time.Time) []time.Time {
+	var res []time.Time
+	for d := from; d.Before(to); d = d.AddDate(0, 0, 1) {
+		res = append(res, d)
+	}
+	return res
+}
+
+func daysInMonth(y int, m time.Month) int {
+	return time.Date(y, m+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+func daysInYear(y int) int {
+	return time.Date(y, time.December, 31, 0, 0, 0, 0, time.UTC).YearDay()
+}
+
+// matchOrdinal reports whether v in 1..n matches one of list which may be negative
+func matchOrdinal(list []int64, v, n int) bool {
+	for _, l := range list {
+		if l == int64(v) || l == int64(v-n-1) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchNth reports whether day v in 1..n is nth week day
+func matchNth(nth int64, v, n int) bool {
+	if nth > 0 {
+		return int64((v-1)/7+1) == nth
+	}
+	return int64(-((n-v)/7 + 1)) == nth
+}
+
+func containsInt(list []int64, v int64) bool {
+	for _, l := range list {
+		if l == v {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedInts returns sorted list without duplicates
+func sortedInts(list []int64) []int64 {
+	res := append([]int64(nil), list...)
+	sort.Slice(res, func(i, j int) bool { return res[i] < res[j] })
+	uniq := res[:0]
+	for i, v := range res {
+		if i == 0 || v != res[i-1] {
+			uniq = append(uniq, v)
+		}
+	}
+	return uniq
+}
@@ -0,0 +1,269 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRecurrenceRule_Iterator(t *testing.T) {
+	t.Parallel()
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	utc := func(y int, m time.Month, d, h, min int) time.Time {
+		return time.Date(y, m, d, h, min, 0, 0, time.UTC)
+	}
+	testcases := map[string]struct {
+		rule     string
+		dtstart  time.Time
+		limit    int
+		expected []time.Time
+	}{
+		"daily for 10 occurrences": {
+			rule:    "FREQ=DAILY;COUNT=10",
+			dtstart: utc(1997, 9, 2, 9, 0),
+			limit:   100,
+			expected: []time.Time{
+				utc(1997, 9, 2, 9, 0), utc(1997, 9, 3, 9, 0), utc(1997, 9, 4, 9, 0), utc(1997, 9, 5, 9, 0), utc(1997, 9, 6, 9, 0),
+				utc(1997, 9, 7, 9, 0), utc(1997, 9, 8, 9, 0), utc(1997, 9, 9, 9, 0), utc(1997, 9, 10, 9, 0), utc(1997, 9, 11, 9, 0),
+			},
+		},
+		"daily until date": {
+			rule:    "FREQ=DAILY;UNTIL=19970904",
+			dtstart: utc(1997, 9, 2, 9, 0),
+			limit:   100,
+			expected: []time.Time{
+				utc(1997, 9, 2, 9, 0), utc(1997, 9, 3, 9, 0), utc(1997, 9, 4, 9, 0),
+			},
+		},
+		"weekly on tuesday and thursday until": {
+			rule:    "FREQ=WEEKLY;UNTIL=19971007T000000Z;WKST=SU;BYDAY=TU,TH",
+			dtstart: utc(1997, 9, 2, 9, 0),
+			limit:   100,
+			expected: []time.Time{
+				utc(1997, 9, 2, 9, 0), utc(1997, 9, 4, 9, 0), utc(1997, 9, 9, 9, 0), utc(1997, 9, 11, 9, 0), utc(1997, 9, 16, 9, 0),
+				utc(1997, 9, 18, 9, 0), utc(1997, 9, 23, 9, 0), utc(1997, 9, 25, 9, 0), utc(1997, 9, 30, 9, 0), utc(1997, 10, 2, 9, 0),
+			},
+		},
+		"week start monday": {
+			rule:    "FREQ=WEEKLY;INTERVAL=2;COUNT=4;BYDAY=TU,SU;WKST=MO",
+			dtstart: utc(1997, 8, 5, 9, 0),
+			limit:   100,
+			expected: []time.Time{
+				utc(1997, 8, 5, 9, 0), utc(1997, 8, 10, 9, 0), utc(1997, 8, 19, 9, 0), utc(1997, 8, 24, 9, 0),
+			},
+		},
+		"week start sunday": {
+			rule:    "FREQ=WEEKLY;INTERVAL=2;COUNT=4;BYDAY=TU,SU;WKST=SU",
+			dtstart: utc(1997, 8, 5, 9, 0),
+			limit:   100,
+			expected: []time.Time{
+				utc(1997, 8, 5, 9, 0), utc(1997, 8, 17, 9, 0), utc(1997, 8, 19, 9, 0), utc(1997, 8, 31, 9, 0),
+			},
+		},
+		"monthly on the first friday": {
+			rule:    "FREQ=MONTHLY;COUNT=10;BYDAY=1FR",
+			dtstart: utc(1997, 9, 5, 9, 0),
+			limit:   100,
+			expected: []time.Time{
+				utc(1997, 9, 5, 9, 0), utc(1997, 10, 3, 9, 0), utc(1997, 11, 7, 9, 0), utc(1997, 12, 5, 9, 0), utc(1998, 1, 2, 9, 0),
+				utc(1998, 2, 6, 9, 0), utc(1998, 3, 6, 9, 0), utc(1998, 4, 3, 9, 0), utc(1998, 5, 1, 9, 0), utc(1998, 6, 5, 9, 0),
+			},
+		},
+		"monthly on the second to last monday": {
+			rule:    "FREQ=MONTHLY;COUNT=6;BYDAY=-2MO",
+			dtstart: utc(1997, 9, 22, 9, 0),
+			limit:   100,
+			expected: []time.Time{
+				utc(1997, 9, 22, 9, 0), utc(1997, 10, 20, 9, 0), utc(1997, 11, 17, 9, 0), utc(1997, 12, 22, 9, 0), utc(1998, 1, 19, 9, 0),
+				utc(1998, 2, 16, 9, 0),
+			},
+		},
+		"skip invalid date": {
+			rule:    "FREQ=MONTHLY;BYMONTHDAY=30;COUNT=3",
+			dtstart: utc(2020, 1, 30, 9, 0),
+			limit:   100,
+			expected: []time.Time{
+				utc(2020, 1, 30, 9, 0), utc(2020, 3, 30, 9, 0), utc(2020, 4, 30, 9, 0),
+			},
+		},
+		"last day of month": {
+			rule:    "FREQ=MONTHLY;BYMONTHDAY=-1;COUNT=3",
+			dtstart: utc(2020, 1, 31, 9, 0),
+			limit:   100,
+			expected: []time.Time{
+				utc(2020, 1, 31, 9, 0), utc(2020, 2, 29, 9, 0), utc(2020, 3, 31, 9, 0),
+			},
+		},
+		"last work day of month": {
+			rule:    "FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1;COUNT=3",
+			dtstart: utc(1997, 9, 30, 9, 0),
+			limit:   100,
+			expected: []time.Time{
+				utc(1997, 9, 30, 9, 0), utc(1997, 10, 31, 9, 0), utc(1997, 11, 28, 9, 0),
+			},
+		},
+		"yearly on leap day": {
+			rule:    "FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=29;COUNT=3",
+			dtstart: utc(2020, 2, 29, 9, 0),
+			limit:   100,
+			expected: []time.Time{
+				utc(2020, 2, 29, 9, 0), utc(2024, 2, 29, 9, 0), utc(2028, 2, 29, 9, 0),
+			},
+		},
+		"never match": {
+			rule:    "FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=30",
+			dtstart: utc(2020, 1, 1, 9, 0),
+			limit:   100,
+			expected: []time.Time{
+				utc(2020, 1, 1, 9, 0),
+			},
+		},
+		"monday of week number 20": {
+			rule:    "FREQ=YEARLY;BYWEEKNO=20;BYDAY=MO;COUNT=3",
+			dtstart: utc(1997, 5, 12, 9, 0),
+			limit:   100,
+			expected: []time.Time{
+				utc(1997, 5, 12, 9, 0), utc(1998, 5, 11, 9, 0), utc(1999, 5, 17, 9, 0),
+			},
+		},
+		"20th monday of the year": {
+			rule:    "FREQ=YEARLY;BYDAY=20MO;COUNT=3",
+			dtstart: utc(1997, 5, 19, 9, 0),
+			limit:   100,
+			expected: []time.Time{
+				utc(1997, 5, 19, 9, 0), utc(1998, 5, 18, 9, 0), utc(1999, 5, 17, 9, 0),
+			},
+		},
+		"every 100th day of the year": {
+			rule:    "FREQ=YEARLY;INTERVAL=3;COUNT=3;BYYEARDAY=1,100,200",
+			dtstart: utc(1997, 1, 1, 9, 0),
+			limit:   100,
+			expected: []time.Time{
+				utc(1997, 1, 1, 9, 0), utc(1997, 4, 10, 9, 0), utc(1997, 7, 19, 9, 0),
+			},
+		},
+		"every 20 minutes in work hours": {
+			rule:    "FREQ=MINUTELY;INTERVAL=20;BYHOUR=9,10,11,12,13,14,15,16",
+			dtstart: utc(1997, 9, 2, 16, 20),
+			limit:   5,
+			expected: []time.Time{
+				utc(1997, 9, 2, 16, 20), utc(1997, 9, 2, 16, 40), utc(1997, 9, 3, 9, 0), utc(1997, 9, 3, 9, 20), utc(1997, 9, 3, 9, 40),
+			},
+		},
+		"hourly with minutes": {
+			rule:    "FREQ=HOURLY;INTERVAL=3;BYMINUTE=0,30;COUNT=4",
+			dtstart: utc(1997, 9, 2, 9, 0),
+			limit:   100,
+			expected: []time.Time{
+				utc(1997, 9, 2, 9, 0), utc(1997, 9, 2, 9, 30), utc(1997, 9, 2, 12, 0), utc(1997, 9, 2, 12, 30),
+			},
+		},
+		"keep wall clock over daylight saving time": {
+			rule:    "FREQ=DAILY;COUNT=3",
+			dtstart: time.Date(2020, 3, 7, 9, 0, 0, 0, newYork),
+			limit:   100,
+			expected: []time.Time{
+				time.Date(2020, 3, 7, 9, 0, 0, 0, newYork), time.Date(2020, 3, 8, 9, 0, 0, 0, newYork), time.Date(2020, 3, 9, 9, 0, 0, 0, newYork),
+			},
+		},
+		"daily in gap of daylight saving time": {
+			rule:    "FREQ=DAILY;COUNT=3",
+			dtstart: time.Date(2020, 3, 7, 2, 30, 0, 0, newYork),
+			limit:   100,
+			expected: []time.Time{
+				// 02:30 does not exist on 8th, it is 03:30 EDT
+				utc(2020, 3, 7, 7, 30), utc(2020, 3, 8, 7, 30), utc(2020, 3, 9, 6, 30),
+			},
+		},
+		"hourly over gap of daylight saving time": {
+			rule:    "FREQ=HOURLY;COUNT=4",
+			dtstart: time.Date(2020, 3, 8, 0, 0, 0, 0, newYork),
+			limit:   100,
+			expected: []time.Time{
+				// 02:00 and 03:00 are both 03:00 EDT
+				utc(2020, 3, 8, 5, 0), utc(2020, 3, 8, 6, 0), utc(2020, 3, 8, 7, 0), utc(2020, 3, 8, 8, 0),
+			},
+		},
+		"daily in overlap of daylight saving time": {
+			rule:    "FREQ=DAILY;COUNT=3",
+			dtstart: time.Date(2020, 10, 31, 1, 30, 0, 0, newYork),
+			limit:   100,
+			expected: []time.Time{
+				// 01:30 on 1st is the first one in EDT
+				utc(2020, 10, 31, 5, 30), utc(2020, 11, 1, 5, 30), utc(2020, 11, 2, 6, 30),
+			},
+		},
+		"daily in overlap of daylight saving time east of UTC": {
+			rule:    "FREQ=DAILY;COUNT=3",
+			dtstart: time.Date(2020, 10, 24, 2, 30, 0, 0, berlin),
+			limit:   100,
+			expected: []time.Time{
+				// 02:30 on 25th is the first one in CEST
+				utc(2020, 10, 24, 0, 30), utc(2020, 10, 25, 0, 30), utc(2020, 10, 26, 1, 30),
+			},
+		},
+		"hourly over overlap of daylight saving time": {
+			rule:    "FREQ=HOURLY;COUNT=4",
+			dtstart: time.Date(2020, 11, 1, 0, 0, 0, 0, newYork),
+			limit:   100,
+			expected: []time.Time{
+				// wall clock 01:00 is the first one in EDT
+				utc(2020, 11, 1, 4, 0), utc(2020, 11, 1, 5, 0), utc(2020, 11, 1, 7, 0), utc(2020, 11, 1, 8, 0),
+			},
+		},
+		"until in gap of daylight saving time": {
+			rule:    "FREQ=HOURLY;UNTIL=20200308T023000",
+			dtstart: time.Date(2020, 3, 8, 0, 30, 0, 0, newYork),
+			limit:   100,
+			expected: []time.Time{
+				// UNTIL is 03:30 EDT
+				utc(2020, 3, 8, 5, 30), utc(2020, 3, 8, 6, 30), utc(2020, 3, 8, 7, 30),
+			},
+		},
+	}
+
+	for title, tt := range testcases {
+		tt := tt
+		t.Run(title, func(t *testing.T) {
+			t.Parallel()
+			rr, err := NewRecurrenceRule(tt.rule)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []time.Time
+			it := rr.Iterator(tt.dtstart)
+			for v, ok := it.Next(); ok && len(got) < tt.limit; v, ok = it.Next() {
+				got = append(got, v)
+			}
+			if diff := cmp.Diff(tt.expected, got); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRecurrenceRule_Between(t *testing.T) {
+	t.Parallel()
+	rr, err := NewRecurrenceRule("FREQ=DAILY")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dtstart := time.Date(1997, 9, 2, 9, 0, 0, 0, time.UTC)
+	got := rr.Between(dtstart, time.Date(1997, 9, 5, 9, 0, 0, 0, time.UTC), time.Date(1997, 9, 8, 9, 0, 0, 0, time.UTC))
+	expected := []time.Time{
+		time.Date(1997, 9, 5, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 9, 6, 9, 0, 0, 0, time.UTC),
+		time.Date(1997, 9, 7, 9, 0, 0, 0, time.UTC),
+	}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
@@ -4,15 +4,16 @@ import (
 	"bytes"
 	"encoding/base64"
 	"fmt"
-	"math"
 	"net/url"
 	"regexp"
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/knsh14/ical/errcode"
 )
 
-var ErrEmpty = fmt.Errorf("empty")
+var ErrEmpty = errcode.Errorf(errcode.ValueFormat, "empty")
 
 // Binary is defined in https://tools.ietf.org/html/rfc5545#section-3.3.1
 // BASE64 encoded string
@@ -27,7 +28,7 @@ func (b Binary) String() string {
 
 func NewBinary(v string) (Binary, error) {
 	if _, err := base64.StdEncoding.DecodeString(v); err != nil {
-		return Binary{}, fmt.Errorf("base64 decode: %w", err)
+		return Binary{}, errcode.Errorf(errcode.ValueFormat, "base64 decode: %w", err)
 	}
 	return Binary{Value: v}, nil
 }
@@ -49,7 +50,7 @@ func NewBoolean(v string) (Boolean, error) {
 	if strings.ToUpper(v) == "FALSE" {
 		return Boolean(false), nil
 	}
-	return Boolean(false), fmt.Errorf("input[%s] is not TRUE or FALSE", v)
+	return Boolean(false), errcode.Errorf(errcode.ValueFormat, "input[%s] is not TRUE or FALSE", v)
 }
 
 // CalenderUserAddress is defined in https://tools.ietf.org/html/rfc5545#section-3.3.3
@@ -67,7 +68,7 @@ func (cua CalenderUserAddress) String() string {
 func NewCalenderUserAddress(v string) (CalenderUserAddress, error) {
 	uri, err := url.ParseRequestURI(v)
 	if err != nil {
-		return CalenderUserAddress{}, fmt.Errorf("invalid CalenderUserAddress type: %w", err)
+		return CalenderUserAddress{}, errcode.Errorf(errcode.ValueFormat, "invalid CalenderUserAddress type: %w", err)
 	}
 	return CalenderUserAddress{URI: uri}, nil
 }
@@ -85,7 +86,7 @@ func (d Date) String() string {
 func NewDate(v string) (Date, error) {
 	t, err := time.Parse("20060102", v)
 	if err != nil {
-		return Date{}, fmt.Errorf("parse date: %w", err)
+		return Date{}, errcode.Errorf(errcode.ValueFormat, "parse date: %w", err)
 	}
 	return Date(t), nil
 }
@@ -110,10 +111,16 @@ func NewDateTime(v, tz string) (DateTime, error) {
 	if tz != "" {
 		z, err := time.LoadLocation(tz)
 		if err != nil {
-			return DateTime{}, fmt.Errorf("get timezone: %w", err)
+			return DateTime{}, errcode.Errorf(errcode.TimezoneNotFound, "get timezone: %w", err)
 		}
 		loc = z
 	}
+	return NewDateTimeInLocation(v, loc)
+}
+
+// NewDateTimeInLocation returns DateTime.
+// local time is interpreted in loc.
+func NewDateTimeInLocation(v string, loc *time.Location) (DateTime, error) {
 	t, err := time.ParseInLocation("20060102T150405", v, loc)
 	if err == nil {
 		return DateTime(t), nil
@@ -122,7 +129,7 @@ func NewDateTime(v, tz string) (DateTime, error) {
 	if err == nil {
 		return DateTime(t), nil
 	}
-	return DateTime{}, fmt.Errorf("input %s is invalid format for DateTime", v)
+	return DateTime{}, errcode.Errorf(errcode.ValueFormat, "input %s is invalid format for DateTime", v)
 }
 
 // Duration is defined in https://tools.ietf.org/html/rfc5545#section-3.3.6
@@ -147,30 +154,42 @@ func (d Duration) String() string {
 		fmt.Fprintf(&buf, "%dD", d.Day)
 	}
 	if d.HourDuration == 0 {
+		if d.Day == 0 {
+			buf.WriteString("T0S")
+		}
 		return buf.String()
 	}
+	h := int64(d.HourDuration / time.Hour)
+	m := int64(d.HourDuration%time.Hour) / int64(time.Minute)
+	s := int64(d.HourDuration%time.Minute) / int64(time.Second)
 	buf.WriteString("T")
-	if d.HourDuration.Hours() > 0 {
-		fmt.Fprintf(&buf, "%.fH", math.Floor(d.HourDuration.Hours()))
+	// dur-hour must be followed by dur-minute if dur-second exists
+	if h > 0 {
+		fmt.Fprintf(&buf, "%dH", h)
 	}
-	if int64(d.HourDuration.Minutes())%60 > 0 {
-		fmt.Fprintf(&buf, "%dM", int64(d.HourDuration.Minutes())%60)
+	if m > 0 || (h > 0 && s > 0) {
+		fmt.Fprintf(&buf, "%dM", m)
 	}
-	if int64(d.HourDuration.Seconds())%60 > 0 {
-		if d.HourDuration.Hours() == 0 {
-			fmt.Fprint(&buf, "0H")
-		}
-		if int64(d.HourDuration.Minutes())%60 == 0 {
-			fmt.Fprint(&buf, "0M")
-		}
-		fmt.Fprintf(&buf, "%dS", int64(d.HourDuration.Seconds())%3600)
+	if s > 0 {
+		fmt.Fprintf(&buf, "%dS", s)
 	}
 	return buf.String()
 }
 
+// Add returns t added d.
+// weeks and days are nominal, so wall clock is kept over daylight saving time.
+// https://tools.ietf.org/html/rfc5545#section-3.3.6
+func (d Duration) Add(t time.Time) time.Time {
+	sign := 1
+	if d.Direction == "-" {
+		sign = -1
+	}
+	return t.AddDate(0, 0, sign*int(d.Week*7+d.Day)).Add(time.Duration(sign) * d.HourDuration)
+}
+
 var (
 	durationWeekRe = regexp.MustCompile(`([+-]?)P(\d+W)`)
-	durationDateRe = regexp.MustCompile(`([+-]?)P(\d+D)?(T(\d+H)?(\d+M)?(\d+S)?)`)
+	durationDateRe = regexp.MustCompile(`([+-]?)P(\d+D)?(T(\d+H)?(\d+M)?(\d+S)?)?`)
 )
 
 func NewDuration(v string) (Duration, error) {
@@ -203,25 +222,29 @@ func NewDuration(v string) (Duration, error) {
 			}
 			d.Day = n
 		}
+		// dur-time is omitted in dur-date such as P1D
+		if matches[2] == "" {
+			return d, nil
+		}
 		duration, err := time.ParseDuration(strings.ToLower(strings.TrimPrefix(matches[2], "T")))
 		if err != nil {
-			return Duration{}, fmt.Errorf("parse hour to second duration: %w", err)
+			return Duration{}, errcode.Errorf(errcode.ValueFormat, "parse hour to second duration: %w", err)
 		}
 		d.HourDuration = duration
 		return d, nil
 	}
-	return Duration{}, fmt.Errorf("invalid format for DURATION type, see https://tools.ietf.org/html/rfc5545#section-3.3.6")
+	return Duration{}, errcode.Errorf(errcode.ValueFormat, "invalid format for DURATION type, see https://tools.ietf.org/html/rfc5545#section-3.3.6")
 }
 
 func getDuration(v, unit string) (int64, error) {
 	if strings.HasSuffix(v, unit) {
 		n, err := strconv.ParseInt(strings.TrimSuffix(v, unit), 10, 64)
 		if err != nil {
-			return 0, fmt.Errorf("parse %s into duration %s: %w", v, unit, err)
+			return 0, errcode.Errorf(errcode.ValueFormat, "parse %s into duration %s: %w", v, unit, err)
 		}
 		return n, nil
 	}
-	return 0, fmt.Errorf("input[%s] dont have required suffix %s", v, unit)
+	return 0, errcode.Errorf(errcode.ValueFormat, "input[%s] dont have required suffix %s", v, unit)
 }
 
 // Float is defined in https://tools.ietf.org/html/rfc5545#section-3.3.7
@@ -230,7 +253,7 @@ type Float float64
 func NewFloat(v string) (Float, error) {
 	f, err := strconv.ParseFloat(v, 64)
 	if err != nil {
-		return 0, fmt.Errorf("parse input[v] to float64: %w", err)
+		return 0, errcode.Errorf(errcode.ValueFormat, "parse input[v] to float64: %w", err)
 	}
 	return Float(f), nil
 }
@@ -241,7 +264,7 @@ type Integer int64
 func NewInteger(v string) (Integer, error) {
 	i, err := strconv.ParseInt(v, 10, 64)
 	if err != nil {
-		return 0, fmt.Errorf("parse input[v] to int64: %w", err)
+		return 0, errcode.Errorf(errcode.ValueFormat, "parse input[v] to int64: %w", err)
 	}
 	return Integer(i), nil
 }
@@ -270,7 +293,7 @@ func (p Period) String() string {
 func NewPeriod(v string) (Period, error) {
 	l := strings.Split(v, "/")
 	if len(l) != 2 {
-		return Period{}, fmt.Errorf("input[%s] must be divided by /", v)
+		return Period{}, errcode.Errorf(errcode.ValueFormat, "input[%s] must be divided by /", v)
 	}
 	var p Period
 	s, err := NewDateTime(l[0], "")
@@ -292,7 +315,7 @@ func NewPeriod(v string) (Period, error) {
 		return p, nil
 	}
 
-	return Period{}, fmt.Errorf("%s need to match DURATION or DATE-TIME", l[1])
+	return Period{}, errcode.Errorf(errcode.ValueFormat, "%s need to match DURATION or DATE-TIME", l[1])
 }
 
 // RecurrenceRule is defined in https://tools.ietf.org/html/rfc5545#section-3.3.10
@@ -411,28 +434,28 @@ func (wd WeekDay) String() string {
 
 func NewRecurrenceRule(v string) (RecurrenceRule, error) {
 	if v == "" {
-		return RecurrenceRule{}, ErrEmpty
+		return RecurrenceRule{}, errcode.Wrap(errcode.ValueFormat, ErrEmpty)
 	}
 	values := strings.Split(v, ";")
 	if len(values) == 0 {
-		return RecurrenceRule{}, ErrEmpty
+		return RecurrenceRule{}, errcode.Wrap(errcode.ValueFormat, ErrEmpty)
 	}
 	var res RecurrenceRule
 	for _, value := range values {
 		kv := strings.Split(value, "=")
 		if len(kv) != 2 {
-			return RecurrenceRule{}, fmt.Errorf("")
+			return RecurrenceRule{}, errcode.Errorf(errcode.ValueFormat, "%s is not rule part of NAME=VALUE", value)
 		}
 		switch kv[0] {
 		case "FREQ":
 			res.Frequency = recurrenceRuleFrequencyPattern(kv[1])
 			if res.Frequency == FrequencyPatternInvalid {
-				return RecurrenceRule{}, fmt.Errorf("%s is invalid Frequency pattern", kv[1])
+				return RecurrenceRule{}, errcode.Errorf(errcode.ValueFormat, "%s is invalid Frequency pattern", kv[1])
 			}
 		case "WKST":
 			res.WeekDay = recurrenceRuleWeekdayPattern(kv[1])
 			if res.WeekDay == WeekDayPatternInvalid {
-				return RecurrenceRule{}, fmt.Errorf("%s is invalid WeekDay pattern", kv[1])
+				return RecurrenceRule{}, errcode.Errorf(errcode.ValueFormat, "%s is invalid WeekDay pattern", kv[1])
 			}
 		case "UNTIL":
 			dt, err := NewDateTime(kv[1], "")
@@ -446,17 +469,17 @@ func NewRecurrenceRule(v string) (RecurrenceRule, error) {
 				res.EndDate = d
 				break
 			}
-			return RecurrenceRule{}, fmt.Errorf("%s cant convert DATE or DATE-TIME", kv[1])
+			return RecurrenceRule{}, errcode.Errorf(errcode.ValueFormat, "%s cant convert DATE or DATE-TIME", kv[1])
 		case "COUNT":
 			c, err := strconv.Atoi(kv[1])
 			if err != nil {
-				return RecurrenceRule{}, fmt.Errorf("convert %s to Int: %w", kv[1], err)
+				return RecurrenceRule{}, errcode.Errorf(errcode.ValueFormat, "convert %s to Int: %w", kv[1], err)
 			}
 			res.Count = int64(c)
 		case "INTERVAL":
 			c, err := strconv.Atoi(kv[1])
 			if err != nil {
-				return RecurrenceRule{}, fmt.Errorf("convert %s to Int: %w", kv[1], err)
+				return RecurrenceRule{}, errcode.Errorf(errcode.ValueFormat, "convert %s to Int: %w", kv[1], err)
 			}
 			res.Interval = int64(c)
 		case "BYSECOND":
@@ -556,16 +579,16 @@ func getNumberList(v string, check func(int64) bool) ([]int64, error) {
 	var res []int64
 	values := strings.Split(v, ",")
 	if len(values) == 0 {
-		return nil, fmt.Errorf("get number list: %w", ErrEmpty)
+		return nil, errcode.Errorf(errcode.ValueFormat, "get number list: %w", ErrEmpty)
 	}
 	for _, v := range values {
 		a, err := strconv.Atoi(v)
 		if err != nil {
-			return nil, fmt.Errorf("convert %s into int: %w", v, err)
+			return nil, errcode.Errorf(errcode.ValueFormat, "convert %s into int: %w", v, err)
 		}
 		n := int64(a)
 		if !check(n) {
-			return nil, fmt.Errorf("%d is out of range", n)
+			return nil, errcode.Errorf(errcode.ValueFormat, "%d is out of range", n)
 		}
 		res = append(res, n)
 	}
@@ -578,25 +601,25 @@ func getWeekDayList(v string) ([]WeekDay, error) {
 	var days []WeekDay
 	values := strings.Split(v, ",")
 	if len(values) == 0 {
-		return nil, fmt.Errorf("get number list: %w", ErrEmpty)
+		return nil, errcode.Errorf(errcode.ValueFormat, "get number list: %w", ErrEmpty)
 	}
 	for _, v := range values {
 		var w WeekDay
 		res := weekDayNumRe.FindAllStringSubmatch(v, -1)
 		if len(res) == 0 || len(res[0]) != 3 {
-			return nil, fmt.Errorf("%s is invalid pattern", v)
+			return nil, errcode.Errorf(errcode.ValueFormat, "%s is invalid pattern", v)
 		}
 		matches := res[0]
 		if matches[1] != "" {
 			a, err := strconv.Atoi(matches[1])
 			if err != nil {
-				return nil, fmt.Errorf("convert %s into int: %w", v, err)
+				return nil, errcode.Errorf(errcode.ValueFormat, "convert %s into int: %w", v, err)
 			}
 			w.Week = int64(a)
 		}
 		w.Day = recurrenceRuleWeekdayPattern(matches[2])
 		if w.Day == WeekDayPatternInvalid {
-			return nil, fmt.Errorf("convert %s into week day", matches[2])
+			return nil, errcode.Errorf(errcode.ValueFormat, "convert %s into week day", matches[2])
 		}
 		days = append(days, w)
 	}
@@ -610,6 +633,39 @@ func NewText(v string) Text {
 	return Text(v)
 }
 
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// Escape returns value with backslash, semicolon, comma and newline escaped
+// https://tools.ietf.org/html/rfc5545#section-3.3.11
+func (t Text) Escape() string {
+	return textEscaper.Replace(string(t))
+}
+
+// UnescapeText returns Text from escaped value in content line
+// https://tools.ietf.org/html/rfc5545#section-3.3.11
+func UnescapeText(v string) Text {
+	if !strings.Contains(v, `\`) {
+		return Text(v)
+	}
+	var b strings.Builder
+	b.Grow(len(v))
+	for i := 0; i < len(v); i++ {
+		if v[i] != '\\' || i+1 == len(v) {
+			b.WriteByte(v[i])
+			continue
+		}
+		i++
+		switch v[i] {
+		case 'n', 'N':
+			b.WriteByte('\n')
+		default:
+			// \\, \; and \, are unescaped to the character itself
+			b.WriteByte(v[i])
+		}
+	}
+	return Text(b.String())
+}
+
 // Time is defined in https://tools.ietf.org/html/rfc5545#section-3.3.12
 type Time time.Time
 
@@ -626,7 +682,7 @@ func NewTime(v, tz string) (Time, error) {
 	if tz != "" {
 		z, err := time.LoadLocation(tz)
 		if err != nil {
-			return Time{}, fmt.Errorf("get timezone: %w", err)
+			return Time{}, errcode.Errorf(errcode.TimezoneNotFound, "get timezone: %w", err)
 		}
 		loc = z
 	}
@@ -638,7 +694,7 @@ func NewTime(v, tz string) (Time, error) {
 	if err == nil {
 		return Time(t), nil
 	}
-	return Time{}, fmt.Errorf("input %s is invalid format for Time", v)
+	return Time{}, errcode.Errorf(errcode.ValueFormat, "input %s is invalid format for Time", v)
 }
 
 // URI is defined in https://tools.ietf.org/html/rfc5545#section-3.3.13
@@ -657,7 +713,7 @@ func (u URI) String() string {
 func NewURI(v string) (URI, error) {
 	uri, err := url.ParseRequestURI(v)
 	if err != nil {
-		return URI{}, fmt.Errorf("invalid format for URI: %w", err)
+		return URI{}, errcode.Errorf(errcode.ValueFormat, "invalid format for URI: %w", err)
 	}
 	return URI{URI: uri}, nil
 }
@@ -684,34 +740,43 @@ func (utco UTCOffset) String() string {
 	return buf.String()
 }
 
+// Seconds returns offset from UTC in seconds
+func (utco UTCOffset) Seconds() int {
+	s := int(utco.Hour*3600 + utco.Minute*60 + utco.Second)
+	if !utco.Direction {
+		return -s
+	}
+	return s
+}
+
 func NewUTCOffset(v string) (UTCOffset, error) {
 	var o UTCOffset
+	if len(v) < 5 {
+		return UTCOffset{}, errcode.Errorf(errcode.ValueFormat, "input[%s] is too short to parse", v)
+	}
 	switch v[0] {
 	case '+':
 		o.Direction = true
 	case '-':
 		o.Direction = false
 	default:
-		return UTCOffset{}, fmt.Errorf("UTCOffset must start from + or -")
-	}
-	if len(v) < 5 {
-		return UTCOffset{}, fmt.Errorf("input[%s] is too short to parse", v)
+		return UTCOffset{}, errcode.Errorf(errcode.ValueFormat, "UTCOffset must start from + or -")
 	}
 	h, err := strconv.ParseUint(v[1:3], 10, 64)
 	if err != nil {
-		return UTCOffset{}, fmt.Errorf("parse hour offset: %w", err)
+		return UTCOffset{}, errcode.Errorf(errcode.ValueFormat, "parse hour offset: %w", err)
 	}
 	o.Hour = h
-	m, err := strconv.ParseUint(v[3:4], 10, 64)
+	m, err := strconv.ParseUint(v[3:5], 10, 64)
 	if err != nil {
-		return UTCOffset{}, fmt.Errorf("parse minute offset: %w", err)
+		return UTCOffset{}, errcode.Errorf(errcode.ValueFormat, "parse minute offset: %w", err)
 	}
 	o.Minute = m
 
 	if len(v) == 7 {
-		s, err := strconv.ParseUint(v[5:6], 10, 64)
+		s, err := strconv.ParseUint(v[5:7], 10, 64)
 		if err != nil {
-			return UTCOffset{}, fmt.Errorf("parse second offset: %w", err)
+			return UTCOffset{}, errcode.Errorf(errcode.ValueFormat, "parse second offset: %w", err)
 		}
 		o.Second = s
 	}
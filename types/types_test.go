@@ -4,10 +4,13 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/knsh14/ical/errcode"
+	"github.com/morikuni/failure"
 )
 
 func TestBinary(t *testing.T) {
@@ -159,6 +162,22 @@ func TestNewDuration(t *testing.T) {
 			},
 			expectedErr: nil,
 		},
+		"day": {
+			input: "P1D",
+			expected: Duration{
+				Direction: "",
+				Day:       1,
+			},
+			expectedErr: nil,
+		},
+		"negative day": {
+			input: "-P2D",
+			expected: Duration{
+				Direction: "-",
+				Day:       2,
+			},
+			expectedErr: nil,
+		},
 		"time": {
 			input: "PT5H0M20S",
 			expected: Duration{
@@ -209,6 +228,21 @@ func TestRecurrenceRule(t *testing.T) {
 				if !errors.Is(err, ErrEmpty) {
 					t.Fatalf("expect:%v\nactual:%v", ErrEmpty, err)
 				}
+				if !failure.Is(err, errcode.ValueFormat) {
+					t.Fatalf("expect code:%v\nactual:%v", errcode.ValueFormat, err)
+				}
+			},
+		},
+		"no_value": {
+			input:    "FREQ=DAILY;COUNT",
+			expected: RecurrenceRule{},
+			expectError: func(t *testing.T, err error) {
+				if !failure.Is(err, errcode.ValueFormat) {
+					t.Fatalf("expect code:%v\nactual:%v", errcode.ValueFormat, err)
+				}
+				if !strings.Contains(err.Error(), "COUNT") {
+					t.Fatalf("expect message with COUNT\nactual:%v", err)
+				}
 			},
 		},
 		"FREQ": {
@@ -487,6 +521,17 @@ func TestString(t *testing.T) {
 				return v.String(), nil
 			},
 		},
+		{
+			title: "Duration_4",
+			input: "P1D",
+			convert: func(s string) (string, error) {
+				v, err := NewDuration(s)
+				if err != nil {
+					return "", err
+				}
+				return v.String(), nil
+			},
+		},
 		{
 			title: "Period_1",
 			input: "19970101T180000Z/19970102T070000Z",
@@ -586,6 +631,17 @@ func TestString(t *testing.T) {
 				return v.String(), nil
 			},
 		},
+		{
+			title: "UTCOffset",
+			input: "+0530",
+			convert: func(s string) (string, error) {
+				v, err := NewUTCOffset(s)
+				if err != nil {
+					return "", err
+				}
+				return v.String(), nil
+			},
+		},
 		{
 			title: "UTCOffset",
 			input: "+0100",
@@ -612,3 +668,69 @@ func TestString(t *testing.T) {
 		})
 	}
 }
+
+func TestTextEscape(t *testing.T) {
+	t.Parallel()
+	testcases := map[string]struct {
+		input    Text
+		expected string
+	}{
+		"plain": {
+			input:    Text("hello world"),
+			expected: "hello world",
+		},
+		"special characters": {
+			input:    Text("a;b,c\\d"),
+			expected: `a\;b\,c\\d`,
+		},
+		"newline": {
+			input:    Text("line1\nline2\r\nline3"),
+			expected: `line1\nline2\nline3`,
+		},
+	}
+
+	for title, tt := range testcases {
+		tt := tt
+		t.Run(title, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.input.Escape(); got != tt.expected {
+				t.Fatalf("unexpected result\nexpect:\t%s\ngot:\t%s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestUnescapeText(t *testing.T) {
+	t.Parallel()
+	testcases := map[string]struct {
+		input    string
+		expected Text
+	}{
+		"plain": {
+			input:    "hello world",
+			expected: Text("hello world"),
+		},
+		"special characters": {
+			input:    `a\;b\,c\\d`,
+			expected: Text("a;b,c\\d"),
+		},
+		"newline": {
+			input:    `line1\nline2\Nline3`,
+			expected: Text("line1\nline2\nline3"),
+		},
+		"trailing backslash": {
+			input:    `abc\`,
+			expected: Text(`abc\`),
+		},
+	}
+
+	for title, tt := range testcases {
+		tt := tt
+		t.Run(title, func(t *testing.T) {
+			t.Parallel()
+			if got := UnescapeText(tt.input); got != tt.expected {
+				t.Fatalf("unexpected result\nexpect:\t%s\ngot:\t%s", tt.expected, got)
+			}
+		})
+	}
+}
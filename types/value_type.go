@@ -0,0 +1,119 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/knsh14/ical/errcode"
+)
+
+// ValueType is value data type which VALUE parameter specifies
+// https://tools.ietf.org/html/rfc5545#section-3.2.20
+type ValueType string
+
+const (
+	ValueTypeBinary              ValueType = "BINARY"
+	ValueTypeBoolean             ValueType = "BOOLEAN"
+	ValueTypeCalendarUserAddress ValueType = "CAL-ADDRESS"
+	ValueTypeDate                ValueType = "DATE"
+	ValueTypeDateTime            ValueType = "DATE-TIME"
+	ValueTypeDuration            ValueType = "DURATION"
+	ValueTypeFloat               ValueType = "FLOAT"
+	ValueTypeInteger             ValueType = "INTEGER"
+	ValueTypePeriod              ValueType = "PERIOD"
+	ValueTypeRecurrenceRule      ValueType = "RECUR"
+	ValueTypeText                ValueType = "TEXT"
+	ValueTypeTime                ValueType = "TIME"
+	ValueTypeURI                 ValueType = "URI"
+	ValueTypeUTCOffset           ValueType = "UTC-OFFSET"
+
+	// ValueTypeUnknown is type of property whose type is not known, such as X-PROPERTY
+	// https://tools.ietf.org/html/rfc7265#section-5
+	ValueTypeUnknown ValueType = "UNKNOWN"
+)
+
+// ToExtended converts DATE, DATE-TIME, TIME, UTC-OFFSET and PERIOD value in basic format of RFC 5545
+// to extended format of ISO 8601 which jCal and xCal use, such as 2006-01-02T15:04:05Z.
+// other values are returned as they are.
+// https://tools.ietf.org/html/rfc7265#section-3.6
+// https://tools.ietf.org/html/rfc6321#section-3.6
+func ToExtended(t ValueType, v string) (string, error) {
+	switch t {
+	case ValueTypeDate:
+		if len(v) != 8 {
+			return "", errcode.Errorf(errcode.ValueFormat, "invalid DATE %s", v)
+		}
+		return v[:4] + "-" + v[4:6] + "-" + v[6:], nil
+	case ValueTypeTime:
+		if len(v) != 6 && len(v) != 7 {
+			return "", errcode.Errorf(errcode.ValueFormat, "invalid TIME %s", v)
+		}
+		return v[:2] + ":" + v[2:4] + ":" + v[4:], nil
+	case ValueTypeDateTime:
+		i := strings.Index(v, "T")
+		if i < 0 {
+			return "", errcode.Errorf(errcode.ValueFormat, "invalid DATE-TIME %s", v)
+		}
+		d, err := ToExtended(ValueTypeDate, v[:i])
+		if err != nil {
+			return "", fmt.Errorf("invalid DATE-TIME %s: %w", v, err)
+		}
+		t, err := ToExtended(ValueTypeTime, v[i+1:])
+		if err != nil {
+			return "", fmt.Errorf("invalid DATE-TIME %s: %w", v, err)
+		}
+		return d + "T" + t, nil
+	case ValueTypeUTCOffset:
+		if len(v) != 5 && len(v) != 7 {
+			return "", errcode.Errorf(errcode.ValueFormat, "invalid UTC-OFFSET %s", v)
+		}
+		res := v[:3] + ":" + v[3:5]
+		if len(v) == 7 {
+			res += ":" + v[5:]
+		}
+		return res, nil
+	case ValueTypePeriod:
+		i := strings.Index(v, "/")
+		if i < 0 {
+			return "", errcode.Errorf(errcode.ValueFormat, "invalid PERIOD %s", v)
+		}
+		start, err := ToExtended(ValueTypeDateTime, v[:i])
+		if err != nil {
+			return "", err
+		}
+		end := v[i+1:]
+		if !isDurationValue(end) {
+			end, err = ToExtended(ValueTypeDateTime, end)
+			if err != nil {
+				return "", err
+			}
+		}
+		return start + "/" + end, nil
+	}
+	return v, nil
+}
+
+// FromExtended converts value in extended format which ToExtended returns back to basic format of RFC 5545
+func FromExtended(t ValueType, v string) string {
+	switch t {
+	case ValueTypeDate, ValueTypeTime, ValueTypeDateTime:
+		return strings.NewReplacer("-", "", ":", "").Replace(v)
+	case ValueTypeUTCOffset:
+		return strings.ReplaceAll(v, ":", "")
+	case ValueTypePeriod:
+		i := strings.Index(v, "/")
+		if i < 0 {
+			return v
+		}
+		end := v[i+1:]
+		if !isDurationValue(end) {
+			end = FromExtended(ValueTypeDateTime, end)
+		}
+		return FromExtended(ValueTypeDateTime, v[:i]) + "/" + end
+	}
+	return v
+}
+
+func isDurationValue(v string) bool {
+	return strings.HasPrefix(strings.TrimLeft(v, "+-"), "P")
+}
@@ -0,0 +1,38 @@
+package types
+
+import "testing"
+
+func TestToExtended(t *testing.T) {
+	t.Parallel()
+	testcases := map[string]struct {
+		valueType ValueType
+		input     string
+		expected  string
+	}{
+		"date":                 {ValueTypeDate, "20200102", "2020-01-02"},
+		"utc date-time":        {ValueTypeDateTime, "20200102T030405Z", "2020-01-02T03:04:05Z"},
+		"floating date-time":   {ValueTypeDateTime, "20200102T030405", "2020-01-02T03:04:05"},
+		"time":                 {ValueTypeTime, "030405Z", "03:04:05Z"},
+		"utc-offset":           {ValueTypeUTCOffset, "-0500", "-05:00"},
+		"utc-offset seconds":   {ValueTypeUTCOffset, "+013045", "+01:30:45"},
+		"explicit period":      {ValueTypePeriod, "20200102T030405Z/20200102T040000Z", "2020-01-02T03:04:05Z/2020-01-02T04:00:00Z"},
+		"period with duration": {ValueTypePeriod, "20200102T030405Z/PT1H", "2020-01-02T03:04:05Z/PT1H"},
+		"other types":          {ValueTypeDuration, "-PT15M", "-PT15M"},
+	}
+	for title, tt := range testcases {
+		tt := tt
+		t.Run(title, func(t *testing.T) {
+			t.Parallel()
+			got, err := ToExtended(tt.valueType, tt.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.expected {
+				t.Fatalf("expected %s, but got %s", tt.expected, got)
+			}
+			if back := FromExtended(tt.valueType, got); back != tt.input {
+				t.Fatalf("expected %s from extended format, but got %s", tt.input, back)
+			}
+		})
+	}
+}
@@ -0,0 +1,477 @@
+package ical
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/knsh14/ical/component"
+	"github.com/knsh14/ical/errcode"
+	"github.com/knsh14/ical/property"
+	"github.com/knsh14/ical/types"
+	"github.com/morikuni/failure"
+)
+
+// Severity is how serious a Finding is
+type Severity int
+
+const (
+	// SeverityError is violation of a rule which RFC says MUST
+	SeverityError Severity = iota
+	// SeverityWarning is violation of a rule which RFC says SHOULD
+	SeverityWarning
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	}
+	return fmt.Sprintf("Severity(%d)", int(s))
+}
+
+// Rule is ID of a rule which Check verifies
+type Rule string
+
+const (
+	// RuleRequired is property which must occur in the component
+	// https://tools.ietf.org/html/rfc5545#section-3.6
+	RuleRequired Rule = "required"
+	// RuleOnce is property which must not occur more than once in the component.
+	// RRULE is reported as warning since it should not occur more than once.
+	// https://tools.ietf.org/html/rfc5545#section-3.6
+	RuleOnce Rule = "once"
+	// RuleComponentRequired is VCALENDAR without any component, or VTIMEZONE without STANDARD nor DAYLIGHT
+	// https://tools.ietf.org/html/rfc5545#section-3.6
+	// https://tools.ietf.org/html/rfc5545#section-3.6.5
+	RuleComponentRequired Rule = "component-required"
+	// RuleEndOrDuration is DTEND or DUE which occurs with DURATION
+	// https://tools.ietf.org/html/rfc5545#section-3.6.1
+	// https://tools.ietf.org/html/rfc5545#section-3.6.2
+	RuleEndOrDuration Rule = "end-or-duration"
+	// RuleDurationWithStart is DURATION of VTODO without DTSTART
+	// https://tools.ietf.org/html/rfc5545#section-3.6.2
+	RuleDurationWithStart Rule = "duration-with-start"
+	// RuleDurationDays is DURATION of VEVENT which is not days nor weeks though DTSTART is DATE
+	// https://tools.ietf.org/html/rfc5545#section-3.6.1
+	RuleDurationDays Rule = "duration-days"
+	// RuleEndAfterStart is DTEND which is not later than DTSTART, or DUE which is earlier than DTSTART
+	// https://tools.ietf.org/html/rfc5545#section-3.8.2.2
+	// https://tools.ietf.org/html/rfc5545#section-3.8.2.3
+	RuleEndAfterStart Rule = "end-after-start"
+	// RuleEndValueType is DTEND or DUE whose value type is different from DTSTART
+	// https://tools.ietf.org/html/rfc5545#section-3.6.1
+	// https://tools.ietf.org/html/rfc5545#section-3.6.2
+	RuleEndValueType Rule = "end-value-type"
+	// RuleUntilValueType is UNTIL of RRULE whose value type is different from DTSTART,
+	// or which is not UTC though DTSTART is UTC or has TZID
+	// https://tools.ietf.org/html/rfc5545#section-3.3.10
+	RuleUntilValueType Rule = "until-value-type"
+	// RuleUntilOrCount is RRULE which has both UNTIL and COUNT
+	// https://tools.ietf.org/html/rfc5545#section-3.3.10
+	RuleUntilOrCount Rule = "until-or-count"
+	// RuleRecurrenceIDValueType is RECURRENCE-ID whose value type is different from DTSTART
+	// https://tools.ietf.org/html/rfc5545#section-3.8.4.4
+	RuleRecurrenceIDValueType Rule = "recurrence-id-value-type"
+	// RuleTimezoneDefined is TZID parameter which no VTIMEZONE in the calendar defines
+	// https://tools.ietf.org/html/rfc5545#section-3.2.19
+	RuleTimezoneDefined Rule = "timezone-defined"
+	// RuleAlarmRepeat is VALARM which has only one of DURATION and REPEAT
+	// https://tools.ietf.org/html/rfc5545#section-3.6.6
+	RuleAlarmRepeat Rule = "alarm-repeat"
+)
+
+// ruleCodes is codes of errors in findings of each rule
+var ruleCodes = map[Rule]failure.Code{
+	RuleRequired:              errcode.MissingProperty,
+	RuleOnce:                  errcode.Cardinality,
+	RuleComponentRequired:     errcode.Invalid,
+	RuleEndOrDuration:         errcode.Cardinality,
+	RuleDurationWithStart:     errcode.MissingProperty,
+	RuleDurationDays:          errcode.ValueFormat,
+	RuleEndAfterStart:         errcode.Invalid,
+	RuleEndValueType:          errcode.ValueFormat,
+	RuleUntilValueType:        errcode.ValueFormat,
+	RuleUntilOrCount:          errcode.Cardinality,
+	RuleRecurrenceIDValueType: errcode.ValueFormat,
+	RuleTimezoneDefined:       errcode.TimezoneNotFound,
+	RuleAlarmRepeat:           errcode.Cardinality,
+	RuleProfileMethod:         errcode.Invalid,
+	RuleProfileComponent:      errcode.UnsupportedComponent,
+	RuleProfileRequired:       errcode.MissingProperty,
+	RuleProfileCardinality:    errcode.Cardinality,
+	RuleProfileNotAllowed:     errcode.UnknownProperty,
+	RuleProfileUID:            errcode.Invalid,
+	RuleProfileRSVP:           errcode.UnknownParameter,
+}
+
+// Finding is a problem which Check finds
+type Finding struct {
+	Severity Severity
+	Rule     Rule
+	// Path is types of components which have the problem, outermost first
+	Path []component.Type
+	// Property is name of the property which has the problem, it is empty if the component itself has the problem
+	Property property.Name
+	// Err is description of the problem annotated with code of package errcode
+	Err error
+}
+
+func (f *Finding) Error() string {
+	path := make([]string, len(f.Path))
+	for i, ct := range f.Path {
+		path[i] = string(ct)
+	}
+	s := fmt.Sprintf("%s: %s", f.Severity, strings.Join(path, "/"))
+	if f.Property != "" {
+		s += ": " + string(f.Property)
+	}
+	return fmt.Sprintf("%s: %s (%s)", s, f.Err, f.Rule)
+}
+
+func (f *Finding) Unwrap() error {
+	return f.Err
+}
+
+// Findings is all problems which Check finds
+type Findings []*Finding
+
+func (fs Findings) Error() string {
+	s := make([]string, len(fs))
+	for i, f := range fs {
+		s[i] = f.Error()
+	}
+	return strings.Join(s, "\n")
+}
+
+// Errors returns findings whose severity is SeverityError
+func (fs Findings) Errors() Findings {
+	var res Findings
+	for _, f := range fs {
+		if f.Severity == SeverityError {
+			res = append(res, f)
+		}
+	}
+	return res
+}
+
+// Check verifies c and its components against rules of RFC 5545 and restrictions of profiles, and returns all findings.
+// unlike Validate, it does not stop at the first problem.
+// https://tools.ietf.org/html/rfc5545#section-3.6
+func (c *Calendar) Check(profiles ...Profile) Findings {
+	ch := &checker{}
+	path := []component.Type{component.TypeCalendar}
+	ch.require(path, property.NameProdID, c.ProdID != nil && c.ProdID.Value != "")
+	ch.require(path, property.NameVersion, c.Version != nil && c.Version.Max != "")
+	ch.once(path, c.PropertyOrder, onceProperties(c)...)
+	if len(c.Components) == 0 {
+		ch.add(SeverityError, RuleComponentRequired, path, "", "must have at least one component")
+	}
+	for _, cc := range c.Components {
+		switch v := cc.(type) {
+		case *Event:
+			ch.checkEvent(path, v, c.Method != nil)
+		case *ToDo:
+			ch.checkToDo(path, v)
+		case *Journal:
+			ch.checkJournal(path, v)
+		case *FreeBusy:
+			ch.checkFreeBusy(path, v)
+		case *Timezone:
+			ch.checkTimezone(path, v)
+		}
+	}
+	defined := c.definedTimezones()
+	for _, ref := range c.timezoneReferences() {
+		if !defined[ref.tzid] {
+			ch.add(SeverityError, RuleTimezoneDefined, childPath(path, ref.kind), ref.name, "%s of TZID %s is not defined", component.TypeTimezone, ref.tzid)
+		}
+	}
+	for _, p := range profiles {
+		ch.checkProfile(c, p)
+	}
+	return ch.findings
+}
+
+// checker collects findings of Check
+type checker struct {
+	findings Findings
+}
+
+func (ch *checker) add(s Severity, r Rule, path []component.Type, pname property.Name, format string, args ...interface{}) {
+	ch.findings = append(ch.findings, &Finding{
+		Severity: s,
+		Rule:     r,
+		Path:     path,
+		Property: pname,
+		Err:      errcode.Errorf(ruleCodes[r], format, args...),
+	})
+}
+
+// require reports pname as missing if ok is false
+func (ch *checker) require(path []component.Type, pname property.Name, ok bool) {
+	if !ok {
+		ch.add(SeverityError, RuleRequired, path, pname, "is required")
+	}
+}
+
+// once reports properties in names which occur more than once in order, and RRULE which does.
+func (ch *checker) once(path []component.Type, order []property.Name, names ...property.Name) {
+	counts := map[property.Name]int{}
+	for _, n := range order {
+		counts[n]++
+	}
+	ch.occurrences(path, counts, names...)
+}
+
+// CheckOccurrences returns findings of properties which occur more than once in c though RFC 5545 allows at most one.
+// counts is number of occurrences of each property name in c, such as parser counts in a stream,
+// since c keeps only one of such properties.
+// c is *Calendar, *Event, *ToDo, *Journal, *FreeBusy, *Timezone, *Standard, *Daylight or Alarm, and path is path of c.
+func CheckOccurrences(path []component.Type, c interface{}, counts map[property.Name]int) Findings {
+	ch := &checker{}
+	ch.occurrences(path, counts, onceProperties(c)...)
+	return ch.findings
+}
+
+// occurrences reports properties in names which occur more than once in counts, and RRULE which does.
+func (ch *checker) occurrences(path []component.Type, counts map[property.Name]int, names ...property.Name) {
+	for _, n := range names {
+		if counts[n] > 1 {
+			ch.add(SeverityError, RuleOnce, path, n, "must not occur more than once, but %d", counts[n])
+		}
+	}
+	if n := counts[property.NameRecurrenceRule]; n > 1 {
+		ch.add(SeverityWarning, RuleOnce, path, property.NameRecurrenceRule, "should not occur more than once, but %d", n)
+	}
+}
+
+// https://tools.ietf.org/html/rfc5545#section-3.6.1
+func (ch *checker) checkEvent(path []component.Type, e *Event, hasMethod bool) {
+	path = childPath(path, component.TypeEvent)
+	ch.require(path, property.NameUID, e.UID != nil && e.UID.Value != "")
+	ch.require(path, property.NameDateTimeStamp, e.DateTimeStamp != nil)
+	if e.DateTimeStart == nil && !hasMethod {
+		ch.add(SeverityError, RuleRequired, path, property.NameDateTimeStart, "is required if %s has no %s", component.TypeCalendar, property.NameMethod)
+	}
+	ch.once(path, e.PropertyOrder, onceProperties(e)...)
+	ch.checkSchedule(path, e.recurrence(), property.NameDateTimeEnd)
+	for _, a := range e.Alarms {
+		ch.checkAlarm(path, a)
+	}
+}
+
+// https://tools.ietf.org/html/rfc5545#section-3.6.2
+func (ch *checker) checkToDo(path []component.Type, todo *ToDo) {
+	path = childPath(path, component.TypeTODO)
+	ch.require(path, property.NameUID, todo.UID != nil && todo.UID.Value != "")
+	ch.require(path, property.NameDateTimeStamp, todo.DateTimeStamp != nil)
+	ch.once(path, todo.PropertyOrder, onceProperties(todo)...)
+	ch.checkSchedule(path, todo.recurrence(), property.NameDateTimeDue)
+	for _, a := range todo.Alarms {
+		ch.checkAlarm(path, a)
+	}
+}
+
+// https://tools.ietf.org/html/rfc5545#section-3.6.3
+func (ch *checker) checkJournal(path []component.Type, j *Journal) {
+	path = childPath(path, component.TypeJournal)
+	ch.require(path, property.NameUID, j.UID != nil && j.UID.Value != "")
+	ch.require(path, property.NameDateTimeStamp, j.DateTimeStamp != nil)
+	ch.once(path, j.PropertyOrder, onceProperties(j)...)
+	ch.checkSchedule(path, recurrence{
+		kind:         component.TypeJournal,
+		start:        j.DateTimeStart,
+		rule:         j.RecurrenceRule,
+		recurrenceID: j.RecurrenceID,
+	}, "")
+}
+
+// https://tools.ietf.org/html/rfc5545#section-3.6.4
+func (ch *checker) checkFreeBusy(path []component.Type, fb *FreeBusy) {
+	path = childPath(path, component.TypeFreeBusy)
+	ch.require(path, property.NameUID, fb.UID != nil && fb.UID.Value != "")
+	ch.require(path, property.NameDateTimeStamp, fb.DateTimeStamp != nil)
+	ch.once(path, fb.PropertyOrder, onceProperties(fb)...)
+	r := recurrence{kind: component.TypeFreeBusy, start: fb.DateTimeStart}
+	if fb.DateTimeEnd != nil {
+		r.end = fb.DateTimeEnd.Value
+	}
+	ch.checkSchedule(path, r, property.NameDateTimeEnd)
+}
+
+// https://tools.ietf.org/html/rfc5545#section-3.6.5
+func (ch *checker) checkTimezone(path []component.Type, tz *Timezone) {
+	path = childPath(path, component.TypeTimezone)
+	ch.require(path, property.NameTimezoneIdentifier, tz.TimezoneIdentifier != nil && tz.TimezoneIdentifier.Value != "")
+	ch.once(path, tz.PropertyOrder, onceProperties(tz)...)
+	if len(tz.Standards) == 0 && len(tz.Daylights) == 0 {
+		ch.add(SeverityError, RuleComponentRequired, path, "", "must have at least one %s or %s", component.TypeStandard, component.TypeDaylight)
+	}
+	for _, s := range tz.Standards {
+		ch.checkObservance(childPath(path, component.TypeStandard), s.DateTimeStart, s.TimezoneOffsetFrom, s.TimezoneOffsetTo, s.PropertyOrder)
+	}
+	for _, d := range tz.Daylights {
+		ch.checkObservance(childPath(path, component.TypeDaylight), d.DateTimeStart, d.TimezoneOffsetFrom, d.TimezoneOffsetTo, d.PropertyOrder)
+	}
+}
+
+// checkObservance checks STANDARD or DAYLIGHT
+func (ch *checker) checkObservance(path []component.Type, start *property.DateTimeStart, from *property.TimezoneOffsetFrom, to *property.TimezoneOffsetTo, order []property.Name) {
+	ch.require(path, property.NameDateTimeStart, start != nil)
+	ch.require(path, property.NameTimezoneOffsetFrom, from != nil)
+	ch.require(path, property.NameTimezoneOffsetTo, to != nil)
+	ch.once(path, order, observanceOnce...)
+}
+
+// https://tools.ietf.org/html/rfc5545#section-3.6.6
+func (ch *checker) checkAlarm(path []component.Type, a Alarm) {
+	path = childPath(path, component.TypeAlarm)
+	var (
+		action   *property.Action
+		trigger  *property.Trigger
+		duration *property.Duration
+		repeat   *property.RepeatCount
+		order    []property.Name
+	)
+	switch v := a.(type) {
+	case *AlarmAudio:
+		action, trigger, duration, repeat, order = v.Action, v.Trigger, v.Duration, v.RepeatCount, v.PropertyOrder
+	case *AlarmDisplay:
+		action, trigger, duration, repeat, order = v.Action, v.Trigger, v.Duration, v.RepeatCount, v.PropertyOrder
+		ch.require(path, property.NameDescription, v.Description != nil)
+	case *AlarmEmail:
+		action, trigger, duration, repeat, order = v.Action, v.Trigger, v.Duration, v.RepeatCount, v.PropertyOrder
+		ch.require(path, property.NameDescription, v.Description != nil)
+		ch.require(path, property.NameSummary, v.Summary != nil)
+		ch.require(path, property.NameAttendee, len(v.Attendees) > 0)
+	default:
+		return
+	}
+	ch.require(path, property.NameAction, action != nil)
+	ch.require(path, property.NameTrigger, trigger != nil)
+	ch.once(path, order, onceProperties(a)...)
+	switch {
+	case duration != nil && repeat == nil:
+		ch.add(SeverityError, RuleAlarmRepeat, path, property.NameDuration, "must occur with %s", property.NameRepeatCount)
+	case duration == nil && repeat != nil:
+		ch.add(SeverityError, RuleAlarmRepeat, path, property.NameRepeatCount, "must occur with %s", property.NameDuration)
+	}
+}
+
+// observanceOnce is properties which must not occur more than once in STANDARD and DAYLIGHT
+var observanceOnce = []property.Name{property.NameDateTimeStart, property.NameTimezoneOffsetFrom, property.NameTimezoneOffsetTo}
+
+// onceProperties returns properties which must not occur more than once in c
+// https://tools.ietf.org/html/rfc5545#section-3.6
+func onceProperties(c interface{}) []property.Name {
+	alarm := []property.Name{property.NameAction, property.NameTrigger, property.NameDuration, property.NameRepeatCount}
+	switch c.(type) {
+	case *Calendar:
+		return []property.Name{property.NameProdID, property.NameVersion, property.NameCalScale, property.NameMethod}
+	case *Event:
+		return []property.Name{
+			property.NameUID, property.NameDateTimeStamp, property.NameDateTimeStart, property.NameClass,
+			property.NameDateTimeCreated, property.NameDescription, property.NameGeo, property.NameLastModified,
+			property.NameLocation, property.NameOrganizer, property.NamePriority, property.NameSequenceNumber,
+			property.NameStatus, property.NameSummary, property.NameTimeTransparency, property.NameURL,
+			property.NameRecurrenceID, property.NameDateTimeEnd, property.NameDuration,
+		}
+	case *ToDo:
+		return []property.Name{
+			property.NameUID, property.NameDateTimeStamp, property.NameClass, property.NameDateTimeCompleted,
+			property.NameDateTimeCreated, property.NameDescription, property.NameDateTimeStart, property.NameGeo,
+			property.NameLastModified, property.NameLocation, property.NameOrganizer, property.NamePercentComplete,
+			property.NamePriority, property.NameRecurrenceID, property.NameSequenceNumber, property.NameStatus,
+			property.NameSummary, property.NameURL, property.NameDateTimeDue, property.NameDuration,
+		}
+	case *Journal:
+		return []property.Name{
+			property.NameUID, property.NameDateTimeStamp, property.NameClass, property.NameDateTimeCreated,
+			property.NameDateTimeStart, property.NameLastModified, property.NameOrganizer, property.NameRecurrenceID,
+			property.NameSequenceNumber, property.NameStatus, property.NameSummary, property.NameURL,
+		}
+	case *FreeBusy:
+		return []property.Name{
+			property.NameUID, property.NameDateTimeStamp, property.NameContact, property.NameDateTimeStart,
+			property.NameDateTimeEnd, property.NameOrganizer, property.NameURL,
+		}
+	case *Timezone:
+		return []property.Name{property.NameTimezoneIdentifier, property.NameLastModified, property.NameTimezoneURL}
+	case *Standard, *Daylight:
+		return observanceOnce
+	case *AlarmAudio:
+		return append(alarm, property.NameAttachment)
+	case *AlarmDisplay:
+		return append(alarm, property.NameDescription)
+	case *AlarmEmail:
+		return append(alarm, property.NameDescription, property.NameSummary)
+	}
+	return nil
+}
+
+// checkSchedule checks DTSTART, DTEND or DUE, DURATION, RECURRENCE-ID and RRULE.
+// endName is name of property of r.end.
+func (ch *checker) checkSchedule(path []component.Type, r recurrence, endName property.Name) {
+	if r.end != nil && r.duration != nil {
+		ch.add(SeverityError, RuleEndOrDuration, path, endName, "must not occur with %s", property.NameDuration)
+	}
+	if r.start == nil {
+		if r.kind == component.TypeTODO && r.duration != nil {
+			ch.add(SeverityError, RuleDurationWithStart, path, property.NameDuration, "must occur with %s", property.NameDateTimeStart)
+		}
+		return
+	}
+	start := r.start.Value
+	if r.end != nil {
+		end := timeOf(r.end)
+		switch {
+		case isDate(r.end) != isDate(start):
+			ch.add(SeverityError, RuleEndValueType, path, endName, "must have the same value type as %s", property.NameDateTimeStart)
+		case r.kind == component.TypeTODO && end.Before(timeOf(start)):
+			ch.add(SeverityError, RuleEndAfterStart, path, endName, "must not be earlier than %s", property.NameDateTimeStart)
+		case r.kind != component.TypeTODO && !end.After(timeOf(start)):
+			ch.add(SeverityError, RuleEndAfterStart, path, endName, "must be later than %s", property.NameDateTimeStart)
+		}
+	}
+	if r.kind == component.TypeEvent && r.duration != nil && isDate(start) && r.duration.Value.HourDuration != 0 {
+		ch.add(SeverityError, RuleDurationDays, path, property.NameDuration, "must be days or weeks since %s is DATE", property.NameDateTimeStart)
+	}
+	if r.recurrenceID != nil && isDate(r.recurrenceID.Value) != isDate(start) {
+		ch.add(SeverityError, RuleRecurrenceIDValueType, path, property.NameRecurrenceID, "must have the same value type as %s", property.NameDateTimeStart)
+	}
+	if r.rule != nil {
+		ch.checkRecurrenceRule(path, r.start, r.rule.Value)
+	}
+}
+
+// https://tools.ietf.org/html/rfc5545#section-3.3.10
+func (ch *checker) checkRecurrenceRule(path []component.Type, start *property.DateTimeStart, rr types.RecurrenceRule) {
+	if rr.EndDate == nil {
+		return
+	}
+	if rr.Count > 0 {
+		ch.add(SeverityError, RuleUntilOrCount, path, property.NameRecurrenceRule, "UNTIL must not occur with COUNT")
+	}
+	utcUntil := timeOf(rr.EndDate).Location() == time.UTC
+	switch {
+	case isDate(rr.EndDate) != isDate(start.Value):
+		ch.add(SeverityError, RuleUntilValueType, path, property.NameRecurrenceRule, "UNTIL must have the same value type as %s", property.NameDateTimeStart)
+	case isDate(start.Value):
+	case timeOf(start.Value).Location() == time.UTC || start.Parameter.GetTimezone() != "":
+		if !utcUntil {
+			ch.add(SeverityError, RuleUntilValueType, path, property.NameRecurrenceRule, "UNTIL must be UTC since %s is UTC or has TZID", property.NameDateTimeStart)
+		}
+	case utcUntil:
+		ch.add(SeverityError, RuleUntilValueType, path, property.NameRecurrenceRule, "UNTIL must be local time since %s is local time", property.NameDateTimeStart)
+	}
+}
+
+// childPath returns path of component of ct in path
+func childPath(path []component.Type, ct component.Type) []component.Type {
+	res := make([]component.Type, 0, len(path)+1)
+	res = append(res, path...)
+	return append(res, ct)
+}
@@ -0,0 +1,263 @@
+package ical
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/knsh14/ical/component"
+	"github.com/knsh14/ical/errcode"
+	"github.com/knsh14/ical/parameter"
+	"github.com/knsh14/ical/property"
+	"github.com/knsh14/ical/types"
+	"github.com/morikuni/failure"
+)
+
+func TestCalendar_Check(t *testing.T) {
+	t.Parallel()
+
+	event := []component.Type{component.TypeCalendar, component.TypeEvent}
+	testcases := map[string]struct {
+		input    func(*testing.T) *Calendar
+		expected Findings
+	}{
+		"valid": {
+			input: func(t *testing.T) *Calendar {
+				c := newTestCalendar(t)
+				c.Components = append(c.Components, newTestEvent(t))
+				return c
+			},
+		},
+		"no component": {
+			input: newTestCalendar,
+			expected: Findings{
+				{Severity: SeverityError, Rule: RuleComponentRequired, Path: []component.Type{component.TypeCalendar}},
+			},
+		},
+		"missing properties": {
+			input: func(t *testing.T) *Calendar {
+				c := NewCalendar()
+				c.Components = append(c.Components, NewEvent(), NewToDo())
+				return c
+			},
+			expected: Findings{
+				{Severity: SeverityError, Rule: RuleRequired, Path: []component.Type{component.TypeCalendar}, Property: property.NameProdID},
+				{Severity: SeverityError, Rule: RuleRequired, Path: event, Property: property.NameUID},
+				{Severity: SeverityError, Rule: RuleRequired, Path: event, Property: property.NameDateTimeStamp},
+				{Severity: SeverityError, Rule: RuleRequired, Path: event, Property: property.NameDateTimeStart},
+				{Severity: SeverityError, Rule: RuleRequired, Path: []component.Type{component.TypeCalendar, component.TypeTODO}, Property: property.NameUID},
+				{Severity: SeverityError, Rule: RuleRequired, Path: []component.Type{component.TypeCalendar, component.TypeTODO}, Property: property.NameDateTimeStamp},
+			},
+		},
+		"DTEND with DURATION before DTSTART": {
+			input: func(t *testing.T) *Calendar {
+				c := newTestCalendar(t)
+				e := newTestEvent(t)
+				mustNoError(t, e.SetDateTimeEnd(parameter.Container{}, types.DateTime(time.Date(1997, 7, 14, 16, 0, 0, 0, time.UTC))))
+				mustNoError(t, e.SetDuration(parameter.Container{}, types.Duration{HourDuration: time.Hour}))
+				c.Components = append(c.Components, e)
+				return c
+			},
+			expected: Findings{
+				{Severity: SeverityError, Rule: RuleEndOrDuration, Path: event, Property: property.NameDateTimeEnd},
+				{Severity: SeverityError, Rule: RuleEndAfterStart, Path: event, Property: property.NameDateTimeEnd},
+			},
+		},
+		"DTEND of DATE": {
+			input: func(t *testing.T) *Calendar {
+				c := newTestCalendar(t)
+				e := newTestEvent(t)
+				mustNoError(t, e.SetDateTimeEnd(parameter.Container{}, types.Date(time.Date(1997, 7, 15, 0, 0, 0, 0, time.UTC))))
+				c.Components = append(c.Components, e)
+				return c
+			},
+			expected: Findings{
+				{Severity: SeverityError, Rule: RuleEndValueType, Path: event, Property: property.NameDateTimeEnd},
+			},
+		},
+		"DURATION of hours for DATE": {
+			input: func(t *testing.T) *Calendar {
+				c := newTestCalendar(t)
+				e := newTestEvent(t)
+				e.DateTimeEnd = nil
+				mustNoError(t, e.SetDateTimeStart(parameter.Container{}, types.Date(time.Date(1997, 7, 14, 0, 0, 0, 0, time.UTC))))
+				mustNoError(t, e.SetDuration(parameter.Container{}, types.Duration{HourDuration: time.Hour}))
+				c.Components = append(c.Components, e)
+				return c
+			},
+			expected: Findings{
+				{Severity: SeverityError, Rule: RuleDurationDays, Path: event, Property: property.NameDuration},
+			},
+		},
+		"UNTIL of local time with COUNT": {
+			input: func(t *testing.T) *Calendar {
+				c := newTestCalendar(t)
+				e := newTestEvent(t)
+				mustNoError(t, e.SetRecurrenceRule(parameter.Container{}, types.RecurrenceRule{
+					Frequency: types.FrequencyPatternDaily,
+					EndDate:   types.DateTime(time.Date(1997, 8, 1, 0, 0, 0, 0, time.Local)),
+					Count:     3,
+				}))
+				c.Components = append(c.Components, e)
+				return c
+			},
+			expected: Findings{
+				{Severity: SeverityError, Rule: RuleUntilOrCount, Path: event, Property: property.NameRecurrenceRule},
+				{Severity: SeverityError, Rule: RuleUntilValueType, Path: event, Property: property.NameRecurrenceRule},
+			},
+		},
+		"undefined TZID": {
+			input: func(t *testing.T) *Calendar {
+				c := newTestCalendar(t)
+				e := newTestEvent(t)
+				tzid, err := parameter.NewReferenceTimezone("Asia/Tokyo")
+				mustNoError(t, err)
+				loc, err := time.LoadLocation("Asia/Tokyo")
+				mustNoError(t, err)
+				mustNoError(t, e.SetDateTimeStart(parameter.Container{
+					parameter.TypeNameReferenceTimezone: []parameter.Base{tzid},
+				}, types.DateTime(time.Date(1997, 7, 14, 17, 0, 0, 0, loc))))
+				c.Components = append(c.Components, e)
+				return c
+			},
+			expected: Findings{
+				{Severity: SeverityError, Rule: RuleTimezoneDefined, Path: event, Property: property.NameDateTimeStart},
+			},
+		},
+		"invalid alarm": {
+			input: func(t *testing.T) *Calendar {
+				c := newTestCalendar(t)
+				e := newTestEvent(t)
+				a := &AlarmDisplay{Action: &property.Action{Value: types.Text(property.ActionTypeDisplay)}}
+				mustNoError(t, a.SetTrigger(parameter.Container{}, types.Duration{Direction: "-", HourDuration: 15 * time.Minute}))
+				mustNoError(t, a.SetDuration(parameter.Container{}, types.Duration{HourDuration: 5 * time.Minute}))
+				e.AddAlarm(a)
+				c.Components = append(c.Components, e)
+				return c
+			},
+			expected: Findings{
+				{Severity: SeverityError, Rule: RuleRequired, Path: append(event, component.TypeAlarm), Property: property.NameDescription},
+				{Severity: SeverityError, Rule: RuleAlarmRepeat, Path: append(event, component.TypeAlarm), Property: property.NameDuration},
+			},
+		},
+		"duplicated properties": {
+			input: func(t *testing.T) *Calendar {
+				c := newTestCalendar(t)
+				e := newTestEvent(t)
+				e.PropertyOrder = []property.Name{
+					property.NameUID, property.NameDateTimeStamp, property.NameSummary, property.NameSummary,
+					property.NameRecurrenceRule, property.NameRecurrenceRule,
+				}
+				c.Components = append(c.Components, e)
+				return c
+			},
+			expected: Findings{
+				{Severity: SeverityError, Rule: RuleOnce, Path: event, Property: property.NameSummary},
+				{Severity: SeverityWarning, Rule: RuleOnce, Path: event, Property: property.NameRecurrenceRule},
+			},
+		},
+		"todo": {
+			input: func(t *testing.T) *Calendar {
+				c := newTestCalendar(t)
+				todo := NewToDo()
+				mustNoError(t, todo.SetUID(parameter.Container{}, types.NewText("1")))
+				mustNoError(t, todo.SetDateTimeStamp(parameter.Container{}, types.DateTime(time.Date(1997, 6, 10, 17, 23, 45, 0, time.UTC))))
+				mustNoError(t, todo.SetDuration(parameter.Container{}, types.Duration{Day: 1}))
+				done := NewToDo()
+				mustNoError(t, done.SetUID(parameter.Container{}, types.NewText("2")))
+				mustNoError(t, done.SetDateTimeStamp(parameter.Container{}, types.DateTime(time.Date(1997, 6, 10, 17, 23, 45, 0, time.UTC))))
+				mustNoError(t, done.SetDateTimeStart(parameter.Container{}, types.Date(time.Date(1997, 7, 14, 0, 0, 0, 0, time.UTC))))
+				mustNoError(t, done.SetDateTimeDue(parameter.Container{}, types.Date(time.Date(1997, 7, 14, 0, 0, 0, 0, time.UTC))))
+				c.Components = append(c.Components, todo, done)
+				return c
+			},
+			expected: Findings{
+				{Severity: SeverityError, Rule: RuleDurationWithStart, Path: []component.Type{component.TypeCalendar, component.TypeTODO}, Property: property.NameDuration},
+			},
+		},
+	}
+
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got := tc.input(t).Check()
+			if diff := cmp.Diff(tc.expected, got, cmpopts.IgnoreFields(Finding{}, "Err")); diff != "" {
+				t.Errorf("diff: (-expected +got)\n%s", diff)
+			}
+			for _, f := range got {
+				if f.Err == nil {
+					t.Errorf("%s: error is not recorded", f.Rule)
+				}
+			}
+		})
+	}
+}
+
+func TestFindings(t *testing.T) {
+	t.Parallel()
+	c := newTestCalendar(t)
+	e := newTestEvent(t)
+	e.PropertyOrder = []property.Name{property.NameRecurrenceRule, property.NameRecurrenceRule}
+	e.UID = nil
+	c.Components = append(c.Components, e)
+
+	findings := c.Check()
+	if len(findings) != 2 {
+		t.Fatalf("expected 2 findings, but %v", findings)
+	}
+	errs := findings.Errors()
+	if len(errs) != 1 || errs[0].Property != property.NameUID {
+		t.Errorf("expected error of UID only, but %v", errs)
+	}
+	if !failure.Is(errs[0], errcode.MissingProperty) {
+		code, _ := failure.CodeOf(errs[0])
+		t.Errorf("expected code %s, but %v", errcode.MissingProperty, code)
+	}
+	expected := "error: VCALENDAR/VEVENT: UID: is required (required)"
+	if got := errs[0].Error(); got != expected {
+		t.Errorf("expected %q, but %q", expected, got)
+	}
+}
+
+func TestCheckOccurrences(t *testing.T) {
+	t.Parallel()
+	alarm := []component.Type{component.TypeCalendar, component.TypeEvent, component.TypeAlarm}
+	counts := map[property.Name]int{property.NameAttachment: 2, property.NameDescription: 2}
+
+	testcases := map[string]struct {
+		alarm    Alarm
+		expected []property.Name
+	}{
+		"audio": {
+			alarm:    NewAlarmAudio(),
+			expected: []property.Name{property.NameAttachment},
+		},
+		"display": {
+			alarm:    &AlarmDisplay{},
+			expected: []property.Name{property.NameDescription},
+		},
+		"email may have attachments": {
+			alarm:    &AlarmEmail{},
+			expected: []property.Name{property.NameDescription},
+		},
+	}
+
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			var got []property.Name
+			for _, f := range CheckOccurrences(alarm, tc.alarm, counts) {
+				if f.Rule != RuleOnce || !cmp.Equal(f.Path, alarm) {
+					t.Errorf("unexpected finding %v", f)
+				}
+				got = append(got, f.Property)
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("diff: (-expected +got)\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,409 @@
+package xcal
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/knsh14/ical"
+	"github.com/knsh14/ical/contentline"
+	"github.com/knsh14/ical/lexer"
+	"github.com/knsh14/ical/parameter"
+	"github.com/knsh14/ical/parser"
+	"github.com/knsh14/ical/property"
+	"github.com/knsh14/ical/types"
+)
+
+// Namespace is XML namespace of xCal
+// https://tools.ietf.org/html/rfc6321#section-3.1
+const Namespace = "urn:ietf:params:xml:ns:icalendar-2.0"
+
+// element is any element of xCal
+type element struct {
+	XMLName  xml.Name
+	Attrs    []xml.Attr `xml:",any,attr"`
+	Children []*element `xml:",any"`
+	Text     string     `xml:",chardata"`
+}
+
+func newElement(name string, children ...*element) *element {
+	return &element{XMLName: xml.Name{Local: name}, Children: children}
+}
+
+func newText(name, text string) *element {
+	return &element{XMLName: xml.Name{Local: name}, Text: text}
+}
+
+// child returns the first child named name, or nil
+func (e *element) child(name string) *element {
+	for _, c := range e.Children {
+		if c.XMLName.Local == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// parameterValueTypes is value type of parameters which is not TEXT
+// https://tools.ietf.org/html/rfc6321#section-3.5
+var parameterValueTypes = map[parameter.TypeName]types.ValueType{
+	parameter.TypeNameAlternateTextRepresentation: types.ValueTypeURI,
+	parameter.TypeNameDelegator:                   types.ValueTypeCalendarUserAddress,
+	parameter.TypeNameDelegatee:                   types.ValueTypeCalendarUserAddress,
+	parameter.TypeNameDirectoryEntry:              types.ValueTypeURI,
+	parameter.TypeNameMembership:                  types.ValueTypeCalendarUserAddress,
+	parameter.TypeNameSentBy:                      types.ValueTypeCalendarUserAddress,
+}
+
+// Marshal returns xCal document of c
+// https://tools.ietf.org/html/rfc6321#section-3
+func Marshal(c *ical.Calendar) ([]byte, error) {
+	b := &bytes.Buffer{}
+	if err := ical.NewEncoder(b).Encode(c); err != nil {
+		return nil, fmt.Errorf("encode calendar: %w", err)
+	}
+	lines, err := contentline.Unfold(b)
+	if err != nil {
+		return nil, err
+	}
+
+	type component struct {
+		name       string
+		properties []*element
+		components []*element
+	}
+	var (
+		stack []*component
+		root  *element
+	)
+	for _, l := range lines {
+		cl, err := contentline.ConvertContentLine(lexer.New(l))
+		if err != nil {
+			return nil, fmt.Errorf("convert content line %q: %w", l, err)
+		}
+		switch property.Name(strings.ToUpper(cl.Name)) {
+		case property.NameBegin:
+			stack = append(stack, &component{name: strings.ToLower(strings.Join(cl.Values, ","))})
+		case property.NameEnd:
+			n := len(stack)
+			if n == 0 {
+				return nil, fmt.Errorf("END without BEGIN")
+			}
+			top := stack[n-1]
+			e := newElement(top.name)
+			if len(top.properties) > 0 {
+				e.Children = append(e.Children, newElement("properties", top.properties...))
+			}
+			if len(top.components) > 0 {
+				e.Children = append(e.Children, newElement("components", top.components...))
+			}
+			stack = stack[:n-1]
+			if n == 1 {
+				root = e
+				continue
+			}
+			stack[n-2].components = append(stack[n-2].components, e)
+		default:
+			if len(stack) == 0 {
+				return nil, fmt.Errorf("property %s is out of component", cl.Name)
+			}
+			p, err := marshalProperty(cl)
+			if err != nil {
+				return nil, fmt.Errorf("property %s: %w", cl.Name, err)
+			}
+			top := stack[len(stack)-1]
+			top.properties = append(top.properties, p)
+		}
+	}
+	if root == nil {
+		return nil, fmt.Errorf("VCALENDAR is not closed")
+	}
+
+	// children inherit the default namespace declared here
+	doc := newElement("icalendar", root)
+	doc.Attrs = []xml.Attr{{Name: xml.Name{Local: "xmlns"}, Value: Namespace}}
+	res, err := xml.MarshalIndent(doc, "", " ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), res...), nil
+}
+
+func marshalProperty(cl *contentline.ContentLine) (*element, error) {
+	name := property.Name(strings.ToUpper(cl.Name))
+	t := property.DefaultValueType(name)
+	e := newElement(strings.ToLower(string(name)))
+
+	params := newElement("parameters")
+	for _, p := range cl.Parameters {
+		pname := parameter.TypeName(strings.ToUpper(p.Name))
+		if pname == parameter.TypeNameValueType && len(p.Values) == 1 {
+			t = types.ValueType(strings.ToUpper(p.Values[0]))
+			continue
+		}
+		pt, ok := parameterValueTypes[pname]
+		if !ok {
+			pt = types.ValueTypeText
+		}
+		pe := newElement(strings.ToLower(string(pname)))
+		for _, v := range p.Values {
+			pe.Children = append(pe.Children, newText(strings.ToLower(string(pt)), v))
+		}
+		params.Children = append(params.Children, pe)
+	}
+	if len(params.Children) > 0 {
+		e.Children = append(e.Children, params)
+	}
+
+	raw := strings.Join(cl.Values, ",")
+	switch {
+	case t == types.ValueTypeUnknown:
+		e.Children = append(e.Children, newText("unknown", raw))
+		return e, nil
+	case t == types.ValueTypeRecurrenceRule:
+		r, err := marshalRecurrenceRule(raw)
+		if err != nil {
+			return nil, err
+		}
+		e.Children = append(e.Children, r)
+		return e, nil
+	case name == property.NameGeo:
+		parts := property.SplitEscaped(raw, ';', -1)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("GEO must have latitude and longitude, but %s", raw)
+		}
+		e.Children = append(e.Children, newText("latitude", parts[0]), newText("longitude", parts[1]))
+		return e, nil
+	case name == property.NameRequestStatus:
+		parts := property.SplitEscaped(raw, ';', -1)
+		for i, n := range []string{"code", "description", "data"} {
+			if i < len(parts) {
+				e.Children = append(e.Children, newText(n, string(types.UnescapeText(parts[i]))))
+			}
+		}
+		return e, nil
+	}
+	for _, v := range cl.Values {
+		ve, err := marshalValue(t, v)
+		if err != nil {
+			return nil, err
+		}
+		e.Children = append(e.Children, ve)
+	}
+	return e, nil
+}
+
+// marshalValue returns value element of v in iCalendar format
+// https://tools.ietf.org/html/rfc6321#section-3.6
+func marshalValue(t types.ValueType, v string) (*element, error) {
+	name := strings.ToLower(string(t))
+	switch t {
+	case types.ValueTypeText:
+		return newText(name, string(types.UnescapeText(v))), nil
+	case types.ValueTypeBoolean:
+		return newText(name, strings.ToLower(v)), nil
+	case types.ValueTypePeriod:
+		i := strings.Index(v, "/")
+		if i < 0 {
+			return nil, fmt.Errorf("invalid PERIOD %s", v)
+		}
+		start, err := types.ToExtended(types.ValueTypeDateTime, v[:i])
+		if err != nil {
+			return nil, err
+		}
+		end := v[i+1:]
+		if strings.HasPrefix(end, "P") || strings.HasPrefix(end, "+P") {
+			return newElement(name, newText("start", start), newText("duration", end)), nil
+		}
+		end, err = types.ToExtended(types.ValueTypeDateTime, end)
+		if err != nil {
+			return nil, err
+		}
+		return newElement(name, newText("start", start), newText("end", end)), nil
+	}
+	s, err := types.ToExtended(t, v)
+	if err != nil {
+		return nil, err
+	}
+	return newText(name, s), nil
+}
+
+// marshalRecurrenceRule returns recur element which has an element for each value of rule parts
+// https://tools.ietf.org/html/rfc6321#section-3.6.10
+func marshalRecurrenceRule(v string) (*element, error) {
+	e := newElement(strings.ToLower(string(types.ValueTypeRecurrenceRule)))
+	for _, part := range strings.Split(v, ";") {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid rule part %s", part)
+		}
+		key := strings.ToLower(kv[0])
+		for _, s := range strings.Split(kv[1], ",") {
+			if key == "until" {
+				t := types.ValueTypeDateTime
+				if !strings.Contains(s, "T") {
+					t = types.ValueTypeDate
+				}
+				u, err := types.ToExtended(t, s)
+				if err != nil {
+					return nil, err
+				}
+				s = u
+			}
+			e.Children = append(e.Children, newText(key, s))
+		}
+	}
+	return e, nil
+}
+
+// Unmarshal parses xCal document b by parser.Parse
+func Unmarshal(b []byte, opts ...parser.Option) (*ical.Calendar, error) {
+	var doc element
+	if err := xml.Unmarshal(b, &doc); err != nil {
+		return nil, fmt.Errorf("decode xml: %w", err)
+	}
+	if doc.XMLName.Space != Namespace || doc.XMLName.Local != "icalendar" {
+		return nil, fmt.Errorf("root element must be icalendar in %s, but %s in %s", Namespace, doc.XMLName.Local, doc.XMLName.Space)
+	}
+	vcalendar := doc.child("vcalendar")
+	if vcalendar == nil {
+		return nil, fmt.Errorf("vcalendar is not found")
+	}
+	buf := &bytes.Buffer{}
+	if err := writeComponent(buf, vcalendar); err != nil {
+		return nil, err
+	}
+	return parser.Parse(buf, opts...)
+}
+
+func writeComponent(buf *bytes.Buffer, e *element) error {
+	name := strings.ToUpper(e.XMLName.Local)
+	buf.WriteString(contentline.Fold(fmt.Sprintf("%s:%s", property.NameBegin, name)))
+	if props := e.child("properties"); props != nil {
+		for _, p := range props.Children {
+			l, err := unmarshalProperty(p)
+			if err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			buf.WriteString(contentline.Fold(l))
+		}
+	}
+	if components := e.child("components"); components != nil {
+		for _, c := range components.Children {
+			if err := writeComponent(buf, c); err != nil {
+				return err
+			}
+		}
+	}
+	buf.WriteString(contentline.Fold(fmt.Sprintf("%s:%s", property.NameEnd, name)))
+	return nil
+}
+
+// unmarshalProperty returns content line of property element e
+func unmarshalProperty(e *element) (string, error) {
+	name := property.Name(strings.ToUpper(e.XMLName.Local))
+	var b, params strings.Builder
+	b.WriteString(string(name))
+	var values []*element
+	for _, c := range e.Children {
+		if c.XMLName.Local != "parameters" {
+			values = append(values, c)
+			continue
+		}
+		for _, p := range c.Children {
+			var pvs []string
+			for _, v := range p.Children {
+				pv, err := parameter.QuoteIfNeeded(v.Text)
+				if err != nil {
+					return "", fmt.Errorf("parameter %s of %s: %w", p.XMLName.Local, name, err)
+				}
+				pvs = append(pvs, pv)
+			}
+			fmt.Fprintf(&params, ";%s=%s", strings.ToUpper(p.XMLName.Local), strings.Join(pvs, ","))
+		}
+	}
+	if len(values) == 0 {
+		return "", fmt.Errorf("%s does not have value", name)
+	}
+
+	switch name {
+	case property.NameGeo, property.NameRequestStatus:
+		b.WriteString(params.String())
+	}
+	switch name {
+	case property.NameGeo:
+		lat, lon := e.child("latitude"), e.child("longitude")
+		if lat == nil || lon == nil {
+			return "", fmt.Errorf("GEO must have latitude and longitude")
+		}
+		return fmt.Sprintf("%s:%s;%s", b.String(), strings.TrimSpace(lat.Text), strings.TrimSpace(lon.Text)), nil
+	case property.NameRequestStatus:
+		var parts []string
+		for _, n := range []string{"code", "description", "data"} {
+			if c := e.child(n); c != nil {
+				parts = append(parts, types.NewText(c.Text).Escape())
+			}
+		}
+		return fmt.Sprintf("%s:%s", b.String(), strings.Join(parts, ";")), nil
+	}
+
+	t := types.ValueType(strings.ToUpper(values[0].XMLName.Local))
+	if t != property.DefaultValueType(name) && t != types.ValueTypeUnknown {
+		// VALUE is the first parameter, position of it is not kept in xCal
+		fmt.Fprintf(&b, ";%s=%s", parameter.TypeNameValueType, t)
+	}
+	b.WriteString(params.String())
+	var vs []string
+	for _, v := range values {
+		s, err := unmarshalValue(v)
+		if err != nil {
+			return "", fmt.Errorf("value of %s: %w", name, err)
+		}
+		vs = append(vs, s)
+	}
+	return fmt.Sprintf("%s:%s", b.String(), strings.Join(vs, ",")), nil
+}
+
+// unmarshalValue returns value element e in iCalendar format
+func unmarshalValue(e *element) (string, error) {
+	t := types.ValueType(strings.ToUpper(e.XMLName.Local))
+	switch t {
+	case types.ValueTypeText:
+		return types.NewText(e.Text).Escape(), nil
+	case types.ValueTypeUnknown:
+		return e.Text, nil
+	case types.ValueTypeBoolean:
+		return strings.ToUpper(strings.TrimSpace(e.Text)), nil
+	case types.ValueTypeRecurrenceRule:
+		var parts []string
+		for _, c := range e.Children {
+			key := strings.ToUpper(c.XMLName.Local)
+			v := strings.TrimSpace(c.Text)
+			if key == "UNTIL" {
+				v = types.FromExtended(types.ValueTypeDateTime, v)
+			}
+			// values of the same rule part are joined by COMMA
+			if n := len(parts); n > 0 && strings.HasPrefix(parts[n-1], key+"=") {
+				parts[n-1] += "," + v
+				continue
+			}
+			parts = append(parts, key+"="+v)
+		}
+		return strings.Join(parts, ";"), nil
+	case types.ValueTypePeriod:
+		start := e.child("start")
+		if start == nil {
+			return "", fmt.Errorf("period must have start")
+		}
+		s := types.FromExtended(types.ValueTypeDateTime, strings.TrimSpace(start.Text))
+		if end := e.child("end"); end != nil {
+			return s + "/" + types.FromExtended(types.ValueTypeDateTime, strings.TrimSpace(end.Text)), nil
+		}
+		if d := e.child("duration"); d != nil {
+			return s + "/" + strings.TrimSpace(d.Text), nil
+		}
+		return "", fmt.Errorf("period must have end or duration")
+	}
+	return types.FromExtended(t, strings.TrimSpace(e.Text)), nil
+}
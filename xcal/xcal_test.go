@@ -0,0 +1,186 @@
+package xcal
+
+import (
+	"bytes"
+	"encoding/xml"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/knsh14/ical"
+	"github.com/knsh14/ical/parser"
+)
+
+func TestMarshal(t *testing.T) {
+	t.Parallel()
+	input := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"PRODID:-//Example Inc.//Example Calendar//EN",
+		"VERSION:2.0",
+		"BEGIN:VEVENT",
+		"UID:4088E990AD89CB3DBB484909",
+		"DTSTAMP:20080205T191224Z",
+		"DTSTART;VALUE=DATE:20081006",
+		"SUMMARY:Planning meeting\\, room 1",
+		"GEO:37.386013;-122.082932",
+		"CATEGORIES:MEETING,PLAN\\,NING",
+		"RRULE:FREQ=WEEKLY;UNTIL=20081231T000000Z;BYDAY=MO,WE;INTERVAL=2",
+		"ATTENDEE;CN=\"Doe, John\";DELEGATED-FROM=\"mailto:a@example.com\",\"mailto:b@example.com\":mailto:john@example.com",
+		"REQUEST-STATUS:2.0;Success",
+		"X-EXAMPLE;X-PARAM=1:raw,values",
+		"END:VEVENT",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n")
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<icalendar xmlns="urn:ietf:params:xml:ns:icalendar-2.0">
+ <vcalendar>
+  <properties>
+   <prodid><text>-//Example Inc.//Example Calendar//EN</text></prodid>
+   <version><text>2.0</text></version>
+  </properties>
+  <components>
+   <vevent>
+    <properties>
+     <uid><text>4088E990AD89CB3DBB484909</text></uid>
+     <dtstamp><date-time>2008-02-05T19:12:24Z</date-time></dtstamp>
+     <dtstart><date>2008-10-06</date></dtstart>
+     <summary><text>Planning meeting, room 1</text></summary>
+     <geo><latitude>37.386013</latitude><longitude>-122.082932</longitude></geo>
+     <categories><text>MEETING</text><text>PLAN,NING</text></categories>
+     <rrule>
+      <recur>
+       <freq>WEEKLY</freq>
+       <until>2008-12-31T00:00:00Z</until>
+       <interval>2</interval>
+       <byday>MO</byday>
+       <byday>WE</byday>
+      </recur>
+     </rrule>
+     <attendee>
+      <parameters>
+       <cn><text>Doe, John</text></cn>
+       <delegated-from>
+        <cal-address>mailto:a@example.com</cal-address>
+        <cal-address>mailto:b@example.com</cal-address>
+       </delegated-from>
+      </parameters>
+      <cal-address>mailto:john@example.com</cal-address>
+     </attendee>
+     <request-status><code>2.0</code><description>Success</description></request-status>
+     <x-example>
+      <parameters><x-param><text>1</text></x-param></parameters>
+      <unknown>raw,values</unknown>
+     </x-example>
+    </properties>
+   </vevent>
+  </components>
+ </vcalendar>
+</icalendar>`
+
+	c, err := parser.Parse(strings.NewReader(input), parser.WithRoundTrip())
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(tree(t, []byte(expected)), tree(t, got)); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	back, err := Unmarshal(got, parser.WithRoundTrip())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(encode(t, c), encode(t, back)); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	t.Parallel()
+	files, err := filepath.Glob(filepath.Join("..", "parser", "testdata", "roundtrip", "*.ics"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("no test data")
+	}
+	for _, file := range files {
+		file := file
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			t.Parallel()
+			c, err := parser.ParseFile(file, parser.WithRoundTrip())
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := Marshal(c)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := Unmarshal(b, parser.WithRoundTrip())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(encode(t, c), encode(t, got)); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_Error(t *testing.T) {
+	t.Parallel()
+	testcases := map[string]string{
+		"not xml":             `vcalendar`,
+		"no namespace":        `<icalendar><vcalendar></vcalendar></icalendar>`,
+		"no vcalendar":        `<icalendar xmlns="urn:ietf:params:xml:ns:icalendar-2.0"></icalendar>`,
+		"no value":            `<icalendar xmlns="urn:ietf:params:xml:ns:icalendar-2.0"><vcalendar><properties><version/></properties></vcalendar></icalendar>`,
+		"period no start":     `<icalendar xmlns="urn:ietf:params:xml:ns:icalendar-2.0"><vcalendar><properties><x-a><period><end>2008-01-01T00:00:00Z</end></period></x-a></properties></vcalendar></icalendar>`,
+		"dquote in parameter": `<icalendar xmlns="urn:ietf:params:xml:ns:icalendar-2.0"><vcalendar><properties><version><parameters><x-a><text>a"b</text></x-a></parameters><text>2.0</text></version></properties></vcalendar></icalendar>`,
+	}
+	for title, input := range testcases {
+		input := input
+		t.Run(title, func(t *testing.T) {
+			t.Parallel()
+			if _, err := Unmarshal([]byte(input)); err == nil {
+				t.Fatal("expected error but got nil")
+			}
+		})
+	}
+}
+
+// tree returns b as element without whitespace between elements
+func tree(t *testing.T, b []byte) *element {
+	t.Helper()
+	var e element
+	mustNoError(t, xml.Unmarshal(b, &e))
+	var trim func(e *element)
+	trim = func(e *element) {
+		if len(e.Children) > 0 {
+			e.Text = strings.TrimSpace(e.Text)
+		}
+		for _, c := range e.Children {
+			trim(c)
+		}
+	}
+	trim(&e)
+	return &e
+}
+
+func encode(t *testing.T, c *ical.Calendar) string {
+	t.Helper()
+	b := &bytes.Buffer{}
+	mustNoError(t, ical.NewEncoder(b).Encode(c))
+	return b.String()
+}
+
+func mustNoError(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}